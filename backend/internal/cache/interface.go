@@ -38,4 +38,22 @@ type CacheInterface interface {
 	GetTTL(key string) (time.Duration, error)
 	Clear() error
 	Close() error
+
+	// Sorted sets (used for rankings). Members of a set share the key's TTL.
+	ZReplace(key string, members []SortedSetMember, ttl time.Duration) error
+	ZAddIfExists(key string, member SortedSetMember) (bool, error)
+	ZScore(key string, member string) (float64, error)
+	ZCard(key string) (int64, error)
+	ZCount(key string, min, max string) (int64, error)
+	ZRevRangeWithScores(key string, start, stop int64) ([]SortedSetMember, error)
+
+	// Lists (used for append-only logs). Pushing refreshes the key's TTL.
+	RPush(key string, values []string, ttl time.Duration) error
+	LRange(key string, start, stop int64) ([]string, error)
+}
+
+// SortedSetMember is a member of a sorted set with its score
+type SortedSetMember struct {
+	Member string
+	Score  float64
 }
@@ -10,6 +10,8 @@ import (
 // MemoryCache is an in-memory cache implementation
 type MemoryCache struct {
 	data          map[string]cacheItem
+	sortedSets    map[string]*memorySortedSet
+	lists         map[string]*memoryList
 	mu            sync.RWMutex
 	stopCh        chan struct{}
 	cleanupWG     sync.WaitGroup
@@ -29,6 +31,8 @@ type cacheItem struct {
 func NewMemoryCache(maxMemoryMB int64, maxItems int) *MemoryCache {
 	cache := &MemoryCache{
 		data:          make(map[string]cacheItem),
+		sortedSets:    make(map[string]*memorySortedSet),
+		lists:         make(map[string]*memoryList),
 		stopCh:        make(chan struct{}),
 		maxMemoryMB:   maxMemoryMB,
 		currentMemory: 0,
@@ -122,6 +126,15 @@ func (c *MemoryCache) Delete(key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if _, exists := c.sortedSets[key]; exists {
+		delete(c.sortedSets, key)
+		return nil
+	}
+	if _, exists := c.lists[key]; exists {
+		delete(c.lists, key)
+		return nil
+	}
+
 	item, exists := c.data[key]
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
@@ -137,6 +150,10 @@ func (c *MemoryCache) Delete(key string) error {
 
 // Exists checks if a key exists in cache
 func (c *MemoryCache) Exists(key string) bool {
+	if c.liveSortedSet(key) != nil || c.liveList(key) != nil {
+		return true
+	}
+
 	c.mu.RLock()
 	item, exists := c.data[key]
 	c.mu.RUnlock()
@@ -162,6 +179,10 @@ func (c *MemoryCache) Exists(key string) bool {
 
 // GetTTL returns the remaining time to live for a key
 func (c *MemoryCache) GetTTL(key string) (time.Duration, error) {
+	if set := c.liveSortedSet(key); set != nil {
+		return time.Until(set.expiresAt), nil
+	}
+
 	c.mu.RLock()
 	item, exists := c.data[key]
 	c.mu.RUnlock()
@@ -191,6 +212,8 @@ func (c *MemoryCache) Clear() error {
 	defer c.mu.Unlock()
 
 	c.data = make(map[string]cacheItem)
+	c.sortedSets = make(map[string]*memorySortedSet)
+	c.lists = make(map[string]*memoryList)
 	c.currentMemory = 0
 	return nil
 }
@@ -285,6 +308,16 @@ func (c *MemoryCache) cleanup() {
 					c.currentMemory -= freedMemory
 				}
 			}
+			for key, set := range c.sortedSets {
+				if now.After(set.expiresAt) {
+					delete(c.sortedSets, key)
+				}
+			}
+			for key, list := range c.lists {
+				if now.After(list.expiresAt) {
+					delete(c.lists, key)
+				}
+			}
 			c.mu.Unlock()
 		}
 	}
@@ -0,0 +1,80 @@
+package cache
+
+import "time"
+
+// memoryList is the in-memory counterpart of a Redis list
+type memoryList struct {
+	values    []string
+	expiresAt time.Time
+}
+
+// RPush appends values to the end of a list, creating it if needed, and refreshes its TTL
+func (c *MemoryCache) RPush(key string, values []string, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list, exists := c.lists[key]
+	if !exists || time.Now().After(list.expiresAt) {
+		list = &memoryList{}
+		c.lists[key] = list
+	}
+	list.values = append(list.values, values...)
+	list.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+// LRange returns the values of a list between two indexes, inclusive. Negative indexes count from the end, like Redis.
+func (c *MemoryCache) LRange(key string, start, stop int64) ([]string, error) {
+	list := c.liveList(key)
+	if list == nil {
+		return []string{}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	length := int64(len(list.values))
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop {
+		return []string{}, nil
+	}
+
+	values := make([]string, stop-start+1)
+	copy(values, list.values[start:stop+1])
+	return values, nil
+}
+
+// liveList returns the list stored under key, or nil if it is missing or expired
+func (c *MemoryCache) liveList(key string) *memoryList {
+	c.mu.RLock()
+	list, exists := c.lists[key]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+	if time.Now().After(list.expiresAt) {
+		c.mu.Lock()
+		if list, exists := c.lists[key]; exists && time.Now().After(list.expiresAt) {
+			delete(c.lists, key)
+		}
+		c.mu.Unlock()
+		return nil
+	}
+	return list
+}
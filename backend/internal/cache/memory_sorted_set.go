@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// memorySortedSet is the in-memory counterpart of a Redis sorted set
+type memorySortedSet struct {
+	scores    map[string]float64
+	expiresAt time.Time
+}
+
+// ZReplace replaces a sorted set with the given members and TTL
+func (c *MemoryCache) ZReplace(key string, members []SortedSetMember, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sortedSets, key)
+	if len(members) == 0 {
+		return nil
+	}
+
+	set := &memorySortedSet{
+		scores:    make(map[string]float64, len(members)),
+		expiresAt: time.Now().Add(ttl),
+	}
+	for _, member := range members {
+		set.scores[member.Member] = member.Score
+	}
+	c.sortedSets[key] = set
+	return nil
+}
+
+// ZAddIfExists adds or raises a member's score when the sorted set exists, returning false when it does not
+func (c *MemoryCache) ZAddIfExists(key string, member SortedSetMember) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set, exists := c.sortedSets[key]
+	if !exists || time.Now().After(set.expiresAt) {
+		return false, nil
+	}
+
+	if current, ok := set.scores[member.Member]; !ok || member.Score > current {
+		set.scores[member.Member] = member.Score
+	}
+	return true, nil
+}
+
+// ZScore returns the score of a member of a sorted set
+func (c *MemoryCache) ZScore(key string, member string) (float64, error) {
+	set := c.liveSortedSet(key)
+	if set == nil {
+		return 0, fmt.Errorf("%w: %s %s", ErrKeyNotFound, key, member)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	score, ok := set.scores[member]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s %s", ErrKeyNotFound, key, member)
+	}
+	return score, nil
+}
+
+// ZCard returns the number of members of a sorted set
+func (c *MemoryCache) ZCard(key string) (int64, error) {
+	set := c.liveSortedSet(key)
+	if set == nil {
+		return 0, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return int64(len(set.scores)), nil
+}
+
+// ZCount returns the number of members with a score in range. Bounds use Redis syntax ("-inf", "+inf", "(1.5").
+func (c *MemoryCache) ZCount(key string, min, max string) (int64, error) {
+	minScore, minExclusive, err := parseScoreBound(min)
+	if err != nil {
+		return 0, err
+	}
+	maxScore, maxExclusive, err := parseScoreBound(max)
+	if err != nil {
+		return 0, err
+	}
+
+	set := c.liveSortedSet(key)
+	if set == nil {
+		return 0, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var count int64
+	for _, score := range set.scores {
+		if score < minScore || (minExclusive && score == minScore) {
+			continue
+		}
+		if score > maxScore || (maxExclusive && score == maxScore) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ZRevRangeWithScores returns members by rank, highest score first (ties ordered by member, descending, like Redis)
+func (c *MemoryCache) ZRevRangeWithScores(key string, start, stop int64) ([]SortedSetMember, error) {
+	set := c.liveSortedSet(key)
+	if set == nil {
+		return []SortedSetMember{}, nil
+	}
+
+	c.mu.RLock()
+	members := make([]SortedSetMember, 0, len(set.scores))
+	for member, score := range set.scores {
+		members = append(members, SortedSetMember{Member: member, Score: score})
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score > members[j].Score
+		}
+		return members[i].Member > members[j].Member
+	})
+
+	// Negative indexes count from the end, like Redis
+	length := int64(len(members))
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop {
+		return []SortedSetMember{}, nil
+	}
+	return members[start : stop+1], nil
+}
+
+// liveSortedSet returns the sorted set stored under key, or nil if it is missing or expired
+func (c *MemoryCache) liveSortedSet(key string) *memorySortedSet {
+	c.mu.RLock()
+	set, exists := c.sortedSets[key]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+	if time.Now().After(set.expiresAt) {
+		c.mu.Lock()
+		if set, exists := c.sortedSets[key]; exists && time.Now().After(set.expiresAt) {
+			delete(c.sortedSets, key)
+		}
+		c.mu.Unlock()
+		return nil
+	}
+	return set
+}
+
+// parseScoreBound parses a Redis score bound such as "-inf", "+inf", "10" or "(10"
+func parseScoreBound(bound string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	value := strings.TrimPrefix(bound, "(")
+
+	switch value {
+	case "-inf":
+		return math.Inf(-1), exclusive, nil
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, nil
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid score bound %q: %w", bound, err)
+	}
+	return score, exclusive, nil
+}
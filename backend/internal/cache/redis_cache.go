@@ -266,3 +266,118 @@ func (r *RedisCache) GetConnectionInfo() map[string]interface{} {
 		"pool_timeout":   opts.PoolTimeout,
 	}
 }
+
+// zAddIfExistsScript adds a member only when the set already exists, keeping the higher score
+var zAddIfExistsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+return redis.call('ZADD', KEYS[1], 'GT', ARGV[1], ARGV[2])
+`)
+
+// ZReplace atomically replaces a sorted set with the given members and TTL
+func (r *RedisCache) ZReplace(key string, members []SortedSetMember, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, key)
+		if len(members) == 0 {
+			return nil
+		}
+
+		zMembers := make([]redis.Z, 0, len(members))
+		for _, member := range members {
+			zMembers = append(zMembers, redis.Z{Score: member.Score, Member: member.Member})
+		}
+		pipe.ZAdd(r.ctx, key, zMembers...)
+		pipe.Expire(r.ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to replace sorted set %s: %v", ErrConnection, key, err)
+	}
+	return nil
+}
+
+// ZAddIfExists adds or raises a member's score when the sorted set exists, returning false when it does not
+func (r *RedisCache) ZAddIfExists(key string, member SortedSetMember) (bool, error) {
+	result, err := zAddIfExistsScript.Run(r.ctx, r.client, []string{key}, member.Score, member.Member).Int64()
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to add to sorted set %s: %v", ErrConnection, key, err)
+	}
+	return result >= 0, nil
+}
+
+// ZScore returns the score of a member of a sorted set
+func (r *RedisCache) ZScore(key string, member string) (float64, error) {
+	score, err := r.client.ZScore(r.ctx, key, member).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, fmt.Errorf("%w: %s %s", ErrKeyNotFound, key, member)
+		}
+		return 0, fmt.Errorf("%w: failed to get score from sorted set %s: %v", ErrConnection, key, err)
+	}
+	return score, nil
+}
+
+// ZCard returns the number of members of a sorted set
+func (r *RedisCache) ZCard(key string) (int64, error) {
+	count, err := r.client.ZCard(r.ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to count sorted set %s: %v", ErrConnection, key, err)
+	}
+	return count, nil
+}
+
+// ZCount returns the number of members with a score in range. Bounds use Redis syntax ("-inf", "+inf", "(1.5").
+func (r *RedisCache) ZCount(key string, min, max string) (int64, error) {
+	count, err := r.client.ZCount(r.ctx, key, min, max).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to count range of sorted set %s: %v", ErrConnection, key, err)
+	}
+	return count, nil
+}
+
+// ZRevRangeWithScores returns members by rank, highest score first
+func (r *RedisCache) ZRevRangeWithScores(key string, start, stop int64) ([]SortedSetMember, error) {
+	zMembers, err := r.client.ZRevRangeWithScores(r.ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read sorted set %s: %v", ErrConnection, key, err)
+	}
+
+	members := make([]SortedSetMember, 0, len(zMembers))
+	for _, z := range zMembers {
+		member, _ := z.Member.(string)
+		members = append(members, SortedSetMember{Member: member, Score: z.Score})
+	}
+	return members, nil
+}
+
+// RPush appends values to the end of a list and refreshes its TTL
+func (r *RedisCache) RPush(key string, values []string, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(r.ctx, key, args...)
+		pipe.Expire(r.ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to push to list %s: %v", ErrConnection, key, err)
+	}
+	return nil
+}
+
+// LRange returns the values of a list between two indexes, inclusive
+func (r *RedisCache) LRange(key string, start, stop int64) ([]string, error) {
+	values, err := r.client.LRange(r.ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read list %s: %v", ErrConnection, key, err)
+	}
+	return values, nil
+}
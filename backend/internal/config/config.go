@@ -11,13 +11,15 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Server   ServerConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Cleanup  CleanupConfig
-	OAuth    OAuthConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Server    ServerConfig
+	JWT       JWTConfig
+	CORS      CORSConfig
+	Cleanup   CleanupConfig
+	Analytics AnalyticsConfig
+	OAuth     OAuthConfig
+	Payment   PaymentConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -94,6 +96,39 @@ type CleanupConfig struct {
 	GracePeriod     time.Duration // Grace period to avoid race conditions (default: 2 minutes)
 }
 
+// AnalyticsConfig holds question analytics background worker configuration
+type AnalyticsConfig struct {
+	Enabled         bool          // Whether the analytics worker is enabled (default: true)
+	ProcessInterval time.Duration // How often to process finished attempts (default: 1 minute)
+	BatchSize       int           // Attempts loaded per query (default: 100)
+}
+
+// PaymentConfig holds payment gateway configuration
+type PaymentConfig struct {
+	Currency        string // Currency of package prices (default: BDT)
+	CallbackBaseURL string // Public URL of this API that gateways redirect and notify to
+	FakeEnabled     bool   // Whether the local fake gateway is offered (default: false)
+	FakeAutoApprove bool   // Whether fake checkouts are paid immediately (default: true)
+	SSLCommerz      SSLCommerzConfig
+	BKash           BKashConfig
+}
+
+// SSLCommerzConfig holds SSLCommerz store credentials, the gateway is disabled without a store ID
+type SSLCommerzConfig struct {
+	StoreID       string
+	StorePassword string
+	Sandbox       bool
+}
+
+// BKashConfig holds bKash tokenized checkout credentials, the gateway is disabled without an app key
+type BKashConfig struct {
+	AppKey    string
+	AppSecret string
+	Username  string
+	Password  string
+	Sandbox   bool
+}
+
 // OAuthConfig holds OAuth provider configurations
 type OAuthConfig struct {
 	Google   GoogleOAuthConfig
@@ -134,6 +169,14 @@ func Load() *Config {
 	cleanupInterval := parseDuration("CLEANUP_INTERVAL", "1m")
 	gracePeriod := parseDuration("CLEANUP_GRACE_PERIOD", "2m")
 
+	// Parse question analytics worker configuration
+	analyticsBatchSize := 100
+	if sizeStr := getEnv("ANALYTICS_BATCH_SIZE", "100"); sizeStr != "" {
+		if parsed, err := strconv.Atoi(sizeStr); err == nil && parsed > 0 {
+			analyticsBatchSize = parsed
+		}
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -163,6 +206,11 @@ func Load() *Config {
 			CleanupInterval: cleanupInterval,
 			GracePeriod:     gracePeriod,
 		},
+		Analytics: AnalyticsConfig{
+			Enabled:         getEnv("ANALYTICS_ENABLED", "true") == "true",
+			ProcessInterval: parseDuration("ANALYTICS_INTERVAL", "1m"),
+			BatchSize:       analyticsBatchSize,
+		},
 		OAuth: OAuthConfig{
 			Google: GoogleOAuthConfig{
 				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
@@ -175,6 +223,24 @@ func Load() *Config {
 				RedirectURL: getEnv("FACEBOOK_REDIRECT_URL", "http://localhost:8080/api/v1/auth/facebook/callback"),
 			},
 		},
+		Payment: PaymentConfig{
+			Currency:        getEnv("PAYMENT_CURRENCY", "BDT"),
+			CallbackBaseURL: getEnv("PAYMENT_CALLBACK_BASE_URL", "http://localhost:8080"),
+			FakeEnabled:     getEnv("PAYMENT_FAKE_ENABLED", "false") == "true",
+			FakeAutoApprove: getEnv("PAYMENT_FAKE_AUTO_APPROVE", "true") == "true",
+			SSLCommerz: SSLCommerzConfig{
+				StoreID:       getEnv("SSLCOMMERZ_STORE_ID", ""),
+				StorePassword: getEnv("SSLCOMMERZ_STORE_PASSWORD", ""),
+				Sandbox:       getEnv("SSLCOMMERZ_SANDBOX", "true") == "true",
+			},
+			BKash: BKashConfig{
+				AppKey:    getEnv("BKASH_APP_KEY", ""),
+				AppSecret: getEnv("BKASH_APP_SECRET", ""),
+				Username:  getEnv("BKASH_USERNAME", ""),
+				Password:  getEnv("BKASH_PASSWORD", ""),
+				Sandbox:   getEnv("BKASH_SANDBOX", "true") == "true",
+			},
+		},
 	}
 }
 
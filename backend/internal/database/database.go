@@ -62,14 +62,27 @@ func (d *Database) AutoMigrate() error {
 		&models.PackageExam{},
 		&models.UserPackageEnrollment{},
 		&models.UserExamAttempt{},
+		&models.UserSessionAnswer{},
 		&models.UserQuestionAnswer{},
+		&models.QuestionReviewSchedule{},
+		&models.QuestionBookmark{},
 		&models.Coupon{},
 		&models.CouponUsage{},
+		&models.PaymentTransaction{},
+		&models.EnrollmentRenewal{},
+		&models.PaymentRefund{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Attempts used to be unique per user, exam and package; the new index adds attempt_number
+	if d.DB.Migrator().HasIndex(&models.UserExamAttempt{}, "idx_user_exam_package") {
+		if err := d.DB.Migrator().DropIndex(&models.UserExamAttempt{}, "idx_user_exam_package"); err != nil {
+			return fmt.Errorf("failed to drop legacy attempt index: %w", err)
+		}
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
@@ -0,0 +1,35 @@
+package dto
+
+// BookmarkQuestionRequest represents a request to bookmark a question from the results of a finished session
+type BookmarkQuestionRequest struct {
+	SessionID  string `json:"session_id" binding:"required"` // Finished session the question was answered in
+	QuestionID uint   `json:"question_id" binding:"required"`
+	Note       string `json:"note,omitempty" binding:"max=2000"` // Private note, an existing note is kept when empty
+}
+
+// UpdateBookmarkNoteRequest represents a request to change the note of a bookmarked question
+type UpdateBookmarkNoteRequest struct {
+	Note string `json:"note" binding:"max=2000"` // Empty clears the note
+}
+
+// BookmarkResponse represents a bookmarked question with its note
+type BookmarkResponse struct {
+	QuestionID      uint   `json:"question_id"`
+	QuestionText    string `json:"question_text"`
+	QuestionType    string `json:"question_type"`
+	DifficultyLevel string `json:"difficulty_level"`
+	SystemID        uint   `json:"system_id"`
+	SystemName      string `json:"system_name"`
+	SubjectID       uint   `json:"subject_id"`
+	SubjectName     string `json:"subject_name"`
+	Note            string `json:"note"`
+	AttemptID       uint   `json:"attempt_id"` // Attempt the question was bookmarked from
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// BookmarkListResponse represents a user's bookmarks, newest first
+type BookmarkListResponse struct {
+	Total     int                `json:"total"`
+	Bookmarks []BookmarkResponse `json:"bookmarks"`
+}
@@ -34,10 +34,14 @@ type DashboardEnrollmentDTO struct {
 	PackageSlug    string  `json:"package_slug"`
 	PackageType    string  `json:"package_type"`
 	ExpiryDate     *string `json:"expiry_date"` // ISO string or null
-	Status         string  `json:"status"`      // "active" or "enrolled"
+	Status         string  `json:"status"`      // "active", "trial", "enrolled" or "payment_pending"
 	Progress       float64 `json:"progress"`    // Percentage 0-100
 	TotalExams     int     `json:"total_exams"`
 	CompletedExams int     `json:"completed_exams"`
+	// Renewal prompt ("expires in N days, renew now")
+	DaysUntilExpiry *int     `json:"days_until_expiry"`       // null when the enrollment never expires
+	CanRenew        bool     `json:"can_renew"`               // Expires within the renewal window
+	RenewalPrice    *float64 `json:"renewal_price,omitempty"` // Set when the enrollment can be renewed
 }
 
 // DashboardEnrollmentsResponse represents dashboard enrollments response
@@ -46,3 +50,44 @@ type DashboardEnrollmentsResponse struct {
 	Total       int                      `json:"total"`
 	Active      int                      `json:"active"`
 }
+
+// TopicPerformanceDTO represents a student's performance in one subject or system
+type TopicPerformanceDTO struct {
+	ID               uint    `json:"id"` // 0 groups answers to questions without a known subject or system
+	Name             string  `json:"name"`
+	SubjectID        uint    `json:"subject_id,omitempty"` // Systems only
+	SubjectName      string  `json:"subject_name,omitempty"`
+	Attempts         int     `json:"attempts"` // Exam attempts that included the topic
+	Questions        int     `json:"questions"`
+	Answered         int     `json:"answered"`
+	CorrectAnswers   int     `json:"correct_answers"`
+	AccuracyRate     float64 `json:"accuracy_rate"`      // Percentage of questions answered correctly
+	ScoreRate        float64 `json:"score_rate"`         // Percentage of available points earned, after partial credit and penalties
+	AverageTimeSpent float64 `json:"average_time_spent"` // Seconds per question
+}
+
+// TopicTrendPointDTO represents a student's accuracy in a subject during one week
+type TopicTrendPointDTO struct {
+	WeekStart    string  `json:"week_start"` // Monday, YYYY-MM-DD
+	Questions    int     `json:"questions"`
+	AccuracyRate float64 `json:"accuracy_rate"`
+	ScoreRate    float64 `json:"score_rate"`
+}
+
+// SubjectTrendDTO represents a student's weekly accuracy in a subject
+type SubjectTrendDTO struct {
+	SubjectID      uint                 `json:"subject_id"`
+	SubjectName    string               `json:"subject_name"`
+	Direction      string               `json:"direction"`       // "improving", "declining" or "steady"
+	AccuracyChange float64              `json:"accuracy_change"` // Percentage points from the first to the last week
+	Points         []TopicTrendPointDTO `json:"points"`
+}
+
+// TopicPerformanceResponse represents a student's performance by subject and system
+type TopicPerformanceResponse struct {
+	Subjects     []TopicPerformanceDTO `json:"subjects"`
+	Systems      []TopicPerformanceDTO `json:"systems"`
+	WeakestAreas []TopicPerformanceDTO `json:"weakest_areas"` // Systems with enough answers, lowest accuracy first
+	Trends       []SubjectTrendDTO     `json:"trends"`
+	TrendWeeks   int                   `json:"trend_weeks"`
+}
@@ -8,6 +8,7 @@ import (
 type EnrollmentRequest struct {
 	PackageID  uint    `json:"package_id" binding:"required"`
 	CouponCode *string `json:"coupon_code,omitempty"`
+	Trial      bool    `json:"trial,omitempty"` // Start the package's free trial, paid for later to upgrade
 }
 
 // EnrollmentResponse represents the enrollment response
@@ -18,6 +19,12 @@ type EnrollmentResponse struct {
 	EnrollmentType      string                  `json:"enrollment_type"`
 	EnrolledAt          time.Time               `json:"enrolled_at"`
 	ExpiresAt           *time.Time              `json:"expires_at"`
+	TrialExpiresAt      *time.Time              `json:"trial_expires_at,omitempty"`
+	TrialExtendedAt     *time.Time              `json:"trial_extended_at,omitempty"`
+	CanExtendTrial      bool                    `json:"can_extend_trial"`
+	DaysUntilExpiry     *int                    `json:"days_until_expiry"` // Negative once expired, null when it never expires
+	CanRenew            bool                    `json:"can_renew"`
+	RenewalPrice        *float64                `json:"renewal_price,omitempty"` // Set when the enrollment can be renewed
 	EnrolledPackageType string                  `json:"enrolled_package_type"`
 	EnrolledPrice       float64                 `json:"enrolled_price"`
 	PaymentStatus       string                  `json:"payment_status"`
@@ -30,6 +37,9 @@ type EnrollmentResponse struct {
 	EffectiveStatus     string                  `json:"effective_status"`
 	CanAccessContent    bool                    `json:"can_access_content"`
 	IsActive            bool                    `json:"is_active"`
+	CancelledAt         *time.Time              `json:"cancelled_at,omitempty"`
+	CancelledBy         *uint                   `json:"cancelled_by,omitempty"`
+	CancellationReason  *string                 `json:"cancellation_reason,omitempty"`
 	Package             *PackageBasicResponse   `json:"package,omitempty"`
 	PriceCalculation    *PriceCalculationResult `json:"price_calculation,omitempty"`
 	CreatedAt           time.Time               `json:"created_at"`
@@ -80,5 +90,30 @@ type PriceCalculationResult struct {
 // EnrollmentStatusResponse represents enrollment status check
 type EnrollmentStatusResponse struct {
 	HasActiveEnrollment bool                `json:"has_active_enrollment"`
+	CanRenew            bool                `json:"can_renew"` // Expired or expiring enrollment to renew instead of enrolling again
 	Enrollment          *EnrollmentResponse `json:"enrollment,omitempty"`
 }
+
+// RenewalResponse represents a renewal of an enrollment. Renewals that cost something are paid through
+// checkout with their ID, the period is set once the renewal completes.
+type RenewalResponse struct {
+	ID                uint       `json:"id"`
+	EnrollmentID      uint       `json:"enrollment_id"`
+	Status            string     `json:"status"`
+	Price             float64    `json:"price"`
+	OriginalPrice     float64    `json:"original_price"`
+	PaymentRequired   bool       `json:"payment_required"`
+	PreviousExpiresAt *time.Time `json:"previous_expires_at,omitempty"`
+	PeriodStart       *time.Time `json:"period_start,omitempty"`
+	PeriodEnd         *time.Time `json:"period_end,omitempty"`
+	RenewedAt         *time.Time `json:"renewed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// RenewalHistoryResponse lists the completed renewal periods of an enrollment
+type RenewalHistoryResponse struct {
+	EnrollmentID uint              `json:"enrollment_id"`
+	EnrolledAt   time.Time         `json:"enrolled_at"`
+	ExpiresAt    *time.Time        `json:"expires_at"`
+	Renewals     []RenewalResponse `json:"renewals"`
+}
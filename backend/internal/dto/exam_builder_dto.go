@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/Mahfuz2811/medecole/backend/internal/models"
+
+// ExamBlueprintSection describes how many questions to draw from a subject or system
+type ExamBlueprintSection struct {
+	SubjectID     uint                           `json:"subject_id,omitempty"`
+	SystemID      uint                           `json:"system_id,omitempty"`
+	Count         int                            `json:"count" binding:"required,min=1"`
+	DifficultyMix map[models.DifficultyLevel]int `json:"difficulty_mix,omitempty"` // Overrides the blueprint mix for this section
+}
+
+// ExamBlueprintRequest describes how an exam should be assembled from the question bank
+type ExamBlueprintRequest struct {
+	Sections          []ExamBlueprintSection         `json:"sections" binding:"required,min=1,dive"`
+	DifficultyMix     map[models.DifficultyLevel]int `json:"difficulty_mix,omitempty"` // Percentages per difficulty, must add up to 100
+	QuestionTypes     []models.QuestionType          `json:"question_types,omitempty"` // Empty means all types
+	AvoidOverused     bool                           `json:"avoid_overused"`
+	OverusedThreshold int                            `json:"overused_threshold,omitempty"` // Passed to Question.IsOverused
+	PointsPerQuestion int                            `json:"points_per_question,omitempty"`
+}
+
+// ExamBuildResponse summarizes the generated exam snapshot
+type ExamBuildResponse struct {
+	ExamID              uint                           `json:"exam_id"`
+	TotalQuestions      int                            `json:"total_questions"`
+	TotalMarks          float64                        `json:"total_marks"`
+	QuestionIDs         []uint                         `json:"question_ids"`
+	DifficultyBreakdown map[models.DifficultyLevel]int `json:"difficulty_breakdown"`
+	TypeBreakdown       map[models.QuestionType]int    `json:"type_breakdown"`
+}
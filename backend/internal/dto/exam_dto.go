@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/Mahfuz2811/medecole/backend/internal/models"
+import (
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+)
 
 // ExamResponse represents the API response structure for package exams
 type ExamResponse struct {
@@ -22,13 +26,23 @@ type ExamResponse struct {
 	PassRate           float64              `json:"pass_rate"`
 	ComputedStatus     string               `json:"computed_status"`
 	SortOrder          int                  `json:"sort_order"`
+	IsTrialVisible     bool                 `json:"is_trial_visible"` // Open to students on a trial
 	HasAttempted       bool                 `json:"has_attempted"`
 	UserAttempt        *UserAttemptResponse `json:"user_attempt,omitempty"`
+
+	// Retake information for the requesting user in this package
+	AttemptsUsed       int                   `json:"attempts_used"`
+	RemainingAttempts  *int                  `json:"remaining_attempts"` // nil when attempts are unlimited
+	CanRetake          bool                  `json:"can_retake"`
+	AttemptScorePolicy string                `json:"attempt_score_policy"`
+	EffectiveScore     *float64              `json:"effective_score,omitempty"` // Score that counts under the policy
+	AttemptHistory     []UserAttemptResponse `json:"attempt_history"`
 }
 
 // UserAttemptResponse represents user attempt data for an exam
 type UserAttemptResponse struct {
 	ID             uint     `json:"id"`
+	AttemptNumber  int      `json:"attempt_number,omitempty"`
 	Status         string   `json:"status"`
 	StartedAt      string   `json:"started_at"`
 	CompletedAt    string   `json:"completed_at,omitempty"`
@@ -50,6 +64,7 @@ type PackageInfoResponse struct {
 	ValidityType          models.ValidityType `json:"validity_type"`
 	ValidityDays          *int                `json:"validity_days,omitempty"`
 	ValidityDate          *string             `json:"validity_date,omitempty"`
+	TrialDays             int                 `json:"trial_days"` // 0 when the package offers no trial
 	TotalExams            int                 `json:"total_exams"`
 	EnrollmentCount       int                 `json:"enrollment_count"`
 	ActiveEnrollmentCount int                 `json:"active_enrollment_count"`
@@ -112,6 +127,37 @@ type StartExamResponse struct {
 	ExamMeta  ExamMetaResponse `json:"exam_meta"`
 }
 
+// MaxAttemptsExceededResponse represents the error returned when no attempts are left for an exam
+type MaxAttemptsExceededResponse struct {
+	Error          string `json:"error"`
+	Code           string `json:"code"`
+	ErrorCode      string `json:"error_code"`
+	Message        string `json:"message"`
+	MaxAttempts    int    `json:"max_attempts"`
+	AttemptedCount int    `json:"attempted_count"`
+}
+
+// ExamScheduleErrorResponse represents the error returned when an exam is started outside its schedule
+type ExamScheduleErrorResponse struct {
+	Error     string  `json:"error"`
+	Code      string  `json:"code"`
+	ErrorCode string  `json:"error_code"`
+	Message   string  `json:"message"`
+	OpensAt   *string `json:"opens_at,omitempty"`
+	ClosedAt  *string `json:"closed_at,omitempty"`
+}
+
+// EntitlementErrorResponse represents the error returned when an enrollment does not give access to a package,
+// with 402 when paying restores access and 403 otherwise
+type EntitlementErrorResponse struct {
+	Error        string `json:"error"`
+	Code         string `json:"code"`
+	ErrorCode    string `json:"error_code"` // PAYMENT_REQUIRED, TRIAL_EXPIRED, ENROLLMENT_EXPIRED, ENROLLMENT_INACTIVE or ENROLLMENT_REFUNDED
+	Message      string `json:"message"`
+	PackageID    uint   `json:"package_id"`
+	EnrollmentID uint   `json:"enrollment_id"`
+}
+
 // ExamMetaResponse represents basic exam metadata for session initialization
 type ExamMetaResponse struct {
 	ID              uint    `json:"id"`
@@ -143,6 +189,7 @@ type ExamSessionExamData struct {
 	TotalMarks      float64                      `json:"total_marks"`
 	MaxAttempts     int                          `json:"max_attempts"`
 	Instructions    *string                      `json:"instructions"`
+	Mode            string                       `json:"mode"`      // TIMED, or TUTOR when answers can be checked during the session
 	Questions       []SecureExamQuestionResponse `json:"questions"` // Secure questions without answers
 }
 
@@ -157,7 +204,9 @@ type ExamSessionSessionData struct {
 	CanSubmit        bool                 `json:"can_submit"`
 	CanPause         bool                 `json:"can_pause"`
 	LastActivity     string               `json:"last_activity"`
-	SavedAnswers     []UserAnswerResponse `json:"saved_answers,omitempty"` // Cached answers from Redis
+	SavedAnswers     []UserAnswerResponse `json:"saved_answers,omitempty"`     // Cached answers from Redis
+	CheckedAnswers   []AnswerFeedback     `json:"checked_answers,omitempty"`   // Tutor mode feedback for locked answers
+	FlaggedQuestions []uint               `json:"flagged_questions,omitempty"` // Questions flagged to revisit
 }
 
 // UserAnswerResponse represents a saved user answer from cache
@@ -166,23 +215,54 @@ type UserAnswerResponse struct {
 	SelectedOption string `json:"selected_option"` // Single option or JSON array string for multiple options
 }
 
-// SyncSessionRequest represents the request to sync user answers during an exam session
+// SyncSessionRequest represents the request to sync user answers during an exam session.
+// Only the questions that changed since the last sync need to be sent.
 type SyncSessionRequest struct {
 	Answers []UserAnswerSync `json:"answers"`
 }
 
-// UserAnswerSync represents a single answer to sync (question ID and selected option)
+// UserAnswerSync represents the state of a single question to sync (selected option, review flag and timing)
 type UserAnswerSync struct {
-	QuestionID     uint   `json:"question_id" binding:"required"`
-	SelectedOption string `json:"selected_option" binding:"required"` // e.g., "a", "b", "c", "d", "e"
+	QuestionID       uint       `json:"question_id" binding:"required"`
+	SelectedOption   string     `json:"selected_option"`              // e.g., "a", "b", "c", "d", "e" - empty clears the answer
+	Flagged          bool       `json:"flagged,omitempty"`            // Flagged to revisit before submitting
+	AnsweredAt       *time.Time `json:"answered_at,omitempty"`        // Client time the answer or flag was last changed, server time when missing. The latest write wins.
+	TimeSpentSeconds int        `json:"time_spent_seconds,omitempty"` // Total time the question has been on screen
 }
 
-// SyncSessionResponse represents the response after syncing answers
+// SyncSessionResponse represents the response after syncing answers, with the session's authoritative state
 type SyncSessionResponse struct {
-	Success       bool   `json:"success"`
-	SyncedCount   int    `json:"synced_count"`
-	LastSyncAt    string `json:"last_sync_at"`
-	TimeRemaining int    `json:"time_remaining"` // Current remaining time in seconds
+	Success             bool                 `json:"success"`
+	SyncedCount         int                  `json:"synced_count"`                    // Questions whose state was accepted
+	RejectedQuestionIDs []uint               `json:"rejected_question_ids,omitempty"` // Stale writes, the session already has a newer state
+	Answers             []UserAnswerResponse `json:"answers"`                         // All saved answers after the merge
+	FlaggedQuestions    []uint               `json:"flagged_questions"`
+	LastSyncAt          string               `json:"last_sync_at"`
+	TimeRemaining       int                  `json:"time_remaining"` // Current remaining time in seconds
+}
+
+// CheckAnswerRequest represents a tutor mode request to check one answer
+type CheckAnswerRequest struct {
+	QuestionID     uint   `json:"question_id" binding:"required"`
+	SelectedOption string `json:"selected_option" binding:"required"` // Same format as UserAnswerSync
+}
+
+// AnswerFeedback represents the feedback for a checked answer. The answer is locked once checked.
+type AnswerFeedback struct {
+	QuestionID     uint                   `json:"question_id"`
+	SelectedOption string                 `json:"selected_option"`
+	IsCorrect      bool                   `json:"is_correct"`
+	CorrectAnswer  interface{}            `json:"correct_answer"` // SBA: option key, TRUE_FALSE: map of option key to expected value
+	Options        []AnswerFeedbackOption `json:"options"`
+	Explanation    string                 `json:"explanation,omitempty"`
+	Reference      string                 `json:"reference,omitempty"`
+}
+
+// AnswerFeedbackOption represents an option with its correctness
+type AnswerFeedbackOption struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
 }
 
 // ExamAlreadySubmittedError represents error details when exam is already submitted
@@ -214,14 +294,45 @@ type SubmitExamRequest struct {
 // SubmitExamResponse represents the response after submitting an exam
 type SubmitExamResponse struct {
 	SessionID        string  `json:"session_id"`
-	Score            float64 `json:"score"`
-	Passed           bool    `json:"passed"`
+	Score            float64 `json:"score"`          // Net score after negative marking
+	RawScore         float64 `json:"raw_score"`      // Score before negative marking
+	PenaltyPoints    float64 `json:"penalty_points"` // Points deducted by negative marking
+	Passed           bool    `json:"passed"`         // Always false for practice sessions, which are not graded
 	TotalQuestions   int     `json:"total_questions"`
 	CorrectAnswers   int     `json:"correct_answers"`
 	TimeTakenSeconds int     `json:"time_taken_seconds"`
 	SubmittedAt      string  `json:"submitted_at"`
 }
 
+// LeaderboardEntryResponse represents a ranked user on an exam leaderboard
+type LeaderboardEntryResponse struct {
+	Rank          int64   `json:"rank"`
+	UserID        uint    `json:"user_id"`
+	Name          string  `json:"name"`
+	Score         float64 `json:"score"`
+	TimeSpent     int     `json:"time_spent"` // Seconds, breaks ties on score
+	IsCurrentUser bool    `json:"is_current_user"`
+}
+
+// LeaderboardRankResponse represents the current user's standing on an exam leaderboard
+type LeaderboardRankResponse struct {
+	Rank              int64   `json:"rank"`
+	TotalParticipants int64   `json:"total_participants"`
+	Percentile        float64 `json:"percentile"`
+	Score             float64 `json:"score"`
+	TimeSpent         int     `json:"time_spent"`
+}
+
+// LeaderboardResponse represents the top scorers of an exam within a package
+type LeaderboardResponse struct {
+	ExamID            uint                       `json:"exam_id"`
+	ExamTitle         string                     `json:"exam_title"`
+	PackageSlug       string                     `json:"package_slug"`
+	TotalParticipants int64                      `json:"total_participants"`
+	Entries           []LeaderboardEntryResponse `json:"entries"`
+	CurrentUser       *LeaderboardRankResponse   `json:"current_user"` // Null until the user has a scored attempt
+}
+
 // ExamResultResponse represents the complete exam result data for the results page
 type ExamResultResponse struct {
 	Exam      ExamResultExamData    `json:"exam"`
@@ -0,0 +1,56 @@
+package dto
+
+// ItemDistractorStats represents how often an answer option was selected
+type ItemDistractorStats struct {
+	Option     string  `json:"option"` // Option key for SBA, "key:true"/"key:false" for TRUE_FALSE stems
+	IsCorrect  bool    `json:"is_correct"`
+	Count      int     `json:"count"`
+	Proportion float64 `json:"proportion"`  // Share of all responses that selected the option
+	UpperCount int     `json:"upper_count"` // Selections in the upper 27% group
+	LowerCount int     `json:"lower_count"` // Selections in the lower 27% group
+}
+
+// ItemStats represents the psychometric statistics of a question
+type ItemStats struct {
+	Responses           int                   `json:"responses"`
+	CorrectCount        int                   `json:"correct_count"`
+	SkippedCount        int                   `json:"skipped_count"`
+	DifficultyIndex     *float64              `json:"difficulty_index"`     // p-value: proportion of responses answered correctly
+	DiscriminationIndex *float64              `json:"discrimination_index"` // p(upper 27%) - p(lower 27%)
+	PointBiserial       *float64              `json:"point_biserial"`       // Correlation between answering correctly and the attempt score
+	AverageScore        float64               `json:"average_score"`        // Mean points earned (negative marking included)
+	AverageTimeSeconds  *float64              `json:"average_time_seconds"` // Mean time spent, when tracked
+	Distractors         []ItemDistractorStats `json:"distractors"`
+	Flags               []string              `json:"flags"` // TOO_EASY, TOO_HARD, LOW_DISCRIMINATION, NEGATIVE_DISCRIMINATION
+}
+
+// ExamItemAnalysis represents the analysis of one question within an exam
+type ExamItemAnalysis struct {
+	QuestionID   uint   `json:"question_id"`
+	QuestionText string `json:"question_text"`
+	QuestionType string `json:"question_type"`
+	ItemStats
+}
+
+// ExamItemAnalysisResponse represents the item analysis report of an exam
+type ExamItemAnalysisResponse struct {
+	ExamID   uint               `json:"exam_id"`
+	Title    string             `json:"title"`
+	Attempts int                `json:"attempts"`
+	Items    []ExamItemAnalysis `json:"items"`
+}
+
+// QuestionExamItemAnalysis represents the analysis of a question within one of the exams it appeared in
+type QuestionExamItemAnalysis struct {
+	ExamID uint `json:"exam_id"`
+	ItemStats
+}
+
+// QuestionItemAnalysisResponse represents the item analysis of a question bank question across all exams
+type QuestionItemAnalysisResponse struct {
+	QuestionID   uint                       `json:"question_id"`
+	QuestionText string                     `json:"question_text"`
+	QuestionType string                     `json:"question_type"`
+	Overall      ItemStats                  `json:"overall"`
+	ByExam       []QuestionExamItemAnalysis `json:"by_exam"`
+}
@@ -32,6 +32,7 @@ type PackageResponse struct {
 	ValidityType models.ValidityType  `json:"validity_type"`
 	ValidityDays *int                 `json:"validity_days,omitempty"`
 	ValidityDate *string              `json:"validity_date,omitempty"`
+	TrialDays    int                  `json:"trial_days"` // 0 when the package offers no trial
 	TotalExams   int                  `json:"total_exams"`
 	IsActive     bool                 `json:"is_active"`
 	SortOrder    int                  `json:"sort_order"`
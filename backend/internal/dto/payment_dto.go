@@ -0,0 +1,79 @@
+package dto
+
+// CheckoutRequest starts paying for a pending enrollment, or for its renewal, at a payment gateway
+type CheckoutRequest struct {
+	EnrollmentID uint   `json:"enrollment_id" binding:"required"`
+	RenewalID    *uint  `json:"renewal_id,omitempty"`
+	Provider     string `json:"provider" binding:"required"`
+}
+
+// CheckoutResponse tells the client where to send the student to pay
+type CheckoutResponse struct {
+	TransactionID string  `json:"transaction_id"`
+	Provider      string  `json:"provider"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	RedirectURL   string  `json:"redirect_url"`
+}
+
+// PaymentStatusResponse represents a payment transaction and the payment status of its enrollment
+type PaymentStatusResponse struct {
+	TransactionID string  `json:"transaction_id"`
+	EnrollmentID  uint    `json:"enrollment_id"`
+	RenewalID     *uint   `json:"renewal_id,omitempty"`
+	Provider      string  `json:"provider"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Status        string  `json:"status"`
+	FailureReason *string `json:"failure_reason,omitempty"`
+	PaidAt        *string `json:"paid_at,omitempty"`
+	Duplicate     bool    `json:"duplicate,omitempty"` // Paid for something already paid, the payment will be refunded
+}
+
+// PaymentProvidersResponse lists the gateways a student can pay with
+type PaymentProvidersResponse struct {
+	Providers []string `json:"providers"`
+	Currency  string   `json:"currency"`
+}
+
+// RefundRequest refunds a paid enrollment in full or in part. Without a transaction ID the latest paid
+// transaction is refunded, without an amount everything still refundable of it.
+type RefundRequest struct {
+	TransactionID    string   `json:"transaction_id,omitempty"`
+	Amount           *float64 `json:"amount,omitempty"`
+	Reason           string   `json:"reason" binding:"required,max=500"`
+	CancelEnrollment bool     `json:"cancel_enrollment"` // Also cancel after a partial refund, full refunds always cancel, once the gateway has processed the refund. For a renewal's payment only its period is taken off.
+}
+
+// RefundReconciliationResponse counts what became of the pending refunds checked with their gateways
+type RefundReconciliationResponse struct {
+	Checked   int `json:"checked"`
+	Processed int `json:"processed"` // Returned to the student, enrollments were cancelled or renewals taken off where due
+	Failed    int `json:"failed"`    // Fell through at the gateway, the amount can be refunded again
+	Pending   int `json:"pending"`   // Still processing, or the gateway could not be asked
+}
+
+// CancelEnrollmentRequest cancels an enrollment without refunding it
+type CancelEnrollmentRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// RefundResponse represents a refund made at the gateway and the enrollment it belongs to
+type RefundResponse struct {
+	ID                  uint                `json:"id"`
+	TransactionID       string              `json:"transaction_id"`
+	EnrollmentID        uint                `json:"enrollment_id"`
+	Provider            string              `json:"provider"`
+	RefundReference     *string             `json:"refund_reference,omitempty"`
+	Amount              float64             `json:"amount"`
+	Currency            string              `json:"currency"`
+	Status              string              `json:"status"`
+	Reason              string              `json:"reason"`
+	RefundedBy          uint                `json:"refunded_by"`
+	RemainingRefundable float64             `json:"remaining_refundable"`
+	EnrollmentCancelled bool                `json:"enrollment_cancelled"`
+	RenewalID           *uint               `json:"renewal_id,omitempty"` // Set when the payment was for a renewal
+	RenewalRefunded     bool                `json:"renewal_refunded"`     // Renewal period was taken off the enrollment
+	Enrollment          *EnrollmentResponse `json:"enrollment,omitempty"`
+	CreatedAt           string              `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "github.com/Mahfuz2811/medecole/backend/internal/models"
+
+// StartPracticeRequest represents a request to start an on-demand practice session.
+// Without subjects, systems or bookmarks the session targets the student's weakest systems.
+type StartPracticeRequest struct {
+	PackageSlug     string                         `json:"package_slug" binding:"required"` // Enrollment that grants access to practice
+	SubjectIDs      []uint                         `json:"subject_ids,omitempty"`
+	SystemIDs       []uint                         `json:"system_ids,omitempty"`
+	QuestionCount   int                            `json:"question_count,omitempty"`   // Defaults to 20
+	QuestionTypes   []models.QuestionType          `json:"question_types,omitempty"`   // Empty means all types
+	Bookmarked      bool                           `json:"bookmarked,omitempty"`       // Practice bookmarked questions only, narrowed by subjects and systems
+	DifficultyMix   map[models.DifficultyLevel]int `json:"difficulty_mix,omitempty"`   // Percentages per difficulty, must add up to 100
+	DurationMinutes int                            `json:"duration_minutes,omitempty"` // Defaults to 90 seconds per question
+	Mode            models.ExamMode                `json:"mode,omitempty"`             // TUTOR to check each answer during the session, defaults to TIMED
+	DeviceInfo      map[string]string              `json:"device_info,omitempty"`
+}
+
+// StartPracticeResponse represents a started practice session. The session is taken, synced and
+// submitted through the regular exam session endpoints.
+type StartPracticeResponse struct {
+	StartExamResponse
+	QuestionCount      int    `json:"question_count"`      // May be below the requested count when few questions are left
+	SystemIDs          []uint `json:"system_ids"`          // Systems targeted, empty when practising by subject or across the bank
+	TargetsWeakAreas   bool   `json:"targets_weak_areas"`  // Systems were chosen from the student's weakest areas
+	AvailableQuestions int    `json:"available_questions"` // Unseen or previously missed questions matching the request, or bookmarked ones
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+)
+
+// QuestionOptionInput represents a single answer option in a question payload
+type QuestionOptionInput struct {
+	Text      string   `json:"text"`
+	IsCorrect *bool    `json:"is_correct"`
+	Weight    *float64 `json:"weight,omitempty"` // Partial credit weight (0-1), used by exams scoring with option weights
+}
+
+// QuestionRequest represents the payload for creating or replacing a question
+type QuestionRequest struct {
+	SystemID        uint                           `json:"system_id" binding:"required"`
+	QuestionText    string                         `json:"question_text" binding:"required"`
+	QuestionType    models.QuestionType            `json:"question_type" binding:"required,oneof=SBA TRUE_FALSE"`
+	DifficultyLevel models.DifficultyLevel         `json:"difficulty_level" binding:"omitempty,oneof=EASY MEDIUM HARD"`
+	Options         map[string]QuestionOptionInput `json:"options" binding:"required"`
+	Explanation     *string                        `json:"explanation,omitempty"`
+	Reference       *string                        `json:"reference,omitempty"`
+	Tags            []string                       `json:"tags,omitempty"`
+	IsActive        *bool                          `json:"is_active,omitempty"`
+}
+
+// QuestionListRequest represents the query parameters for listing questions
+type QuestionListRequest struct {
+	SubjectID       uint   `form:"subject_id"`
+	SystemID        uint   `form:"system_id"`
+	QuestionType    string `form:"question_type" binding:"omitempty,oneof=SBA TRUE_FALSE"`
+	DifficultyLevel string `form:"difficulty_level" binding:"omitempty,oneof=EASY MEDIUM HARD"`
+	Tags            string `form:"tags"` // Comma separated, all tags must match
+	UsageLevel      string `form:"usage_level" binding:"omitempty,oneof=UNUSED LOW_USAGE MEDIUM_USAGE HIGH_USAGE OVERUSED"`
+	IsActive        *bool  `form:"is_active"`
+	Search          string `form:"search"`
+	Page            int    `form:"page"`
+	Limit           int    `form:"limit"`
+}
+
+// QuestionOptionResponse represents an answer option in admin responses (includes correctness)
+type QuestionOptionResponse struct {
+	Text      string   `json:"text"`
+	IsCorrect bool     `json:"is_correct"`
+	Weight    *float64 `json:"weight,omitempty"`
+}
+
+// QuestionSubjectResponse represents the subject a question belongs to
+type QuestionSubjectResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// QuestionSystemResponse represents the system a question belongs to
+type QuestionSystemResponse struct {
+	ID      uint                     `json:"id"`
+	Name    string                   `json:"name"`
+	Slug    string                   `json:"slug"`
+	Subject *QuestionSubjectResponse `json:"subject,omitempty"`
+}
+
+// QuestionResponse represents a question bank entry
+type QuestionResponse struct {
+	ID              uint                              `json:"id"`
+	SystemID        uint                              `json:"system_id"`
+	System          *QuestionSystemResponse           `json:"system,omitempty"`
+	QuestionText    string                            `json:"question_text"`
+	QuestionType    models.QuestionType               `json:"question_type"`
+	DifficultyLevel models.DifficultyLevel            `json:"difficulty_level"`
+	Options         map[string]QuestionOptionResponse `json:"options"`
+	Explanation     *string                           `json:"explanation"`
+	Reference       *string                           `json:"reference"`
+	Tags            []string                          `json:"tags"`
+	UsageCount      int                               `json:"usage_count"`
+	UsageLevel      string                            `json:"usage_level"`
+	IsActive        bool                              `json:"is_active"`
+	CreatedBy       *uint                             `json:"created_by"`
+	CreatedAt       time.Time                         `json:"created_at"`
+	UpdatedAt       time.Time                         `json:"updated_at"`
+}
+
+// QuestionListResponse represents a page of questions
+type QuestionListResponse struct {
+	Questions []QuestionResponse `json:"questions"`
+	Total     int64              `json:"total"`
+	Page      int                `json:"page"`
+	Limit     int                `json:"limit"`
+}
+
+// TaxonomySystemResponse represents a system in the subject/system taxonomy
+type TaxonomySystemResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+	IsActive      bool   `json:"is_active"`
+	QuestionCount int64  `json:"question_count"`
+}
+
+// TaxonomySubjectResponse represents a subject with its systems
+type TaxonomySubjectResponse struct {
+	ID            uint                     `json:"id"`
+	Name          string                   `json:"name"`
+	Slug          string                   `json:"slug"`
+	IsActive      bool                     `json:"is_active"`
+	QuestionCount int64                    `json:"question_count"`
+	Systems       []TaxonomySystemResponse `json:"systems"`
+}
+
+// TaxonomyResponse represents the full subject/system taxonomy
+type TaxonomyResponse struct {
+	Subjects []TaxonomySubjectResponse `json:"subjects"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/Mahfuz2811/medecole/backend/internal/models"
+
+// ReviewQueueItem represents a question in a student's spaced-repetition queue
+type ReviewQueueItem struct {
+	QuestionID     uint    `json:"question_id"`
+	QuestionText   string  `json:"question_text"`
+	QuestionType   string  `json:"question_type"`
+	SystemID       uint    `json:"system_id"`
+	SubjectID      uint    `json:"subject_id"`
+	DueAt          string  `json:"due_at"`
+	IntervalDays   int     `json:"interval_days"`
+	EaseFactor     float64 `json:"ease_factor"`
+	Repetitions    int     `json:"repetitions"` // Consecutive correct reviews
+	Lapses         int     `json:"lapses"`
+	LastReviewedAt string  `json:"last_reviewed_at"`
+}
+
+// ReviewQueueResponse represents the questions due for review today, most overdue first
+type ReviewQueueResponse struct {
+	DueCount int64             `json:"due_count"` // All questions due today, Items may be limited
+	DueBy    string            `json:"due_by"`
+	Items    []ReviewQueueItem `json:"items"`
+}
+
+// StartReviewRequest represents a request to start a review session on the questions due today
+type StartReviewRequest struct {
+	PackageSlug   string            `json:"package_slug" binding:"required"` // Enrollment that grants access to practice
+	QuestionCount int               `json:"question_count,omitempty"`        // Defaults to 20
+	Mode          models.ExamMode   `json:"mode,omitempty"`                  // Defaults to TUTOR so each answer can be checked
+	DeviceInfo    map[string]string `json:"device_info,omitempty"`
+}
+
+// StartReviewResponse represents a started review session. Answers update the review schedule once
+// the session is submitted and processed.
+type StartReviewResponse struct {
+	StartExamResponse
+	QuestionCount int   `json:"question_count"`
+	DueCount      int64 `json:"due_count"` // Questions due today, including those left for another session
+}
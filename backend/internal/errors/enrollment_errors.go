@@ -169,6 +169,85 @@ func NewEnrollmentFetchError(enrollmentID uint, cause error) *EnrollmentFetchErr
 	}
 }
 
+type TrialNotAvailableError struct {
+	*EnrollmentError
+	PackageID uint
+}
+
+func NewTrialNotAvailableError(packageID uint) *TrialNotAvailableError {
+	return &TrialNotAvailableError{
+		EnrollmentError: &EnrollmentError{
+			Code:    "TRIAL_NOT_AVAILABLE",
+			Message: "This package does not offer a free trial",
+		},
+		PackageID: packageID,
+	}
+}
+
+type TrialAlreadyUsedError struct {
+	*EnrollmentError
+	UserID    uint
+	PackageID uint
+}
+
+func NewTrialAlreadyUsedError(userID, packageID uint) *TrialAlreadyUsedError {
+	return &TrialAlreadyUsedError{
+		EnrollmentError: &EnrollmentError{
+			Code:    "TRIAL_ALREADY_USED",
+			Message: "You have already used the free trial of this package",
+		},
+		UserID:    userID,
+		PackageID: packageID,
+	}
+}
+
+type TrialNotExtendableError struct {
+	*EnrollmentError
+	EnrollmentID uint
+}
+
+func NewTrialNotExtendableError(enrollmentID uint, message string) *TrialNotExtendableError {
+	return &TrialNotExtendableError{
+		EnrollmentError: &EnrollmentError{
+			Code:    "TRIAL_NOT_EXTENDABLE",
+			Message: message,
+		},
+		EnrollmentID: enrollmentID,
+	}
+}
+
+type RenewalRequiredError struct {
+	*EnrollmentError
+	EnrollmentID uint
+	PackageID    uint
+}
+
+func NewRenewalRequiredError(enrollmentID, packageID uint) *RenewalRequiredError {
+	return &RenewalRequiredError{
+		EnrollmentError: &EnrollmentError{
+			Code:    "RENEWAL_REQUIRED",
+			Message: "Your enrollment in this package has expired, renew it instead of enrolling again",
+		},
+		EnrollmentID: enrollmentID,
+		PackageID:    packageID,
+	}
+}
+
+type RenewalNotAvailableError struct {
+	*EnrollmentError
+	EnrollmentID uint
+}
+
+func NewRenewalNotAvailableError(enrollmentID uint, message string) *RenewalNotAvailableError {
+	return &RenewalNotAvailableError{
+		EnrollmentError: &EnrollmentError{
+			Code:    "RENEWAL_NOT_AVAILABLE",
+			Message: message,
+		},
+		EnrollmentID: enrollmentID,
+	}
+}
+
 // Helper functions to check error types
 func IsPackageNotActiveError(err error) bool {
 	_, ok := err.(*PackageNotActiveError)
@@ -214,3 +293,28 @@ func IsEnrollmentFetchError(err error) bool {
 	_, ok := err.(*EnrollmentFetchError)
 	return ok
 }
+
+func IsTrialNotAvailableError(err error) bool {
+	_, ok := err.(*TrialNotAvailableError)
+	return ok
+}
+
+func IsTrialAlreadyUsedError(err error) bool {
+	_, ok := err.(*TrialAlreadyUsedError)
+	return ok
+}
+
+func IsTrialNotExtendableError(err error) bool {
+	_, ok := err.(*TrialNotExtendableError)
+	return ok
+}
+
+func IsRenewalRequiredError(err error) bool {
+	_, ok := err.(*RenewalRequiredError)
+	return ok
+}
+
+func IsRenewalNotAvailableError(err error) bool {
+	_, ok := err.(*RenewalNotAvailableError)
+	return ok
+}
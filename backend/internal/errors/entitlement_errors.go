@@ -0,0 +1,55 @@
+package errors
+
+import "fmt"
+
+// PaymentRequiredError is returned when package content is used by an enrollment that has to be paid,
+// renewed or bought after its trial first
+type PaymentRequiredError struct {
+	PackageID    uint
+	EnrollmentID uint
+	Reason       string // PAYMENT_REQUIRED, TRIAL_EXPIRED or ENROLLMENT_EXPIRED
+}
+
+func (e *PaymentRequiredError) Error() string {
+	return fmt.Sprintf("enrollment %d in package %d needs payment: %s", e.EnrollmentID, e.PackageID, e.Reason)
+}
+
+func NewPaymentRequiredError(packageID, enrollmentID uint, reason string) *PaymentRequiredError {
+	return &PaymentRequiredError{
+		PackageID:    packageID,
+		EnrollmentID: enrollmentID,
+		Reason:       reason,
+	}
+}
+
+// IsPaymentRequiredError checks if the error is a payment required error
+func IsPaymentRequiredError(err error) bool {
+	_, ok := err.(*PaymentRequiredError)
+	return ok
+}
+
+// EntitlementDeniedError is returned when package content is used by an enrollment that no longer
+// gives access and cannot be restored by paying
+type EntitlementDeniedError struct {
+	PackageID    uint
+	EnrollmentID uint
+	Reason       string // ENROLLMENT_INACTIVE, ENROLLMENT_REFUNDED or ENROLLMENT_EXPIRED
+}
+
+func (e *EntitlementDeniedError) Error() string {
+	return fmt.Sprintf("enrollment %d in package %d does not give access: %s", e.EnrollmentID, e.PackageID, e.Reason)
+}
+
+func NewEntitlementDeniedError(packageID, enrollmentID uint, reason string) *EntitlementDeniedError {
+	return &EntitlementDeniedError{
+		PackageID:    packageID,
+		EnrollmentID: enrollmentID,
+		Reason:       reason,
+	}
+}
+
+// IsEntitlementDeniedError checks if the error is an entitlement denied error
+func IsEntitlementDeniedError(err error) bool {
+	_, ok := err.(*EntitlementDeniedError)
+	return ok
+}
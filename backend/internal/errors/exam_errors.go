@@ -0,0 +1,259 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxAttemptsExceededError is returned when a user has used every attempt an exam allows in a package
+type MaxAttemptsExceededError struct {
+	ExamID         uint
+	MaxAttempts    int
+	AttemptedCount int
+}
+
+func (e *MaxAttemptsExceededError) Error() string {
+	return fmt.Sprintf("exam %d allows %d attempts and %d have been used", e.ExamID, e.MaxAttempts, e.AttemptedCount)
+}
+
+func NewMaxAttemptsExceededError(examID uint, maxAttempts, attemptedCount int) *MaxAttemptsExceededError {
+	return &MaxAttemptsExceededError{
+		ExamID:         examID,
+		MaxAttempts:    maxAttempts,
+		AttemptedCount: attemptedCount,
+	}
+}
+
+// IsMaxAttemptsExceededError checks if the error is a max attempts exceeded error
+func IsMaxAttemptsExceededError(err error) bool {
+	_, ok := err.(*MaxAttemptsExceededError)
+	return ok
+}
+
+// LeaderboardUnavailableError is returned when an exam type has no leaderboard
+type LeaderboardUnavailableError struct {
+	ExamID   uint
+	ExamType string
+}
+
+func (e *LeaderboardUnavailableError) Error() string {
+	return fmt.Sprintf("exam %d is a %s exam and has no leaderboard", e.ExamID, e.ExamType)
+}
+
+func NewLeaderboardUnavailableError(examID uint, examType string) *LeaderboardUnavailableError {
+	return &LeaderboardUnavailableError{
+		ExamID:   examID,
+		ExamType: examType,
+	}
+}
+
+// IsLeaderboardUnavailableError checks if the error is a leaderboard unavailable error
+func IsLeaderboardUnavailableError(err error) bool {
+	_, ok := err.(*LeaderboardUnavailableError)
+	return ok
+}
+
+// PracticeValidationError is returned when a practice session request is malformed
+type PracticeValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *PracticeValidationError) Error() string {
+	return fmt.Sprintf("invalid practice request %s: %s", e.Field, e.Reason)
+}
+
+func NewPracticeValidationError(field, reason string) *PracticeValidationError {
+	return &PracticeValidationError{
+		Field:  field,
+		Reason: reason,
+	}
+}
+
+// IsPracticeValidationError checks if the error is a practice validation error
+func IsPracticeValidationError(err error) bool {
+	_, ok := err.(*PracticeValidationError)
+	return ok
+}
+
+// NoPracticeQuestionsError is returned when no unseen or previously missed questions match a practice request
+type NoPracticeQuestionsError struct {
+	UserID uint
+}
+
+func (e *NoPracticeQuestionsError) Error() string {
+	return fmt.Sprintf("no unseen or previously missed questions left to practice for user %d", e.UserID)
+}
+
+func NewNoPracticeQuestionsError(userID uint) *NoPracticeQuestionsError {
+	return &NoPracticeQuestionsError{
+		UserID: userID,
+	}
+}
+
+// IsNoPracticeQuestionsError checks if the error is a no practice questions error
+func IsNoPracticeQuestionsError(err error) bool {
+	_, ok := err.(*NoPracticeQuestionsError)
+	return ok
+}
+
+// AnswerCheckingUnavailableError is returned when answers are checked on an exam that is not in tutor mode
+type AnswerCheckingUnavailableError struct {
+	ExamID   uint
+	ExamType string
+}
+
+func (e *AnswerCheckingUnavailableError) Error() string {
+	return fmt.Sprintf("exam %d is a %s exam that is not in tutor mode", e.ExamID, e.ExamType)
+}
+
+func NewAnswerCheckingUnavailableError(examID uint, examType string) *AnswerCheckingUnavailableError {
+	return &AnswerCheckingUnavailableError{
+		ExamID:   examID,
+		ExamType: examType,
+	}
+}
+
+// IsAnswerCheckingUnavailableError checks if the error is an answer checking unavailable error
+func IsAnswerCheckingUnavailableError(err error) bool {
+	_, ok := err.(*AnswerCheckingUnavailableError)
+	return ok
+}
+
+// QuestionNotInExamError is returned when a session request names a question the exam does not contain
+type QuestionNotInExamError struct {
+	ExamID     uint
+	QuestionID uint
+}
+
+func (e *QuestionNotInExamError) Error() string {
+	return fmt.Sprintf("question %d is not part of exam %d", e.QuestionID, e.ExamID)
+}
+
+func NewQuestionNotInExamError(examID, questionID uint) *QuestionNotInExamError {
+	return &QuestionNotInExamError{
+		ExamID:     examID,
+		QuestionID: questionID,
+	}
+}
+
+// IsQuestionNotInExamError checks if the error is a question not in exam error
+func IsQuestionNotInExamError(err error) bool {
+	_, ok := err.(*QuestionNotInExamError)
+	return ok
+}
+
+// AnswerLockedError is returned when a checked answer is changed
+type AnswerLockedError struct {
+	QuestionID     uint
+	LockedAnswer   string
+	RejectedAnswer string
+}
+
+func (e *AnswerLockedError) Error() string {
+	return fmt.Sprintf("answer to question %d was checked and is locked to %q", e.QuestionID, e.LockedAnswer)
+}
+
+func NewAnswerLockedError(questionID uint, lockedAnswer, rejectedAnswer string) *AnswerLockedError {
+	return &AnswerLockedError{
+		QuestionID:     questionID,
+		LockedAnswer:   lockedAnswer,
+		RejectedAnswer: rejectedAnswer,
+	}
+}
+
+// IsAnswerLockedError checks if the error is an answer locked error
+func IsAnswerLockedError(err error) bool {
+	_, ok := err.(*AnswerLockedError)
+	return ok
+}
+
+// NoDueReviewsError is returned when a review session is started with no questions due
+type NoDueReviewsError struct {
+	UserID uint
+}
+
+func (e *NoDueReviewsError) Error() string {
+	return fmt.Sprintf("no questions are due for review for user %d", e.UserID)
+}
+
+func NewNoDueReviewsError(userID uint) *NoDueReviewsError {
+	return &NoDueReviewsError{
+		UserID: userID,
+	}
+}
+
+// IsNoDueReviewsError checks if the error is a no due reviews error
+func IsNoDueReviewsError(err error) bool {
+	_, ok := err.(*NoDueReviewsError)
+	return ok
+}
+
+// ExamNotAvailableError is returned when an exam is started while its status does not allow attempts
+type ExamNotAvailableError struct {
+	ExamID uint
+	Status string
+}
+
+func (e *ExamNotAvailableError) Error() string {
+	return fmt.Sprintf("exam %d is %s and cannot be started", e.ExamID, e.Status)
+}
+
+func NewExamNotAvailableError(examID uint, status string) *ExamNotAvailableError {
+	return &ExamNotAvailableError{
+		ExamID: examID,
+		Status: status,
+	}
+}
+
+// IsExamNotAvailableError checks if the error is an exam not available error
+func IsExamNotAvailableError(err error) bool {
+	_, ok := err.(*ExamNotAvailableError)
+	return ok
+}
+
+// ExamNotOpenError is returned when a scheduled exam is started before it opens
+type ExamNotOpenError struct {
+	ExamID  uint
+	OpensAt time.Time
+}
+
+func (e *ExamNotOpenError) Error() string {
+	return fmt.Sprintf("exam %d opens at %s", e.ExamID, e.OpensAt.UTC().Format(time.RFC3339))
+}
+
+func NewExamNotOpenError(examID uint, opensAt time.Time) *ExamNotOpenError {
+	return &ExamNotOpenError{
+		ExamID:  examID,
+		OpensAt: opensAt,
+	}
+}
+
+// IsExamNotOpenError checks if the error is an exam not open error
+func IsExamNotOpenError(err error) bool {
+	_, ok := err.(*ExamNotOpenError)
+	return ok
+}
+
+// ExamClosedError is returned when a scheduled exam is started after it closes
+type ExamClosedError struct {
+	ExamID   uint
+	ClosedAt time.Time
+}
+
+func (e *ExamClosedError) Error() string {
+	return fmt.Sprintf("exam %d closed at %s", e.ExamID, e.ClosedAt.UTC().Format(time.RFC3339))
+}
+
+func NewExamClosedError(examID uint, closedAt time.Time) *ExamClosedError {
+	return &ExamClosedError{
+		ExamID:   examID,
+		ClosedAt: closedAt,
+	}
+}
+
+// IsExamClosedError checks if the error is an exam closed error
+func IsExamClosedError(err error) bool {
+	_, ok := err.(*ExamClosedError)
+	return ok
+}
@@ -0,0 +1,122 @@
+package errors
+
+import "fmt"
+
+// PaymentProviderUnavailableError is returned when a checkout names a provider that is not configured
+type PaymentProviderUnavailableError struct {
+	Provider string
+}
+
+func (e *PaymentProviderUnavailableError) Error() string {
+	return fmt.Sprintf("payment provider %q is not available", e.Provider)
+}
+
+func NewPaymentProviderUnavailableError(provider string) *PaymentProviderUnavailableError {
+	return &PaymentProviderUnavailableError{
+		Provider: provider,
+	}
+}
+
+// IsPaymentProviderUnavailableError checks if the error is a payment provider unavailable error
+func IsPaymentProviderUnavailableError(err error) bool {
+	_, ok := err.(*PaymentProviderUnavailableError)
+	return ok
+}
+
+// PaymentNotRequiredError is returned when a checkout is started for an enrollment that has nothing to pay
+type PaymentNotRequiredError struct {
+	EnrollmentID  uint
+	PaymentStatus string
+}
+
+func (e *PaymentNotRequiredError) Error() string {
+	return fmt.Sprintf("enrollment %d does not await payment, its payment status is %s", e.EnrollmentID, e.PaymentStatus)
+}
+
+func NewPaymentNotRequiredError(enrollmentID uint, paymentStatus string) *PaymentNotRequiredError {
+	return &PaymentNotRequiredError{
+		EnrollmentID:  enrollmentID,
+		PaymentStatus: paymentStatus,
+	}
+}
+
+// IsPaymentNotRequiredError checks if the error is a payment not required error
+func IsPaymentNotRequiredError(err error) bool {
+	_, ok := err.(*PaymentNotRequiredError)
+	return ok
+}
+
+// PaymentGatewayError is returned when a payment gateway call fails
+type PaymentGatewayError struct {
+	Provider  string
+	Operation string
+	Cause     error
+}
+
+func (e *PaymentGatewayError) Error() string {
+	return fmt.Sprintf("%s %s failed: %v", e.Provider, e.Operation, e.Cause)
+}
+
+func (e *PaymentGatewayError) Unwrap() error {
+	return e.Cause
+}
+
+func NewPaymentGatewayError(provider, operation string, cause error) *PaymentGatewayError {
+	return &PaymentGatewayError{
+		Provider:  provider,
+		Operation: operation,
+		Cause:     cause,
+	}
+}
+
+// IsPaymentGatewayError checks if the error is a payment gateway error
+func IsPaymentGatewayError(err error) bool {
+	_, ok := err.(*PaymentGatewayError)
+	return ok
+}
+
+// RefundNotAllowedError is returned when a refund is requested for a payment that cannot be refunded
+type RefundNotAllowedError struct {
+	EnrollmentID uint
+	Reason       string
+}
+
+func (e *RefundNotAllowedError) Error() string {
+	return fmt.Sprintf("enrollment %d cannot be refunded: %s", e.EnrollmentID, e.Reason)
+}
+
+func NewRefundNotAllowedError(enrollmentID uint, reason string) *RefundNotAllowedError {
+	return &RefundNotAllowedError{
+		EnrollmentID: enrollmentID,
+		Reason:       reason,
+	}
+}
+
+// IsRefundNotAllowedError checks if the error is a refund not allowed error
+func IsRefundNotAllowedError(err error) bool {
+	_, ok := err.(*RefundNotAllowedError)
+	return ok
+}
+
+// InvalidRefundAmountError is returned when a refund amount is not positive or exceeds what is left to refund
+type InvalidRefundAmountError struct {
+	Amount     float64
+	Refundable float64
+}
+
+func (e *InvalidRefundAmountError) Error() string {
+	return fmt.Sprintf("refund amount %.2f must be more than 0 and at most %.2f", e.Amount, e.Refundable)
+}
+
+func NewInvalidRefundAmountError(amount, refundable float64) *InvalidRefundAmountError {
+	return &InvalidRefundAmountError{
+		Amount:     amount,
+		Refundable: refundable,
+	}
+}
+
+// IsInvalidRefundAmountError checks if the error is an invalid refund amount error
+func IsInvalidRefundAmountError(err error) bool {
+	_, ok := err.(*InvalidRefundAmountError)
+	return ok
+}
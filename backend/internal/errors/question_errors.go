@@ -0,0 +1,97 @@
+package errors
+
+import "fmt"
+
+// QuestionValidationError is returned when a question payload is rejected by the question bank
+type QuestionValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *QuestionValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func NewQuestionValidationError(field, reason string) *QuestionValidationError {
+	return &QuestionValidationError{
+		Field:  field,
+		Reason: reason,
+	}
+}
+
+// IsQuestionValidationError checks if the error is a question validation error
+func IsQuestionValidationError(err error) bool {
+	_, ok := err.(*QuestionValidationError)
+	return ok
+}
+
+// BlueprintValidationError is returned when an exam blueprint is malformed
+type BlueprintValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *BlueprintValidationError) Error() string {
+	return fmt.Sprintf("invalid blueprint %s: %s", e.Field, e.Reason)
+}
+
+func NewBlueprintValidationError(field, reason string) *BlueprintValidationError {
+	return &BlueprintValidationError{
+		Field:  field,
+		Reason: reason,
+	}
+}
+
+// InsufficientQuestionsError is returned when the question bank cannot fill a blueprint section
+type InsufficientQuestionsError struct {
+	Section   int
+	Requested int
+	Available int
+}
+
+func (e *InsufficientQuestionsError) Error() string {
+	return fmt.Sprintf("blueprint section %d requests %d questions but only %d are available", e.Section, e.Requested, e.Available)
+}
+
+func NewInsufficientQuestionsError(section, requested, available int) *InsufficientQuestionsError {
+	return &InsufficientQuestionsError{
+		Section:   section,
+		Requested: requested,
+		Available: available,
+	}
+}
+
+// ExamLockedError is returned when an exam can no longer be regenerated because users attempted it
+type ExamLockedError struct {
+	ExamID       uint
+	AttemptCount int
+}
+
+func (e *ExamLockedError) Error() string {
+	return fmt.Sprintf("exam %d already has %d attempts and cannot be regenerated", e.ExamID, e.AttemptCount)
+}
+
+func NewExamLockedError(examID uint, attemptCount int) *ExamLockedError {
+	return &ExamLockedError{
+		ExamID:       examID,
+		AttemptCount: attemptCount,
+	}
+}
+
+// IsBlueprintValidationError checks if the error is a blueprint validation error
+func IsBlueprintValidationError(err error) bool {
+	_, ok := err.(*BlueprintValidationError)
+	return ok
+}
+
+// IsInsufficientQuestionsError checks if the error is an insufficient questions error
+func IsInsufficientQuestionsError(err error) bool {
+	_, ok := err.(*InsufficientQuestionsError)
+	return ok
+}
+
+// IsExamLockedError checks if the error is an exam locked error
+func IsExamLockedError(err error) bool {
+	_, ok := err.(*ExamLockedError)
+	return ok
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// BookmarkHandler handles question bookmark and note HTTP requests
+type BookmarkHandler struct {
+	bookmarkService service.BookmarkService
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(bookmarkService service.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// GetBookmarks handles GET /api/bookmarks - List bookmarked questions, optionally by subject_id or system_id
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	userID, ok := bookmarkUserID(c)
+	if !ok {
+		return
+	}
+
+	subjectID, ok := parseBookmarkFilterID(c, "subject_id")
+	if !ok {
+		return
+	}
+	systemID, ok := parseBookmarkFilterID(c, "system_id")
+	if !ok {
+		return
+	}
+	filter := repository.BookmarkFilter{SubjectID: subjectID, SystemID: systemID}
+
+	bookmarks, err := h.bookmarkService.GetBookmarks(userID, filter)
+	if err != nil {
+		logger.WithOperation("GetBookmarks").WithField("user_id", userID).WithError(err).Error("Failed to fetch bookmarks")
+		response.ErrorInternalServer(c, "Failed to fetch bookmarks")
+		return
+	}
+
+	response.SuccessResponse(c, bookmarks)
+}
+
+// BookmarkQuestion handles POST /api/bookmarks - Bookmark a question from the results of a finished session
+func (h *BookmarkHandler) BookmarkQuestion(c *gin.Context) {
+	userID, ok := bookmarkUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookmarkQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	bookmark, err := h.bookmarkService.BookmarkQuestion(userID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrAttemptNotFound) {
+			response.ErrorNotFound(c, "Finished session not found")
+			return
+		}
+		if apperrors.IsQuestionNotInExamError(err) {
+			response.ErrorBadRequest(c, "Question is not part of this session")
+			return
+		}
+		logger.WithOperation("BookmarkQuestion").WithFields(logrus.Fields{
+			"user_id":     userID,
+			"session_id":  req.SessionID,
+			"question_id": req.QuestionID,
+		}).WithError(err).Error("Failed to bookmark question")
+		response.ErrorInternalServer(c, "Failed to bookmark question")
+		return
+	}
+
+	response.SuccessResponse(c, bookmark)
+}
+
+// UpdateNote handles PUT /api/bookmarks/:questionId - Change the note of a bookmarked question
+func (h *BookmarkHandler) UpdateNote(c *gin.Context) {
+	userID, ok := bookmarkUserID(c)
+	if !ok {
+		return
+	}
+	questionID, ok := parseBookmarkQuestionID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateBookmarkNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	bookmark, err := h.bookmarkService.UpdateNote(userID, questionID, req.Note)
+	if err != nil {
+		if errors.Is(err, repository.ErrBookmarkNotFound) {
+			response.ErrorNotFound(c, "Bookmark not found")
+			return
+		}
+		logger.WithOperation("UpdateBookmarkNote").WithFields(logrus.Fields{
+			"user_id":     userID,
+			"question_id": questionID,
+		}).WithError(err).Error("Failed to update bookmark note")
+		response.ErrorInternalServer(c, "Failed to update note")
+		return
+	}
+
+	response.SuccessResponse(c, bookmark)
+}
+
+// RemoveBookmark handles DELETE /api/bookmarks/:questionId - Remove a bookmark and its note
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	userID, ok := bookmarkUserID(c)
+	if !ok {
+		return
+	}
+	questionID, ok := parseBookmarkQuestionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bookmarkService.RemoveBookmark(userID, questionID); err != nil {
+		if errors.Is(err, repository.ErrBookmarkNotFound) {
+			response.ErrorNotFound(c, "Bookmark not found")
+			return
+		}
+		logger.WithOperation("RemoveBookmark").WithFields(logrus.Fields{
+			"user_id":     userID,
+			"question_id": questionID,
+		}).WithError(err).Error("Failed to remove bookmark")
+		response.ErrorInternalServer(c, "Failed to remove bookmark")
+		return
+	}
+
+	response.SuccessResponseWithMessage(c, nil, "Bookmark removed")
+}
+
+// bookmarkUserID reads the user ID set by the auth middleware, writing an unauthorized response if missing
+func bookmarkUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return 0, false
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return 0, false
+	}
+	return userIDUint, true
+}
+
+// parseBookmarkQuestionID reads the :questionId path parameter, writing a bad request response if invalid
+func parseBookmarkQuestionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("questionId"), 10, 32)
+	if err != nil || id == 0 {
+		response.ErrorBadRequest(c, "Invalid question ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseBookmarkFilterID reads an optional ID query parameter, writing a bad request response if invalid
+func parseBookmarkFilterID(c *gin.Context, param string) (uint, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		response.ErrorBadRequest(c, param+" must be a positive number")
+		return 0, false
+	}
+	return uint(id), true
+}
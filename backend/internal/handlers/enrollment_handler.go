@@ -8,6 +8,7 @@ import (
 	"github.com/Mahfuz2811/medecole/backend/internal/errors"
 	"github.com/Mahfuz2811/medecole/backend/internal/logger"
 	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
 	"github.com/Mahfuz2811/medecole/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -105,6 +106,24 @@ func (h *EnrollmentHandler) EnrollInPackage(c *gin.Context) {
 				Message: "You are already enrolled in this package",
 			})
 			return
+		case errors.IsRenewalRequiredError(err):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: err.(*errors.RenewalRequiredError).Message,
+			})
+			return
+		case errors.IsTrialNotAvailableError(err):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "This package does not offer a free trial",
+			})
+			return
+		case errors.IsTrialAlreadyUsedError(err):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "You have already used the free trial of this package",
+			})
+			return
 		case errors.IsCouponValidationError(err):
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Bad Request",
@@ -291,3 +310,158 @@ func (h *EnrollmentHandler) ValidateCoupon(c *gin.Context) {
 	log.WithField("coupon_valid", response.Valid).Info("Coupon validation completed")
 	c.JSON(http.StatusOK, response)
 }
+
+func (h *EnrollmentHandler) ExtendTrial(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"handler":   "EnrollmentHandler",
+		"operation": "ExtendTrial",
+	})
+
+	uid, enrollmentID, ok := enrollmentRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"user_id":       uid,
+		"enrollment_id": enrollmentID,
+	})
+
+	response, err := h.enrollmentService.ExtendTrial(ctx, uid, enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Trial extension failed")
+
+		switch {
+		case err == repository.ErrEnrollmentNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Enrollment not found",
+			})
+		case errors.IsTrialNotExtendableError(err):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: err.(*errors.TrialNotExtendableError).Message,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "Failed to extend trial",
+			})
+		}
+		return
+	}
+
+	log.Info("Trial extended")
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *EnrollmentHandler) RenewEnrollment(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"handler":   "EnrollmentHandler",
+		"operation": "RenewEnrollment",
+	})
+
+	uid, enrollmentID, ok := enrollmentRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"user_id":       uid,
+		"enrollment_id": enrollmentID,
+	})
+
+	response, err := h.enrollmentService.RenewEnrollment(ctx, uid, enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Renewal failed")
+
+		switch {
+		case err == repository.ErrEnrollmentNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Enrollment not found",
+			})
+		case errors.IsRenewalNotAvailableError(err):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: err.(*errors.RenewalNotAvailableError).Message,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "Failed to renew enrollment",
+			})
+		}
+		return
+	}
+
+	log.WithField("renewal_id", response.ID).Info("Renewal processed")
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *EnrollmentHandler) GetRenewalHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"handler":   "EnrollmentHandler",
+		"operation": "GetRenewalHistory",
+	})
+
+	uid, enrollmentID, ok := enrollmentRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.enrollmentService.GetRenewalHistory(ctx, uid, enrollmentID)
+	if err != nil {
+		log.WithError(err).WithField("enrollment_id", enrollmentID).Error("Failed to get renewal history")
+		if err == repository.ErrEnrollmentNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Enrollment not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to get renewal history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// enrollmentRequestIDs reads the authenticated user ID and the enrollment ID path parameter, writing an
+// error response if either is missing or invalid
+func enrollmentRequestIDs(c *gin.Context) (userID, enrollmentID uint, ok bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User not authenticated",
+		})
+		return 0, 0, false
+	}
+
+	userID, ok = value.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid user ID",
+		})
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid enrollment ID",
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(id), true
+}
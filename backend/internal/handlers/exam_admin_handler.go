@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ExamAdminHandler handles exam authoring HTTP requests
+type ExamAdminHandler struct {
+	examBuilderService service.ExamBuilderService
+}
+
+// NewExamAdminHandler creates a new exam admin handler
+func NewExamAdminHandler(examBuilderService service.ExamBuilderService) *ExamAdminHandler {
+	return &ExamAdminHandler{
+		examBuilderService: examBuilderService,
+	}
+}
+
+// BuildExam handles POST /api/admin/exams/:id/build - Generate exam questions from a blueprint
+func (h *ExamAdminHandler) BuildExam(c *gin.Context) {
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ExamBlueprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid blueprint", err.Error())
+		return
+	}
+
+	result, err := h.examBuilderService.BuildExam(examID, req)
+	if err != nil {
+		var blueprintErr *apperrors.BlueprintValidationError
+		var insufficientErr *apperrors.InsufficientQuestionsError
+		var lockedErr *apperrors.ExamLockedError
+		switch {
+		case errors.As(err, &blueprintErr):
+			response.ErrorValidation(c, "Invalid blueprint", blueprintErr.Error())
+		case errors.As(err, &insufficientErr):
+			response.ErrorValidation(c, "Not enough questions in the question bank", insufficientErr.Error())
+		case errors.As(err, &lockedErr):
+			response.ErrorBadRequest(c, "Exam has already been attempted and cannot be regenerated")
+		case errors.Is(err, repository.ErrExamNotFound):
+			response.ErrorNotFound(c, "Exam not found")
+		default:
+			logger.WithOperation("BuildExam").WithFields(logrus.Fields{
+				"exam_id": examID,
+			}).WithError(err).Error("Failed to build exam")
+			response.ErrorInternalServer(c, "Failed to build exam")
+		}
+		return
+	}
+
+	response.SuccessResponse(c, result)
+}
+
+// parseExamID reads the :id path parameter, writing a bad request response if invalid
+func parseExamID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.ErrorBadRequest(c, "Invalid exam ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
 	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
 	"github.com/Mahfuz2811/medecole/backend/internal/repository"
 	"github.com/Mahfuz2811/medecole/backend/internal/response"
 	"github.com/Mahfuz2811/medecole/backend/internal/service"
@@ -108,10 +113,58 @@ func (h *ExamHandler) StartExam(c *gin.Context) {
 			response.ErrorBadRequest(c, "You must be enrolled in this package to take the exam")
 			return
 		}
+		if writeEntitlementError(c, err) {
+			return
+		}
 		if errors.Is(err, repository.ErrExamAlreadySubmitted) {
 			response.ErrorBadRequest(c, "You have already completed this exam. Multiple attempts are not allowed.")
 			return
 		}
+		var maxAttemptsErr *apperrors.MaxAttemptsExceededError
+		if errors.As(err, &maxAttemptsErr) {
+			c.JSON(http.StatusForbidden, dto.MaxAttemptsExceededResponse{
+				Error:          "Maximum attempts reached",
+				Code:           "FORBIDDEN",
+				ErrorCode:      "MAX_ATTEMPTS_EXCEEDED",
+				Message:        "You have used all attempts allowed for this exam.",
+				MaxAttempts:    maxAttemptsErr.MaxAttempts,
+				AttemptedCount: maxAttemptsErr.AttemptedCount,
+			})
+			return
+		}
+		if apperrors.IsExamNotAvailableError(err) {
+			c.JSON(http.StatusForbidden, dto.ExamScheduleErrorResponse{
+				Error:     "Exam not available",
+				Code:      "FORBIDDEN",
+				ErrorCode: "EXAM_NOT_AVAILABLE",
+				Message:   "This exam is not open for attempts.",
+			})
+			return
+		}
+		var notOpenErr *apperrors.ExamNotOpenError
+		if errors.As(err, &notOpenErr) {
+			opensAt := notOpenErr.OpensAt.UTC().Format("2006-01-02T15:04:05Z")
+			c.JSON(http.StatusForbidden, dto.ExamScheduleErrorResponse{
+				Error:     "Exam not open yet",
+				Code:      "FORBIDDEN",
+				ErrorCode: "EXAM_NOT_OPEN",
+				Message:   "This exam has not opened yet.",
+				OpensAt:   &opensAt,
+			})
+			return
+		}
+		var closedErr *apperrors.ExamClosedError
+		if errors.As(err, &closedErr) {
+			closedAt := closedErr.ClosedAt.UTC().Format("2006-01-02T15:04:05Z")
+			c.JSON(http.StatusForbidden, dto.ExamScheduleErrorResponse{
+				Error:     "Exam closed",
+				Code:      "FORBIDDEN",
+				ErrorCode: "EXAM_CLOSED",
+				Message:   "This exam has closed.",
+				ClosedAt:  &closedAt,
+			})
+			return
+		}
 		response.ErrorInternalServer(c, "Failed to start exam session")
 		return
 	}
@@ -152,6 +205,13 @@ func (h *ExamHandler) GetSession(c *gin.Context) {
 			response.ErrorNotFound(c, "Session not found")
 			return
 		}
+		if errors.Is(err, repository.ErrNotEnrolledInPackage) {
+			response.ErrorBadRequest(c, "You must be enrolled in this package to continue the exam")
+			return
+		}
+		if writeEntitlementError(c, err) {
+			return
+		}
 		response.ErrorInternalServer(c, "Failed to fetch session data")
 		return
 	}
@@ -203,6 +263,56 @@ func (h *ExamHandler) SyncSession(c *gin.Context) {
 	response.SuccessResponse(c, syncResponse)
 }
 
+// CheckAnswer handles POST /api/exams/session/:sessionId/check - Check one answer in a tutor mode session
+func (h *ExamHandler) CheckAnswer(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	// Parse request body
+	var req dto.CheckAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Invalid request format")
+		return
+	}
+
+	// Check the answer and lock it
+	feedback, err := h.examService.CheckAnswer(sessionID, userIDUint, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrAttemptNotFound) {
+			response.ErrorNotFound(c, "Session not found")
+			return
+		}
+		if apperrors.IsAnswerCheckingUnavailableError(err) {
+			response.ErrorBadRequest(c, "Answers can only be checked in tutor mode review and practice sessions")
+			return
+		}
+		if apperrors.IsQuestionNotInExamError(err) {
+			response.ErrorNotFound(c, "Question not found in this exam")
+			return
+		}
+		if apperrors.IsAnswerLockedError(err) {
+			response.ErrorBadRequest(c, "This answer has already been checked and cannot be changed")
+			return
+		}
+		response.ErrorInternalServer(c, "Failed to check answer")
+		return
+	}
+
+	response.SuccessResponse(c, feedback)
+}
+
 // SubmitExam handles POST /api/exams/submit - Submit exam and finalize session
 func (h *ExamHandler) SubmitExam(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
@@ -232,6 +342,10 @@ func (h *ExamHandler) SubmitExam(c *gin.Context) {
 			response.ErrorNotFound(c, "Session not found")
 			return
 		}
+		if errors.Is(err, repository.ErrExamAlreadySubmitted) {
+			response.ErrorBadRequest(c, "This exam session has already been submitted")
+			return
+		}
 		response.ErrorInternalServer(c, "Failed to submit exam")
 		return
 	}
@@ -273,6 +387,13 @@ func (h *ExamHandler) GetExamResults(c *gin.Context) {
 			response.ErrorNotFound(c, "Exam session not found")
 			return
 		}
+		if errors.Is(err, repository.ErrNotEnrolledInPackage) {
+			response.ErrorBadRequest(c, "You must be enrolled in this package to view the results")
+			return
+		}
+		if writeEntitlementError(c, err) {
+			return
+		}
 		log.WithError(err).Error("Failed to retrieve exam results from service")
 		response.ErrorInternalServer(c, "Failed to fetch exam results")
 		return
@@ -280,3 +401,104 @@ func (h *ExamHandler) GetExamResults(c *gin.Context) {
 
 	response.SuccessResponseWithMessage(c, results, "Exam results retrieved successfully")
 }
+
+// GetLeaderboard handles GET /api/exams/:slug/leaderboard - Get the top scorers of a mock or final exam within a package
+func (h *ExamHandler) GetLeaderboard(c *gin.Context) {
+	examSlug := c.Param("slug")
+
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	packageSlug := c.Query("package_slug")
+	if packageSlug == "" {
+		response.ErrorBadRequest(c, "package_slug is required")
+		return
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 {
+			response.ErrorBadRequest(c, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	leaderboard, err := h.examService.GetLeaderboard(packageSlug, examSlug, userIDUint, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrExamNotFound) {
+			response.ErrorNotFound(c, "Exam not found")
+			return
+		}
+		if errors.Is(err, repository.ErrPackageNotFound) {
+			response.ErrorNotFound(c, "Package not found")
+			return
+		}
+		if errors.Is(err, repository.ErrNotEnrolledInPackage) {
+			response.ErrorBadRequest(c, "You must be enrolled in this package to view the leaderboard")
+			return
+		}
+		if writeEntitlementError(c, err) {
+			return
+		}
+		if apperrors.IsLeaderboardUnavailableError(err) {
+			response.ErrorBadRequest(c, "Leaderboards are only available for mock and final exams")
+			return
+		}
+		response.ErrorInternalServer(c, "Failed to fetch leaderboard")
+		return
+	}
+
+	response.SuccessResponse(c, leaderboard)
+}
+
+// entitlementMessages explains each reason an enrollment does not give access
+var entitlementMessages = map[string]string{
+	string(models.AccessDeniedPaymentRequired): "Complete the payment for this package to continue.",
+	string(models.AccessDeniedTrialExpired):    "Your free trial has ended. Buy the package to continue.",
+	string(models.AccessDeniedTrialExamLocked): "This exam is not part of the free trial. Buy the package to unlock it.",
+	string(models.AccessDeniedExpired):         "Your enrollment in this package has expired. Renew it to continue.",
+	string(models.AccessDeniedInactive):        "Your enrollment in this package is no longer active.",
+	string(models.AccessDeniedRefunded):        "Your payment for this package was refunded.",
+}
+
+// writeEntitlementError writes a 402 or 403 response for errors of the package entitlement check,
+// reporting whether err was one of them
+func writeEntitlementError(c *gin.Context, err error) bool {
+	var paymentErr *apperrors.PaymentRequiredError
+	if errors.As(err, &paymentErr) {
+		c.JSON(http.StatusPaymentRequired, dto.EntitlementErrorResponse{
+			Error:        "Payment required",
+			Code:         "PAYMENT_REQUIRED",
+			ErrorCode:    paymentErr.Reason,
+			Message:      entitlementMessages[paymentErr.Reason],
+			PackageID:    paymentErr.PackageID,
+			EnrollmentID: paymentErr.EnrollmentID,
+		})
+		return true
+	}
+	var deniedErr *apperrors.EntitlementDeniedError
+	if errors.As(err, &deniedErr) {
+		c.JSON(http.StatusForbidden, dto.EntitlementErrorResponse{
+			Error:        "Access denied",
+			Code:         "FORBIDDEN",
+			ErrorCode:    deniedErr.Reason,
+			Message:      entitlementMessages[deniedErr.Reason],
+			PackageID:    deniedErr.PackageID,
+			EnrollmentID: deniedErr.EnrollmentID,
+		})
+		return true
+	}
+	return false
+}
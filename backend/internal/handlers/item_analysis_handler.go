@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ItemAnalysisHandler handles item analysis report HTTP requests
+type ItemAnalysisHandler struct {
+	itemAnalysisService service.ItemAnalysisService
+}
+
+// NewItemAnalysisHandler creates a new item analysis handler
+func NewItemAnalysisHandler(itemAnalysisService service.ItemAnalysisService) *ItemAnalysisHandler {
+	return &ItemAnalysisHandler{
+		itemAnalysisService: itemAnalysisService,
+	}
+}
+
+// GetExamItemAnalysis handles GET /api/admin/analytics/exams/:id/items - Item analysis of every question of an exam
+func (h *ItemAnalysisHandler) GetExamItemAnalysis(c *gin.Context) {
+	examID, ok := parseExamID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.itemAnalysisService.GetExamItemAnalysis(examID)
+	if err != nil {
+		if errors.Is(err, repository.ErrExamNotFound) {
+			response.ErrorNotFound(c, "Exam not found")
+			return
+		}
+		logger.WithOperation("GetExamItemAnalysis").WithFields(logrus.Fields{
+			"exam_id": examID,
+		}).WithError(err).Error("Failed to build exam item analysis")
+		response.ErrorInternalServer(c, "Failed to fetch item analysis")
+		return
+	}
+
+	response.SuccessResponse(c, report)
+}
+
+// GetQuestionItemAnalysis handles GET /api/admin/analytics/questions/:id/items - Item analysis of a question across all exams
+func (h *ItemAnalysisHandler) GetQuestionItemAnalysis(c *gin.Context) {
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.itemAnalysisService.GetQuestionItemAnalysis(questionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			response.ErrorNotFound(c, "Question not found")
+			return
+		}
+		logger.WithOperation("GetQuestionItemAnalysis").WithFields(logrus.Fields{
+			"question_id": questionID,
+		}).WithError(err).Error("Failed to build question item analysis")
+		response.ErrorInternalServer(c, "Failed to fetch item analysis")
+		return
+	}
+
+	response.SuccessResponse(c, report)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// PaymentHandler handles checkout and payment gateway HTTP requests
+type PaymentHandler struct {
+	paymentService service.PaymentService
+	frontendURL    string
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(paymentService service.PaymentService, frontendURL string) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		frontendURL:    frontendURL,
+	}
+}
+
+// GetProviders handles GET /api/payments/providers - List the gateways a student can pay with
+func (h *PaymentHandler) GetProviders(c *gin.Context) {
+	response.SuccessResponse(c, h.paymentService.GetProviders())
+}
+
+// InitiateCheckout handles POST /api/payments/checkout - Start paying for a pending enrollment
+func (h *PaymentHandler) InitiateCheckout(c *gin.Context) {
+	userID, ok := paymentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	checkout, err := h.paymentService.InitiateCheckout(c.Request.Context(), userID, req)
+	if err != nil {
+		logger.WithOperation("InitiateCheckout").WithFields(logrus.Fields{
+			"user_id":       userID,
+			"enrollment_id": req.EnrollmentID,
+			"provider":      req.Provider,
+		}).WithError(err).Error("Failed to start checkout")
+		writePaymentError(c, err, "Failed to start checkout")
+		return
+	}
+
+	response.SuccessResponse(c, checkout)
+}
+
+// GetPayment handles GET /api/payments/:transactionId - Get a payment's status, checking with the gateway while unpaid
+func (h *PaymentHandler) GetPayment(c *gin.Context) {
+	userID, ok := paymentUserID(c)
+	if !ok {
+		return
+	}
+	transactionID := c.Param("transactionId")
+
+	status, err := h.paymentService.VerifyPayment(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		logger.WithOperation("GetPayment").WithFields(logrus.Fields{
+			"user_id":        userID,
+			"transaction_id": transactionID,
+		}).WithError(err).Error("Failed to verify payment")
+		writePaymentError(c, err, "Failed to verify payment")
+		return
+	}
+
+	response.SuccessResponse(c, status)
+}
+
+// Callback handles GET/POST /api/payments/callback/:provider - The gateway returns the student here,
+// who is sent on to the frontend's payment result page
+func (h *PaymentHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+
+	status, err := h.paymentService.HandleCallback(c.Request.Context(), provider, callbackValues(c))
+	result := url.Values{}
+	if err != nil {
+		logger.WithOperation("PaymentCallback").WithField("provider", provider).WithError(err).Error("Failed to process payment callback")
+		result.Set("status", "ERROR")
+	} else {
+		result.Set("transaction_id", status.TransactionID)
+		result.Set("status", status.Status)
+	}
+
+	c.Redirect(http.StatusSeeOther, h.frontendURL+"/payment/result?"+result.Encode())
+}
+
+// Notify handles POST /api/payments/ipn/:provider - Server-to-server payment notifications
+func (h *PaymentHandler) Notify(c *gin.Context) {
+	provider := c.Param("provider")
+
+	status, err := h.paymentService.HandleCallback(c.Request.Context(), provider, callbackValues(c))
+	if err != nil {
+		logger.WithOperation("PaymentNotify").WithField("provider", provider).WithError(err).Error("Failed to process payment notification")
+		writePaymentError(c, err, "Failed to process notification")
+		return
+	}
+
+	response.SuccessResponse(c, status)
+}
+
+// callbackValues returns the query and form parameters a gateway sent
+func callbackValues(c *gin.Context) url.Values {
+	if err := c.Request.ParseForm(); err != nil {
+		return c.Request.URL.Query()
+	}
+	return c.Request.Form
+}
+
+// writePaymentError maps payment errors to responses
+func writePaymentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrEnrollmentNotFound):
+		response.ErrorNotFound(c, "Enrollment not found")
+	case errors.Is(err, repository.ErrRenewalNotFound):
+		response.ErrorNotFound(c, "Renewal not found")
+	case errors.Is(err, repository.ErrPaymentTransactionNotFound):
+		response.ErrorNotFound(c, "Payment not found")
+	case apperrors.IsPaymentProviderUnavailableError(err):
+		response.ErrorBadRequest(c, "Payment method is not available")
+	case apperrors.IsPaymentNotRequiredError(err):
+		response.ErrorBadRequest(c, "This enrollment or renewal has nothing to pay")
+	case errors.Is(err, repository.ErrEnrollmentNotActive):
+		response.ErrorBadRequest(c, "Enrollment is already cancelled")
+	case apperrors.IsRefundNotAllowedError(err):
+		response.ErrorBadRequest(c, err.Error())
+	case apperrors.IsInvalidRefundAmountError(err):
+		response.ErrorValidation(c, "Invalid refund amount", err.Error())
+	case apperrors.IsPaymentGatewayError(err):
+		c.JSON(http.StatusBadGateway, response.ErrorResponse{
+			Error: "The payment gateway could not be reached, please try again",
+			Code:  "PAYMENT_GATEWAY_ERROR",
+		})
+	default:
+		response.ErrorInternalServer(c, fallback)
+	}
+}
+
+// paymentUserID reads the user ID set by the auth middleware, writing an unauthorized response if missing
+func paymentUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return 0, false
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return 0, false
+	}
+	return userIDUint, true
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// PracticeHandler handles practice and review session HTTP requests
+type PracticeHandler struct {
+	practiceService service.PracticeService
+	reviewService   service.ReviewService
+}
+
+// NewPracticeHandler creates a new practice handler
+func NewPracticeHandler(practiceService service.PracticeService, reviewService service.ReviewService) *PracticeHandler {
+	return &PracticeHandler{
+		practiceService: practiceService,
+		reviewService:   reviewService,
+	}
+}
+
+// StartPractice handles POST /api/practice/start - Start a practice session from the question bank
+func (h *PracticeHandler) StartPractice(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	// Parse request body
+	var req dto.StartPracticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Invalid request format")
+		return
+	}
+
+	// Start practice session
+	practiceResponse, err := h.practiceService.StartPractice(userIDUint, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrPackageNotFound) {
+			response.ErrorNotFound(c, "Package not found")
+			return
+		}
+		if errors.Is(err, repository.ErrNotEnrolledInPackage) {
+			response.ErrorBadRequest(c, "You must be enrolled in this package to practice")
+			return
+		}
+		if writeEntitlementError(c, err) {
+			return
+		}
+		if apperrors.IsPracticeValidationError(err) {
+			response.ErrorValidation(c, "Invalid practice request", err.Error())
+			return
+		}
+		if apperrors.IsNoPracticeQuestionsError(err) {
+			response.ErrorBadRequest(c, "No unseen or previously missed questions left for this selection")
+			return
+		}
+		logger.WithOperation("StartPractice").WithFields(logrus.Fields{
+			"user_id":      userIDUint,
+			"package_slug": req.PackageSlug,
+		}).WithError(err).Error("Failed to start practice session")
+		response.ErrorInternalServer(c, "Failed to start practice session")
+		return
+	}
+
+	response.SuccessResponse(c, practiceResponse)
+}
+
+// GetReviewQueue handles GET /api/review/due - Get the questions due for review today
+func (h *PracticeHandler) GetReviewQueue(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 {
+			response.ErrorBadRequest(c, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	queue, err := h.reviewService.GetDueQueue(userIDUint, limit)
+	if err != nil {
+		logger.WithOperation("GetReviewQueue").WithField("user_id", userIDUint).WithError(err).Error("Failed to fetch review queue")
+		response.ErrorInternalServer(c, "Failed to fetch review queue")
+		return
+	}
+
+	response.SuccessResponse(c, queue)
+}
+
+// StartReview handles POST /api/review/start - Start a review session on the questions due today
+func (h *PracticeHandler) StartReview(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	// Parse request body
+	var req dto.StartReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorBadRequest(c, "Invalid request format")
+		return
+	}
+
+	// Start review session
+	reviewResponse, err := h.practiceService.StartReview(userIDUint, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrPackageNotFound) {
+			response.ErrorNotFound(c, "Package not found")
+			return
+		}
+		if errors.Is(err, repository.ErrNotEnrolledInPackage) {
+			response.ErrorBadRequest(c, "You must be enrolled in this package to practice")
+			return
+		}
+		if writeEntitlementError(c, err) {
+			return
+		}
+		if apperrors.IsPracticeValidationError(err) {
+			response.ErrorValidation(c, "Invalid review request", err.Error())
+			return
+		}
+		if apperrors.IsNoDueReviewsError(err) {
+			response.ErrorBadRequest(c, "No questions are due for review today")
+			return
+		}
+		logger.WithOperation("StartReview").WithFields(logrus.Fields{
+			"user_id":      userIDUint,
+			"package_slug": req.PackageSlug,
+		}).WithError(err).Error("Failed to start review session")
+		response.ErrorInternalServer(c, "Failed to start review session")
+		return
+	}
+
+	response.SuccessResponse(c, reviewResponse)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// QuestionHandler handles admin question bank HTTP requests
+type QuestionHandler struct {
+	questionService service.QuestionService
+}
+
+// NewQuestionHandler creates a new question handler
+func NewQuestionHandler(questionService service.QuestionService) *QuestionHandler {
+	return &QuestionHandler{
+		questionService: questionService,
+	}
+}
+
+// ListQuestions handles GET /api/admin/questions - List questions with filters
+func (h *QuestionHandler) ListQuestions(c *gin.Context) {
+	var req dto.QuestionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorValidation(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	questions, err := h.questionService.ListQuestions(req)
+	if err != nil {
+		logger.WithOperation("ListQuestions").WithError(err).Error("Failed to list questions")
+		response.ErrorInternalServer(c, "Failed to fetch questions")
+		return
+	}
+
+	response.SuccessResponse(c, questions)
+}
+
+// GetQuestion handles GET /api/admin/questions/:id - Get a single question
+func (h *QuestionHandler) GetQuestion(c *gin.Context) {
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	question, err := h.questionService.GetQuestion(questionID)
+	if err != nil {
+		h.handleError(c, "GetQuestion", err)
+		return
+	}
+
+	response.SuccessResponse(c, question)
+}
+
+// CreateQuestion handles POST /api/admin/questions - Add a question to the bank
+func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.ErrorUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		response.ErrorUnauthorized(c, "Invalid user ID")
+		return
+	}
+
+	var req dto.QuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	question, err := h.questionService.CreateQuestion(req, userIDUint)
+	if err != nil {
+		h.handleError(c, "CreateQuestion", err)
+		return
+	}
+
+	response.CreatedResponse(c, question, "Question created successfully")
+}
+
+// UpdateQuestion handles PUT /api/admin/questions/:id - Replace a question's content
+func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.QuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	question, err := h.questionService.UpdateQuestion(questionID, req)
+	if err != nil {
+		h.handleError(c, "UpdateQuestion", err)
+		return
+	}
+
+	response.SuccessResponse(c, question)
+}
+
+// DeleteQuestion handles DELETE /api/admin/questions/:id - Soft delete a question
+func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
+	questionID, ok := parseQuestionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.questionService.DeleteQuestion(questionID); err != nil {
+		h.handleError(c, "DeleteQuestion", err)
+		return
+	}
+
+	response.SuccessResponseWithMessage(c, gin.H{"id": questionID}, "Question deleted successfully")
+}
+
+// GetTaxonomy handles GET /api/admin/taxonomy - List subjects with their systems
+func (h *QuestionHandler) GetTaxonomy(c *gin.Context) {
+	taxonomy, err := h.questionService.GetTaxonomy()
+	if err != nil {
+		logger.WithOperation("GetTaxonomy").WithError(err).Error("Failed to fetch taxonomy")
+		response.ErrorInternalServer(c, "Failed to fetch taxonomy")
+		return
+	}
+
+	response.SuccessResponse(c, taxonomy)
+}
+
+// handleError maps question bank errors to HTTP responses
+func (h *QuestionHandler) handleError(c *gin.Context, operation string, err error) {
+	var validationErr *apperrors.QuestionValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		response.ErrorValidation(c, "Invalid question", validationErr.Error())
+	case errors.Is(err, repository.ErrQuestionNotFound):
+		response.ErrorNotFound(c, "Question not found")
+	case errors.Is(err, repository.ErrSystemNotFound):
+		response.ErrorValidation(c, "Invalid question", "system not found")
+	default:
+		logger.WithOperation(operation).WithFields(logrus.Fields{
+			"path": c.Request.URL.Path,
+		}).WithError(err).Error("Question bank operation failed")
+		response.ErrorInternalServer(c, "Failed to process question")
+	}
+}
+
+// parseQuestionID reads the :id path parameter, writing a bad request response if invalid
+func parseQuestionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.ErrorBadRequest(c, "Invalid question ID")
+		return 0, false
+	}
+	return uint(id), true
+}
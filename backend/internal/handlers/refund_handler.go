@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/response"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RefundHandler handles admin refund and enrollment cancellation HTTP requests
+type RefundHandler struct {
+	refundService service.RefundService
+}
+
+// NewRefundHandler creates a new refund handler
+func NewRefundHandler(refundService service.RefundService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+	}
+}
+
+// RefundEnrollment handles POST /api/admin/enrollments/:id/refund - Refund an enrollment's payment in full or in part
+func (h *RefundHandler) RefundEnrollment(c *gin.Context) {
+	adminID, ok := paymentUserID(c)
+	if !ok {
+		return
+	}
+	enrollmentID, ok := parseEnrollmentID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	refund, err := h.refundService.RefundEnrollment(c.Request.Context(), adminID, enrollmentID, req)
+	if err != nil {
+		logger.WithOperation("RefundEnrollment").WithFields(logrus.Fields{
+			"admin_id":       adminID,
+			"enrollment_id":  enrollmentID,
+			"transaction_id": req.TransactionID,
+		}).WithError(err).Error("Failed to refund enrollment")
+		writePaymentError(c, err, "Failed to refund enrollment")
+		return
+	}
+
+	response.SuccessResponse(c, refund)
+}
+
+// ReconcileRefunds handles POST /api/admin/refunds/reconcile - Check pending refunds with their gateways
+func (h *RefundHandler) ReconcileRefunds(c *gin.Context) {
+	adminID, ok := paymentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.refundService.ReconcileRefunds(c.Request.Context())
+	if err != nil {
+		logger.WithOperation("ReconcileRefunds").WithField("admin_id", adminID).
+			WithError(err).Error("Failed to reconcile refunds")
+		writePaymentError(c, err, "Failed to reconcile refunds")
+		return
+	}
+
+	response.SuccessResponse(c, result)
+}
+
+// CancelEnrollment handles POST /api/admin/enrollments/:id/cancel - Cancel an enrollment without a refund
+func (h *RefundHandler) CancelEnrollment(c *gin.Context) {
+	adminID, ok := paymentUserID(c)
+	if !ok {
+		return
+	}
+	enrollmentID, ok := parseEnrollmentID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CancelEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorValidation(c, "Invalid request format", err.Error())
+		return
+	}
+
+	enrollment, err := h.refundService.CancelEnrollment(c.Request.Context(), adminID, enrollmentID, req)
+	if err != nil {
+		logger.WithOperation("CancelEnrollment").WithFields(logrus.Fields{
+			"admin_id":      adminID,
+			"enrollment_id": enrollmentID,
+		}).WithError(err).Error("Failed to cancel enrollment")
+		writePaymentError(c, err, "Failed to cancel enrollment")
+		return
+	}
+
+	response.SuccessResponse(c, enrollment)
+}
+
+// parseEnrollmentID reads the :id path parameter, writing a bad request response if invalid
+func parseEnrollmentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.ErrorBadRequest(c, "Invalid enrollment ID")
+		return 0, false
+	}
+	return uint(id), true
+}
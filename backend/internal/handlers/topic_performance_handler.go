@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TopicPerformanceHandler handles a student's performance by subject and system
+type TopicPerformanceHandler struct {
+	topicPerformanceService service.TopicPerformanceService
+}
+
+// NewTopicPerformanceHandler creates a new topic performance handler
+func NewTopicPerformanceHandler(topicPerformanceService service.TopicPerformanceService) *TopicPerformanceHandler {
+	return &TopicPerformanceHandler{
+		topicPerformanceService: topicPerformanceService,
+	}
+}
+
+// GetTopicPerformance handles GET /api/dashboard/performance?weeks= - Accuracy by subject and system with weak areas and trends
+func (h *TopicPerformanceHandler) GetTopicPerformance(c *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User not authenticated",
+		})
+		return
+	}
+
+	uid, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	weeks := 0
+	if weeksParam := c.Query("weeks"); weeksParam != "" {
+		parsed, err := strconv.Atoi(weeksParam)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "weeks must be a positive number",
+			})
+			return
+		}
+		weeks = parsed
+	}
+
+	response, err := h.topicPerformanceService.GetTopicPerformance(uid, weeks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve performance data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Performance data retrieved successfully",
+		"data":    response,
+	})
+}
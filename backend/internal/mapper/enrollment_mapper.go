@@ -37,6 +37,28 @@ func (m *EnrollmentMapper) ToEnrollmentResponse(enrollment *models.UserPackageEn
 		UpdatedAt:           enrollment.UpdatedAt,
 	}
 
+	// Add trial information for trials and trials that were upgraded
+	if enrollment.TrialExpiresAt != nil {
+		response.TrialExpiresAt = enrollment.TrialExpiresAt
+		response.TrialExtendedAt = enrollment.TrialExtendedAt
+		response.CanExtendTrial = enrollment.CanExtendTrial() && enrollment.Package.TrialExtensionDays > 0
+	}
+
+	// Add renewal information, the renewal price needs the package
+	response.DaysUntilExpiry = enrollment.DaysUntilExpiry()
+	response.CanRenew = enrollment.CanRenew()
+	if response.CanRenew && enrollment.Package.ID != 0 {
+		renewalPrice := enrollment.Package.RenewalCost()
+		response.RenewalPrice = &renewalPrice
+	}
+
+	// Add cancellation information for enrollments an admin cancelled or refunded
+	if enrollment.CancelledAt != nil {
+		response.CancelledAt = enrollment.CancelledAt
+		response.CancelledBy = enrollment.CancelledBy
+		response.CancellationReason = enrollment.CancellationReason
+	}
+
 	// Add coupon information if used
 	if enrollment.CouponID != nil {
 		response.CouponCode = enrollment.CouponCode
@@ -77,3 +99,39 @@ func (m *EnrollmentMapper) ToEnrollmentListResponse(enrollments []models.UserPac
 	}
 	return responses
 }
+
+// ToRenewalResponse converts EnrollmentRenewal model to RenewalResponse DTO
+func (m *EnrollmentMapper) ToRenewalResponse(renewal *models.EnrollmentRenewal) *dto.RenewalResponse {
+	if renewal == nil {
+		return nil
+	}
+
+	return &dto.RenewalResponse{
+		ID:                renewal.ID,
+		EnrollmentID:      renewal.EnrollmentID,
+		Status:            string(renewal.Status),
+		Price:             renewal.Price,
+		OriginalPrice:     renewal.OriginalPrice,
+		PaymentRequired:   renewal.AwaitsPayment(),
+		PreviousExpiresAt: renewal.PreviousExpiresAt,
+		PeriodStart:       renewal.PeriodStart,
+		PeriodEnd:         renewal.PeriodEnd,
+		RenewedAt:         renewal.RenewedAt,
+		CreatedAt:         renewal.CreatedAt,
+	}
+}
+
+// ToRenewalHistoryResponse converts an enrollment and its completed renewals to RenewalHistoryResponse DTO
+func (m *EnrollmentMapper) ToRenewalHistoryResponse(enrollment *models.UserPackageEnrollment, renewals []models.EnrollmentRenewal) *dto.RenewalHistoryResponse {
+	responses := make([]dto.RenewalResponse, len(renewals))
+	for i := range renewals {
+		responses[i] = *m.ToRenewalResponse(&renewals[i])
+	}
+
+	return &dto.RenewalHistoryResponse{
+		EnrollmentID: enrollment.ID,
+		EnrolledAt:   enrollment.EnrolledAt,
+		ExpiresAt:    enrollment.ExpiresAt,
+		Renewals:     responses,
+	}
+}
@@ -71,6 +71,10 @@ func (m *examMapper) ToExamResponse(exam repository.ExamWithUserData) dto.ExamRe
 			SessionID:      exam.SessionID,
 		}
 
+		if exam.UserAttemptNumber != nil {
+			userAttempt.AttemptNumber = *exam.UserAttemptNumber
+		}
+
 		// Format started_at if available
 		if exam.UserAttemptStartedAt != nil {
 			userAttempt.StartedAt = exam.UserAttemptStartedAt.Format("2006-01-02T15:04:05Z")
@@ -83,6 +87,17 @@ func (m *examMapper) ToExamResponse(exam repository.ExamWithUserData) dto.ExamRe
 		}
 	}
 
+	// Attempt history and retake state
+	attemptHistory := make([]dto.UserAttemptResponse, len(exam.Attempts))
+	for i, attempt := range exam.Attempts {
+		attemptHistory[i] = m.toUserAttemptResponse(attempt)
+	}
+
+	scorePolicy := exam.ResolveAttemptScorePolicy(exam.EffectiveScorePolicy)
+	attemptsUsed := exam.AttemptsUsed()
+	remainingAttempts := exam.RemainingAttempts(attemptsUsed)
+	canRetake := exam.ActiveAttempt() == nil && (remainingAttempts == nil || *remainingAttempts > 0)
+
 	return dto.ExamResponse{
 		ID:                 int(exam.ID),
 		Title:              exam.Title,
@@ -102,11 +117,43 @@ func (m *examMapper) ToExamResponse(exam repository.ExamWithUserData) dto.ExamRe
 		PassRate:           passRate,
 		ComputedStatus:     exam.ComputedStatus,
 		SortOrder:          exam.SortOrder,
+		IsTrialVisible:     exam.IsTrialVisible,
 		HasAttempted:       exam.HasAttempted,
 		UserAttempt:        userAttempt,
+		AttemptsUsed:       attemptsUsed,
+		RemainingAttempts:  remainingAttempts,
+		CanRetake:          canRetake,
+		AttemptScorePolicy: string(scorePolicy),
+		EffectiveScore:     scorePolicy.AggregateScore(exam.Attempts),
+		AttemptHistory:     attemptHistory,
 	}
 }
 
+// toUserAttemptResponse converts a UserExamAttempt to UserAttemptResponse
+func (m *examMapper) toUserAttemptResponse(attempt models.UserExamAttempt) dto.UserAttemptResponse {
+	response := dto.UserAttemptResponse{
+		ID:             attempt.ID,
+		AttemptNumber:  attempt.AttemptNumber,
+		Status:         string(attempt.Status),
+		StartedAt:      attempt.StartedAt.Format("2006-01-02T15:04:05Z"),
+		Score:          attempt.Score,
+		CorrectAnswers: attempt.CorrectAnswers,
+		IsPassed:       attempt.IsPassed,
+		SessionID:      attempt.SessionID,
+	}
+
+	if attempt.IsCompleted() {
+		timeSpent := attempt.ActualTimeSpent
+		response.TimeSpent = &timeSpent
+	}
+
+	if attempt.CompletedAt != nil {
+		response.CompletedAt = attempt.CompletedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	return response
+}
+
 // ToExamListResponse converts a slice of ExamWithUserData to ExamListResponse (no pagination)
 func (m *examMapper) ToExamListResponse(exams []repository.ExamWithUserData) dto.ExamListResponse {
 	examResponses := make([]dto.ExamResponse, len(exams))
@@ -149,6 +196,10 @@ func (m *examMapper) ToExamListResponseWithPackage(pkg models.Package, exams []r
 		packageInfo.ValidityDate = &validityDateStr
 	}
 
+	if pkg.OffersTrial() {
+		packageInfo.TrialDays = pkg.TrialDays
+	}
+
 	return dto.ExamListResponse{
 		Package: packageInfo,
 		Exams:   examResponses,
@@ -232,6 +283,8 @@ func (m *examMapper) ToExamSessionResponse(attempt models.UserExamAttempt, exam
 	if exam.QuestionsData != "" {
 		var examQuestions []models.ExamQuestion
 		if err := json.Unmarshal([]byte(exam.QuestionsData), &examQuestions); err == nil {
+			// Show the questions and options in the attempt's own order
+			examQuestions = attempt.Layout(examQuestions).Apply(examQuestions)
 			questions = make([]dto.SecureExamQuestionResponse, len(examQuestions))
 			for i, q := range examQuestions {
 				// Create secure options without 'is_correct' field
@@ -279,6 +332,7 @@ func (m *examMapper) ToExamSessionResponse(attempt models.UserExamAttempt, exam
 			TotalMarks:      exam.TotalMarks,
 			MaxAttempts:     exam.MaxAttempts,
 			Instructions:    exam.Instructions,
+			Mode:            string(exam.SessionMode()),
 			Questions:       questions,
 		},
 		Session: dto.ExamSessionSessionData{
@@ -302,6 +356,8 @@ func (m *examMapper) ToExamSessionResponseWithAnswers(attempt models.UserExamAtt
 	if exam.QuestionsData != "" {
 		var examQuestions []models.ExamQuestion
 		if err := json.Unmarshal([]byte(exam.QuestionsData), &examQuestions); err == nil {
+			// Show the questions and options in the attempt's own order
+			examQuestions = attempt.Layout(examQuestions).Apply(examQuestions)
 			questions = make([]dto.SecureExamQuestionResponse, len(examQuestions))
 			for i, q := range examQuestions {
 				// Create secure options without 'is_correct' field
@@ -349,6 +405,7 @@ func (m *examMapper) ToExamSessionResponseWithAnswers(attempt models.UserExamAtt
 			TotalMarks:      exam.TotalMarks,
 			MaxAttempts:     exam.MaxAttempts,
 			Instructions:    exam.Instructions,
+			Mode:            string(exam.SessionMode()),
 			Questions:       questions,
 		},
 		Session: dto.ExamSessionSessionData{
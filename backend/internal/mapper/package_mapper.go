@@ -31,6 +31,7 @@ func (m *PackageMapper) ToPackageResponse(pkg models.Package) dto.PackageRespons
 		ValidityType: pkg.ValidityType,
 		ValidityDays: pkg.ValidityDays,
 		ValidityDate: m.formatValidityDate(pkg),
+		TrialDays:    m.trialDays(pkg),
 		TotalExams:   pkg.TotalExams,
 		IsActive:     pkg.IsActive,
 		SortOrder:    pkg.SortOrder,
@@ -88,6 +89,14 @@ func (m *PackageMapper) buildImageResponse(pkg models.Package) dto.PackageImageR
 	}
 }
 
+// trialDays returns the package's trial length, 0 when it offers no trial
+func (m *PackageMapper) trialDays(pkg models.Package) int {
+	if pkg.OffersTrial() {
+		return pkg.TrialDays
+	}
+	return 0
+}
+
 // formatValidityDate converts validity date to ISO string if exists
 func (m *PackageMapper) formatValidityDate(pkg models.Package) *string {
 	if pkg.ValidityDate != nil {
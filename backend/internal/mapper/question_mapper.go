@@ -0,0 +1,122 @@
+package mapper
+
+import (
+	"encoding/json"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+// QuestionMapper handles mapping between question bank models and DTOs
+type QuestionMapper struct{}
+
+// NewQuestionMapper creates a new question mapper
+func NewQuestionMapper() *QuestionMapper {
+	return &QuestionMapper{}
+}
+
+// ToQuestionResponse converts a Question model to QuestionResponse DTO
+func (m *QuestionMapper) ToQuestionResponse(question *models.Question) *dto.QuestionResponse {
+	if question == nil {
+		return nil
+	}
+
+	options := make(map[string]dto.QuestionOptionResponse)
+	if question.Options != "" {
+		_ = json.Unmarshal([]byte(question.Options), &options)
+	}
+
+	tags := []string{}
+	if question.Tags != "" {
+		_ = json.Unmarshal([]byte(question.Tags), &tags)
+	}
+
+	response := &dto.QuestionResponse{
+		ID:              question.ID,
+		SystemID:        question.SystemID,
+		QuestionText:    question.QuestionText,
+		QuestionType:    question.QuestionType,
+		DifficultyLevel: question.DifficultyLevel,
+		Options:         options,
+		Explanation:     question.Explanation,
+		Reference:       question.Reference,
+		Tags:            tags,
+		UsageCount:      question.UsageCount,
+		UsageLevel:      question.GetUsageLevel(),
+		IsActive:        question.IsActive,
+		CreatedBy:       question.CreatedBy,
+		CreatedAt:       question.CreatedAt,
+		UpdatedAt:       question.UpdatedAt,
+	}
+
+	// Add taxonomy if the relation was loaded
+	if question.System.ID != 0 {
+		response.System = &dto.QuestionSystemResponse{
+			ID:   question.System.ID,
+			Name: question.System.Name,
+			Slug: question.System.Slug,
+		}
+		if question.System.Subject.ID != 0 {
+			response.System.Subject = &dto.QuestionSubjectResponse{
+				ID:   question.System.Subject.ID,
+				Name: question.System.Subject.Name,
+				Slug: question.System.Subject.Slug,
+			}
+		}
+	}
+
+	return response
+}
+
+// ToQuestionListResponse converts a page of questions to QuestionListResponse DTO
+func (m *QuestionMapper) ToQuestionListResponse(questions []models.Question, total int64, page, limit int) *dto.QuestionListResponse {
+	responses := make([]dto.QuestionResponse, 0, len(questions))
+	for i := range questions {
+		responses = append(responses, *m.ToQuestionResponse(&questions[i]))
+	}
+
+	return &dto.QuestionListResponse{
+		Questions: responses,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+}
+
+// ToTaxonomyResponse converts subjects with systems to TaxonomyResponse DTO
+func (m *QuestionMapper) ToTaxonomyResponse(subjects []models.Subject, counts []repository.SystemQuestionCount) *dto.TaxonomyResponse {
+	countBySystem := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		countBySystem[count.SystemID] = count.Count
+	}
+
+	response := &dto.TaxonomyResponse{
+		Subjects: make([]dto.TaxonomySubjectResponse, 0, len(subjects)),
+	}
+
+	for _, subject := range subjects {
+		subjectResponse := dto.TaxonomySubjectResponse{
+			ID:       subject.ID,
+			Name:     subject.Name,
+			Slug:     subject.Slug,
+			IsActive: subject.IsActive,
+			Systems:  make([]dto.TaxonomySystemResponse, 0, len(subject.Systems)),
+		}
+
+		for _, system := range subject.Systems {
+			subjectResponse.Systems = append(subjectResponse.Systems, dto.TaxonomySystemResponse{
+				ID:            system.ID,
+				Name:          system.Name,
+				Slug:          system.Slug,
+				IsActive:      system.IsActive,
+				QuestionCount: countBySystem[system.ID],
+			})
+			subjectResponse.QuestionCount += countBySystem[system.ID]
+		}
+
+		response.Subjects = append(response.Subjects, subjectResponse)
+	}
+
+	return response
+}
@@ -63,6 +63,7 @@ func AuthMiddleware(jwtSecret string, authService *service.AuthService) gin.Hand
 		c.Set("user", user)
 		c.Set("userID", user.ID)
 		c.Set("msisdn", user.MSISDN)
+		c.Set("userRole", user.Role)
 
 		c.Next()
 	}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request through only if the authenticated user has one of the roles.
+// It must be used after AuthMiddleware, which loads the user from the database, so a role change
+// applies to tokens issued before it.
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		if !user.HasRole(roles...) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Forbidden",
+				Message: "You do not have access to this resource",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission allows the request through only if the authenticated user holds every permission.
+// It must be used after AuthMiddleware.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			return
+		}
+
+		for _, permission := range permissions {
+			if !user.HasPermission(permission) {
+				c.JSON(http.StatusForbidden, models.ErrorResponse{
+					Error:   "Forbidden",
+					Message: "Missing permission: " + string(permission),
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// currentUser reads the user set by AuthMiddleware, aborting with 401 if it is missing
+func currentUser(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get("user")
+	user, ok := value.(*models.User)
+	if !exists || !ok || user == nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User not authenticated",
+		})
+		c.Abort()
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	mathrand "math/rand"
+	"sort"
+	"strings"
+)
+
+// AttemptLayout is the order an attempt shows its exam's questions and options in. Answers are stored,
+// scored and reported with the exam's canonical option keys; the layout translates to and from the keys
+// the student sees.
+type AttemptLayout struct {
+	questionOrder      []uint                     // Question IDs in display order, nil keeps the exam order
+	displayToCanonical map[uint]map[string]string // Per question: displayed option key to canonical key
+	canonicalToDisplay map[uint]map[string]string // Per question: canonical option key to displayed key
+}
+
+// SnapshotShuffle copies the exam's shuffle settings onto a new attempt and seeds its order
+func (u *UserExamAttempt) SnapshotShuffle(exam *Exam) {
+	u.ShuffleQuestions = exam.ShuffleQuestions
+	u.ShuffleOptions = exam.ShuffleOptions
+	u.ShuffleSeed = 0
+	if !exam.ShuffleQuestions && !exam.ShuffleOptions {
+		return
+	}
+
+	for u.ShuffleSeed == 0 {
+		var seed [8]byte
+		if _, err := rand.Read(seed[:]); err != nil {
+			// Fall back to the exam order rather than failing the start
+			u.ShuffleQuestions = false
+			u.ShuffleOptions = false
+			return
+		}
+		u.ShuffleSeed = int64(binary.BigEndian.Uint64(seed[:]))
+	}
+}
+
+// Layout returns the attempt's display order for its exam questions. The order only depends on the
+// attempt's seed and the questions, so a resumed session sees the same order.
+func (u *UserExamAttempt) Layout(questions []ExamQuestion) AttemptLayout {
+	layout := AttemptLayout{
+		displayToCanonical: make(map[uint]map[string]string),
+		canonicalToDisplay: make(map[uint]map[string]string),
+	}
+	if u.ShuffleSeed == 0 {
+		return layout
+	}
+
+	if u.ShuffleQuestions {
+		order := make([]uint, 0, len(questions))
+		for _, question := range questions {
+			order = append(order, question.ID)
+		}
+		rng := mathrand.New(mathrand.NewSource(u.ShuffleSeed))
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		layout.questionOrder = order
+	}
+
+	if u.ShuffleOptions {
+		for _, question := range questions {
+			keys := make([]string, 0, len(question.Options))
+			for key := range question.Options {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			// Seeded per question so the option order does not depend on the question order
+			shuffled := append([]string(nil), keys...)
+			rng := mathrand.New(mathrand.NewSource(u.ShuffleSeed + int64(question.ID)))
+			rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+			toCanonical := make(map[string]string, len(keys))
+			toDisplay := make(map[string]string, len(keys))
+			for i, displayed := range keys {
+				toCanonical[displayed] = shuffled[i]
+				toDisplay[shuffled[i]] = displayed
+			}
+			layout.displayToCanonical[question.ID] = toCanonical
+			layout.canonicalToDisplay[question.ID] = toDisplay
+		}
+	}
+
+	return layout
+}
+
+// Apply returns the questions in display order with their options under the displayed keys
+func (l AttemptLayout) Apply(questions []ExamQuestion) []ExamQuestion {
+	byID := make(map[uint]ExamQuestion, len(questions))
+	for _, question := range questions {
+		if toCanonical, ok := l.displayToCanonical[question.ID]; ok {
+			options := make(map[string]interface{}, len(question.Options))
+			for displayed, canonical := range toCanonical {
+				options[displayed] = question.Options[canonical]
+			}
+			question.Options = options
+		}
+		byID[question.ID] = question
+	}
+
+	if l.questionOrder == nil {
+		ordered := make([]ExamQuestion, 0, len(questions))
+		for _, question := range questions {
+			ordered = append(ordered, byID[question.ID])
+		}
+		return ordered
+	}
+
+	ordered := make([]ExamQuestion, 0, len(l.questionOrder))
+	for _, questionID := range l.questionOrder {
+		ordered = append(ordered, byID[questionID])
+	}
+	return ordered
+}
+
+// DisplayedKey returns the key an option of a question is shown under
+func (l AttemptLayout) DisplayedKey(questionID uint, canonicalKey string) string {
+	if displayed, ok := l.canonicalToDisplay[questionID][canonicalKey]; ok {
+		return displayed
+	}
+	return canonicalKey
+}
+
+// ToCanonical translates an answer given with displayed keys to the exam's keys
+func (l AttemptLayout) ToCanonical(questionID uint, answer string) string {
+	return remapAnswerKeys(answer, l.displayToCanonical[questionID])
+}
+
+// ToDisplayed translates an answer stored with the exam's keys to the displayed keys
+func (l AttemptLayout) ToDisplayed(questionID uint, answer string) string {
+	return remapAnswerKeys(answer, l.canonicalToDisplay[questionID])
+}
+
+// remapAnswerKeys maps the option keys of a saved answer: a single key ("b") or a JSON array of keys or
+// key:value pairs (["a:true","b:false"])
+func remapAnswerKeys(answer string, keys map[string]string) string {
+	if answer == "" || len(keys) == 0 {
+		return answer
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(answer), &values); err != nil {
+		return remapAnswerValue(answer, keys)
+	}
+	for i, value := range values {
+		values[i] = remapAnswerValue(value, keys)
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return answer
+	}
+	return string(encoded)
+}
+
+// remapAnswerValue maps the option key of "key" or "key:value"
+func remapAnswerValue(value string, keys map[string]string) string {
+	key, rest, hasValue := strings.Cut(value, ":")
+	mapped, ok := keys[key]
+	if !ok {
+		return value
+	}
+	if hasValue {
+		return mapped + ":" + rest
+	}
+	return mapped
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RenewalStatus enum for the states of an enrollment renewal
+type RenewalStatus string
+
+const (
+	RenewalStatusPending   RenewalStatus = "PENDING"   // Waiting for its payment
+	RenewalStatusCompleted RenewalStatus = "COMPLETED" // Enrollment was extended
+	RenewalStatusFailed    RenewalStatus = "FAILED"    // Last payment failed, can be paid again
+	RenewalStatusRefunded  RenewalStatus = "REFUNDED"  // Payment was returned, the period was taken off again
+)
+
+// EnrollmentRenewal is one renewal of an enrollment. Completed renewals are the enrollment's renewal
+// history: each adds the period from PeriodStart to PeriodEnd.
+type EnrollmentRenewal struct {
+	ID           uint `json:"id" gorm:"primarykey"`
+	EnrollmentID uint `json:"enrollment_id" gorm:"not null;index:idx_enrollment_id"`
+	UserID       uint `json:"user_id" gorm:"not null;index:idx_user_id"`
+	PackageID    uint `json:"package_id" gorm:"not null;index:idx_package_id"`
+
+	// Pricing
+	Price         float64 `json:"price" gorm:"type:decimal(10,2);not null;comment:'Renewal price charged'"`
+	OriginalPrice float64 `json:"original_price" gorm:"type:decimal(10,2);not null;comment:'Package price at renewal'"`
+
+	// Period, set when the renewal completes
+	Status            RenewalStatus `json:"status" gorm:"type:enum('PENDING','COMPLETED','FAILED','REFUNDED');not null;default:'PENDING';index:idx_status"`
+	PreviousExpiresAt *time.Time    `json:"previous_expires_at" gorm:"comment:'Enrollment expiry before the renewal'"`
+	PeriodStart       *time.Time    `json:"period_start"`
+	PeriodEnd         *time.Time    `json:"period_end"`
+	PaymentReference  *string       `json:"payment_reference" gorm:"size:100;comment:'Payment gateway reference'"`
+	RenewedAt         *time.Time    `json:"renewed_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Enrollment UserPackageEnrollment `json:"enrollment,omitempty" gorm:"foreignKey:EnrollmentID"`
+}
+
+// TableName specifies the table name for EnrollmentRenewal
+func (EnrollmentRenewal) TableName() string {
+	return "enrollment_renewals"
+}
+
+// AwaitsPayment reports whether the renewal can be paid for
+func (r *EnrollmentRenewal) AwaitsPayment() bool {
+	return r.Price > 0 && (r.Status == RenewalStatusPending || r.Status == RenewalStatusFailed)
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -24,8 +26,26 @@ const (
 	ExamTypeMock   ExamType = "MOCK"
 	ExamTypeReview ExamType = "REVIEW"
 	ExamTypeFinal  ExamType = "FINAL"
+
+	// ExamTypePractice exams are generated for one student's practice session and never listed in packages
+	ExamTypePractice ExamType = "PRACTICE"
+)
+
+// ExamMode controls whether students see feedback during a session
+type ExamMode string
+
+const (
+	// ExamModeTimed hides answers until the attempt is submitted
+	ExamModeTimed ExamMode = "TIMED"
+	// ExamModeTutor lets students check each answer during the session
+	ExamModeTutor ExamMode = "TUTOR"
 )
 
+// IsValid checks if the mode is a known exam mode
+func (m ExamMode) IsValid() bool {
+	return m == ExamModeTimed || m == ExamModeTutor
+}
+
 // Exam represents the exams table
 type Exam struct {
 	ID          uint    `json:"id" gorm:"primarykey"`
@@ -34,12 +54,23 @@ type Exam struct {
 	Description *string `json:"description" gorm:"type:text"`
 
 	// Exam Type & Configuration
-	ExamType        ExamType `json:"exam_type" gorm:"type:enum('DAILY','MOCK','REVIEW','FINAL');not null;default:'DAILY';index:idx_exam_type"`
+	ExamType        ExamType `json:"exam_type" gorm:"type:enum('DAILY','MOCK','REVIEW','FINAL','PRACTICE');not null;default:'DAILY';index:idx_exam_type"`
 	TotalQuestions  int      `json:"total_questions" gorm:"not null"`
 	DurationMinutes int      `json:"duration_minutes" gorm:"not null;default:60"`
 	TotalMarks      float64  `json:"total_marks" gorm:"type:decimal(8,2);not null;default:0.00;comment:'Total marks/points for this exam'"`
 	PassingScore    float64  `json:"passing_score" gorm:"type:decimal(5,2);default:60.00"`
-	MaxAttempts     int      `json:"max_attempts" gorm:"default:1"`
+	MaxAttempts     int      `json:"max_attempts" gorm:"default:1;comment:'Attempts allowed per package context, 0 means unlimited'"`
+	Mode            ExamMode `json:"mode" gorm:"type:enum('TIMED','TUTOR');not null;default:'TIMED';comment:'TUTOR gives per-question feedback on REVIEW and PRACTICE exams'"`
+
+	// Display order, each attempt gets its own order so students cannot share answers by position or key
+	ShuffleQuestions bool `json:"shuffle_questions" gorm:"default:false;comment:'Show questions in a different order per attempt'"`
+	ShuffleOptions   bool `json:"shuffle_options" gorm:"default:false;comment:'Show options under different keys per attempt'"`
+
+	// AttemptScorePolicy overrides the package policy when set
+	AttemptScorePolicy *AttemptScorePolicy `json:"attempt_score_policy" gorm:"type:enum('BEST','LATEST','AVERAGE');comment:'Overrides package attempt_score_policy when set'"`
+
+	// Scoring policy (JSON, see scoring.Policy) - NULL uses the default policy
+	ScoringPolicy *string `json:"scoring_policy" gorm:"type:json;comment:'Partial credit, option weights and negative marking settings'"`
 
 	// Embedded Questions (JSON) - No JOIN needed!
 	QuestionsData string `json:"questions_data" gorm:"type:longtext;not null;comment:'JSON array of complete question objects with options, answers, explanations'"`
@@ -47,6 +78,8 @@ type Exam struct {
 	// Scheduling
 	ScheduledStartDate *time.Time `json:"scheduled_start_date"`
 	ScheduledEndDate   *time.Time `json:"scheduled_end_date"`
+	// Sessions of a live exam end when it closes, late starters get less time unless this is set
+	LateStartFullDuration bool `json:"late_start_full_duration" gorm:"default:false;comment:'Late starters keep the full duration past the scheduled end'"`
 
 	// Settings
 	Instructions *string `json:"instructions" gorm:"type:text"`
@@ -82,6 +115,119 @@ type ExamQuestion struct {
 	QuestionType QuestionType           `json:"question_type"`
 	Options      map[string]interface{} `json:"options"`
 	Points       int                    `json:"points"` // Points for this question
+
+	Explanation     string          `json:"explanation,omitempty"`
+	Reference       string          `json:"reference,omitempty"`
+	DifficultyLevel DifficultyLevel `json:"difficulty_level,omitempty"`
+	SystemID        uint            `json:"system_id,omitempty"`
+	SubjectID       uint            `json:"subject_id,omitempty"` // Subject of the system when the exam was built
+}
+
+// NewExamQuestion builds the QuestionsData snapshot entry for a question bank row
+func NewExamQuestion(question *Question, points int) (ExamQuestion, error) {
+	var options map[string]interface{}
+	if err := json.Unmarshal([]byte(question.Options), &options); err != nil {
+		return ExamQuestion{}, fmt.Errorf("failed to parse options for question %d: %w", question.ID, err)
+	}
+
+	examQuestion := ExamQuestion{
+		ID:              question.ID,
+		QuestionText:    question.QuestionText,
+		QuestionType:    question.QuestionType,
+		Options:         options,
+		Points:          points,
+		DifficultyLevel: question.DifficultyLevel,
+		SystemID:        question.SystemID,
+		SubjectID:       question.System.SubjectID,
+	}
+	if question.Explanation != nil {
+		examQuestion.Explanation = *question.Explanation
+	}
+	if question.Reference != nil {
+		examQuestion.Reference = *question.Reference
+	}
+
+	return examQuestion, nil
+}
+
+// IsPractice reports whether the exam was generated for a practice session
+func (e *Exam) IsPractice() bool {
+	return e.ExamType == ExamTypePractice
+}
+
+// SessionMode returns the mode sessions of this exam run in. Tutor mode only applies to REVIEW and
+// PRACTICE exams so answers to ranked or graded exams are never revealed before submission.
+func (e *Exam) SessionMode() ExamMode {
+	if e.Mode == ExamModeTutor && (e.ExamType == ExamTypeReview || e.ExamType == ExamTypePractice) {
+		return ExamModeTutor
+	}
+	return ExamModeTimed
+}
+
+// AllowsAnswerChecking reports whether students can check answers during a session
+func (e *Exam) AllowsAnswerChecking() bool {
+	return e.SessionMode() == ExamModeTutor
+}
+
+// HasLeaderboard reports whether students are ranked against each other on this exam
+func (e *Exam) HasLeaderboard() bool {
+	return e.ExamType == ExamTypeMock || e.ExamType == ExamTypeFinal
+}
+
+// ScheduleWindow returns when a scheduled exam opens and closes, the same window package listings compute
+// UPCOMING/LIVE/COMPLETED from. Exams without a start date are always available and return nil.
+func (e *Exam) ScheduleWindow() (opensAt, closesAt *time.Time) {
+	if e.ScheduledStartDate == nil {
+		return nil, nil
+	}
+	if e.ScheduledEndDate != nil {
+		return e.ScheduledStartDate, e.ScheduledEndDate
+	}
+	end := e.ScheduledStartDate.Add(time.Duration(e.DurationMinutes) * time.Minute)
+	return e.ScheduledStartDate, &end
+}
+
+// SessionTimeLimitSeconds returns the time limit of an attempt started at startedAt. Sessions of a scheduled
+// exam end when it closes unless the exam gives late starters the full duration.
+func (e *Exam) SessionTimeLimitSeconds(startedAt time.Time) int {
+	limit := e.DurationMinutes * 60
+	if e.LateStartFullDuration {
+		return limit
+	}
+	if _, closesAt := e.ScheduleWindow(); closesAt != nil {
+		if untilClose := int(closesAt.Sub(startedAt).Seconds()); untilClose < limit {
+			limit = max(untilClose, 0)
+		}
+	}
+	return limit
+}
+
+// HasUnlimitedAttempts reports whether retakes are uncapped
+func (e *Exam) HasUnlimitedAttempts() bool {
+	return e.MaxAttempts <= 0
+}
+
+// RemainingAttempts returns how many attempts are left after attemptsUsed, or nil when unlimited
+func (e *Exam) RemainingAttempts(attemptsUsed int) *int {
+	if e.HasUnlimitedAttempts() {
+		return nil
+	}
+	remaining := e.MaxAttempts - attemptsUsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// ResolveAttemptScorePolicy returns the exam override or falls back to the package policy
+func (e *Exam) ResolveAttemptScorePolicy(packagePolicy AttemptScorePolicy) AttemptScorePolicy {
+	if e.AttemptScorePolicy != nil && e.AttemptScorePolicy.IsValid() {
+		return *e.AttemptScorePolicy
+	}
+	if packagePolicy.IsValid() {
+		return packagePolicy
+	}
+	return AttemptScorePolicyBest
 }
 
 // UpdateAttemptCount increments the attempt counter
@@ -118,7 +264,7 @@ func (e *Exam) RecalculateExamStats(db *gorm.DB) error {
 	}
 
 	if err := db.Model(&UserExamAttempt{}).
-		Where("exam_id = ? AND status IN ? AND is_scored = true", e.ID, []AttemptStatus{AttemptStatusCompleted, AttemptStatusAutoSubmitted}).
+		Where("exam_id = ? AND status IN ? AND score IS NOT NULL", e.ID, []AttemptStatus{AttemptStatusCompleted, AttemptStatusAutoSubmitted}).
 		Select("COUNT(*) as count, AVG(score) as avg_score").
 		Scan(&result).Error; err != nil {
 		return err
@@ -48,6 +48,14 @@ type Package struct {
 	ValidityType ValidityType `json:"validity_type" gorm:"type:enum('FIXED','RELATIVE');default:'RELATIVE';index:idx_validity_type"`
 	ValidityDays *int         `json:"validity_days" gorm:"comment:'Days from enrollment (for RELATIVE type)'"`
 	ValidityDate *time.Time   `json:"validity_date" gorm:"comment:'Fixed expiry date (for FIXED type)'"`
+	RenewalPrice *float64     `json:"renewal_price" gorm:"type:decimal(10,2);comment:'Discounted price of renewing an enrollment, package price when null'"`
+
+	// Free trial (PREMIUM packages only)
+	TrialDays          int `json:"trial_days" gorm:"default:0;comment:'Length of the free trial, 0 means no trial'"`
+	TrialExtensionDays int `json:"trial_extension_days" gorm:"default:0;comment:'Days a trial can be extended by once, 0 means no extension'"`
+
+	// Retake scoring
+	AttemptScorePolicy AttemptScorePolicy `json:"attempt_score_policy" gorm:"type:enum('BEST','LATEST','AVERAGE');default:'BEST';comment:'Which attempt score counts when exams allow retakes'"`
 
 	// Metadata
 	TotalExams int `json:"total_exams" gorm:"default:0"`
@@ -91,6 +99,37 @@ func (p *Package) CalculateExpiryDate(enrollmentDate time.Time) *time.Time {
 	}
 }
 
+// ExpiryDateFrom returns when an enrollment starting at start expires, one year when validity is not configured
+func (p *Package) ExpiryDateFrom(start time.Time) time.Time {
+	if expiresAt := p.CalculateExpiryDate(start); expiresAt != nil {
+		return *expiresAt
+	}
+	return start.AddDate(1, 0, 0)
+}
+
+// RenewalCost returns what renewing an enrollment in the package costs
+func (p *Package) RenewalCost() float64 {
+	if p.RenewalPrice != nil {
+		return *p.RenewalPrice
+	}
+	return p.Price
+}
+
+// RenewalPeriod returns the validity period a renewal made at adds. It starts when the current enrollment
+// expires, or at the renewal when that already happened, so renewing early loses no days.
+func (p *Package) RenewalPeriod(currentExpiresAt *time.Time, at time.Time) (start, end time.Time) {
+	start = at
+	if currentExpiresAt != nil && currentExpiresAt.After(at) {
+		start = *currentExpiresAt
+	}
+	return start, p.ExpiryDateFrom(start)
+}
+
+// OffersTrial checks if students can try the package for free before buying it
+func (p *Package) OffersTrial() bool {
+	return p.PackageType == PackageTypePremium && p.Price > 0 && p.TrialDays > 0
+}
+
 // IsValidConfiguration checks if the package validity configuration is valid
 func (p *Package) IsValidConfiguration() bool {
 	switch p.ValidityType {
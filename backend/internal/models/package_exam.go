@@ -15,6 +15,9 @@ type PackageExam struct {
 	// Ordering within package
 	SortOrder int `json:"sort_order" gorm:"default:0"`
 
+	// Trial visibility
+	IsTrialVisible bool `json:"is_trial_visible" gorm:"default:false;comment:'Whether trial enrollments can take this exam'"`
+
 	// Status
 	IsActive bool `json:"is_active" gorm:"default:true"`
 
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// PaymentRefundStatus enum for the states of a refund at the gateway
+type PaymentRefundStatus string
+
+const (
+	PaymentRefundProcessed PaymentRefundStatus = "PROCESSED" // Money returned to the student
+	PaymentRefundPending   PaymentRefundStatus = "PENDING"   // Accepted by the gateway, or its answer was lost, until reconciled
+	PaymentRefundReserved  PaymentRefundStatus = "RESERVED"  // Held while the gateway is asked for the refund
+	PaymentRefundFailed    PaymentRefundStatus = "FAILED"    // Fell through at the gateway, the amount can be refunded again
+)
+
+// PaymentRefund represents a full or partial refund of a paid transaction, made by an admin
+type PaymentRefund struct {
+	ID            uint   `json:"id" gorm:"primarykey"`
+	TransactionID string `json:"transaction_id" gorm:"size:64;not null;index:idx_transaction_id;comment:'Refunded payment transaction'"`
+	EnrollmentID  uint   `json:"enrollment_id" gorm:"not null;index:idx_enrollment_id"`
+	UserID        uint   `json:"user_id" gorm:"not null;index:idx_user_id;comment:'Student the money is returned to'"`
+
+	// Gateway
+	Provider        string  `json:"provider" gorm:"size:30;not null"`
+	RefundReference *string `json:"refund_reference" gorm:"size:100;comment:'Gateway refund ID'"`
+	GatewayData     *string `json:"-" gorm:"type:text;comment:'Gateway refund response'"`
+
+	// Amount
+	Amount   float64             `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Currency string              `json:"currency" gorm:"size:3;not null;default:'BDT'"`
+	Status   PaymentRefundStatus `json:"status" gorm:"type:enum('PROCESSED','PENDING','RESERVED','FAILED');not null;default:'PENDING';index:idx_status"`
+
+	// Enrollment
+	CancelEnrollment bool `json:"cancel_enrollment" gorm:"not null;default:false;comment:'Cancel the enrollment once the refund is processed, even if partial'"`
+
+	// Audit
+	Reason     string `json:"reason" gorm:"size:500;not null"`
+	RefundedBy uint   `json:"refunded_by" gorm:"not null;index:idx_refunded_by;comment:'Admin who made the refund'"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for PaymentRefund
+func (PaymentRefund) TableName() string {
+	return "payment_refunds"
+}
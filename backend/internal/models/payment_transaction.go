@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// PaymentTransactionStatus enum for the states of a checkout
+type PaymentTransactionStatus string
+
+const (
+	PaymentTransactionInitiated PaymentTransactionStatus = "INITIATED" // Sent to the gateway, not paid yet
+	PaymentTransactionPaid      PaymentTransactionStatus = "PAID"
+	PaymentTransactionFailed    PaymentTransactionStatus = "FAILED"
+	PaymentTransactionCancelled PaymentTransactionStatus = "CANCELLED"
+)
+
+// IsSettled reports whether the gateway has decided the transaction
+func (s PaymentTransactionStatus) IsSettled() bool {
+	return s == PaymentTransactionPaid || s == PaymentTransactionFailed || s == PaymentTransactionCancelled
+}
+
+// PaymentTransaction represents one checkout of an enrollment, or of its renewal, at a payment gateway
+type PaymentTransaction struct {
+	ID            uint   `json:"id" gorm:"primarykey"`
+	TransactionID string `json:"transaction_id" gorm:"size:64;uniqueIndex;not null;comment:'Our reference sent to the gateway'"`
+	EnrollmentID  uint   `json:"enrollment_id" gorm:"not null;index:idx_enrollment_id"`
+	RenewalID     *uint  `json:"renewal_id" gorm:"index:idx_renewal_id;comment:'Set when the checkout pays for a renewal'"`
+	UserID        uint   `json:"user_id" gorm:"not null;index:idx_user_id"`
+
+	// Gateway
+	Provider              string  `json:"provider" gorm:"size:30;not null;index:idx_provider_reference,priority:1"`
+	ProviderReference     *string `json:"provider_reference" gorm:"size:100;index:idx_provider_reference,priority:2;comment:'Gateway session or payment ID'"`
+	ProviderTransactionID *string `json:"provider_transaction_id" gorm:"size:100;comment:'Gateway transaction ID, used for refunds'"`
+
+	// Amount
+	Amount   float64 `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Currency string  `json:"currency" gorm:"size:3;not null;default:'BDT'"`
+
+	// Outcome
+	Status        PaymentTransactionStatus `json:"status" gorm:"type:enum('INITIATED','PAID','FAILED','CANCELLED');not null;default:'INITIATED';index:idx_status"`
+	FailureReason *string                  `json:"failure_reason" gorm:"size:255"`
+	GatewayData   *string                  `json:"-" gorm:"type:text;comment:'Last gateway verification response'"`
+	PaidAt        *time.Time               `json:"paid_at"`
+	Duplicate     bool                     `json:"duplicate" gorm:"not null;default:false;comment:'Paid for what another transaction had already paid, to be refunded'"`
+	VerifiedAt    *time.Time               `json:"verified_at" gorm:"comment:'When the gateway was last asked'"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Enrollment UserPackageEnrollment `json:"enrollment,omitempty" gorm:"foreignKey:EnrollmentID"`
+}
+
+// TableName specifies the table name for PaymentTransaction
+func (PaymentTransaction) TableName() string {
+	return "payment_transactions"
+}
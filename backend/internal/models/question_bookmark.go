@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// QuestionBookmark represents a question a user saved from their results, with an optional private note
+type QuestionBookmark struct {
+	ID         uint   `json:"id" gorm:"primarykey"`
+	UserID     uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_user_question,priority:1"`
+	QuestionID uint   `json:"question_id" gorm:"not null;uniqueIndex:idx_user_question,priority:2"`
+	AttemptID  uint   `json:"attempt_id" gorm:"default:0;comment:'Attempt the question was bookmarked from'"`
+	Note       string `json:"note" gorm:"type:text;comment:'Private note, only visible to the user'"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Question Question `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
+}
+
+// TableName specifies the table name for QuestionBookmark
+func (QuestionBookmark) TableName() string {
+	return "question_bookmarks"
+}
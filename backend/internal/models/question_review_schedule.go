@@ -0,0 +1,105 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// SM-2 scheduling parameters
+const (
+	// DefaultEaseFactor is the ease a new review schedule starts with
+	DefaultEaseFactor = 2.5
+	// MinEaseFactor keeps hard questions from being reviewed every day forever
+	MinEaseFactor = 1.3
+	// ReviewPassQuality is the lowest review grade (0-5) that counts as remembered
+	ReviewPassQuality = 3
+)
+
+// QuestionReviewSchedule represents a user's spaced-repetition schedule for one question (SM-2).
+// Schedules are created when a question is missed and updated every time it is answered again.
+type QuestionReviewSchedule struct {
+	ID         uint `json:"id" gorm:"primarykey"`
+	UserID     uint `json:"user_id" gorm:"not null;uniqueIndex:idx_user_question,priority:1;index:idx_user_due,priority:1"`
+	QuestionID uint `json:"question_id" gorm:"not null;uniqueIndex:idx_user_question,priority:2"`
+	SystemID   uint `json:"system_id" gorm:"default:0;comment:'System of the question, 0 when unknown'"`
+	SubjectID  uint `json:"subject_id" gorm:"default:0;comment:'Subject of the system, 0 when unknown'"`
+
+	// SM-2 state
+	EaseFactor   float64   `json:"ease_factor" gorm:"type:decimal(4,2);not null;default:2.50"`
+	IntervalDays int       `json:"interval_days" gorm:"not null;default:0;comment:'Days between the last review and the next'"`
+	Repetitions  int       `json:"repetitions" gorm:"not null;default:0;comment:'Consecutive reviews answered correctly'"`
+	DueAt        time.Time `json:"due_at" gorm:"not null;index:idx_user_due,priority:2"`
+
+	// Review history
+	Lapses         int       `json:"lapses" gorm:"default:0;comment:'Times the question was missed again'"`
+	TotalReviews   int       `json:"total_reviews" gorm:"default:0"`
+	LastQuality    int       `json:"last_quality" gorm:"default:0;comment:'Last review grade, 0-5'"`
+	LastReviewedAt time.Time `json:"last_reviewed_at" gorm:"comment:'When the question was last answered, reviews at or before this are ignored'"`
+	LastAttemptID  uint      `json:"last_attempt_id" gorm:"default:0"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Question Question `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
+}
+
+// TableName specifies the table name for QuestionReviewSchedule
+func (QuestionReviewSchedule) TableName() string {
+	return "question_review_schedules"
+}
+
+// NewQuestionReviewSchedule starts a schedule for a question the user has not reviewed yet
+func NewQuestionReviewSchedule(userID, questionID uint) QuestionReviewSchedule {
+	return QuestionReviewSchedule{
+		UserID:     userID,
+		QuestionID: questionID,
+		EaseFactor: DefaultEaseFactor,
+	}
+}
+
+// ApplyReview updates the schedule with a review graded 0-5 using SM-2. A pass grows the interval
+// (1 day, 6 days, then interval x ease); a fail starts the question over from tomorrow. Ease moves
+// with every review and never drops below MinEaseFactor.
+func (s *QuestionReviewSchedule) ApplyReview(quality int, reviewedAt time.Time) {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > 5 {
+		quality = 5
+	}
+
+	if quality >= ReviewPassQuality {
+		switch s.Repetitions {
+		case 0:
+			s.IntervalDays = 1
+		case 1:
+			s.IntervalDays = 6
+		default:
+			s.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.EaseFactor))
+		}
+		s.Repetitions++
+	} else {
+		if s.TotalReviews > 0 {
+			s.Lapses++
+		}
+		s.Repetitions = 0
+		s.IntervalDays = 1
+	}
+
+	miss := float64(5 - quality)
+	s.EaseFactor = math.Round((s.EaseFactor+0.1-miss*(0.08+miss*0.02))*100) / 100
+	if s.EaseFactor < MinEaseFactor {
+		s.EaseFactor = MinEaseFactor
+	}
+
+	s.TotalReviews++
+	s.LastQuality = quality
+	s.LastReviewedAt = reviewedAt
+	s.DueAt = reviewedAt.AddDate(0, 0, s.IntervalDays)
+}
+
+// IsDue reports whether the question should be reviewed by the given time
+func (s *QuestionReviewSchedule) IsDue(at time.Time) bool {
+	return !s.DueAt.After(at)
+}
@@ -0,0 +1,110 @@
+package models
+
+import "encoding/json"
+
+// UserRole enum for user roles
+type UserRole string
+
+const (
+	RoleAdmin   UserRole = "ADMIN"   // Full access including user and enrollment management
+	RoleEditor  UserRole = "EDITOR"  // Content authoring: question bank and exams
+	RoleStudent UserRole = "STUDENT" // Default role for registered users
+)
+
+// Permission represents a single capability that can be granted to a user
+type Permission string
+
+const (
+	PermissionManageQuestions   Permission = "questions:manage"
+	PermissionManageExams       Permission = "exams:manage"
+	PermissionManagePackages    Permission = "packages:manage"
+	PermissionViewAnalytics     Permission = "analytics:view"
+	PermissionManageEnrollments Permission = "enrollments:manage"
+	PermissionManageUsers       Permission = "users:manage"
+)
+
+// rolePermissions defines the permissions every role is granted by default
+var rolePermissions = map[UserRole][]Permission{
+	RoleAdmin: {
+		PermissionManageQuestions,
+		PermissionManageExams,
+		PermissionManagePackages,
+		PermissionViewAnalytics,
+		PermissionManageEnrollments,
+		PermissionManageUsers,
+	},
+	RoleEditor: {
+		PermissionManageQuestions,
+		PermissionManageExams,
+		PermissionViewAnalytics,
+	},
+	RoleStudent: {},
+}
+
+// IsValid checks if the role is one of the known roles
+func (r UserRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions returns the default permissions of the role
+func (r UserRole) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// GetPermissions returns the role permissions combined with any extra grants stored on the user
+func (u *User) GetPermissions() []Permission {
+	seen := make(map[Permission]bool)
+	permissions := make([]Permission, 0)
+
+	for _, permission := range u.Role.Permissions() {
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	if u.Permissions != nil && *u.Permissions != "" {
+		var extra []Permission
+		if err := json.Unmarshal([]byte(*u.Permissions), &extra); err == nil {
+			for _, permission := range extra {
+				if !seen[permission] {
+					seen[permission] = true
+					permissions = append(permissions, permission)
+				}
+			}
+		}
+	}
+
+	return permissions
+}
+
+// GetPermissionNames returns the user's permissions as plain strings (used in user responses)
+func (u *User) GetPermissionNames() []string {
+	permissions := u.GetPermissions()
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return names
+}
+
+// HasRole checks if the user has any of the given roles
+func (u *User) HasRole(roles ...UserRole) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if the user has been granted the permission
+func (u *User) HasPermission(permission Permission) bool {
+	for _, granted := range u.GetPermissions() {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SessionAnswerChange is an entry of a session's append-only answer change log. The log is kept in the
+// cache while the session is active and folded into the attempt's AnswersData on submission.
+type SessionAnswerChange struct {
+	QuestionID uint      `json:"question_id"`
+	From       string    `json:"from"`       // Previous option, empty when the question was unanswered
+	To         string    `json:"to"`         // New option, empty when the answer was cleared
+	ChangedAt  time.Time `json:"changed_at"` // Client time of the change, kept within the session
+	SyncedAt   time.Time `json:"synced_at"`  // Server time the change was received
+}
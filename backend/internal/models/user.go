@@ -23,6 +23,10 @@ type User struct {
 	ProviderUserID string `json:"provider_user_id" gorm:"size:255;index"`                // Google/Facebook user ID
 	ProfilePicture string `json:"profile_picture" gorm:"type:text"`                      // Avatar URL from social provider
 	EmailVerified  bool   `json:"email_verified" gorm:"default:false"`                   // Email verification status
+
+	// Authorization
+	Role        UserRole `json:"role" gorm:"type:enum('ADMIN','EDITOR','STUDENT');default:'STUDENT';not null;index"`
+	Permissions *string  `json:"-" gorm:"type:json"` // Extra permissions granted on top of the role
 }
 
 // UserResponse represents the user data returned in API responses
@@ -35,6 +39,8 @@ type UserResponse struct {
 	ProfilePicture string    `json:"profile_picture,omitempty"` // Include if available
 	EmailVerified  bool      `json:"email_verified"`            // Email verification status
 	IsActive       bool      `json:"is_active"`
+	Role           UserRole  `json:"role"`
+	Permissions    []string  `json:"permissions"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
@@ -49,6 +55,8 @@ func (u *User) ToResponse() UserResponse {
 		ProfilePicture: u.ProfilePicture,
 		EmailVerified:  u.EmailVerified,
 		IsActive:       u.IsActive,
+		Role:           u.Role,
+		Permissions:    u.GetPermissionNames(),
 		CreatedAt:      u.CreatedAt,
 	}
 }
@@ -16,18 +16,79 @@ const (
 	AttemptStatusAbandoned     AttemptStatus = "ABANDONED"      // Never completed (cleanup job)
 )
 
+// MaxAnalyticsFailures is how often the analytics worker tries an attempt before giving up on it
+const MaxAnalyticsFailures = 5
+
+// AttemptScorePolicy decides which attempt score counts when an exam allows retakes
+type AttemptScorePolicy string
+
+const (
+	AttemptScorePolicyBest    AttemptScorePolicy = "BEST"    // Highest scoring attempt
+	AttemptScorePolicyLatest  AttemptScorePolicy = "LATEST"  // Most recent finished attempt
+	AttemptScorePolicyAverage AttemptScorePolicy = "AVERAGE" // Mean of all finished attempts
+)
+
+// IsValid checks if the policy is one of the supported values
+func (p AttemptScorePolicy) IsValid() bool {
+	switch p {
+	case AttemptScorePolicyBest, AttemptScorePolicyLatest, AttemptScorePolicyAverage:
+		return true
+	default:
+		return false
+	}
+}
+
+// AggregateScore combines the scores of finished attempts according to the policy.
+// Returns nil when no attempt has been scored yet.
+func (p AttemptScorePolicy) AggregateScore(attempts []UserExamAttempt) *float64 {
+	var result float64
+	var latestNumber int
+	scored := 0
+
+	for _, attempt := range attempts {
+		if !attempt.IsCompleted() || attempt.Score == nil {
+			continue
+		}
+		score := *attempt.Score
+
+		switch p {
+		case AttemptScorePolicyLatest:
+			if scored == 0 || attempt.AttemptNumber > latestNumber {
+				result = score
+				latestNumber = attempt.AttemptNumber
+			}
+		case AttemptScorePolicyAverage:
+			result += score
+		default:
+			if scored == 0 || score > result {
+				result = score
+			}
+		}
+		scored++
+	}
+
+	if scored == 0 {
+		return nil
+	}
+	if p == AttemptScorePolicyAverage {
+		result = result / float64(scored)
+	}
+	return &result
+}
+
 // UserExamAttempt represents user attempts at exams (simplified for Redis workflow)
 type UserExamAttempt struct {
 	ID        uint `json:"id" gorm:"primarykey"`
-	UserID    uint `json:"user_id" gorm:"not null;uniqueIndex:idx_user_exam_package"`
-	ExamID    uint `json:"exam_id" gorm:"not null;uniqueIndex:idx_user_exam_package"`
-	PackageID uint `json:"package_id" gorm:"not null;uniqueIndex:idx_user_exam_package;index:idx_package_id;comment:'Package context for this attempt - same exam in different packages are separate attempts'"`
+	UserID    uint `json:"user_id" gorm:"not null;uniqueIndex:idx_user_exam_package_attempt"`
+	ExamID    uint `json:"exam_id" gorm:"not null;uniqueIndex:idx_user_exam_package_attempt;index:idx_exam_package_status,priority:1"`
+	PackageID uint `json:"package_id" gorm:"not null;uniqueIndex:idx_user_exam_package_attempt;index:idx_package_id;index:idx_exam_package_status,priority:2;comment:'Package context for this attempt - same exam in different packages are separate attempts'"`
 
-	// Single attempt control (ready for future scaling)
-	AttemptNumber int `json:"attempt_number" gorm:"default:1;comment:'Always 1 for now, ready for multiple attempts'"`
+	// Attempt control (numbered per user, exam and package, capped by exam.max_attempts)
+	AttemptNumber int  `json:"attempt_number" gorm:"not null;default:1;uniqueIndex:idx_user_exam_package_attempt;comment:'1-based attempt number within the package context'"`
+	IsPractice    bool `json:"is_practice" gorm:"default:false;index:idx_practice;comment:'Practice sessions do not count towards package progress'"`
 
 	// Status and timing (final state only - active state managed in Redis)
-	Status      AttemptStatus `json:"status" gorm:"type:enum('STARTED','COMPLETED','AUTO_SUBMITTED','ABANDONED');default:'STARTED';index:idx_status"`
+	Status      AttemptStatus `json:"status" gorm:"type:enum('STARTED','COMPLETED','AUTO_SUBMITTED','ABANDONED');default:'STARTED';index:idx_status;index:idx_exam_package_status,priority:3"`
 	StartedAt   time.Time     `json:"started_at" gorm:"not null;comment:'When exam was started'"`
 	CompletedAt *time.Time    `json:"completed_at" gorm:"comment:'When exam was completed or auto-submitted'"`
 
@@ -46,12 +107,22 @@ type UserExamAttempt struct {
 	TotalQuestions int     `json:"total_questions" gorm:"not null;comment:'Snapshot from exam at start time'"`
 	PassingScore   float64 `json:"passing_score" gorm:"type:decimal(5,2);not null;comment:'Snapshot from exam at start time'"`
 
-	// Scoring (processed in background after completion)
+	// Display order snapshot (see Layout) - the seed is not sent to clients
+	ShuffleSeed      int64 `json:"-" gorm:"default:0;comment:'Seeds the question and option order, 0 shows the exam order'"`
+	ShuffleQuestions bool  `json:"shuffle_questions" gorm:"default:false;comment:'Snapshot from exam at start time'"`
+	ShuffleOptions   bool  `json:"shuffle_options" gorm:"default:false;comment:'Snapshot from exam at start time'"`
+
+	// Scoring (score is set on completion, per-question analytics are processed in background)
 	IsScored       bool     `json:"is_scored" gorm:"default:false;index:idx_scored;comment:'Whether background scoring is completed'"`
 	Score          *float64 `json:"score" gorm:"type:decimal(5,2);comment:'Final score percentage (0-100)'"`
 	CorrectAnswers *int     `json:"is_corrects" gorm:"comment:'Number of correct answers'"`
 	IsPassed       *bool    `json:"is_passed" gorm:"comment:'Whether attempt passed based on passing_score'"`
 
+	// Background analytics failures (reset once the attempt is processed)
+	AnalyticsFailures int        `json:"-" gorm:"default:0;comment:'Failed analytics runs, the worker gives up at MaxAnalyticsFailures'"`
+	AnalyticsFailedAt *time.Time `json:"-" gorm:"comment:'Last failed analytics run'"`
+	AnalyticsError    *string    `json:"-" gorm:"size:255;comment:'Error of the last failed analytics run'"`
+
 	// Metadata
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -105,6 +176,16 @@ func (u *UserExamAttempt) IsTimeExpired() bool {
 	return u.GetRemainingTimeSeconds() <= 0
 }
 
+// SessionStateTTL returns how long the session's cached state has to be kept: until the time limit and the
+// given grace period have passed, and never less than the grace period
+func (u *UserExamAttempt) SessionStateTTL(grace time.Duration) time.Duration {
+	deadline := u.StartedAt.Add(time.Duration(u.TimeLimitSeconds)*time.Second + grace)
+	if ttl := time.Until(deadline); ttl > grace {
+		return ttl
+	}
+	return grace
+}
+
 // HasActiveSession checks if there's an active Redis session
 func (u *UserExamAttempt) HasActiveSession() bool {
 	return u.SessionID != nil && u.Status == AttemptStatusStarted
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -62,6 +63,11 @@ type UserPackageEnrollment struct {
 	DiscountAmount     *float64 `json:"discount_amount" gorm:"type:decimal(10,2);comment:'Discount amount applied'"`
 	FinalPrice         *float64 `json:"final_price" gorm:"type:decimal(10,2);comment:'Final price after discount'"`
 
+	// Cancellation (set when an admin cancels or refunds the enrollment)
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CancelledBy        *uint      `json:"cancelled_by" gorm:"comment:'Admin who cancelled the enrollment'"`
+	CancellationReason *string    `json:"cancellation_reason" gorm:"size:500"`
+
 	// Status
 	IsActive bool `json:"is_active" gorm:"default:true;index:idx_active"`
 
@@ -98,6 +104,15 @@ func (u *UserPackageEnrollment) IsExpired() bool {
 	return time.Now().After(*u.ExpiresAt)
 }
 
+// DaysUntilExpiry returns the days left until the enrollment expires, negative once it has, nil when it never does
+func (u *UserPackageEnrollment) DaysUntilExpiry() *int {
+	if u.ExpiresAt == nil {
+		return nil
+	}
+	days := int(math.Ceil(time.Until(*u.ExpiresAt).Hours() / 24))
+	return &days
+}
+
 // IsTrialExpired checks if the trial period has expired
 func (u *UserPackageEnrollment) IsTrialExpired() bool {
 	if u.TrialExpiresAt == nil {
@@ -106,24 +121,61 @@ func (u *UserPackageEnrollment) IsTrialExpired() bool {
 	return time.Now().After(*u.TrialExpiresAt)
 }
 
-// CanAccessContent checks if user can currently access package content
-func (u *UserPackageEnrollment) CanAccessContent() bool {
+// AccessDenial explains why an enrollment does not give access to package content
+type AccessDenial string
+
+const (
+	AccessGranted               AccessDenial = ""
+	AccessDeniedInactive        AccessDenial = "ENROLLMENT_INACTIVE" // Deactivated or cancelled
+	AccessDeniedRefunded        AccessDenial = "ENROLLMENT_REFUNDED" // Payment was returned
+	AccessDeniedPaymentRequired AccessDenial = "PAYMENT_REQUIRED"    // Priced enrollment not paid yet, or its payment failed
+	AccessDeniedTrialExpired    AccessDenial = "TRIAL_EXPIRED"       // Trial is over, the full package has to be bought
+	AccessDeniedExpired         AccessDenial = "ENROLLMENT_EXPIRED"  // Validity period is over
+	AccessDeniedTrialExamLocked AccessDenial = "TRIAL_EXAM_LOCKED"   // Exam is not part of the trial
+)
+
+// AccessDenial returns why the enrollment does not give access to package content, or AccessGranted
+func (u *UserPackageEnrollment) AccessDenial() AccessDenial {
 	if !u.IsActive {
-		return false
+		return AccessDeniedInactive
 	}
 
-	// If it's a paid enrollment, check main expiry
-	if u.IsPaidPurchase() {
-		return !u.IsExpired()
+	switch u.PaymentStatus {
+	case PaymentStatusRefunded:
+		return AccessDeniedRefunded
+	case PaymentStatusPaid, PaymentStatusUpgraded:
+		if u.IsExpired() {
+			return AccessDeniedExpired
+		}
+		return AccessGranted
 	}
 
-	// If it's a trial enrollment, check trial expiry
+	// Trials are free until the trial ends
 	if u.EnrollmentType == EnrollmentTypeTrial {
-		return !u.IsTrialExpired()
+		if u.IsTrialExpired() || u.PaymentStatus == PaymentStatusExpired {
+			return AccessDeniedTrialExpired
+		}
+		return AccessGranted
+	}
+
+	// Paid enrollments marked expired are renewed, not paid for again
+	if u.PaymentStatus == PaymentStatusExpired {
+		return AccessDeniedExpired
+	}
+
+	// PENDING and FAILED enrollments are still waiting for their payment
+	if u.PaymentStatus != PaymentStatusFree {
+		return AccessDeniedPaymentRequired
+	}
+	if u.IsExpired() {
+		return AccessDeniedExpired
 	}
+	return AccessGranted
+}
 
-	// For other free enrollments, check main expiry
-	return !u.IsExpired()
+// CanAccessContent checks if user can currently access package content
+func (u *UserPackageEnrollment) CanAccessContent() bool {
+	return u.AccessDenial() == AccessGranted
 }
 
 // NeedsPaymentToAccess checks if user needs to pay to access content
@@ -131,6 +183,40 @@ func (u *UserPackageEnrollment) NeedsPaymentToAccess() bool {
 	return u.IsTrialExpired() && u.PaymentStatus != PaymentStatusPaid
 }
 
+// AwaitsPayment reports whether the enrollment can be paid for: a priced enrollment that is pending, whose
+// last payment failed or whose trial ended. Paying for a trial upgrades it to the full package.
+func (u *UserPackageEnrollment) AwaitsPayment() bool {
+	return u.IsActive && u.EnrolledPrice > 0 &&
+		(u.PaymentStatus == PaymentStatusPending || u.PaymentStatus == PaymentStatusFailed ||
+			(u.PaymentStatus == PaymentStatusExpired && u.IsOnTrial()))
+}
+
+// RenewalWindowDays is how many days before expiry an enrollment can be renewed
+const RenewalWindowDays = 30
+
+// CanRenew checks if the enrollment can be renewed: a paid, upgraded or free enrollment that expired or
+// expires within RenewalWindowDays. Trials are upgraded instead.
+func (u *UserPackageEnrollment) CanRenew() bool {
+	if !u.IsActive || u.IsOnTrial() || u.ExpiresAt == nil {
+		return false
+	}
+	switch u.PaymentStatus {
+	case PaymentStatusPaid, PaymentStatusUpgraded, PaymentStatusFree, PaymentStatusExpired:
+		return time.Until(*u.ExpiresAt) <= RenewalWindowDays*24*time.Hour
+	}
+	return false
+}
+
+// IsOnTrial checks if the enrollment is a trial that has not been upgraded
+func (u *UserPackageEnrollment) IsOnTrial() bool {
+	return u.EnrollmentType == EnrollmentTypeTrial
+}
+
+// CanExtendTrial checks if the trial can still be extended, which is allowed once
+func (u *UserPackageEnrollment) CanExtendTrial() bool {
+	return u.IsActive && u.IsOnTrial() && u.TrialExtendedAt == nil
+}
+
 // GetEffectiveStatus returns the current effective status considering expiry
 func (u *UserPackageEnrollment) GetEffectiveStatus() string {
 	if !u.IsActive {
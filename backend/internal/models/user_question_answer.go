@@ -9,16 +9,18 @@ import (
 // UserQuestionAnswer represents individual question answers for analytics (populated in background)
 type UserQuestionAnswer struct {
 	ID        uint `json:"id" gorm:"primarykey"`
-	AttemptID uint `json:"attempt_id" gorm:"not null;index:idx_attempt_id;comment:'Reference to user_exam_attempts'"`
-	UserID    uint `json:"user_id" gorm:"not null;index:idx_user_id;comment:'For direct user analytics'"`
+	AttemptID uint `json:"attempt_id" gorm:"not null;index:idx_attempt_id;uniqueIndex:idx_attempt_question,priority:1;comment:'Reference to user_exam_attempts'"`
+	UserID    uint `json:"user_id" gorm:"not null;index:idx_user_id;index:idx_user_system,priority:1;index:idx_user_subject,priority:1;comment:'For direct user analytics'"`
 	ExamID    uint `json:"exam_id" gorm:"not null;index:idx_exam_id;comment:'For exam-level analytics'"`
 
 	// Question details (snapshot for analytics, populated from exam questions)
-	QuestionID      uint            `json:"question_id" gorm:"not null;index:idx_question_id;comment:'Original question ID'"`
+	QuestionID      uint            `json:"question_id" gorm:"not null;index:idx_question_id;uniqueIndex:idx_attempt_question,priority:2;comment:'Original question ID'"`
 	QuestionType    QuestionType    `json:"question_type" gorm:"not null;index:idx_question_type;comment:'SBA, TRUE_FALSE, etc.'"`
 	QuestionText    string          `json:"question_text" gorm:"type:text;comment:'Snapshot for analytics'"`
 	DifficultyLevel DifficultyLevel `json:"difficulty_level" gorm:"index:idx_difficulty;comment:'EASY, MEDIUM, HARD'"`
 	QuestionIndex   int             `json:"question_index" gorm:"not null;comment:'Position in exam (0-based)'"`
+	SystemID        uint            `json:"system_id" gorm:"default:0;index:idx_user_system,priority:2;comment:'System of the question, 0 when unknown'"`
+	SubjectID       uint            `json:"subject_id" gorm:"default:0;index:idx_user_subject,priority:2;comment:'Subject of the system, 0 when unknown'"`
 
 	// Answer details (extracted from AnswersData JSON during background processing)
 	SelectedOptions string `json:"selected_options" gorm:"type:text;comment:'JSON array of selected options'"`
@@ -34,9 +36,10 @@ type UserQuestionAnswer struct {
 	AnsweredAt time.Time `json:"answered_at" gorm:"comment:'When this question was answered'"`
 
 	// Behavioral analytics
-	IsSkipped    bool `json:"is_skipped" gorm:"default:false;index:idx_skipped;comment:'Whether question was skipped'"`
-	ChangeCount  int  `json:"change_count" gorm:"default:0;comment:'How many times answer was changed'"`
-	IsLastAnswer bool `json:"is_last_answer" gorm:"default:true;comment:'Whether this was the final answer or changed later'"`
+	IsSkipped          bool `json:"is_skipped" gorm:"default:false;index:idx_skipped;comment:'Whether question was skipped'"`
+	ChangeCount        int  `json:"change_count" gorm:"default:0;comment:'How many times answer was changed'"`
+	ChangedFromCorrect bool `json:"changed_from_correct" gorm:"default:false;comment:'Whether a correct answer was changed to a wrong one'"`
+	IsLastAnswer       bool `json:"is_last_answer" gorm:"default:true;comment:'Whether this was the final answer or changed later'"`
 
 	// Metadata
 	CreatedAt time.Time      `json:"created_at"`
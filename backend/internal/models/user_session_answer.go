@@ -7,18 +7,23 @@ import (
 )
 
 // UserSessionAnswer represents a real-time answer stored during an active exam session
-// This is used for auto-save functionality and real-time sync
+// Session answers are written through from the cache so they survive a cache restart, and removed once the attempt ends.
+// A cleared answer keeps its row with an empty option, so the time it was cleared is still known.
+// A question flagged or changed without an answer has a row with an empty option too.
 type UserSessionAnswer struct {
 	ID        uint `json:"id" gorm:"primarykey"`
-	AttemptID uint `json:"attempt_id" gorm:"not null;index:idx_attempt_id;comment:'Reference to user_exam_attempts'"`
+	AttemptID uint `json:"attempt_id" gorm:"not null;uniqueIndex:idx_attempt_question;comment:'Reference to user_exam_attempts'"`
 	UserID    uint `json:"user_id" gorm:"not null;index:idx_user_id;comment:'For quick user filtering'"`
 
 	// Question and Answer (minimal data for sync)
-	QuestionID     uint   `json:"question_id" gorm:"not null;index:idx_question_id;comment:'Question being answered'"`
-	SelectedOption string `json:"selected_option" gorm:"not null;comment:'Selected option key (a, b, c, d, e)'"`
+	QuestionID     uint   `json:"question_id" gorm:"not null;uniqueIndex:idx_attempt_question;comment:'Question being answered'"`
+	SelectedOption string `json:"selected_option" gorm:"type:text;not null;comment:'Selected option key (a, b, c, d, e) or JSON for multiple options'"`
+	Flagged        bool   `json:"flagged" gorm:"not null;default:false;comment:'Flagged to revisit before submitting'"`
 
 	// Timing
-	AnsweredAt time.Time `json:"answered_at" gorm:"comment:'When this answer was last updated'"`
+	AnsweredAt time.Time  `json:"answered_at" gorm:"comment:'When this answer was last updated'"`
+	ChangedAt  *time.Time `json:"changed_at" gorm:"comment:'When the student last changed the question on their device, its sync version'"`
+	CheckedAt  *time.Time `json:"checked_at" gorm:"comment:'When tutor mode checked the answer, which locks it'"`
 
 	// Metadata
 	CreatedAt time.Time      `json:"created_at"`
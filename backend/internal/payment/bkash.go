@@ -0,0 +1,299 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BKashProviderName is the name of the bKash provider
+const BKashProviderName = "bkash"
+
+const (
+	bkashSandboxURL = "https://tokenized.sandbox.bka.sh/v1.2.0-beta"
+	bkashLiveURL    = "https://tokenized.pay.bka.sh/v1.2.0-beta"
+
+	bkashSuccessCode = "0000"
+)
+
+// BKashConfig holds bKash tokenized checkout credentials
+type BKashConfig struct {
+	AppKey     string
+	AppSecret  string
+	Username   string
+	Password   string
+	Sandbox    bool
+	BaseURL    string       // Overrides the sandbox/live URL, for tests
+	HTTPClient *http.Client // Defaults to a client with a 30s timeout
+}
+
+// BKashProvider takes bKash wallet payments through the tokenized checkout API
+type BKashProvider struct {
+	config  BKashConfig
+	baseURL string
+	client  *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewBKashProvider creates a bKash provider
+func NewBKashProvider(config BKashConfig) *BKashProvider {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = bkashLiveURL
+		if config.Sandbox {
+			baseURL = bkashSandboxURL
+		}
+	}
+	return &BKashProvider{
+		config:  config,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  httpClientOrDefault(config.HTTPClient),
+	}
+}
+
+// Name returns the provider name
+func (p *BKashProvider) Name() string {
+	return BKashProviderName
+}
+
+// bkashToken is the response of the grant token API
+type bkashToken struct {
+	StatusCode    string `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+	IDToken       string `json:"id_token"`
+	ExpiresIn     int    `json:"expires_in"`
+}
+
+// authToken returns a cached grant token, requesting a new one shortly before it expires
+func (p *BKashProvider) authToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"app_key": p.config.AppKey, "app_secret": p.config.AppSecret})
+	if err != nil {
+		return "", err
+	}
+	var token bkashToken
+	_, err = doJSON(ctx, p.client, http.MethodPost, p.baseURL+"/tokenized/checkout/token/grant", bytes.NewReader(body), map[string]string{
+		"Content-Type": "application/json",
+		"username":     p.config.Username,
+		"password":     p.config.Password,
+	}, &token)
+	if err != nil {
+		return "", fmt.Errorf("bkash grant token: %w", err)
+	}
+	if token.IDToken == "" {
+		return "", fmt.Errorf("bkash grant token rejected: %s", token.StatusMessage)
+	}
+
+	p.token = token.IDToken
+	p.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
+
+// call sends an authorised request to the checkout API
+func (p *BKashProvider) call(ctx context.Context, path string, payload interface{}, out interface{}) (string, error) {
+	token, err := p.authToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return doJSON(ctx, p.client, http.MethodPost, p.baseURL+path, bytes.NewReader(body), map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": token,
+		"X-APP-Key":     p.config.AppKey,
+	}, out)
+}
+
+// bkashPayment is the payment reported by the create, execute and query APIs
+type bkashPayment struct {
+	StatusCode            string `json:"statusCode"`
+	StatusMessage         string `json:"statusMessage"`
+	PaymentID             string `json:"paymentID"`
+	BKashURL              string `json:"bkashURL"`
+	TrxID                 string `json:"trxID"`
+	TransactionStatus     string `json:"transactionStatus"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	MerchantInvoiceNumber string `json:"merchantInvoiceNumber"`
+	PaymentExecuteTime    string `json:"paymentExecuteTime"`
+}
+
+// InitiateCheckout creates a payment the student authorises in the bKash app or web page
+func (p *BKashProvider) InitiateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	payerReference := req.CustomerPhone
+	if payerReference == "" {
+		payerReference = req.TransactionID
+	}
+
+	var created bkashPayment
+	_, err := p.call(ctx, "/tokenized/checkout/create", map[string]string{
+		"mode":                  "0011",
+		"payerReference":        payerReference,
+		"callbackURL":           req.ReturnURL,
+		"amount":                formatAmount(req.Amount),
+		"currency":              req.Currency,
+		"intent":                "sale",
+		"merchantInvoiceNumber": req.TransactionID,
+	}, &created)
+	if err != nil {
+		return nil, fmt.Errorf("bkash create payment: %w", err)
+	}
+	if created.StatusCode != bkashSuccessCode || created.BKashURL == "" {
+		return nil, fmt.Errorf("bkash create payment rejected: %s", created.StatusMessage)
+	}
+
+	return &Checkout{ProviderReference: created.PaymentID, RedirectURL: created.BKashURL}, nil
+}
+
+// Verify queries the payment and executes it once the student has authorised it, bKash only captures
+// the money on execution
+func (p *BKashProvider) Verify(ctx context.Context, transactionID, providerReference string) (*Verification, error) {
+	if providerReference == "" {
+		return nil, fmt.Errorf("bkash payment %s has no payment ID", transactionID)
+	}
+
+	var payment bkashPayment
+	raw, err := p.call(ctx, "/tokenized/checkout/payment/status", map[string]string{"paymentID": providerReference}, &payment)
+	if err != nil {
+		return nil, fmt.Errorf("bkash query payment: %w", err)
+	}
+
+	if payment.TransactionStatus == "Initiated" {
+		// Fails until the student authorises the payment, the query result stands then
+		var executed bkashPayment
+		executedRaw, err := p.call(ctx, "/tokenized/checkout/execute", map[string]string{"paymentID": providerReference}, &executed)
+		if err == nil && executed.StatusCode == bkashSuccessCode {
+			payment, raw = executed, executedRaw
+		}
+	}
+
+	verification := &Verification{
+		TransactionID:         transactionID,
+		ProviderReference:     providerReference,
+		ProviderTransactionID: payment.TrxID,
+		Status:                StatusPending,
+		Currency:              payment.Currency,
+		Raw:                   raw,
+	}
+	verification.Amount, _ = strconv.ParseFloat(payment.Amount, 64)
+
+	switch payment.TransactionStatus {
+	case "Completed":
+		verification.Status = StatusPaid
+		verification.PaidAt = parseBKashTime(payment.PaymentExecuteTime)
+	case "Failed", "Expired", "Declined":
+		verification.Status = StatusFailed
+		verification.FailureReason = payment.StatusMessage
+	case "Cancelled":
+		verification.Status = StatusCancelled
+	}
+	return verification, nil
+}
+
+// parseBKashTime parses execution times like "2023-05-09T13:48:04:392 GMT+0600", returning nil if malformed
+func parseBKashTime(value string) *time.Time {
+	if len(value) < len("2006-01-02T15:04:05") {
+		return nil
+	}
+	parsed, err := time.ParseInLocation("2006-01-02T15:04:05", value[:len("2006-01-02T15:04:05")], dhakaTime)
+	if err != nil {
+		return nil
+	}
+	parsed = parsed.UTC()
+	return &parsed
+}
+
+// ParseCallback reads the paymentID bKash appends to the callback URL
+func (p *BKashProvider) ParseCallback(values url.Values) (*Callback, error) {
+	paymentID := values.Get("paymentID")
+	if paymentID == "" {
+		return nil, fmt.Errorf("bkash callback has no paymentID")
+	}
+	return &Callback{ProviderReference: paymentID}, nil
+}
+
+// bkashRefund is the response of the refund and refund status APIs
+type bkashRefund struct {
+	StatusCode        string `json:"statusCode"`
+	StatusMessage     string `json:"statusMessage"`
+	RefundTrxID       string `json:"refundTrxID"`
+	TransactionStatus string `json:"transactionStatus"`
+	Amount            string `json:"amount"`
+}
+
+// refundStatus maps a bKash refund's transaction status
+func (r bkashRefund) refundStatus() Status {
+	switch r.TransactionStatus {
+	case "Completed":
+		return StatusPaid
+	case "Failed", "Cancelled":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+// Refund returns a full or partial amount of a completed payment to the student's wallet
+func (p *BKashProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	var result bkashRefund
+	raw, err := p.call(ctx, "/tokenized/checkout/payment/refund", map[string]string{
+		"paymentID": req.ProviderReference,
+		"trxID":     req.ProviderTransactionID,
+		"amount":    formatAmount(req.Amount),
+		"reason":    req.Reason,
+		"sku":       req.TransactionID,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("bkash refund: %w", err)
+	}
+	if result.RefundTrxID == "" || result.refundStatus() == StatusFailed {
+		return nil, fmt.Errorf("%w: bkash refund rejected: %s", ErrRefundDeclined, result.StatusMessage)
+	}
+
+	return &RefundResult{RefundReference: result.RefundTrxID, Status: result.refundStatus(), Raw: raw}, nil
+}
+
+// RefundStatus asks for the refund of a payment. bKash reports the payment's latest refund, so without a
+// reference it is only taken as this refund when the amounts match.
+func (p *BKashProvider) RefundStatus(ctx context.Context, req RefundRequest, refundReference string) (*RefundResult, error) {
+	var result bkashRefund
+	raw, err := p.call(ctx, "/tokenized/checkout/payment/refund", map[string]string{
+		"paymentID": req.ProviderReference,
+		"trxID":     req.ProviderTransactionID,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("bkash refund status: %w", err)
+	}
+	if result.RefundTrxID == "" {
+		return nil, fmt.Errorf("%w: bkash payment %s has no refund: %s", ErrRefundNotFound, req.ProviderTransactionID, result.StatusMessage)
+	}
+	if refundReference != "" && result.RefundTrxID != refundReference {
+		return nil, fmt.Errorf("bkash refund status: latest refund is %s, not %s", result.RefundTrxID, refundReference)
+	}
+	if refundReference == "" {
+		amount, _ := strconv.ParseFloat(result.Amount, 64)
+		if formatAmount(amount) != formatAmount(req.Amount) {
+			return nil, fmt.Errorf("bkash refund status: latest refund of %s does not match %s", result.Amount, formatAmount(req.Amount))
+		}
+	}
+
+	return &RefundResult{RefundReference: result.RefundTrxID, Status: result.refundStatus(), Raw: raw}, nil
+}
@@ -0,0 +1,190 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FakeProviderName is the name of the local provider
+const FakeProviderName = "fake"
+
+// FakeProvider is an in-memory gateway for development and tests. Checkouts redirect straight back to the
+// return URL; with auto-approve they are paid, otherwise they stay pending until SetStatus is called.
+// Refunds are processed straight away unless held with HoldRefunds.
+type FakeProvider struct {
+	mu          sync.Mutex
+	autoApprove bool
+	holdRefunds bool
+	payments    map[string]*fakePayment
+	refunds     map[string]*fakeRefund
+}
+
+type fakePayment struct {
+	reference string
+	amount    float64
+	currency  string
+	status    Status
+	paidAt    *time.Time
+	refunded  float64
+}
+
+type fakeRefund struct {
+	transactionID string
+	amount        float64
+	status        Status
+}
+
+// NewFakeProvider creates a local provider
+func NewFakeProvider(autoApprove bool) *FakeProvider {
+	return &FakeProvider{
+		autoApprove: autoApprove,
+		payments:    make(map[string]*fakePayment),
+		refunds:     make(map[string]*fakeRefund),
+	}
+}
+
+// Name returns the provider name
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+// InitiateCheckout records the payment and redirects back to the return URL
+func (p *FakeProvider) InitiateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment := &fakePayment{
+		reference: "FAKE-" + req.TransactionID,
+		amount:    req.Amount,
+		currency:  req.Currency,
+		status:    StatusPending,
+	}
+	if p.autoApprove {
+		now := time.Now().UTC()
+		payment.status = StatusPaid
+		payment.paidAt = &now
+	}
+	p.payments[req.TransactionID] = payment
+
+	redirect, err := url.Parse(req.ReturnURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid return URL: %w", err)
+	}
+	query := redirect.Query()
+	query.Set("tran_id", req.TransactionID)
+	query.Set("reference", payment.reference)
+	redirect.RawQuery = query.Encode()
+
+	return &Checkout{ProviderReference: payment.reference, RedirectURL: redirect.String()}, nil
+}
+
+// Verify returns the recorded status of the payment
+func (p *FakeProvider) Verify(ctx context.Context, transactionID, providerReference string) (*Verification, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[transactionID]
+	if !ok {
+		return nil, fmt.Errorf("fake payment %s not found", transactionID)
+	}
+	return &Verification{
+		TransactionID:         transactionID,
+		ProviderReference:     payment.reference,
+		ProviderTransactionID: payment.reference,
+		Status:                payment.status,
+		Amount:                payment.amount,
+		Currency:              payment.currency,
+		PaidAt:                payment.paidAt,
+	}, nil
+}
+
+// ParseCallback reads the tran_id the fake checkout redirected with
+func (p *FakeProvider) ParseCallback(values url.Values) (*Callback, error) {
+	transactionID := values.Get("tran_id")
+	if transactionID == "" {
+		return nil, fmt.Errorf("callback has no tran_id")
+	}
+	return &Callback{TransactionID: transactionID, ProviderReference: values.Get("reference")}, nil
+}
+
+// Refund returns part or all of a paid fake payment
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[req.TransactionID]
+	if !ok || payment.status != StatusPaid {
+		return nil, fmt.Errorf("%w: fake payment %s is not paid", ErrRefundDeclined, req.TransactionID)
+	}
+	if req.Amount <= 0 || payment.refunded+req.Amount > payment.amount {
+		return nil, fmt.Errorf("%w: refund of %.2f exceeds the refundable amount", ErrRefundDeclined, req.Amount)
+	}
+	payment.refunded += req.Amount
+
+	refund := &fakeRefund{transactionID: req.TransactionID, amount: req.Amount, status: StatusPaid}
+	if p.holdRefunds {
+		refund.status = StatusPending
+	}
+	reference := payment.reference + "-R" + strconv.Itoa(len(p.refunds)+1)
+	p.refunds[reference] = refund
+
+	return &RefundResult{RefundReference: reference, Status: refund.status}, nil
+}
+
+// RefundStatus returns the recorded status of a refund
+func (p *FakeProvider) RefundStatus(ctx context.Context, req RefundRequest, refundReference string) (*RefundResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund, ok := p.refunds[refundReference]
+	if !ok || refund.transactionID != req.TransactionID {
+		return nil, fmt.Errorf("%w: fake refund %q", ErrRefundNotFound, refundReference)
+	}
+	return &RefundResult{RefundReference: refundReference, Status: refund.status}, nil
+}
+
+// HoldRefunds makes new refunds stay pending until SetRefundStatus is called, as gateways that process
+// refunds in batches do
+func (p *FakeProvider) HoldRefunds(hold bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.holdRefunds = hold
+}
+
+// SetRefundStatus changes what the gateway reports for a refund. A failed refund's amount can be refunded again.
+func (p *FakeProvider) SetRefundStatus(refundReference string, status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund, ok := p.refunds[refundReference]
+	if !ok || refund.status == status {
+		return
+	}
+	if status == StatusFailed {
+		if payment, ok := p.payments[refund.transactionID]; ok {
+			payment.refunded -= refund.amount
+		}
+	}
+	refund.status = status
+}
+
+// SetStatus changes what the gateway reports for a payment, as if the student paid or gave up
+func (p *FakeProvider) SetStatus(transactionID string, status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[transactionID]
+	if !ok {
+		return
+	}
+	payment.status = status
+	if status == StatusPaid && payment.paidAt == nil {
+		now := time.Now().UTC()
+		payment.paidAt = &now
+	}
+}
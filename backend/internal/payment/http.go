@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPTimeout bounds every gateway call
+const defaultHTTPTimeout = 30 * time.Second
+
+// dhakaTime is the zone gateway timestamps are reported in
+var dhakaTime = time.FixedZone("Asia/Dhaka", 6*60*60)
+
+// httpClientOrDefault returns the configured client or one with the default timeout
+func httpClientOrDefault(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+// doJSON sends a request and decodes a JSON response into out, returning the raw body for audits
+func doJSON(ctx context.Context, client *http.Client, method, endpoint string, body io.Reader, headers map[string]string, out interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return string(raw), fmt.Errorf("gateway returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return string(raw), fmt.Errorf("failed to decode response: %w", err)
+	}
+	return string(raw), nil
+}
+
+// formatAmount formats an amount the way gateways expect it
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
@@ -0,0 +1,147 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Status is a gateway's verdict on a payment
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"   // Not paid yet, the student may still complete it
+	StatusPaid      Status = "PAID"      // Money captured by the gateway
+	StatusFailed    Status = "FAILED"    // Declined or errored, a new checkout is needed
+	StatusCancelled Status = "CANCELLED" // Abandoned by the student at the gateway
+)
+
+// IsFinal reports whether the gateway will not change the status any more
+func (s Status) IsFinal() bool {
+	return s == StatusPaid || s == StatusFailed || s == StatusCancelled
+}
+
+var (
+	// ErrUnknownProvider is returned for a provider name that is not registered
+	ErrUnknownProvider = errors.New("unknown payment provider")
+	// ErrRefundDeclined is wrapped by refund errors where the gateway answered and made no refund. Any other
+	// refund error leaves the outcome unknown, the refund may still have been made.
+	ErrRefundDeclined = errors.New("refund declined by gateway")
+	// ErrRefundNotFound is returned when the gateway has no record of a refund
+	ErrRefundNotFound = errors.New("refund not found at gateway")
+)
+
+// CheckoutRequest describes a payment to start at a gateway
+type CheckoutRequest struct {
+	TransactionID string // Our reference, unique per checkout
+	Amount        float64
+	Currency      string
+	Description   string
+
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+
+	ReturnURL string // Where the gateway sends the student's browser afterwards
+	NotifyURL string // Where the gateway posts server-to-server notifications (IPN), if supported
+}
+
+// Checkout is a started payment the student completes at the gateway
+type Checkout struct {
+	ProviderReference string // Gateway's session or payment ID
+	RedirectURL       string // Gateway page the student is sent to
+}
+
+// Verification is the gateway's current view of a payment, as reported by its API
+type Verification struct {
+	TransactionID         string
+	ProviderReference     string
+	ProviderTransactionID string // Gateway transaction ID needed for refunds
+	Status                Status
+	Amount                float64
+	Currency              string
+	PaidAt                *time.Time
+	FailureReason         string
+	Raw                   string // Gateway response, kept for audits
+}
+
+// Callback identifies the payment a gateway redirect or notification is about. Callbacks are not trusted
+// on their own, the payment is verified with the gateway before anything is changed.
+type Callback struct {
+	TransactionID     string
+	ProviderReference string
+}
+
+// RefundRequest describes a full or partial refund of a paid payment
+type RefundRequest struct {
+	TransactionID         string
+	ProviderReference     string
+	ProviderTransactionID string
+	Amount                float64
+	Reason                string
+}
+
+// RefundResult is the gateway's answer to a refund request
+type RefundResult struct {
+	RefundReference string
+	Status          Status // PAID once the refund is processed, PENDING while the gateway processes it, FAILED if it fell through
+	Raw             string
+}
+
+// Provider is a payment gateway adapter
+type Provider interface {
+	// Name is the identifier used in routes and stored on transactions
+	Name() string
+	// InitiateCheckout starts a payment and returns the page to send the student to
+	InitiateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error)
+	// Verify asks the gateway for the payment's status, capturing it first where the gateway needs that
+	Verify(ctx context.Context, transactionID, providerReference string) (*Verification, error)
+	// ParseCallback reads the payment a redirect or notification refers to
+	ParseCallback(values url.Values) (*Callback, error)
+	// Refund returns money of a paid payment to the student
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+	// RefundStatus asks the gateway what became of a refund. The reference is empty when the answer to the
+	// refund request was lost.
+	RefundStatus(ctx context.Context, req RefundRequest, refundReference string) (*RefundResult, error)
+}
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry with the given providers
+func NewRegistry(providers ...Provider) *Registry {
+	registry := &Registry{providers: make(map[string]Provider)}
+	for _, provider := range providers {
+		registry.Register(provider)
+	}
+	return registry
+}
+
+// Register adds a provider, replacing any provider with the same name
+func (r *Registry) Register(provider Provider) {
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider with the given name
+func (r *Registry) Get(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	return provider, nil
+}
+
+// Names returns the registered provider names in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
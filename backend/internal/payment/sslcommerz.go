@@ -0,0 +1,256 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSLCommerzProviderName is the name of the SSLCommerz provider
+const SSLCommerzProviderName = "sslcommerz"
+
+const (
+	sslcommerzSandboxURL = "https://sandbox.sslcommerz.com"
+	sslcommerzLiveURL    = "https://securepay.sslcommerz.com"
+)
+
+// SSLCommerzConfig holds SSLCommerz store credentials
+type SSLCommerzConfig struct {
+	StoreID       string
+	StorePassword string
+	Sandbox       bool
+	BaseURL       string       // Overrides the sandbox/live URL, for tests
+	HTTPClient    *http.Client // Defaults to a client with a 30s timeout
+}
+
+// SSLCommerzProvider takes card, mobile banking and internet banking payments through SSLCommerz
+type SSLCommerzProvider struct {
+	config  SSLCommerzConfig
+	baseURL string
+	client  *http.Client
+}
+
+// NewSSLCommerzProvider creates an SSLCommerz provider
+func NewSSLCommerzProvider(config SSLCommerzConfig) *SSLCommerzProvider {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = sslcommerzLiveURL
+		if config.Sandbox {
+			baseURL = sslcommerzSandboxURL
+		}
+	}
+	return &SSLCommerzProvider{
+		config:  config,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  httpClientOrDefault(config.HTTPClient),
+	}
+}
+
+// Name returns the provider name
+func (p *SSLCommerzProvider) Name() string {
+	return SSLCommerzProviderName
+}
+
+// sslcommerzSession is the response of the session API
+type sslcommerzSession struct {
+	Status         string `json:"status"`
+	FailedReason   string `json:"failedreason"`
+	SessionKey     string `json:"sessionkey"`
+	GatewayPageURL string `json:"GatewayPageURL"`
+}
+
+// InitiateCheckout creates a gateway session. Success, failure and cancellation all return to ReturnURL
+// since the outcome is verified with the validation API either way.
+func (p *SSLCommerzProvider) InitiateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	form := url.Values{}
+	form.Set("store_id", p.config.StoreID)
+	form.Set("store_passwd", p.config.StorePassword)
+	form.Set("total_amount", formatAmount(req.Amount))
+	form.Set("currency", req.Currency)
+	form.Set("tran_id", req.TransactionID)
+	form.Set("success_url", req.ReturnURL)
+	form.Set("fail_url", req.ReturnURL)
+	form.Set("cancel_url", req.ReturnURL)
+	if req.NotifyURL != "" {
+		form.Set("ipn_url", req.NotifyURL)
+	}
+	form.Set("cus_name", req.CustomerName)
+	form.Set("cus_email", req.CustomerEmail)
+	form.Set("cus_phone", req.CustomerPhone)
+	form.Set("cus_add1", "N/A")
+	form.Set("cus_city", "Dhaka")
+	form.Set("cus_country", "Bangladesh")
+	form.Set("shipping_method", "NO")
+	form.Set("product_name", req.Description)
+	form.Set("product_category", "Education")
+	form.Set("product_profile", "non-physical-goods")
+
+	var session sslcommerzSession
+	_, err := doJSON(ctx, p.client, http.MethodPost, p.baseURL+"/gwprocess/v4/api.php", strings.NewReader(form.Encode()),
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, &session)
+	if err != nil {
+		return nil, fmt.Errorf("sslcommerz session: %w", err)
+	}
+	if session.Status != "SUCCESS" || session.GatewayPageURL == "" {
+		return nil, fmt.Errorf("sslcommerz session rejected: %s", session.FailedReason)
+	}
+
+	return &Checkout{ProviderReference: session.SessionKey, RedirectURL: session.GatewayPageURL}, nil
+}
+
+// sslcommerzTransaction is one payment attempt reported by the transaction query API
+type sslcommerzTransaction struct {
+	ValID      string `json:"val_id"`
+	Status     string `json:"status"`
+	TranDate   string `json:"tran_date"`
+	TranID     string `json:"tran_id"`
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	BankTranID string `json:"bank_tran_id"`
+	Error      string `json:"error"`
+}
+
+// sslcommerzQuery is the response of the transaction query API
+type sslcommerzQuery struct {
+	APIConnect string                  `json:"APIConnect"`
+	Found      int                     `json:"no_of_trans_found"`
+	Element    []sslcommerzTransaction `json:"element"`
+}
+
+// Verify queries every attempt made for the transaction, a valid one means the payment succeeded
+func (p *SSLCommerzProvider) Verify(ctx context.Context, transactionID, providerReference string) (*Verification, error) {
+	query := url.Values{}
+	query.Set("tran_id", transactionID)
+	query.Set("store_id", p.config.StoreID)
+	query.Set("store_passwd", p.config.StorePassword)
+	query.Set("format", "json")
+
+	var result sslcommerzQuery
+	raw, err := doJSON(ctx, p.client, http.MethodGet, p.baseURL+"/validator/api/merchantTransIDvalidationAPI.php?"+query.Encode(), nil, nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("sslcommerz transaction query: %w", err)
+	}
+	if result.APIConnect != "DONE" {
+		return nil, fmt.Errorf("sslcommerz transaction query failed: %s", result.APIConnect)
+	}
+
+	verification := &Verification{
+		TransactionID:     transactionID,
+		ProviderReference: providerReference,
+		Status:            StatusPending,
+		Raw:               raw,
+	}
+	if len(result.Element) == 0 {
+		return verification, nil
+	}
+
+	// Each retry at the gateway is an attempt, the last one speaks for the payment unless one succeeded
+	attempt := result.Element[len(result.Element)-1]
+	for _, element := range result.Element {
+		if element.Status == "VALID" || element.Status == "VALIDATED" {
+			attempt = element
+			break
+		}
+	}
+
+	verification.ProviderTransactionID = attempt.BankTranID
+	verification.Currency = attempt.Currency
+	verification.Amount, _ = strconv.ParseFloat(attempt.Amount, 64)
+	switch attempt.Status {
+	case "VALID", "VALIDATED":
+		verification.Status = StatusPaid
+		if paidAt, err := time.ParseInLocation("2006-01-02 15:04:05", attempt.TranDate, dhakaTime); err == nil {
+			paidAt = paidAt.UTC()
+			verification.PaidAt = &paidAt
+		}
+	case "FAILED", "EXPIRED":
+		verification.Status = StatusFailed
+		verification.FailureReason = attempt.Error
+	case "CANCELLED":
+		verification.Status = StatusCancelled
+	}
+	return verification, nil
+}
+
+// ParseCallback reads the tran_id SSLCommerz posts to the return and IPN URLs
+func (p *SSLCommerzProvider) ParseCallback(values url.Values) (*Callback, error) {
+	transactionID := values.Get("tran_id")
+	if transactionID == "" {
+		return nil, fmt.Errorf("sslcommerz callback has no tran_id")
+	}
+	return &Callback{TransactionID: transactionID, ProviderReference: values.Get("sessionkey")}, nil
+}
+
+// sslcommerzRefund is the response of the refund API
+type sslcommerzRefund struct {
+	APIConnect  string `json:"APIConnect"`
+	Status      string `json:"status"`
+	RefundRefID string `json:"refund_ref_id"`
+	ErrorReason string `json:"errorReason"`
+}
+
+// Refund requests a full or partial refund of a paid transaction
+func (p *SSLCommerzProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	query := url.Values{}
+	query.Set("bank_tran_id", req.ProviderTransactionID)
+	query.Set("refund_amount", formatAmount(req.Amount))
+	query.Set("refund_remarks", req.Reason)
+	query.Set("store_id", p.config.StoreID)
+	query.Set("store_passwd", p.config.StorePassword)
+	query.Set("format", "json")
+
+	var result sslcommerzRefund
+	raw, err := doJSON(ctx, p.client, http.MethodGet, p.baseURL+"/validator/api/merchantTransIDvalidationAPI.php?"+query.Encode(), nil, nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("sslcommerz refund: %w", err)
+	}
+	if result.APIConnect != "DONE" {
+		return nil, fmt.Errorf("%w: sslcommerz refund failed: %s", ErrRefundDeclined, result.APIConnect)
+	}
+
+	switch result.Status {
+	case "success":
+		return &RefundResult{RefundReference: result.RefundRefID, Status: StatusPaid, Raw: raw}, nil
+	case "processing":
+		return &RefundResult{RefundReference: result.RefundRefID, Status: StatusPending, Raw: raw}, nil
+	default:
+		return nil, fmt.Errorf("%w: sslcommerz refund rejected: %s", ErrRefundDeclined, result.ErrorReason)
+	}
+}
+
+// RefundStatus queries a refund by its reference. SSLCommerz cannot look a refund up without it.
+func (p *SSLCommerzProvider) RefundStatus(ctx context.Context, req RefundRequest, refundReference string) (*RefundResult, error) {
+	if refundReference == "" {
+		return nil, fmt.Errorf("sslcommerz refund status: refund reference is required")
+	}
+
+	query := url.Values{}
+	query.Set("refund_ref_id", refundReference)
+	query.Set("store_id", p.config.StoreID)
+	query.Set("store_passwd", p.config.StorePassword)
+	query.Set("format", "json")
+
+	var result sslcommerzRefund
+	raw, err := doJSON(ctx, p.client, http.MethodGet, p.baseURL+"/validator/api/merchantTransIDvalidationAPI.php?"+query.Encode(), nil, nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("sslcommerz refund status: %w", err)
+	}
+	if result.APIConnect != "DONE" {
+		return nil, fmt.Errorf("sslcommerz refund status failed: %s", result.APIConnect)
+	}
+
+	switch result.Status {
+	case "refunded":
+		return &RefundResult{RefundReference: refundReference, Status: StatusPaid, Raw: raw}, nil
+	case "processing":
+		return &RefundResult{RefundReference: refundReference, Status: StatusPending, Raw: raw}, nil
+	case "cancelled":
+		return &RefundResult{RefundReference: refundReference, Status: StatusFailed, Raw: raw}, nil
+	default:
+		return nil, fmt.Errorf("%w: sslcommerz refund %s: %s", ErrRefundNotFound, refundReference, result.ErrorReason)
+	}
+}
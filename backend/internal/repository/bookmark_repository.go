@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrBookmarkNotFound is returned when the user has not bookmarked the question
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// BookmarkFilter narrows a user's bookmarks to a subject or system
+type BookmarkFilter struct {
+	SubjectID uint
+	SystemID  uint
+}
+
+// BookmarkRepository stores the questions users bookmark and their notes
+type BookmarkRepository interface {
+	GetBookmarks(userID uint, filter BookmarkFilter) ([]models.QuestionBookmark, error)
+	GetBookmark(userID uint, questionID uint) (*models.QuestionBookmark, error)
+	SaveBookmark(bookmark *models.QuestionBookmark) error
+	DeleteBookmark(userID uint, questionID uint) error
+}
+
+// bookmarkRepository implements BookmarkRepository
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+// NewBookmarkRepository creates a new bookmark repository
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{
+		db: db,
+	}
+}
+
+// GetBookmarks returns the user's bookmarks, newest first, with their questions, systems and subjects.
+// Questions removed from the bank are left out.
+func (r *bookmarkRepository) GetBookmarks(userID uint, filter BookmarkFilter) ([]models.QuestionBookmark, error) {
+	query := r.db.Model(&models.QuestionBookmark{}).
+		Joins("JOIN questions ON questions.id = question_bookmarks.question_id AND questions.deleted_at IS NULL").
+		Where("question_bookmarks.user_id = ?", userID)
+
+	if filter.SystemID != 0 {
+		query = query.Where("questions.system_id = ?", filter.SystemID)
+	}
+	if filter.SubjectID != 0 {
+		query = query.Where("questions.system_id IN (?)", r.db.Model(&models.System{}).Select("id").Where("subject_id = ?", filter.SubjectID))
+	}
+
+	var bookmarks []models.QuestionBookmark
+	err := query.Preload("Question.System.Subject").
+		Order("question_bookmarks.updated_at DESC, question_bookmarks.id DESC").
+		Find(&bookmarks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// GetBookmark returns the user's bookmark for a question with its question, system and subject
+func (r *bookmarkRepository) GetBookmark(userID uint, questionID uint) (*models.QuestionBookmark, error) {
+	var bookmark models.QuestionBookmark
+	err := r.db.Preload("Question.System.Subject").
+		Where("user_id = ? AND question_id = ?", userID, questionID).
+		First(&bookmark).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrBookmarkNotFound
+		}
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+	return &bookmark, nil
+}
+
+// SaveBookmark creates a new bookmark or updates an existing one
+func (r *bookmarkRepository) SaveBookmark(bookmark *models.QuestionBookmark) error {
+	if err := r.db.Omit("Question").Save(bookmark).Error; err != nil {
+		return fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	return nil
+}
+
+// DeleteBookmark removes the user's bookmark for a question
+func (r *bookmarkRepository) DeleteBookmark(userID uint, questionID uint) error {
+	result := r.db.Where("user_id = ? AND question_id = ?", userID, questionID).Delete(&models.QuestionBookmark{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
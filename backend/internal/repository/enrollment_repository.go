@@ -17,8 +17,19 @@ var (
 	ErrCouponInvalid          = errors.New("coupon is invalid")
 	ErrCouponExpired          = errors.New("coupon has expired")
 	ErrCouponExhausted        = errors.New("coupon usage limit exceeded")
+	ErrTrialAlreadyExtended   = errors.New("trial has already been extended")
+	ErrRenewalNotFound        = errors.New("renewal not found")
+	ErrEnrollmentNotActive    = errors.New("enrollment is not active")
+	ErrRenewalNotCompleted    = errors.New("renewal is not completed")
 )
 
+// EnrollmentCancellation is who cancelled an enrollment and why, and whether its payment was refunded
+type EnrollmentCancellation struct {
+	CancelledBy uint
+	Reason      string
+	Refunded    bool
+}
+
 // EnrollmentRepository handles enrollment data operations
 type EnrollmentRepository interface {
 	// Enrollment CRUD
@@ -27,6 +38,20 @@ type EnrollmentRepository interface {
 	GetUserEnrollments(userID uint) ([]models.UserPackageEnrollment, error)
 	GetActiveEnrollment(userID, packageID uint) (*models.UserPackageEnrollment, error)
 
+	// Trial operations
+	HasUsedTrial(userID, packageID uint) (bool, error)
+	ExtendTrial(enrollmentID uint, trialExpiresAt time.Time) error
+
+	// Renewal operations
+	CreateRenewal(renewal *models.EnrollmentRenewal) error
+	GetPendingRenewal(enrollmentID uint) (*models.EnrollmentRenewal, error)
+	CompleteRenewal(renewalID uint, paymentReference *string, renewedAt time.Time) (*models.EnrollmentRenewal, error)
+	GetRenewalHistory(enrollmentID uint) ([]models.EnrollmentRenewal, error)
+
+	// Cancellation
+	CancelEnrollment(enrollmentID uint, cancellation EnrollmentCancellation) error
+	RefundRenewal(renewalID uint) error
+
 	// Package operations
 	GetPackageByID(packageID uint) (*models.Package, error)
 
@@ -41,6 +66,7 @@ type EnrollmentRepository interface {
 
 	// Phase 2: Enrollment validation
 	IsUserEnrolledInPackage(userID, packageID uint) (bool, error)
+	GetPackageEnrollment(userID, packageID uint) (*models.UserPackageEnrollment, error)
 }
 
 // enrollmentRepository implements EnrollmentRepository
@@ -115,6 +141,264 @@ func (r *enrollmentRepository) GetActiveEnrollment(userID, packageID uint) (*mod
 	return &enrollment, nil
 }
 
+// HasUsedTrial checks if user ever started a trial of package, deleted and upgraded enrollments included
+func (r *enrollmentRepository) HasUsedTrial(userID, packageID uint) (bool, error) {
+	var count int64
+	err := r.getDB().Unscoped().Model(&models.UserPackageEnrollment{}).
+		Where("user_id = ? AND package_id = ?", userID, packageID).
+		Where("is_trial_used = ? OR enrollment_type IN ?", true, []models.EnrollmentType{models.EnrollmentTypeTrial, models.EnrollmentTypeUpgrade}).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check trial usage: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ExtendTrial moves the end of a trial that was never extended, a trial whose payment status expired
+// becomes payable again
+func (r *enrollmentRepository) ExtendTrial(enrollmentID uint, trialExpiresAt time.Time) error {
+	result := r.getDB().Model(&models.UserPackageEnrollment{}).
+		Where("id = ? AND enrollment_type = ? AND trial_extended_at IS NULL", enrollmentID, models.EnrollmentTypeTrial).
+		Updates(map[string]interface{}{
+			"trial_expires_at":  trialExpiresAt,
+			"expires_at":        trialExpiresAt,
+			"trial_extended_at": time.Now(),
+			"payment_status":    gorm.Expr("CASE WHEN payment_status = ? THEN ? ELSE payment_status END", models.PaymentStatusExpired, models.PaymentStatusPending),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to extend trial: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTrialAlreadyExtended
+	}
+	return nil
+}
+
+// CreateRenewal stores a new renewal of an enrollment
+func (r *enrollmentRepository) CreateRenewal(renewal *models.EnrollmentRenewal) error {
+	if err := r.getDB().Omit("Enrollment").Create(renewal).Error; err != nil {
+		return fmt.Errorf("failed to create renewal: %w", err)
+	}
+	return nil
+}
+
+// GetPendingRenewal returns the renewal of an enrollment still waiting for its payment, or nil if none
+func (r *enrollmentRepository) GetPendingRenewal(enrollmentID uint) (*models.EnrollmentRenewal, error) {
+	var renewal models.EnrollmentRenewal
+	err := r.getDB().
+		Where("enrollment_id = ? AND status IN ?", enrollmentID, []models.RenewalStatus{models.RenewalStatusPending, models.RenewalStatusFailed}).
+		Order("created_at DESC").
+		First(&renewal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending renewal: %w", err)
+	}
+	return &renewal, nil
+}
+
+// CompleteRenewal extends the renewal's enrollment, see completeRenewal
+func (r *enrollmentRepository) CompleteRenewal(renewalID uint, paymentReference *string, renewedAt time.Time) (*models.EnrollmentRenewal, error) {
+	err := r.getDB().Transaction(func(tx *gorm.DB) error {
+		return completeRenewal(tx, renewalID, paymentReference, renewedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var renewal models.EnrollmentRenewal
+	if err := r.getDB().First(&renewal, renewalID).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload renewal: %w", err)
+	}
+	return &renewal, nil
+}
+
+// GetRenewalHistory returns the completed renewals of an enrollment, oldest period first
+func (r *enrollmentRepository) GetRenewalHistory(enrollmentID uint) ([]models.EnrollmentRenewal, error) {
+	var renewals []models.EnrollmentRenewal
+	err := r.getDB().
+		Where("enrollment_id = ? AND status = ?", enrollmentID, models.RenewalStatusCompleted).
+		Order("period_start ASC").
+		Find(&renewals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewal history: %w", err)
+	}
+	return renewals, nil
+}
+
+// completeRenewal adds the package's validity period to the renewal's enrollment and records the period on
+// the renewal. The period is computed when the renewal completes, so a renewal paid late still starts from
+// the enrollment's expiry or from the payment. Completing a renewal twice changes nothing.
+func completeRenewal(tx *gorm.DB, renewalID uint, paymentReference *string, renewedAt time.Time) error {
+	var renewal models.EnrollmentRenewal
+	if err := tx.Preload("Enrollment.Package").First(&renewal, renewalID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRenewalNotFound
+		}
+		return fmt.Errorf("failed to get renewal: %w", err)
+	}
+	enrollment := renewal.Enrollment
+	start, end := enrollment.Package.RenewalPeriod(enrollment.ExpiresAt, renewedAt)
+
+	// The status condition makes concurrent completions race for the row, only one of them extends
+	result := tx.Model(&models.EnrollmentRenewal{}).
+		Where("id = ? AND status IN ?", renewalID, []models.RenewalStatus{models.RenewalStatusPending, models.RenewalStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":              models.RenewalStatusCompleted,
+			"previous_expires_at": enrollment.ExpiresAt,
+			"period_start":        start,
+			"period_end":          end,
+			"payment_reference":   paymentReference,
+			"renewed_at":          renewedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete renewal: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	updates := map[string]interface{}{"expires_at": end}
+	if enrollment.PaymentStatus == models.PaymentStatusExpired {
+		updates["payment_status"] = models.PaymentStatusPaid
+		if renewal.Price == 0 && enrollment.EnrolledPrice == 0 {
+			updates["payment_status"] = models.PaymentStatusFree
+		}
+	}
+	if err := tx.Model(&models.UserPackageEnrollment{}).Where("id = ?", enrollment.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to extend enrollment: %w", err)
+	}
+	return nil
+}
+
+// CancelEnrollment deactivates an active enrollment in one database transaction, marking it REFUNDED when
+// its payment was refunded. The coupon use of the enrollment is given back and the package's enrollment
+// count goes down again. Returns ErrEnrollmentNotActive when the enrollment was already cancelled.
+func (r *enrollmentRepository) CancelEnrollment(enrollmentID uint, cancellation EnrollmentCancellation) error {
+	return r.getDB().Transaction(func(tx *gorm.DB) error {
+		var enrollment models.UserPackageEnrollment
+		if err := tx.First(&enrollment, enrollmentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEnrollmentNotFound
+			}
+			return fmt.Errorf("failed to get enrollment: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"is_active":           false,
+			"cancelled_at":        time.Now(),
+			"cancelled_by":        cancellation.CancelledBy,
+			"cancellation_reason": cancellation.Reason,
+		}
+		if cancellation.Refunded {
+			updates["payment_status"] = models.PaymentStatusRefunded
+		}
+
+		// The active condition makes concurrent cancellations race for the row, only one of them reverses
+		result := tx.Model(&models.UserPackageEnrollment{}).
+			Where("id = ? AND is_active = ?", enrollmentID, true).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to cancel enrollment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrEnrollmentNotActive
+		}
+
+		// Give the coupon use back, only if it was counted for this enrollment
+		if enrollment.CouponID != nil {
+			usage := tx.Where("enrollment_id = ? AND coupon_id = ?", enrollmentID, *enrollment.CouponID).Delete(&models.CouponUsage{})
+			if usage.Error != nil {
+				return fmt.Errorf("failed to reverse coupon usage: %w", usage.Error)
+			}
+			if usage.RowsAffected > 0 {
+				err := tx.Model(&models.Coupon{}).
+					Where("id = ? AND usage_count > 0", *enrollment.CouponID).
+					Update("usage_count", gorm.Expr("usage_count - 1")).Error
+				if err != nil {
+					return fmt.Errorf("failed to decrement coupon usage: %w", err)
+				}
+			}
+		}
+
+		err := tx.Model(&models.Package{}).
+			Where("id = ? AND enrollment_count > 0", enrollment.PackageID).
+			Update("enrollment_count", gorm.Expr("enrollment_count - 1")).Error
+		if err != nil {
+			return fmt.Errorf("failed to decrement package enrollment count: %w", err)
+		}
+		return nil
+	})
+}
+
+// RefundRenewal takes a refunded renewal's period off its enrollment again and marks the renewal REFUNDED, in
+// one database transaction. The enrollment stays active. When the renewal is the enrollment's latest, its
+// expiry before the renewal is restored; an earlier renewal's period is cut out, moving later renewals back
+// by its length. Returns ErrRenewalNotCompleted when the renewal was not completed or was already refunded.
+func (r *enrollmentRepository) RefundRenewal(renewalID uint) error {
+	return r.getDB().Transaction(func(tx *gorm.DB) error {
+		var renewal models.EnrollmentRenewal
+		if err := tx.Preload("Enrollment").First(&renewal, renewalID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRenewalNotFound
+			}
+			return fmt.Errorf("failed to get renewal: %w", err)
+		}
+
+		// The status condition makes concurrent refunds race for the row, only one of them takes the period off
+		result := tx.Model(&models.EnrollmentRenewal{}).
+			Where("id = ? AND status = ?", renewalID, models.RenewalStatusCompleted).
+			Update("status", models.RenewalStatusRefunded)
+		if result.Error != nil {
+			return fmt.Errorf("failed to refund renewal: %w", result.Error)
+		}
+		if result.RowsAffected == 0 || renewal.PeriodStart == nil || renewal.PeriodEnd == nil {
+			return ErrRenewalNotCompleted
+		}
+
+		enrollment := renewal.Enrollment
+		if enrollment.ExpiresAt == nil || !enrollment.ExpiresAt.After(*renewal.PeriodEnd) {
+			err := tx.Model(&models.UserPackageEnrollment{}).
+				Where("id = ?", enrollment.ID).
+				Update("expires_at", renewal.PreviousExpiresAt).Error
+			if err != nil {
+				return fmt.Errorf("failed to restore enrollment expiry: %w", err)
+			}
+			return nil
+		}
+
+		period := renewal.PeriodEnd.Sub(*renewal.PeriodStart)
+		var later []models.EnrollmentRenewal
+		err := tx.Where("enrollment_id = ? AND status = ? AND period_start >= ?",
+			enrollment.ID, models.RenewalStatusCompleted, *renewal.PeriodEnd).
+			Find(&later).Error
+		if err != nil {
+			return fmt.Errorf("failed to get later renewals: %w", err)
+		}
+		for _, next := range later {
+			updates := map[string]interface{}{
+				"period_start": next.PeriodStart.Add(-period),
+				"period_end":   next.PeriodEnd.Add(-period),
+			}
+			if next.PreviousExpiresAt != nil {
+				updates["previous_expires_at"] = next.PreviousExpiresAt.Add(-period)
+			}
+			if err := tx.Model(&models.EnrollmentRenewal{}).Where("id = ?", next.ID).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to move later renewal: %w", err)
+			}
+		}
+
+		err = tx.Model(&models.UserPackageEnrollment{}).
+			Where("id = ?", enrollment.ID).
+			Update("expires_at", enrollment.ExpiresAt.Add(-period)).Error
+		if err != nil {
+			return fmt.Errorf("failed to shorten enrollment: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetPackageByID retrieves package by ID
 func (r *enrollmentRepository) GetPackageByID(packageID uint) (*models.Package, error) {
 	var pkg models.Package
@@ -194,3 +478,19 @@ func (r *enrollmentRepository) IsUserEnrolledInPackage(userID, packageID uint) (
 	// Use the model's business logic to check if user can access content
 	return enrollment.CanAccessContent(), nil
 }
+
+// GetPackageEnrollment returns the user's current enrollment in a package, deactivated ones included so
+// access checks can tell why it no longer gives access. Returns nil when the user never enrolled.
+func (r *enrollmentRepository) GetPackageEnrollment(userID, packageID uint) (*models.UserPackageEnrollment, error) {
+	var enrollment models.UserPackageEnrollment
+	err := r.getDB().Where("user_id = ? AND package_id = ?", userID, packageID).
+		Order("is_active DESC, created_at DESC").
+		First(&enrollment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Never enrolled (not an error)
+		}
+		return nil, fmt.Errorf("failed to get package enrollment: %w", err)
+	}
+	return &enrollment, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ExamAdminRepository handles database operations for authoring exams
+type ExamAdminRepository interface {
+	GetExamByID(id uint) (*models.Exam, error)
+	SaveExamQuestions(exam *models.Exam, questionIDs []uint) error
+}
+
+// examAdminRepository implements ExamAdminRepository
+type examAdminRepository struct {
+	db *gorm.DB
+}
+
+// NewExamAdminRepository creates a new exam admin repository
+func NewExamAdminRepository(db *gorm.DB) ExamAdminRepository {
+	return &examAdminRepository{db: db}
+}
+
+// GetExamByID retrieves an exam by ID regardless of its status. Exams generated for practice sessions
+// belong to one student and are not managed by admins.
+func (r *examAdminRepository) GetExamByID(id uint) (*models.Exam, error) {
+	var exam models.Exam
+	err := r.db.Where("exam_type <> ?", models.ExamTypePractice).First(&exam, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExamNotFound
+		}
+		return nil, err
+	}
+	return &exam, nil
+}
+
+// SaveExamQuestions stores the generated question snapshot and bumps usage of every picked question
+func (r *examAdminRepository) SaveExamQuestions(exam *models.Exam, questionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(exam).Updates(map[string]interface{}{
+			"questions_data":  exam.QuestionsData,
+			"total_questions": exam.TotalQuestions,
+			"total_marks":     exam.TotalMarks,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, id := range questionIDs {
+			question := &models.Question{ID: id}
+			if err := question.IncrementUsage(tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -3,17 +3,25 @@ package repository
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/Mahfuz2811/medecole/backend/internal/cache"
 	"github.com/Mahfuz2811/medecole/backend/internal/logger"
 	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"sort"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// sessionStateGrace keeps a session's cached state past its time limit for as long as the cleanup can still
+// auto-submit the session and fold the state in. It covers the cleanup's grace period and the delay before it
+// abandons a session (2 and 30 minutes by default) with room to spare.
+const sessionStateGrace = time.Hour
+
 // ExamRepository handles database operations for exams
 type ExamRepository interface {
 	GetExamsByPackageSlug(packageSlug string, userID uint) ([]ExamWithUserData, error)
@@ -26,24 +34,42 @@ type ExamRepository interface {
 	GetCompletedSessionByID(sessionID string) (*SessionWithExamData, error)
 	GetSessionAnswers(sessionID string) (map[uint]string, error)
 	SyncSessionAnswers(sessionID string, answers map[uint]string) error
+	GetCheckedAnswers(sessionID string) (map[uint]string, error)
+	SaveCheckedAnswers(sessionID string, checked map[uint]string) error
+	GetFlaggedQuestions(sessionID string) ([]uint, error)
+	SyncFlaggedQuestions(sessionID string, questionIDs []uint) error
+	AppendAnswerChanges(sessionID string, changes []models.SessionAnswerChange) error
+	GetAnswerChanges(sessionID string) ([]models.SessionAnswerChange, error)
+	GetQuestionTimes(sessionID string) (map[uint]int, error)
+	SyncQuestionTimes(sessionID string, times map[uint]int) error
+	GetAnswerVersions(sessionID string) (map[uint]time.Time, error)
+	SyncAnswerVersions(sessionID string, versions map[uint]time.Time) error
+	WithSessionLock(sessionID string, fn func(repo ExamRepository) error) error
 	CompleteExamAttempt(attemptID uint, score float64, passed bool) error
 	CompleteExamAttemptWithAnswers(attemptID uint, score float64, passed bool, answersData string, correctAnswers int) error
 	GetAttemptBySessionAndUser(sessionID string, userID uint) (*models.UserExamAttempt, error)
 	MarkExpiredSessionsAsAbandoned(currentTime time.Time, gracePeriodSeconds int) (int64, error)
+	GetExpiredActiveSessions(currentTime time.Time, gracePeriodSeconds int, limit int) ([]SessionWithExamData, error)
+	AutoSubmitExamAttempt(attemptID uint, score float64, passed bool, answersData string, correctAnswers int) error
 
 	// Optimized methods for Phase 1 & 2
 	GetUserAttemptForExam(userID uint, examID uint) (*models.UserExamAttempt, error)
 	GetUserAttemptForExamInPackage(userID uint, examID uint, packageID uint) (*models.UserExamAttempt, error)
-	CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, deviceInfo map[string]string) (*models.UserExamAttempt, error)
+	CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, attemptNumber int, deviceInfo map[string]string) (*models.UserExamAttempt, error)
 	GetPackageIDForExam(examID uint) (uint, error)
+
+	// Leaderboards (best finished attempt per user, within a package)
+	GetLeaderboard(examID uint, packageID uint, limit int) ([]LeaderboardEntry, int64, error)
+	GetLeaderboardRank(examID uint, packageID uint, userID uint) (*LeaderboardRank, error)
 }
 
 // ExamWithUserData represents exam data combined with user attempt information
 type ExamWithUserData struct {
 	models.Exam
 	HasAttempted   bool   `json:"has_attempted"`
-	SortOrder      int    `json:"sort_order"`      // From package_exams table
-	ComputedStatus string `json:"computed_status"` // Computed based on scheduling
+	SortOrder      int    `json:"sort_order"`       // From package_exams table
+	IsTrialVisible bool   `json:"is_trial_visible"` // From package_exams table, open to students on a trial
+	ComputedStatus string `json:"computed_status"`  // Computed based on scheduling
 	// User attempt data
 	UserAttemptID          *uint      `json:"user_attempt_id,omitempty"`
 	UserAttemptStatus      *string    `json:"user_attempt_status,omitempty"` // STARTED, COMPLETED, AUTO_SUBMITTED, ABANDONED
@@ -54,6 +80,36 @@ type ExamWithUserData struct {
 	AttemptPassed          *bool      `json:"attempt_passed,omitempty"`
 	ActualTimeSpent        *int       `json:"actual_time_spent,omitempty"`
 	SessionID              *string    `json:"session_id,omitempty"`
+	UserAttemptNumber      *int       `json:"user_attempt_number,omitempty"`
+	// Retake data
+	EffectiveScorePolicy models.AttemptScorePolicy `json:"effective_score_policy"`      // Exam override or package policy
+	Attempts             []models.UserExamAttempt  `json:"attempts,omitempty" gorm:"-"` // All attempts in this package, oldest first
+}
+
+// AttemptsUsed returns how many attempts count against the exam's MaxAttempts
+func (e *ExamWithUserData) AttemptsUsed() int {
+	return len(e.Attempts)
+}
+
+// ActiveAttempt returns the in-progress attempt if one exists
+func (e *ExamWithUserData) ActiveAttempt() *models.UserExamAttempt {
+	for i := range e.Attempts {
+		if e.Attempts[i].IsInProgress() {
+			return &e.Attempts[i]
+		}
+	}
+	return nil
+}
+
+// NextAttemptNumber returns the number the next attempt should use
+func (e *ExamWithUserData) NextAttemptNumber() int {
+	next := 1
+	for _, attempt := range e.Attempts {
+		if attempt.AttemptNumber >= next {
+			next = attempt.AttemptNumber + 1
+		}
+	}
+	return next
 }
 
 // PackageWithExamsData represents package data with its exams and user data
@@ -70,15 +126,18 @@ type SessionWithExamData struct {
 
 // examRepository implements ExamRepository
 type examRepository struct {
-	db    *gorm.DB
-	cache cache.CacheInterface
+	db          *gorm.DB
+	cache       cache.CacheInterface
+	leaderboard LeaderboardRepository
+	afterCommit *[]func() // Cache updates held back until a session lock's transaction commits
 }
 
 // NewExamRepository creates a new exam repository
 func NewExamRepository(db *gorm.DB, cache cache.CacheInterface) ExamRepository {
 	return &examRepository{
-		db:    db,
-		cache: cache,
+		db:          db,
+		cache:       cache,
+		leaderboard: NewLeaderboardRepository(db, cache),
 	}
 }
 
@@ -95,6 +154,9 @@ func (r *examRepository) GetExamsByPackageSlug(packageSlug string, userID uint)
 			e.duration_minutes,
 			e.total_marks,
 			e.passing_score,
+			e.max_attempts,
+			e.attempt_score_policy,
+			COALESCE(e.attempt_score_policy, p.attempt_score_policy, 'BEST') as effective_score_policy,
 			e.scheduled_start_date,
 			e.scheduled_end_date,
 			e.attempt_count,
@@ -108,6 +170,7 @@ func (r *examRepository) GetExamsByPackageSlug(packageSlug string, userID uint)
 				ELSE 'AVAILABLE'
 			END as computed_status,
 			pe.sort_order,
+			pe.is_trial_visible,
 			CASE WHEN uea.user_id IS NOT NULL THEN true ELSE false END as has_attempted,
 			-- User attempt details
 			uea.id as user_attempt_id,
@@ -118,11 +181,16 @@ func (r *examRepository) GetExamsByPackageSlug(packageSlug string, userID uint)
 			uea.correct_answers as attempt_correct_answers,
 			uea.is_passed as attempt_passed,
 			uea.actual_time_spent as actual_time_spent,
-			uea.session_id as session_id
+			uea.session_id as session_id,
+			uea.attempt_number as user_attempt_number
 		FROM exams e
 		INNER JOIN package_exams pe ON e.id = pe.exam_id
 		INNER JOIN packages p ON pe.package_id = p.id
-		LEFT JOIN user_exam_attempts uea ON e.id = uea.exam_id AND uea.user_id = ? AND uea.package_id = p.id
+		-- Only the latest attempt, the full history is loaded separately
+		LEFT JOIN user_exam_attempts uea ON uea.id = (
+			SELECT MAX(latest.id) FROM user_exam_attempts latest
+			WHERE latest.exam_id = e.id AND latest.user_id = ? AND latest.package_id = p.id AND latest.deleted_at IS NULL
+		)
 		WHERE p.slug = ?
 			AND e.is_active = true 
 			AND pe.is_active = true
@@ -138,6 +206,12 @@ func (r *examRepository) GetExamsByPackageSlug(packageSlug string, userID uint)
 	return examsWithUserData, nil
 }
 
+// attemptHistoryColumns are the user_exam_attempts columns read for an exam's attempt history
+var attemptHistoryColumns = []string{
+	"id", "exam_id", "attempt_number", "status", "session_id", "started_at", "completed_at",
+	"actual_time_spent", "score", "correct_answers", "is_passed",
+}
+
 // GetPackageWithExamsBySlug retrieves package data along with all exams for a specific package by package slug
 func (r *examRepository) GetPackageWithExamsBySlug(packageSlug string, userID uint) (*PackageWithExamsData, error) {
 	// First get the package data
@@ -152,6 +226,25 @@ func (r *examRepository) GetPackageWithExamsBySlug(packageSlug string, userID ui
 		return nil, fmt.Errorf("failed to fetch exams: %w", err)
 	}
 
+	// Attach the user's attempt history in this package context (1 query for all exams, practice sessions excluded).
+	// Only the columns the retake rules and the attempt history need, answers_data grows with every attempt.
+	if userID != 0 && len(exams) > 0 {
+		var attempts []models.UserExamAttempt
+		if err := r.db.Select(attemptHistoryColumns).Where("user_id = ? AND package_id = ? AND is_practice = ?", userID, pkg.ID, false).
+			Order("attempt_number ASC").
+			Find(&attempts).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch attempt history: %w", err)
+		}
+
+		attemptsByExam := make(map[uint][]models.UserExamAttempt)
+		for _, attempt := range attempts {
+			attemptsByExam[attempt.ExamID] = append(attemptsByExam[attempt.ExamID], attempt)
+		}
+		for i := range exams {
+			exams[i].Attempts = attemptsByExam[exams[i].ID]
+		}
+	}
+
 	return &PackageWithExamsData{
 		Package: pkg,
 		Exams:   exams,
@@ -203,6 +296,16 @@ func (r *examRepository) CreateExamAttempt(userID uint, examID uint, packageID u
 		return nil, fmt.Errorf("failed to fetch exam for attempt: %w", err)
 	}
 
+	// Number the attempt after any previous ones in this package context
+	var lastAttemptNumber int
+	if err := r.db.Model(&models.UserExamAttempt{}).
+		Where("user_id = ? AND exam_id = ? AND package_id = ?", userID, examID, packageID).
+		Select("COALESCE(MAX(attempt_number), 0)").
+		Scan(&lastAttemptNumber).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch last attempt number: %w", err)
+	}
+	attemptNumber := lastAttemptNumber + 1
+
 	// Generate session ID
 	sessionID := r.generateSessionID()
 	now := time.Now()
@@ -212,17 +315,19 @@ func (r *examRepository) CreateExamAttempt(userID uint, examID uint, packageID u
 		UserID:           userID,
 		ExamID:           examID,
 		PackageID:        packageID,
-		AttemptNumber:    1,
+		AttemptNumber:    attemptNumber,
+		IsPractice:       exam.IsPractice(),
 		Status:           models.AttemptStatusStarted,
 		StartedAt:        now,
 		SessionID:        &sessionID,
 		LastActivityAt:   &now,
-		TimeLimitSeconds: exam.DurationMinutes * 60,
+		TimeLimitSeconds: exam.SessionTimeLimitSeconds(now),
 		TotalQuestions:   exam.TotalQuestions,
 		PassingScore:     exam.PassingScore,
 		AnswersData:      "{}",
 		IsScored:         false,
 	}
+	attempt.SnapshotShuffle(&exam)
 
 	if err := r.db.Create(attempt).Error; err != nil {
 		return nil, fmt.Errorf("failed to create exam attempt: %w", err)
@@ -316,36 +421,650 @@ func (r *examRepository) GetCompletedSessionByID(sessionID string) (*SessionWith
 	return sessionData, nil
 }
 
-// SyncSessionAnswers stores user answers in cache during exam session
+// SyncSessionAnswers stores user answers during an exam session. Answers are written through to
+// user_session_answers first so they survive a cache restart, the cache is a copy for fast reads.
 func (r *examRepository) SyncSessionAnswers(sessionID string, answers map[uint]string) error {
+	if err := r.persistSessionAnswers(sessionID, answers); err != nil {
+		return fmt.Errorf("failed to persist session answers: %w", err)
+	}
+
 	// Create cache key for session answers
 	cacheKey := fmt.Sprintf("exam_session:%s:answers", sessionID)
 
-	// Store answers in cache with 2 hour TTL (should cover most exam durations)
-	err := r.cache.Set(cacheKey, answers, 2*time.Hour)
+	// Store answers in cache until the session can no longer be submitted
+	ttl, err := r.sessionStateTTL(sessionID)
 	if err != nil {
+		_ = r.cache.Delete(cacheKey)
+		return err
+	}
+	err = r.cache.Set(cacheKey, answers, ttl)
+	if err != nil {
+		// Drop the stale copy so the next read recovers the answers from the database
+		_ = r.cache.Delete(cacheKey)
 		return fmt.Errorf("failed to sync session answers to cache: %w", err)
 	}
 
 	return nil
 }
 
-// GetSessionAnswers retrieves user answers from cache for a given session
+// GetSessionAnswers retrieves user answers for a given session from the cache, recovering them from
+// user_session_answers when the cache has lost them
 func (r *examRepository) GetSessionAnswers(sessionID string) (map[uint]string, error) {
 	// Create cache key for session answers
 	cacheKey := fmt.Sprintf("exam_session:%s:answers", sessionID)
 
 	// Retrieve answers from cache
 	var answers map[uint]string
-	err := r.cache.Get(cacheKey, &answers)
+	if err := r.cache.Get(cacheKey, &answers); err == nil {
+		return answers, nil
+	}
+
+	// Not in cache - either nothing is answered yet or the cache was lost
+	saved, err := r.getPersistedSessionAnswers(sessionID)
 	if err != nil {
-		// If not found in cache, return empty map (not an error - user might not have answered yet)
-		return make(map[uint]string), nil
+		return nil, fmt.Errorf("failed to recover session answers: %w", err)
+	}
+
+	answers = make(map[uint]string, len(saved))
+	for _, answer := range saved {
+		if answer.SelectedOption != "" {
+			answers[answer.QuestionID] = answer.SelectedOption
+		}
+	}
+	if len(answers) > 0 {
+		r.restoreSessionState(sessionID, cacheKey, answers)
 	}
 
 	return answers, nil
 }
 
+// sessionStateTTL returns how long a session's cached state has to live, derived from its attempt's time limit
+func (r *examRepository) sessionStateTTL(sessionID string) (time.Duration, error) {
+	var attempt models.UserExamAttempt
+	err := r.db.Select("started_at", "time_limit_seconds").Where("session_id = ?", sessionID).First(&attempt).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrAttemptNotFound
+		}
+		return 0, fmt.Errorf("failed to get session time limit: %w", err)
+	}
+	return attempt.SessionStateTTL(sessionStateGrace), nil
+}
+
+// restoreSessionState puts state recovered from the database back in the cache. A failure only costs the
+// next read another recovery, so it is logged.
+func (r *examRepository) restoreSessionState(sessionID string, cacheKey string, value interface{}) {
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err == nil {
+		err = r.cache.Set(cacheKey, value, ttl)
+	}
+	if err != nil {
+		logger.WithService("ExamRepository").WithFields(logrus.Fields{
+			"session_id": sessionID,
+			"cache_key":  cacheKey,
+		}).WithError(err).Warn("Failed to restore recovered session state to cache")
+	}
+}
+
+// persistSessionAnswers makes user_session_answers match the session's answers. Only answers that changed
+// are written, so answered_at is when the server last received a change to the question. Cleared answers
+// keep their row with an empty option. Answers checked in tutor mode are locked and left as they are.
+func (r *examRepository) persistSessionAnswers(sessionID string, answers map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt, err := startedAttempt(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		var saved []models.UserSessionAnswer
+		if err := tx.Where("attempt_id = ?", attempt.ID).Find(&saved).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		existing := make(map[uint]models.UserSessionAnswer, len(saved))
+		cleared := make([]uint, 0)
+		for _, answer := range saved {
+			existing[answer.QuestionID] = answer
+			if _, ok := answers[answer.QuestionID]; !ok && answer.SelectedOption != "" && answer.CheckedAt == nil {
+				cleared = append(cleared, answer.ID)
+			}
+		}
+		if len(cleared) > 0 {
+			err := tx.Model(&models.UserSessionAnswer{}).Where("id IN ?", cleared).Updates(map[string]interface{}{
+				"selected_option": "",
+				"answered_at":     now,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		created := make([]models.UserSessionAnswer, 0)
+		for questionID, option := range answers {
+			answer, ok := existing[questionID]
+			if !ok {
+				created = append(created, models.UserSessionAnswer{
+					AttemptID:      attempt.ID,
+					UserID:         attempt.UserID,
+					QuestionID:     questionID,
+					SelectedOption: option,
+					AnsweredAt:     now,
+				})
+				continue
+			}
+			if answer.SelectedOption == option || answer.CheckedAt != nil {
+				continue
+			}
+			err := tx.Model(&answer).Updates(map[string]interface{}{
+				"selected_option": option,
+				"answered_at":     now,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// persistCheckedAnswers writes tutor mode answer locks through to user_session_answers. A checked answer's
+// row is created or updated to the checked option; rows that are already locked do not change.
+func (r *examRepository) persistCheckedAnswers(sessionID string, checked map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt, err := startedAttempt(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		var saved []models.UserSessionAnswer
+		if err := tx.Where("attempt_id = ?", attempt.ID).Find(&saved).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint]models.UserSessionAnswer, len(saved))
+		for _, answer := range saved {
+			existing[answer.QuestionID] = answer
+		}
+
+		now := time.Now()
+		for questionID, option := range checked {
+			answer, ok := existing[questionID]
+			if !ok {
+				err := tx.Create(&models.UserSessionAnswer{
+					AttemptID:      attempt.ID,
+					UserID:         attempt.UserID,
+					QuestionID:     questionID,
+					SelectedOption: option,
+					AnsweredAt:     now,
+					CheckedAt:      &now,
+				}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if answer.CheckedAt != nil {
+				continue
+			}
+			updates := map[string]interface{}{"checked_at": now}
+			if answer.SelectedOption != option {
+				updates["selected_option"] = option
+				updates["answered_at"] = now
+			}
+			if err := tx.Model(&answer).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// startedAttempt returns the ID and owner of the in-progress attempt of a session
+func startedAttempt(tx *gorm.DB, sessionID string) (*models.UserExamAttempt, error) {
+	var attempt models.UserExamAttempt
+	err := tx.Select("id", "user_id").
+		Where("session_id = ? AND status = ?", sessionID, models.AttemptStatusStarted).
+		First(&attempt).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAttemptNotFound
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// getPersistedSessionAnswers loads the answers written through for a session
+func (r *examRepository) getPersistedSessionAnswers(sessionID string) ([]models.UserSessionAnswer, error) {
+	var saved []models.UserSessionAnswer
+	err := r.db.Model(&models.UserSessionAnswer{}).
+		Joins("JOIN user_exam_attempts ON user_exam_attempts.id = user_session_answers.attempt_id").
+		Where("user_exam_attempts.session_id = ?", sessionID).
+		Find(&saved).Error
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// deleteSessionAnswers removes the answers written through for attempts that are no longer in progress.
+// A failure only leaves rows behind, so it is logged instead of failing the submission.
+func (r *examRepository) deleteSessionAnswers(attemptIDs ...uint) {
+	if len(attemptIDs) == 0 {
+		return
+	}
+	if err := r.db.Unscoped().Where("attempt_id IN ?", attemptIDs).Delete(&models.UserSessionAnswer{}).Error; err != nil {
+		logger.WithService("ExamRepository").WithField("attempt_ids", attemptIDs).WithError(err).
+			Warn("Failed to clean up session answers")
+	}
+}
+
+// GetCheckedAnswers retrieves the answers a tutor mode session has checked, keyed by question ID, recovering
+// them from user_session_answers when the cache has lost them
+func (r *examRepository) GetCheckedAnswers(sessionID string) (map[uint]string, error) {
+	cacheKey := fmt.Sprintf("exam_session:%s:checked", sessionID)
+
+	var checked map[uint]string
+	if err := r.cache.Get(cacheKey, &checked); err == nil {
+		return checked, nil
+	}
+
+	// Not in cache - either nothing is checked yet or the cache was lost
+	saved, err := r.getPersistedSessionAnswers(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover checked answers: %w", err)
+	}
+
+	checked = make(map[uint]string)
+	for _, answer := range saved {
+		if answer.CheckedAt != nil {
+			checked[answer.QuestionID] = answer.SelectedOption
+		}
+	}
+	if len(checked) > 0 {
+		r.restoreSessionState(sessionID, cacheKey, checked)
+	}
+
+	return checked, nil
+}
+
+// SaveCheckedAnswers stores the checked answers of a tutor mode session, which stay locked until submission.
+// Like the answers, the locks are written through to user_session_answers before the cache.
+func (r *examRepository) SaveCheckedAnswers(sessionID string, checked map[uint]string) error {
+	if err := r.persistCheckedAnswers(sessionID, checked); err != nil {
+		return fmt.Errorf("failed to persist checked answers: %w", err)
+	}
+
+	cacheKey := fmt.Sprintf("exam_session:%s:checked", sessionID)
+
+	// Same TTL as the session answers they lock
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err != nil {
+		_ = r.cache.Delete(cacheKey)
+		return err
+	}
+	if err := r.cache.Set(cacheKey, checked, ttl); err != nil {
+		// Drop the stale copy so the next read recovers the locks from the database
+		_ = r.cache.Delete(cacheKey)
+		return fmt.Errorf("failed to save checked answers to cache: %w", err)
+	}
+
+	return nil
+}
+
+// GetFlaggedQuestions retrieves the questions flagged to revisit during a session, recovering them from
+// user_session_answers when the cache has lost them
+func (r *examRepository) GetFlaggedQuestions(sessionID string) ([]uint, error) {
+	cacheKey := fmt.Sprintf("exam_session:%s:flags", sessionID)
+
+	var questionIDs []uint
+	if err := r.cache.Get(cacheKey, &questionIDs); err == nil {
+		return questionIDs, nil
+	}
+
+	// Not in cache - either nothing is flagged yet or the cache was lost
+	saved, err := r.getPersistedSessionAnswers(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover flagged questions: %w", err)
+	}
+
+	questionIDs = make([]uint, 0)
+	for _, answer := range saved {
+		if answer.Flagged {
+			questionIDs = append(questionIDs, answer.QuestionID)
+		}
+	}
+	sort.Slice(questionIDs, func(i, j int) bool { return questionIDs[i] < questionIDs[j] })
+	if len(questionIDs) > 0 {
+		r.restoreSessionState(sessionID, cacheKey, questionIDs)
+	}
+
+	return questionIDs, nil
+}
+
+// SyncFlaggedQuestions stores the questions flagged during a session, replacing the previous flags. Like the
+// answers, the flags are written through to user_session_answers before the cache.
+func (r *examRepository) SyncFlaggedQuestions(sessionID string, questionIDs []uint) error {
+	if err := r.persistFlaggedQuestions(sessionID, questionIDs); err != nil {
+		return fmt.Errorf("failed to persist flagged questions: %w", err)
+	}
+
+	cacheKey := fmt.Sprintf("exam_session:%s:flags", sessionID)
+
+	// Same TTL as the session answers
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err != nil {
+		_ = r.cache.Delete(cacheKey)
+		return err
+	}
+	if err := r.cache.Set(cacheKey, questionIDs, ttl); err != nil {
+		// Drop the stale copy so the next read recovers the flags from the database
+		_ = r.cache.Delete(cacheKey)
+		return fmt.Errorf("failed to sync flagged questions to cache: %w", err)
+	}
+
+	return nil
+}
+
+// persistFlaggedQuestions makes the flags on user_session_answers match the flagged questions. A question
+// flagged without an answer gets an empty row to hold its flag.
+func (r *examRepository) persistFlaggedQuestions(sessionID string, questionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt, err := startedAttempt(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		var saved []models.UserSessionAnswer
+		if err := tx.Where("attempt_id = ?", attempt.ID).Find(&saved).Error; err != nil {
+			return err
+		}
+
+		flagged := make(map[uint]bool, len(questionIDs))
+		for _, questionID := range questionIDs {
+			flagged[questionID] = true
+		}
+
+		flag := make([]uint, 0)
+		unflag := make([]uint, 0)
+		for _, answer := range saved {
+			switch {
+			case flagged[answer.QuestionID] && !answer.Flagged:
+				flag = append(flag, answer.ID)
+			case !flagged[answer.QuestionID] && answer.Flagged:
+				unflag = append(unflag, answer.ID)
+			}
+			delete(flagged, answer.QuestionID)
+		}
+		if len(flag) > 0 {
+			if err := tx.Model(&models.UserSessionAnswer{}).Where("id IN ?", flag).Update("flagged", true).Error; err != nil {
+				return err
+			}
+		}
+		if len(unflag) > 0 {
+			if err := tx.Model(&models.UserSessionAnswer{}).Where("id IN ?", unflag).Update("flagged", false).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		created := make([]models.UserSessionAnswer, 0, len(flagged))
+		for questionID := range flagged {
+			created = append(created, models.UserSessionAnswer{
+				AttemptID:  attempt.ID,
+				UserID:     attempt.UserID,
+				QuestionID: questionID,
+				Flagged:    true,
+				AnsweredAt: now,
+			})
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AppendAnswerChanges adds entries to the end of a session's answer change log. Entries are never rewritten.
+func (r *examRepository) AppendAnswerChanges(sessionID string, changes []models.SessionAnswerChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	cacheKey := fmt.Sprintf("exam_session:%s:changes", sessionID)
+
+	entries := make([]string, 0, len(changes))
+	for _, change := range changes {
+		entry, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed to encode answer change: %w", err)
+		}
+		entries = append(entries, string(entry))
+	}
+
+	// Same TTL as the session answers
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := r.cache.RPush(cacheKey, entries, ttl); err != nil {
+		return fmt.Errorf("failed to append answer changes to cache: %w", err)
+	}
+
+	return nil
+}
+
+// GetAnswerChanges retrieves a session's answer change log, oldest first
+func (r *examRepository) GetAnswerChanges(sessionID string) ([]models.SessionAnswerChange, error) {
+	cacheKey := fmt.Sprintf("exam_session:%s:changes", sessionID)
+
+	entries, err := r.cache.LRange(cacheKey, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get answer changes from cache: %w", err)
+	}
+
+	changes := make([]models.SessionAnswerChange, 0, len(entries))
+	for _, entry := range entries {
+		var change models.SessionAnswerChange
+		if err := json.Unmarshal([]byte(entry), &change); err != nil {
+			return nil, fmt.Errorf("failed to decode answer change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// GetQuestionTimes retrieves the seconds spent on each question of a session, keyed by question ID
+func (r *examRepository) GetQuestionTimes(sessionID string) (map[uint]int, error) {
+	cacheKey := fmt.Sprintf("exam_session:%s:times", sessionID)
+
+	var times map[uint]int
+	if err := r.cache.Get(cacheKey, &times); err != nil {
+		// No time reported yet
+		return make(map[uint]int), nil
+	}
+
+	return times, nil
+}
+
+// SyncQuestionTimes stores the seconds spent on each question of a session
+func (r *examRepository) SyncQuestionTimes(sessionID string, times map[uint]int) error {
+	cacheKey := fmt.Sprintf("exam_session:%s:times", sessionID)
+
+	// Same TTL as the session answers
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := r.cache.Set(cacheKey, times, ttl); err != nil {
+		return fmt.Errorf("failed to sync question times to cache: %w", err)
+	}
+
+	return nil
+}
+
+// GetAnswerVersions retrieves when each question of a session was last changed on the student's device, keyed
+// by question ID, recovering them from user_session_answers when the cache has lost them
+func (r *examRepository) GetAnswerVersions(sessionID string) (map[uint]time.Time, error) {
+	cacheKey := fmt.Sprintf("exam_session:%s:versions", sessionID)
+
+	var versions map[uint]time.Time
+	if err := r.cache.Get(cacheKey, &versions); err == nil {
+		return versions, nil
+	}
+
+	saved, err := r.getPersistedSessionAnswers(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover answer versions: %w", err)
+	}
+
+	versions = make(map[uint]time.Time, len(saved))
+	for _, answer := range saved {
+		if answer.ChangedAt != nil {
+			versions[answer.QuestionID] = *answer.ChangedAt
+		}
+	}
+	if len(versions) > 0 {
+		r.restoreSessionState(sessionID, cacheKey, versions)
+	}
+	return versions, nil
+}
+
+// SyncAnswerVersions stores when each question of a session was last changed on the student's device. Like the
+// answers, the versions are written through to user_session_answers before the cache.
+func (r *examRepository) SyncAnswerVersions(sessionID string, versions map[uint]time.Time) error {
+	if err := r.persistAnswerVersions(sessionID, versions); err != nil {
+		return fmt.Errorf("failed to persist answer versions: %w", err)
+	}
+
+	cacheKey := fmt.Sprintf("exam_session:%s:versions", sessionID)
+
+	// Same TTL as the session answers
+	ttl, err := r.sessionStateTTL(sessionID)
+	if err != nil {
+		_ = r.cache.Delete(cacheKey)
+		return err
+	}
+	if err := r.cache.Set(cacheKey, versions, ttl); err != nil {
+		// Drop the stale copy so the next read recovers the versions from the database
+		_ = r.cache.Delete(cacheKey)
+		return fmt.Errorf("failed to sync answer versions to cache: %w", err)
+	}
+
+	return nil
+}
+
+// persistAnswerVersions writes the versions to the rows of their questions. A question changed without an
+// answer, such as a flag, gets an empty row to hold its version.
+func (r *examRepository) persistAnswerVersions(sessionID string, versions map[uint]time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt, err := startedAttempt(tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		var saved []models.UserSessionAnswer
+		if err := tx.Where("attempt_id = ?", attempt.ID).Find(&saved).Error; err != nil {
+			return err
+		}
+		existing := make(map[uint]models.UserSessionAnswer, len(saved))
+		for _, answer := range saved {
+			existing[answer.QuestionID] = answer
+		}
+
+		now := time.Now()
+		created := make([]models.UserSessionAnswer, 0)
+		for questionID, version := range versions {
+			changedAt := version
+			answer, ok := existing[questionID]
+			if !ok {
+				created = append(created, models.UserSessionAnswer{
+					AttemptID:  attempt.ID,
+					UserID:     attempt.UserID,
+					QuestionID: questionID,
+					AnsweredAt: now,
+					ChangedAt:  &changedAt,
+				})
+				continue
+			}
+			if answer.ChangedAt != nil && answer.ChangedAt.Equal(version) {
+				continue
+			}
+			if err := tx.Model(&answer).Update("changed_at", changedAt).Error; err != nil {
+				return err
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// WithSessionLock runs fn with a repository bound to a database transaction that holds a lock on the session's
+// attempt row, so changes to a session's state are serialised across every server. What fn writes through to
+// user_session_answers commits together when it returns without error. On an error the cached copies of the
+// written through state are dropped, so the next read recovers what was committed. Cache updates that must not
+// show a rolled back write, such as the leaderboard, run only after the commit.
+func (r *examRepository) WithSessionLock(sessionID string, fn func(repo ExamRepository) error) error {
+	var afterCommit []func()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var attempt models.UserExamAttempt
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("session_id = ? AND status = ?", sessionID, models.AttemptStatusStarted).
+			First(&attempt).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrAttemptNotFound
+			}
+			return fmt.Errorf("failed to lock session: %w", err)
+		}
+
+		return fn(&examRepository{
+			db:          tx,
+			cache:       r.cache,
+			leaderboard: r.leaderboard,
+			afterCommit: &afterCommit,
+		})
+	})
+	if err != nil {
+		for _, state := range []string{"answers", "checked", "versions", "flags"} {
+			_ = r.cache.Delete(fmt.Sprintf("exam_session:%s:%s", sessionID, state))
+		}
+		return err
+	}
+
+	for _, update := range afterCommit {
+		update()
+	}
+	return nil
+}
+
+// onCommit runs a cache update once the repository's transaction has committed, so the cache never shows
+// what a rolled back session lock wrote. Outside a session lock it runs straight away.
+func (r *examRepository) onCommit(update func()) {
+	if r.afterCommit != nil {
+		*r.afterCommit = append(*r.afterCommit, update)
+		return
+	}
+	update()
+}
+
 // CompleteExamAttempt marks an exam attempt as completed and updates the score
 func (r *examRepository) CompleteExamAttempt(attemptID uint, score float64, passed bool) error {
 	now := time.Now()
@@ -369,8 +1088,8 @@ func (r *examRepository) CompleteExamAttempt(attemptID uint, score float64, pass
 		"completed_at":      now,
 		"actual_time_spent": timeSpent,
 		"score":             score,
-		"is_scored":         true,
-		"session_id":        nil, // Clear session ID as exam is completed
+		"is_scored":         false, // Per-question analytics are built in the background
+		"session_id":        nil,   // Clear session ID as exam is completed
 		"last_activity_at":  now,
 	}
 
@@ -378,12 +1097,36 @@ func (r *examRepository) CompleteExamAttempt(attemptID uint, score float64, pass
 	if err != nil {
 		return fmt.Errorf("failed to complete exam attempt: %w", err)
 	}
+	r.deleteSessionAnswers(attemptID)
 
 	return nil
 }
 
 // CompleteExamAttemptWithAnswers marks an exam attempt as completed and updates score with detailed answers
 func (r *examRepository) CompleteExamAttemptWithAnswers(attemptID uint, score float64, passed bool, answersData string, correctAnswers int) error {
+	if err := r.finalizeAttempt(attemptID, models.AttemptStatusCompleted, score, passed, answersData, correctAnswers); err != nil {
+		if errors.Is(err, ErrExamAlreadySubmitted) {
+			return err
+		}
+		return fmt.Errorf("failed to complete exam attempt with answers: %w", err)
+	}
+	return nil
+}
+
+// AutoSubmitExamAttempt stores the scored answers of a session whose time ran out
+func (r *examRepository) AutoSubmitExamAttempt(attemptID uint, score float64, passed bool, answersData string, correctAnswers int) error {
+	if err := r.finalizeAttempt(attemptID, models.AttemptStatusAutoSubmitted, score, passed, answersData, correctAnswers); err != nil {
+		if errors.Is(err, ErrExamAlreadySubmitted) {
+			return err
+		}
+		return fmt.Errorf("failed to auto-submit exam attempt: %w", err)
+	}
+	return nil
+}
+
+// finalizeAttempt moves a STARTED attempt into a final scored state.
+// The status condition makes a user submit and a background auto-submit mutually exclusive.
+func (r *examRepository) finalizeAttempt(attemptID uint, status models.AttemptStatus, score float64, passed bool, answersData string, correctAnswers int) error {
 	now := time.Now()
 
 	// Get the attempt to calculate time spent
@@ -401,26 +1144,75 @@ func (r *examRepository) CompleteExamAttemptWithAnswers(attemptID uint, score fl
 
 	// Update attempt with completion data including answers
 	updates := map[string]interface{}{
-		"status":            models.AttemptStatusCompleted,
+		"status":            status,
 		"completed_at":      now,
 		"actual_time_spent": timeSpent,
 		"score":             score,
 		"correct_answers":   correctAnswers,
 		"is_passed":         passed,
-		"is_scored":         true,
+		"is_scored":         false, // Per-question analytics are built in the background
 		"answers_data":      answersData,
 		// Keep session_id for tracking purposes - don't clear it
 		"last_activity_at": now,
 	}
+	// Practice sessions are not graded
+	if attempt.IsPractice {
+		updates["is_passed"] = nil
+	}
 
-	err = r.db.Model(&attempt).Updates(updates).Error
-	if err != nil {
-		return fmt.Errorf("failed to complete exam attempt with answers: %w", err)
+	result := r.db.Model(&models.UserExamAttempt{}).
+		Where("id = ? AND status = ?", attemptID, models.AttemptStatusStarted).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExamAlreadySubmitted
 	}
 
+	// The answers are now in answers_data
+	r.deleteSessionAnswers(attemptID)
+
+	// Keep the cached leaderboard current; a failure only delays the attempt until the next rebuild
+	attempt.Score = &score
+	attempt.ActualTimeSpent = timeSpent
+	r.onCommit(func() {
+		if err := r.leaderboard.RecordAttempt(attempt); err != nil {
+			logger.WithService("ExamRepository").WithFields(logrus.Fields{
+				"attempt_id": attemptID,
+				"exam_id":    attempt.ExamID,
+			}).WithError(err).Warn("Failed to update leaderboard")
+		}
+	})
+
 	return nil
 }
 
+// GetExpiredActiveSessions retrieves STARTED attempts whose time limit plus grace period has passed, with their exams
+func (r *examRepository) GetExpiredActiveSessions(currentTime time.Time, gracePeriodSeconds int, limit int) ([]SessionWithExamData, error) {
+	var attempts []models.UserExamAttempt
+	err := r.db.Preload("Exam").
+		Where("status = ? AND started_at + INTERVAL (time_limit_seconds + ?) SECOND < ?", models.AttemptStatusStarted, gracePeriodSeconds, currentTime).
+		Order("started_at ASC").
+		Limit(limit).
+		Find(&attempts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired sessions: %w", err)
+	}
+
+	sessions := make([]SessionWithExamData, 0, len(attempts))
+	for _, attempt := range attempts {
+		exam := attempt.Exam
+		attempt.Exam = models.Exam{}
+		sessions = append(sessions, SessionWithExamData{
+			Attempt: attempt,
+			Exam:    exam,
+		})
+	}
+
+	return sessions, nil
+}
+
 // GetAttemptBySessionAndUser gets a user exam attempt by session ID and user ID
 func (r *examRepository) GetAttemptBySessionAndUser(sessionID string, userID uint) (*models.UserExamAttempt, error) {
 	var attempt models.UserExamAttempt
@@ -468,7 +1260,7 @@ func (r *examRepository) GetUserAttemptForExamInPackage(userID uint, examID uint
 
 // CreateExamAttemptWithExam creates a new exam attempt using provided exam data
 // Optimized to avoid additional DB call to fetch exam details
-func (r *examRepository) CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, deviceInfo map[string]string) (*models.UserExamAttempt, error) {
+func (r *examRepository) CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, attemptNumber int, deviceInfo map[string]string) (*models.UserExamAttempt, error) {
 	// Generate session ID
 	sessionID := r.generateSessionID()
 	now := time.Now()
@@ -478,17 +1270,19 @@ func (r *examRepository) CreateExamAttemptWithExam(userID uint, exam *models.Exa
 		UserID:           userID,
 		ExamID:           exam.ID,
 		PackageID:        packageID,
-		AttemptNumber:    1,
+		AttemptNumber:    attemptNumber,
+		IsPractice:       exam.IsPractice(),
 		Status:           models.AttemptStatusStarted,
 		StartedAt:        now,
 		SessionID:        &sessionID,
 		LastActivityAt:   &now,
-		TimeLimitSeconds: exam.DurationMinutes * 60,
+		TimeLimitSeconds: exam.SessionTimeLimitSeconds(now),
 		TotalQuestions:   exam.TotalQuestions,
 		PassingScore:     exam.PassingScore,
 		AnswersData:      "{}",
 		IsScored:         false,
 	}
+	attempt.SnapshotShuffle(exam)
 
 	if err := r.db.Create(attempt).Error; err != nil {
 		return nil, fmt.Errorf("failed to create exam attempt: %w", err)
@@ -510,6 +1304,16 @@ func (r *examRepository) GetPackageIDForExam(examID uint) (uint, error) {
 	return packageExam.PackageID, nil
 }
 
+// GetLeaderboard returns the top users of an exam leaderboard within a package and the number of participants
+func (r *examRepository) GetLeaderboard(examID uint, packageID uint, limit int) ([]LeaderboardEntry, int64, error) {
+	return r.leaderboard.GetTopEntries(examID, packageID, limit)
+}
+
+// GetLeaderboardRank returns a user's rank and percentile on an exam leaderboard within a package
+func (r *examRepository) GetLeaderboardRank(examID uint, packageID uint, userID uint) (*LeaderboardRank, error) {
+	return r.leaderboard.GetUserRank(examID, packageID, userID)
+}
+
 // MarkExpiredSessionsAsAbandoned marks all expired exam sessions as abandoned
 func (r *examRepository) MarkExpiredSessionsAsAbandoned(currentTime time.Time, gracePeriodSeconds int) (int64, error) {
 	// Initialize logger with repository context
@@ -586,6 +1390,16 @@ func (r *examRepository) MarkExpiredSessionsAsAbandoned(currentTime time.Time, g
 		}).Warn("Mismatch between expected and actual updated session count")
 	}
 
+	// Abandoned sessions cannot be resumed, their written-through answers are no longer needed
+	cleanupQuery := `
+		DELETE usa FROM user_session_answers usa
+		INNER JOIN user_exam_attempts uea ON usa.attempt_id = uea.id
+		WHERE uea.status = ?
+	`
+	if err := r.db.Exec(cleanupQuery, models.AttemptStatusAbandoned).Error; err != nil {
+		log.WithError(err).Warn("Failed to clean up answers of abandoned sessions")
+	}
+
 	return updatedRows, nil
 }
 
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ItemResponse is one analysed answer with the total score of the attempt it belongs to
+type ItemResponse struct {
+	AttemptID       uint
+	ExamID          uint
+	QuestionID      uint
+	QuestionType    models.QuestionType
+	QuestionText    string
+	SelectedOptions string
+	CorrectOptions  string
+	IsCorrect       bool
+	PartialScore    float64
+	MaxScore        float64
+	TimeSpent       int
+	IsSkipped       bool
+	AttemptScore    float64 // Sum of partial scores over the whole attempt
+	AttemptMaxScore float64 // Sum of max scores over the whole attempt
+}
+
+// ItemAnalysisRepository defines the interface for reading answers for item analysis
+type ItemAnalysisRepository interface {
+	GetExamItemResponses(examID uint) ([]ItemResponse, error)
+	GetQuestionItemResponses(questionID uint) ([]ItemResponse, error)
+}
+
+// itemAnalysisRepository implements ItemAnalysisRepository
+type itemAnalysisRepository struct {
+	db *gorm.DB
+}
+
+// NewItemAnalysisRepository creates a new item analysis repository
+func NewItemAnalysisRepository(db *gorm.DB) ItemAnalysisRepository {
+	return &itemAnalysisRepository{
+		db: db,
+	}
+}
+
+// GetExamItemResponses returns every analysed answer of finished attempts of an exam
+func (r *itemAnalysisRepository) GetExamItemResponses(examID uint) ([]ItemResponse, error) {
+	responses, err := r.queryResponses("uqa.exam_id = ?", "exam_id = ?", examID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam item responses: %w", err)
+	}
+	return responses, nil
+}
+
+// GetQuestionItemResponses returns every analysed answer to a question bank question across all exams
+func (r *itemAnalysisRepository) GetQuestionItemResponses(questionID uint) ([]ItemResponse, error) {
+	responses, err := r.queryResponses("uqa.question_id = ?",
+		"attempt_id IN (SELECT attempt_id FROM user_question_answers WHERE question_id = ? AND deleted_at IS NULL)", questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question item responses: %w", err)
+	}
+	return responses, nil
+}
+
+// queryResponses selects analysed answers matching the condition, joined with their attempt totals.
+// totalsCondition narrows the attempts totals are computed for. Only COMPLETED and AUTO_SUBMITTED attempts of real
+// exams are included; practice sessions are generated per student and would skew the statistics.
+func (r *itemAnalysisRepository) queryResponses(condition string, totalsCondition string, value uint) ([]ItemResponse, error) {
+	var responses []ItemResponse
+
+	totals := r.db.Table("user_question_answers").
+		Select("attempt_id, SUM(partial_score) AS attempt_score, SUM(max_score) AS attempt_max_score").
+		Where("deleted_at IS NULL").
+		Where(totalsCondition, value).
+		Group("attempt_id")
+
+	err := r.db.Table("user_question_answers uqa").
+		Select(`uqa.attempt_id, uqa.exam_id, uqa.question_id, uqa.question_type, uqa.question_text,
+			uqa.selected_options, uqa.correct_options, uqa.is_correct, uqa.partial_score, uqa.max_score,
+			uqa.time_spent, uqa.is_skipped, totals.attempt_score, totals.attempt_max_score`).
+		Joins("JOIN user_exam_attempts ua ON ua.id = uqa.attempt_id AND ua.deleted_at IS NULL").
+		Joins("JOIN (?) AS totals ON totals.attempt_id = uqa.attempt_id", totals).
+		Where("uqa.deleted_at IS NULL").
+		Where("ua.status IN ?", []models.AttemptStatus{models.AttemptStatusCompleted, models.AttemptStatusAutoSubmitted}).
+		Where("ua.is_practice = ?", false).
+		Where(condition, value).
+		Order("uqa.attempt_id ASC, uqa.question_index ASC").
+		Scan(&responses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/cache"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// leaderboardTTL is how long a leaderboard stays cached; it is kept current on every submission
+	leaderboardTTL = 6 * time.Hour
+	// emptyLeaderboardTTL is how long an exam without scored attempts is remembered, so reads skip the database
+	emptyLeaderboardTTL = 5 * time.Minute
+	// leaderboardRebuildMargin covers attempts finalized in transactions that started before a rebuild's query
+	leaderboardRebuildMargin = time.Minute
+	// leaderboardTimeSlots leaves room for time spent in the ranking score (ties on score rank faster attempts higher)
+	leaderboardTimeSlots = 10000000
+)
+
+// ErrNotRanked is returned when a user has no scored attempt on a leaderboard
+var ErrNotRanked = errors.New("user not ranked on leaderboard")
+
+// LeaderboardEntry represents a user's best attempt on an exam leaderboard
+type LeaderboardEntry struct {
+	Rank      int64
+	UserID    uint
+	UserName  string
+	Score     float64
+	TimeSpent int // Seconds
+}
+
+// LeaderboardRank represents a user's standing on an exam leaderboard
+type LeaderboardRank struct {
+	Rank              int64
+	TotalParticipants int64
+	Percentile        float64 // Percentile rank: share of participants scoring below, counting ties as half
+	Score             float64
+	TimeSpent         int
+}
+
+// LeaderboardRepository ranks users' best finished attempt per exam within a package.
+// Rankings live in cache sorted sets and are rebuilt from user_exam_attempts on a miss.
+type LeaderboardRepository interface {
+	RecordAttempt(attempt models.UserExamAttempt) error
+	GetTopEntries(examID, packageID uint, limit int) ([]LeaderboardEntry, int64, error)
+	GetUserRank(examID, packageID, userID uint) (*LeaderboardRank, error)
+}
+
+// leaderboardRepository implements LeaderboardRepository
+type leaderboardRepository struct {
+	db    *gorm.DB
+	cache cache.CacheInterface
+}
+
+// NewLeaderboardRepository creates a new leaderboard repository
+func NewLeaderboardRepository(db *gorm.DB, cache cache.CacheInterface) LeaderboardRepository {
+	return &leaderboardRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// RecordAttempt adds a finished attempt to its cached leaderboard, keeping the user's best.
+// A leaderboard that is not cached is left alone; it is rebuilt with the attempt on the next read.
+func (r *leaderboardRepository) RecordAttempt(attempt models.UserExamAttempt) error {
+	if attempt.Score == nil {
+		return nil
+	}
+
+	key := leaderboardKey(attempt.ExamID, attempt.PackageID)
+	added, err := r.cache.ZAddIfExists(key, leaderboardMember(attempt))
+	if err != nil {
+		return fmt.Errorf("failed to record leaderboard attempt: %w", err)
+	}
+
+	// The exam may be remembered as having no attempts; forget that so the next read rebuilds with this one
+	if !added {
+		if err := r.cache.Delete(emptyLeaderboardKey(key)); err != nil && !cache.IsKeyNotFound(err) {
+			return fmt.Errorf("failed to reset empty leaderboard: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetTopEntries returns the best ranked users, with user names, and the total number of participants
+func (r *leaderboardRepository) GetTopEntries(examID, packageID uint, limit int) ([]LeaderboardEntry, int64, error) {
+	key, err := r.ensureLeaderboard(examID, packageID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.cache.ZCard(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	members, err := r.cache.ZRevRangeWithScores(key, 0, int64(limit)-1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(members))
+	userIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		userID, err := strconv.ParseUint(member.Member, 10, 64)
+		if err != nil {
+			continue
+		}
+		rank, err := r.rankOf(key, member.Score)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		score, timeSpent := decodeLeaderboardScore(member.Score)
+		entries = append(entries, LeaderboardEntry{
+			Rank:      rank,
+			UserID:    uint(userID),
+			Score:     score,
+			TimeSpent: timeSpent,
+		})
+		userIDs = append(userIDs, uint(userID))
+	}
+
+	// Only the page of users shown needs names
+	if len(userIDs) > 0 {
+		var users []models.User
+		if err := r.db.Select("id", "name").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, 0, fmt.Errorf("failed to get leaderboard users: %w", err)
+		}
+		names := make(map[uint]string, len(users))
+		for _, user := range users {
+			names[user.ID] = user.Name
+		}
+		for i := range entries {
+			entries[i].UserName = names[entries[i].UserID]
+		}
+	}
+
+	return entries, total, nil
+}
+
+// GetUserRank returns the user's rank and percentile, or ErrNotRanked when they have no scored attempt
+func (r *leaderboardRepository) GetUserRank(examID, packageID, userID uint) (*LeaderboardRank, error) {
+	key, err := r.ensureLeaderboard(examID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.cache.ZScore(key, strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		if cache.IsKeyNotFound(err) {
+			return nil, ErrNotRanked
+		}
+		return nil, err
+	}
+
+	total, err := r.cache.ZCard(key)
+	if err != nil {
+		return nil, err
+	}
+	rank, err := r.rankOf(key, value)
+	if err != nil {
+		return nil, err
+	}
+	below, err := r.cache.ZCount(key, "-inf", "("+formatSortedSetScore(value))
+	if err != nil {
+		return nil, err
+	}
+
+	// Everyone not above or below shares the score (the user included)
+	tied := total - (rank - 1) - below
+	score, timeSpent := decodeLeaderboardScore(value)
+	return &LeaderboardRank{
+		Rank:              rank,
+		TotalParticipants: total,
+		Percentile:        math.Round((float64(below)+0.5*float64(tied))/float64(total)*10000) / 100,
+		Score:             score,
+		TimeSpent:         timeSpent,
+	}, nil
+}
+
+// rankOf returns the competition rank of a ranking score: one plus the number of better scores
+func (r *leaderboardRepository) rankOf(key string, value float64) (int64, error) {
+	above, err := r.cache.ZCount(key, "("+formatSortedSetScore(value), "+inf")
+	if err != nil {
+		return 0, err
+	}
+	return above + 1, nil
+}
+
+// ensureLeaderboard rebuilds the cached leaderboard from finished attempts when it is missing. Exams without
+// scored attempts are remembered for a short while instead, since an empty sorted set is not stored.
+func (r *leaderboardRepository) ensureLeaderboard(examID, packageID uint) (string, error) {
+	key := leaderboardKey(examID, packageID)
+	if r.cache.Exists(key) || r.cache.Exists(emptyLeaderboardKey(key)) {
+		return key, nil
+	}
+
+	rebuildStart := time.Now()
+	attempts, err := r.loadLeaderboardAttempts(examID, packageID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep each user's best attempt
+	best := make(map[string]float64)
+	for _, attempt := range attempts {
+		member := leaderboardMember(attempt)
+		if current, ok := best[member.Member]; !ok || member.Score > current {
+			best[member.Member] = member.Score
+		}
+	}
+
+	members := make([]cache.SortedSetMember, 0, len(best))
+	for userID, value := range best {
+		members = append(members, cache.SortedSetMember{Member: userID, Score: value})
+	}
+
+	if len(members) == 0 {
+		if err := r.cache.Set(emptyLeaderboardKey(key), true, emptyLeaderboardTTL); err != nil {
+			return "", fmt.Errorf("failed to cache empty leaderboard: %w", err)
+		}
+	} else if err := r.cache.ZReplace(key, members, leaderboardTTL); err != nil {
+		return "", fmt.Errorf("failed to cache leaderboard: %w", err)
+	}
+
+	// Attempts finalized while the board was rebuilt found no board to be recorded in; add them now
+	since := rebuildStart.Add(-leaderboardRebuildMargin)
+	recent, err := r.loadLeaderboardAttempts(examID, packageID, &since)
+	if err != nil {
+		return "", err
+	}
+	for _, attempt := range recent {
+		if err := r.RecordAttempt(attempt); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// loadLeaderboardAttempts returns the scored finished attempts of an exam, only those updated since the given
+// time when set
+func (r *leaderboardRepository) loadLeaderboardAttempts(examID, packageID uint, updatedSince *time.Time) ([]models.UserExamAttempt, error) {
+	query := r.db.Select("user_id", "exam_id", "package_id", "score", "actual_time_spent").
+		Where("exam_id = ? AND package_id = ? AND status IN ? AND score IS NOT NULL", examID, packageID,
+			[]models.AttemptStatus{models.AttemptStatusCompleted, models.AttemptStatusAutoSubmitted})
+	if updatedSince != nil {
+		query = query.Where("updated_at >= ?", *updatedSince)
+	}
+
+	var attempts []models.UserExamAttempt
+	if err := query.Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load leaderboard attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// leaderboardMember returns the sorted set member of a scored attempt
+func leaderboardMember(attempt models.UserExamAttempt) cache.SortedSetMember {
+	return cache.SortedSetMember{
+		Member: strconv.FormatUint(uint64(attempt.UserID), 10),
+		Score:  encodeLeaderboardScore(*attempt.Score, attempt.ActualTimeSpent),
+	}
+}
+
+// leaderboardKey returns the cache key of an exam's leaderboard within a package
+func leaderboardKey(examID, packageID uint) string {
+	return fmt.Sprintf("leaderboard:package:%d:exam:%d", packageID, examID)
+}
+
+// emptyLeaderboardKey returns the cache key marking a leaderboard without scored attempts
+func emptyLeaderboardKey(key string) string {
+	return key + ":empty"
+}
+
+// encodeLeaderboardScore combines score (2 decimals) and time spent into one ranking score where
+// a higher score wins and, on equal scores, less time spent wins
+func encodeLeaderboardScore(score float64, timeSpent int) float64 {
+	if timeSpent < 0 {
+		timeSpent = 0
+	}
+	if timeSpent >= leaderboardTimeSlots {
+		timeSpent = leaderboardTimeSlots - 1
+	}
+	return math.Round(score*100)*leaderboardTimeSlots + float64(leaderboardTimeSlots-1-timeSpent)
+}
+
+// decodeLeaderboardScore splits a ranking score back into score and time spent
+func decodeLeaderboardScore(value float64) (float64, int) {
+	cents := math.Floor(value / leaderboardTimeSlots)
+	timeSpent := leaderboardTimeSlots - 1 - int(value-cents*leaderboardTimeSlots)
+	return cents / 100, timeSpent
+}
+
+// formatSortedSetScore formats a ranking score for a sorted set range bound
+func formatSortedSetScore(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPaymentTransactionNotFound = errors.New("payment transaction not found")
+	ErrInvalidRefundAmount        = errors.New("refund amount is not refundable")
+)
+
+// PaymentSettlement is the gateway's verdict applied to a transaction
+type PaymentSettlement struct {
+	Status                models.PaymentTransactionStatus // PAID, FAILED or CANCELLED
+	ProviderTransactionID string
+	PaidAt                *time.Time
+	FailureReason         string
+	GatewayData           string
+}
+
+// RefundTotals sums the refunds of a payment transaction
+type RefundTotals struct {
+	Refunded  float64 // Reserved, pending and processed, the money that is or may be on its way back
+	Processed float64 // Confirmed returned by the gateway
+}
+
+// PaymentRepository stores payment transactions and moves their enrollments to PAID or FAILED, or completes
+// the renewals they pay for
+type PaymentRepository interface {
+	GetEnrollmentForCheckout(enrollmentID uint) (*models.UserPackageEnrollment, error)
+	GetRenewalForCheckout(renewalID uint) (*models.EnrollmentRenewal, error)
+	CreateTransaction(transaction *models.PaymentTransaction) error
+	SetProviderReference(transactionID string, reference string) error
+	GetTransaction(transactionID string) (*models.PaymentTransaction, error)
+	GetTransactionByProviderReference(provider, reference string) (*models.PaymentTransaction, error)
+	RecordVerification(transactionID string, gatewayData string) error
+	SettleTransaction(transactionID string, settlement PaymentSettlement) (*models.PaymentTransaction, bool, error)
+
+	// Refunds
+	GetLatestPaidTransaction(enrollmentID uint) (*models.PaymentTransaction, error)
+	ReserveRefund(refund *models.PaymentRefund, amount *float64) (float64, error)
+	SettleRefund(refund *models.PaymentRefund) (bool, error)
+	ReleaseRefund(refundID uint) error
+	GetPendingRefunds(limit int) ([]models.PaymentRefund, error)
+	GetRefundTotals(transactionID string) (*RefundTotals, error)
+}
+
+// paymentRepository implements PaymentRepository
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+// GetEnrollmentForCheckout returns an enrollment with the user and package a checkout is made for
+func (r *paymentRepository) GetEnrollmentForCheckout(enrollmentID uint) (*models.UserPackageEnrollment, error) {
+	var enrollment models.UserPackageEnrollment
+	if err := r.db.Preload("User").Preload("Package").First(&enrollment, enrollmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEnrollmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get enrollment: %w", err)
+	}
+	return &enrollment, nil
+}
+
+// GetRenewalForCheckout returns a renewal a checkout is made for
+func (r *paymentRepository) GetRenewalForCheckout(renewalID uint) (*models.EnrollmentRenewal, error) {
+	var renewal models.EnrollmentRenewal
+	if err := r.db.First(&renewal, renewalID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRenewalNotFound
+		}
+		return nil, fmt.Errorf("failed to get renewal: %w", err)
+	}
+	return &renewal, nil
+}
+
+// CreateTransaction stores a new checkout
+func (r *paymentRepository) CreateTransaction(transaction *models.PaymentTransaction) error {
+	if err := r.db.Omit("Enrollment").Create(transaction).Error; err != nil {
+		return fmt.Errorf("failed to create payment transaction: %w", err)
+	}
+	return nil
+}
+
+// SetProviderReference stores the gateway's session or payment ID of a checkout
+func (r *paymentRepository) SetProviderReference(transactionID string, reference string) error {
+	err := r.db.Model(&models.PaymentTransaction{}).
+		Where("transaction_id = ?", transactionID).
+		Update("provider_reference", reference).Error
+	if err != nil {
+		return fmt.Errorf("failed to set provider reference: %w", err)
+	}
+	return nil
+}
+
+// GetTransaction returns a transaction by our reference
+func (r *paymentRepository) GetTransaction(transactionID string) (*models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	if err := r.db.Where("transaction_id = ?", transactionID).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+// GetTransactionByProviderReference returns a transaction by the gateway's session or payment ID
+func (r *paymentRepository) GetTransactionByProviderReference(provider, reference string) (*models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	err := r.db.Where("provider = ? AND provider_reference = ?", provider, reference).First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+// RecordVerification stores the response of a verification that did not settle the transaction
+func (r *paymentRepository) RecordVerification(transactionID string, gatewayData string) error {
+	err := r.db.Model(&models.PaymentTransaction{}).
+		Where("transaction_id = ? AND status = ?", transactionID, models.PaymentTransactionInitiated).
+		Updates(map[string]interface{}{
+			"gateway_data": gatewayData,
+			"verified_at":  time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record payment verification: %w", err)
+	}
+	return nil
+}
+
+// SettleTransaction applies the gateway's verdict to a transaction and its enrollment in one database
+// transaction. Callbacks, notifications and polling can all report the same payment, so only the first
+// report changes anything: PAID is final, and a failed transaction only changes again when the gateway
+// later reports it paid. Returns the stored transaction and whether this call changed it.
+func (r *paymentRepository) SettleTransaction(transactionID string, settlement PaymentSettlement) (*models.PaymentTransaction, bool, error) {
+	from := []models.PaymentTransactionStatus{models.PaymentTransactionInitiated}
+	if settlement.Status == models.PaymentTransactionPaid {
+		from = append(from, models.PaymentTransactionFailed, models.PaymentTransactionCancelled)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      settlement.Status,
+		"verified_at": now,
+	}
+	if settlement.ProviderTransactionID != "" {
+		updates["provider_transaction_id"] = settlement.ProviderTransactionID
+	}
+	if settlement.GatewayData != "" {
+		updates["gateway_data"] = settlement.GatewayData
+	}
+	if settlement.Status == models.PaymentTransactionPaid {
+		paidAt := now
+		if settlement.PaidAt != nil {
+			paidAt = *settlement.PaidAt
+		}
+		updates["paid_at"] = paidAt
+		updates["failure_reason"] = nil
+	} else if settlement.FailureReason != "" {
+		updates["failure_reason"] = settlement.FailureReason
+	}
+
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The status condition makes concurrent reports race for the row, only one of them wins
+		result := tx.Model(&models.PaymentTransaction{}).
+			Where("transaction_id = ? AND status IN ?", transactionID, from).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to settle payment transaction: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		applied = true
+
+		var transaction models.PaymentTransaction
+		if err := tx.Where("transaction_id = ?", transactionID).First(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to reload payment transaction: %w", err)
+		}
+		if transaction.RenewalID != nil {
+			return settleRenewal(tx, &transaction)
+		}
+		return settleEnrollment(tx, &transaction)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	transaction, err := r.GetTransaction(transactionID)
+	if err != nil {
+		return nil, false, err
+	}
+	return transaction, applied, nil
+}
+
+// paymentReference returns the gateway's transaction ID of a settled transaction, or our own reference
+func paymentReference(transaction *models.PaymentTransaction) string {
+	if transaction.ProviderTransactionID != nil && *transaction.ProviderTransactionID != "" {
+		return *transaction.ProviderTransactionID
+	}
+	return transaction.TransactionID
+}
+
+// settleRenewal completes a paid renewal, extending its enrollment, or marks it FAILED so it can be paid again
+func settleRenewal(tx *gorm.DB, transaction *models.PaymentTransaction) error {
+	switch transaction.Status {
+	case models.PaymentTransactionPaid:
+		paidAt := time.Now()
+		if transaction.PaidAt != nil {
+			paidAt = *transaction.PaidAt
+		}
+		reference := paymentReference(transaction)
+		if err := completeRenewal(tx, *transaction.RenewalID, &reference, paidAt); err != nil {
+			return err
+		}
+
+		// A renewal completed with another payment leaves this one charged for nothing
+		var renewal models.EnrollmentRenewal
+		if err := tx.Select("payment_reference").First(&renewal, *transaction.RenewalID).Error; err != nil {
+			return fmt.Errorf("failed to get renewal for payment: %w", err)
+		}
+		if renewal.PaymentReference == nil || *renewal.PaymentReference != reference {
+			return markDuplicatePayment(tx, transaction)
+		}
+	case models.PaymentTransactionFailed, models.PaymentTransactionCancelled:
+		err := tx.Model(&models.EnrollmentRenewal{}).
+			Where("id = ? AND status = ?", *transaction.RenewalID, models.RenewalStatusPending).
+			Update("status", models.RenewalStatusFailed).Error
+		if err != nil {
+			return fmt.Errorf("failed to update renewal status: %w", err)
+		}
+	}
+	return nil
+}
+
+// settleEnrollment moves a settled transaction's enrollment to PAID or FAILED. Paying for a trial upgrades it
+// in place, so its attempt history stays with it, and starts the package's validity period from the payment.
+// Paid enrollments are never downgraded by another checkout failing, and a second payment is marked as a duplicate.
+func settleEnrollment(tx *gorm.DB, transaction *models.PaymentTransaction) error {
+	var err error
+	switch transaction.Status {
+	case models.PaymentTransactionPaid:
+		var enrollment models.UserPackageEnrollment
+		if err := tx.Preload("Package").First(&enrollment, transaction.EnrollmentID).Error; err != nil {
+			return fmt.Errorf("failed to get enrollment for payment: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"payment_status":    models.PaymentStatusPaid,
+			"payment_amount":    transaction.Amount,
+			"payment_reference": paymentReference(transaction),
+			"payment_date":      transaction.PaidAt,
+		}
+		if enrollment.IsOnTrial() {
+			paidAt := time.Now()
+			if transaction.PaidAt != nil {
+				paidAt = *transaction.PaidAt
+			}
+			updates["enrollment_type"] = models.EnrollmentTypeUpgrade
+			updates["payment_status"] = models.PaymentStatusUpgraded
+			updates["expires_at"] = enrollment.Package.ExpiryDateFrom(paidAt)
+		}
+
+		result := tx.Model(&models.UserPackageEnrollment{}).
+			Where("id = ? AND payment_status IN ?", transaction.EnrollmentID, []models.PaymentStatus{
+				models.PaymentStatusPending, models.PaymentStatusFailed, models.PaymentStatusExpired,
+			}).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update enrollment payment status: %w", result.Error)
+		}
+		// The enrollment is no longer awaiting payment, usually because another checkout paid it first
+		if result.RowsAffected == 0 {
+			return markDuplicatePayment(tx, transaction)
+		}
+	case models.PaymentTransactionFailed, models.PaymentTransactionCancelled:
+		err = tx.Model(&models.UserPackageEnrollment{}).
+			Where("id = ? AND payment_status = ?", transaction.EnrollmentID, models.PaymentStatusPending).
+			Update("payment_status", models.PaymentStatusFailed).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update enrollment payment status: %w", err)
+	}
+	return nil
+}
+
+// markDuplicatePayment flags a paid transaction that bought nothing, so it can be found and refunded
+func markDuplicatePayment(tx *gorm.DB, transaction *models.PaymentTransaction) error {
+	if err := tx.Model(&models.PaymentTransaction{}).Where("id = ?", transaction.ID).Update("duplicate", true).Error; err != nil {
+		return fmt.Errorf("failed to mark duplicate payment: %w", err)
+	}
+	transaction.Duplicate = true
+	return nil
+}
+
+// GetLatestPaidTransaction returns the most recent paid transaction of an enrollment
+func (r *paymentRepository) GetLatestPaidTransaction(enrollmentID uint) (*models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	err := r.db.Where("enrollment_id = ? AND status = ?", enrollmentID, models.PaymentTransactionPaid).
+		Order("paid_at DESC, id DESC").
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get paid transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+// ReserveRefund records a refund as RESERVED before the gateway is asked for it. The paid transaction's row
+// is locked while its refunds are summed, so concurrent refunds of one payment queue up and can never add up
+// to more than was paid. Without an amount everything still refundable is reserved. Returns what was
+// refundable before this refund, with ErrInvalidRefundAmount when the amount is not positive or does not fit.
+func (r *paymentRepository) ReserveRefund(refund *models.PaymentRefund, amount *float64) (float64, error) {
+	var refundable float64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var transaction models.PaymentTransaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("transaction_id = ? AND status = ?", refund.TransactionID, models.PaymentTransactionPaid).
+			First(&transaction).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPaymentTransactionNotFound
+			}
+			return fmt.Errorf("failed to lock payment transaction: %w", err)
+		}
+
+		// Reserved and pending refunds count too, their money may already be on its way back
+		totals, err := refundTotals(tx, refund.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		// Rounded to whole paisa, so repeated partial refunds add up exactly
+		refundable = math.Round((transaction.Amount-totals.Refunded)*100) / 100
+		refund.Amount = refundable
+		if amount != nil {
+			refund.Amount = math.Round(*amount*100) / 100
+		}
+		if refundable <= 0 || refund.Amount <= 0 || refund.Amount > refundable {
+			return ErrInvalidRefundAmount
+		}
+
+		refund.Status = models.PaymentRefundReserved
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to reserve payment refund: %w", err)
+		}
+		return nil
+	})
+	return refundable, err
+}
+
+// SettleRefund records the gateway's answer on a reserved or pending refund. It reports false when the refund
+// had already been settled, so only one caller acts on a refund becoming PROCESSED.
+func (r *paymentRepository) SettleRefund(refund *models.PaymentRefund) (bool, error) {
+	result := r.db.Model(&models.PaymentRefund{}).
+		Where("id = ? AND status IN ?", refund.ID, []models.PaymentRefundStatus{models.PaymentRefundReserved, models.PaymentRefundPending}).
+		Updates(map[string]interface{}{
+			"status":           refund.Status,
+			"refund_reference": refund.RefundReference,
+			"gateway_data":     refund.GatewayData,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to settle payment refund: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseRefund deletes a reserved refund the gateway turned down, so its amount can be refunded again
+func (r *paymentRepository) ReleaseRefund(refundID uint) error {
+	err := r.db.Where("id = ? AND status = ?", refundID, models.PaymentRefundReserved).
+		Delete(&models.PaymentRefund{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release payment refund: %w", err)
+	}
+	return nil
+}
+
+// GetPendingRefunds returns the oldest refunds still waiting for the gateway
+func (r *paymentRepository) GetPendingRefunds(limit int) ([]models.PaymentRefund, error) {
+	var refunds []models.PaymentRefund
+	err := r.db.Where("status = ?", models.PaymentRefundPending).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&refunds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// GetRefundTotals sums the refunds of a payment transaction. Failed refunds returned nothing and are left out.
+func (r *paymentRepository) GetRefundTotals(transactionID string) (*RefundTotals, error) {
+	return refundTotals(r.db, transactionID)
+}
+
+// refundTotals sums a transaction's refunds within tx
+func refundTotals(tx *gorm.DB, transactionID string) (*RefundTotals, error) {
+	var totals RefundTotals
+	err := tx.Model(&models.PaymentRefund{}).
+		Select("COALESCE(SUM(amount), 0) AS refunded, COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS processed", models.PaymentRefundProcessed).
+		Where("transaction_id = ? AND status <> ?", transactionID, models.PaymentRefundFailed).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunded amount: %w", err)
+	}
+	return &totals, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PracticeCriteria narrows the question bank for a practice session
+type PracticeCriteria struct {
+	SubjectIDs    []uint
+	SystemIDs     []uint
+	QuestionTypes []models.QuestionType
+	Bookmarked    bool // Only the user's bookmarked questions, whether answered correctly before or not
+}
+
+// PracticeRepository loads practice questions and stores the exams generated for practice sessions
+type PracticeRepository interface {
+	GetPracticeCandidates(userID uint, criteria PracticeCriteria, perDifficulty int) ([]models.Question, int, error)
+	CreatePracticeExam(exam *models.Exam) error
+}
+
+// practiceRepository implements PracticeRepository
+type practiceRepository struct {
+	db *gorm.DB
+}
+
+// NewPracticeRepository creates a new practice repository
+func NewPracticeRepository(db *gorm.DB) PracticeRepository {
+	return &practiceRepository{
+		db: db,
+	}
+}
+
+// practiceDifficulties are the difficulty levels a practice sample is drawn from
+var practiceDifficulties = []models.DifficultyLevel{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
+
+// GetPracticeCandidates returns a random sample of at most perDifficulty active questions of each difficulty
+// matching the criteria, together with how many questions match in total. Only questions the user has not
+// answered correctly yet qualify: questions they have never seen and questions they got wrong. Bookmarked
+// questions all qualify, the user picked them. Subjects and systems are combined, so a question in any of them qualifies.
+func (r *practiceRepository) GetPracticeCandidates(userID uint, criteria PracticeCriteria, perDifficulty int) ([]models.Question, int, error) {
+	var available int64
+	if err := r.candidatesQuery(userID, criteria).Model(&models.Question{}).Count(&available).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count practice questions: %w", err)
+	}
+	if available == 0 || perDifficulty <= 0 {
+		return []models.Question{}, int(available), nil
+	}
+
+	// Each difficulty is sampled on its own so the session can honour the difficulty mix and top up
+	// from the other levels, without loading the whole bank. Systems are loaded so the practice
+	// snapshot can record each question's subject.
+	questions := make([]models.Question, 0, perDifficulty*len(practiceDifficulties))
+	for _, difficulty := range practiceDifficulties {
+		var sample []models.Question
+		if err := r.candidatesQuery(userID, criteria).
+			Where("difficulty_level = ?", difficulty).
+			Preload("System").
+			Order("RAND()").
+			Limit(perDifficulty).
+			Find(&sample).Error; err != nil {
+			return nil, 0, fmt.Errorf("failed to load practice questions: %w", err)
+		}
+		questions = append(questions, sample...)
+	}
+	return questions, int(available), nil
+}
+
+// candidatesQuery builds the filter shared by the practice count and sample queries
+func (r *practiceRepository) candidatesQuery(userID uint, criteria PracticeCriteria) *gorm.DB {
+	query := r.db.Where("is_active = ?", true)
+	if criteria.Bookmarked {
+		query = query.Where("id IN (?)", r.db.Model(&models.QuestionBookmark{}).
+			Select("question_id").
+			Where("user_id = ?", userID))
+	} else {
+		query = query.Where("id NOT IN (?)", r.db.Model(&models.UserQuestionAnswer{}).
+			Select("question_id").
+			Where("user_id = ? AND is_correct = ?", userID, true))
+	}
+
+	switch {
+	case len(criteria.SubjectIDs) > 0 && len(criteria.SystemIDs) > 0:
+		query = query.Where("system_id IN ? OR system_id IN (?)", criteria.SystemIDs,
+			r.db.Model(&models.System{}).Select("id").Where("subject_id IN ?", criteria.SubjectIDs))
+	case len(criteria.SubjectIDs) > 0:
+		query = query.Where("system_id IN (?)", r.db.Model(&models.System{}).Select("id").Where("subject_id IN ?", criteria.SubjectIDs))
+	case len(criteria.SystemIDs) > 0:
+		query = query.Where("system_id IN ?", criteria.SystemIDs)
+	}
+	if len(criteria.QuestionTypes) > 0 {
+		query = query.Where("question_type IN ?", criteria.QuestionTypes)
+	}
+	return query
+}
+
+// CreatePracticeExam stores an exam generated for a practice session. Practice exams are not linked to packages.
+func (r *practiceRepository) CreatePracticeExam(exam *models.Exam) error {
+	if err := r.db.Create(exam).Error; err != nil {
+		return fmt.Errorf("failed to create practice exam: %w", err)
+	}
+	return nil
+}
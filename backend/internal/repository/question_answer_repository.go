@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// QuestionAnswerRepository defines the interface for per-question analytics rows
+type QuestionAnswerRepository interface {
+	GetAttemptsForAnalytics(afterID uint, limit int, includeProcessed bool) ([]models.UserExamAttempt, error)
+	GetAnalyticsScanStart() (uint, error)
+	RecordAnalyticsFailure(attemptID uint, reason string) error
+	ReplaceQuestionAnswers(attemptID uint, answers []models.UserQuestionAnswer) error
+	GetQuestionTopics(questionIDs []uint) (map[uint]QuestionTopic, error)
+}
+
+// QuestionTopic is the system and subject a question belongs to
+type QuestionTopic struct {
+	QuestionID uint
+	SystemID   uint
+	SubjectID  uint
+}
+
+// questionAnswerRepository implements QuestionAnswerRepository
+type questionAnswerRepository struct {
+	db *gorm.DB
+}
+
+// NewQuestionAnswerRepository creates a new question answer repository
+func NewQuestionAnswerRepository(db *gorm.DB) QuestionAnswerRepository {
+	return &questionAnswerRepository{
+		db: db,
+	}
+}
+
+// GetAttemptsForAnalytics returns completed and auto-submitted attempts after the given ID in ID order,
+// with their exams. Unless includeProcessed is set, only attempts not yet processed (is_scored = false) and
+// not given up on after MaxAnalyticsFailures are returned.
+func (r *questionAnswerRepository) GetAttemptsForAnalytics(afterID uint, limit int, includeProcessed bool) ([]models.UserExamAttempt, error) {
+	var attempts []models.UserExamAttempt
+
+	query := r.db.Preload("Exam").
+		Where("id > ? AND status IN ?", afterID, []models.AttemptStatus{
+			models.AttemptStatusCompleted,
+			models.AttemptStatusAutoSubmitted,
+		})
+	if !includeProcessed {
+		query = query.Where("is_scored = ? AND analytics_failures < ?", false, models.MaxAnalyticsFailures)
+	}
+
+	err := query.Order("id ASC").Limit(limit).Find(&attempts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts for analytics: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// ReplaceQuestionAnswers swaps the analytics rows of an attempt and marks it processed in one transaction,
+// so processing an attempt again never duplicates rows
+func (r *questionAnswerRepository) ReplaceQuestionAnswers(attemptID uint, answers []models.UserQuestionAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("attempt_id = ?", attemptID).Delete(&models.UserQuestionAnswer{}).Error; err != nil {
+			return fmt.Errorf("failed to delete question answers: %w", err)
+		}
+
+		if len(answers) > 0 {
+			if err := tx.CreateInBatches(answers, 100).Error; err != nil {
+				return fmt.Errorf("failed to create question answers: %w", err)
+			}
+		}
+
+		err := tx.Model(&models.UserExamAttempt{}).Where("id = ?", attemptID).Updates(map[string]interface{}{
+			"is_scored":           true,
+			"analytics_failures":  0,
+			"analytics_failed_at": nil,
+			"analytics_error":     nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to mark attempt as processed: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetAnalyticsScanStart returns the ID to scan for pending attempts after: the one before the oldest attempt
+// that may still need analytics. Attempts in progress hold the mark back, since they finish out of ID order.
+func (r *questionAnswerRepository) GetAnalyticsScanStart() (uint, error) {
+	var oldestID *uint
+	err := r.db.Model(&models.UserExamAttempt{}).
+		Select("MIN(id)").
+		Where("is_scored = ? AND analytics_failures < ? AND status IN ?", false, models.MaxAnalyticsFailures, []models.AttemptStatus{
+			models.AttemptStatusStarted,
+			models.AttemptStatusCompleted,
+			models.AttemptStatusAutoSubmitted,
+		}).
+		Scan(&oldestID).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get analytics scan start: %w", err)
+	}
+
+	if oldestID == nil {
+		return 0, nil
+	}
+	return *oldestID - 1, nil
+}
+
+// RecordAnalyticsFailure counts a failed analytics run of an attempt and keeps its error
+func (r *questionAnswerRepository) RecordAnalyticsFailure(attemptID uint, reason string) error {
+	if len(reason) > 255 {
+		reason = reason[:255]
+	}
+
+	err := r.db.Model(&models.UserExamAttempt{}).Where("id = ?", attemptID).Updates(map[string]interface{}{
+		"analytics_failures":  gorm.Expr("analytics_failures + 1"),
+		"analytics_failed_at": time.Now(),
+		"analytics_error":     reason,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record analytics failure: %w", err)
+	}
+	return nil
+}
+
+// GetQuestionTopics returns the current system and subject of bank questions, keyed by question ID
+func (r *questionAnswerRepository) GetQuestionTopics(questionIDs []uint) (map[uint]QuestionTopic, error) {
+	var topics []QuestionTopic
+	// Soft-deleted questions still classify the answers given while they were live
+	err := r.db.Unscoped().Model(&models.Question{}).
+		Select("questions.id AS question_id, questions.system_id, systems.subject_id").
+		Joins("JOIN systems ON systems.id = questions.system_id").
+		Where("questions.id IN ?", questionIDs).
+		Scan(&topics).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question topics: %w", err)
+	}
+
+	byQuestion := make(map[uint]QuestionTopic, len(topics))
+	for _, topic := range topics {
+		byQuestion[topic.QuestionID] = topic
+	}
+	return byQuestion, nil
+}
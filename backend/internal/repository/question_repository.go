@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Question bank errors
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrSystemNotFound   = errors.New("system not found")
+)
+
+// QuestionFilters holds the optional filters for listing questions
+type QuestionFilters struct {
+	SubjectID       uint
+	SystemID        uint
+	QuestionType    models.QuestionType
+	DifficultyLevel models.DifficultyLevel
+	Tags            []string
+	UsageLevel      string
+	IsActive        *bool
+	Search          string
+	Offset          int
+	Limit           int
+}
+
+// QuestionCandidateCriteria narrows the active questions an exam can be built from
+type QuestionCandidateCriteria struct {
+	SubjectID     uint
+	SystemID      uint
+	QuestionTypes []models.QuestionType
+}
+
+// SystemQuestionCount holds the number of active questions in a system
+type SystemQuestionCount struct {
+	SystemID uint
+	Count    int64
+}
+
+// QuestionRepository handles database operations for the question bank
+type QuestionRepository interface {
+	CreateQuestion(question *models.Question) error
+	UpdateQuestion(question *models.Question) error
+	DeleteQuestion(id uint) error
+	GetQuestionByID(id uint) (*models.Question, error)
+	ListQuestions(filters QuestionFilters) ([]models.Question, int64, error)
+	GetSystemByID(id uint) (*models.System, error)
+	GetSubjectsWithSystems() ([]models.Subject, error)
+	GetQuestionCountsBySystem() ([]SystemQuestionCount, error)
+	GetCandidateQuestions(criteria QuestionCandidateCriteria) ([]models.Question, error)
+}
+
+// questionRepository implements QuestionRepository
+type questionRepository struct {
+	db *gorm.DB
+}
+
+// NewQuestionRepository creates a new question repository
+func NewQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &questionRepository{db: db}
+}
+
+// CreateQuestion inserts a new question
+func (r *questionRepository) CreateQuestion(question *models.Question) error {
+	return r.db.Create(question).Error
+}
+
+// UpdateQuestion saves all editable fields of an existing question
+func (r *questionRepository) UpdateQuestion(question *models.Question) error {
+	return r.db.Model(question).
+		Select("system_id", "question_text", "question_type", "difficulty_level", "options", "explanation", "reference", "tags", "is_active").
+		Updates(question).Error
+}
+
+// DeleteQuestion soft deletes a question so existing exam snapshots stay intact
+func (r *questionRepository) DeleteQuestion(id uint) error {
+	result := r.db.Delete(&models.Question{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuestionNotFound
+	}
+	return nil
+}
+
+// GetQuestionByID retrieves a question with its system and subject
+func (r *questionRepository) GetQuestionByID(id uint) (*models.Question, error) {
+	var question models.Question
+	err := r.db.Preload("System.Subject").First(&question, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// ListQuestions retrieves questions matching the filters along with the total count
+func (r *questionRepository) ListQuestions(filters QuestionFilters) ([]models.Question, int64, error) {
+	query := r.db.Model(&models.Question{})
+
+	if filters.SubjectID > 0 {
+		query = query.Where("system_id IN (?)", r.db.Model(&models.System{}).Select("id").Where("subject_id = ?", filters.SubjectID))
+	}
+	if filters.SystemID > 0 {
+		query = query.Where("system_id = ?", filters.SystemID)
+	}
+	if filters.QuestionType != "" {
+		query = query.Where("question_type = ?", filters.QuestionType)
+	}
+	if filters.DifficultyLevel != "" {
+		query = query.Where("difficulty_level = ?", filters.DifficultyLevel)
+	}
+	for _, tag := range filters.Tags {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+	if filters.IsActive != nil {
+		query = query.Where("is_active = ?", *filters.IsActive)
+	}
+	if filters.Search != "" {
+		query = query.Where("question_text LIKE ?", "%"+filters.Search+"%")
+	}
+
+	// Usage level ranges mirror models.Question.GetUsageLevel
+	switch filters.UsageLevel {
+	case "UNUSED":
+		query = query.Where("usage_count = 0")
+	case "LOW_USAGE":
+		query = query.Where("usage_count BETWEEN 1 AND 5")
+	case "MEDIUM_USAGE":
+		query = query.Where("usage_count BETWEEN 6 AND 15")
+	case "HIGH_USAGE":
+		query = query.Where("usage_count BETWEEN 16 AND 30")
+	case "OVERUSED":
+		query = query.Where("usage_count > 30")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var questions []models.Question
+	err := query.Preload("System.Subject").
+		Order("id DESC").
+		Offset(filters.Offset).
+		Limit(filters.Limit).
+		Find(&questions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return questions, total, nil
+}
+
+// GetSystemByID retrieves a system with its subject
+func (r *questionRepository) GetSystemByID(id uint) (*models.System, error) {
+	var system models.System
+	err := r.db.Preload("Subject").First(&system, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSystemNotFound
+		}
+		return nil, err
+	}
+	return &system, nil
+}
+
+// GetSubjectsWithSystems retrieves the subject/system taxonomy ordered for display
+func (r *questionRepository) GetSubjectsWithSystems() ([]models.Subject, error) {
+	var subjects []models.Subject
+	err := r.db.Preload("Systems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, name ASC")
+	}).Order("sort_order ASC, name ASC").Find(&subjects).Error
+	if err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+// GetQuestionCountsBySystem counts active questions per system
+func (r *questionRepository) GetQuestionCountsBySystem() ([]SystemQuestionCount, error) {
+	var counts []SystemQuestionCount
+	err := r.db.Model(&models.Question{}).
+		Select("system_id, COUNT(*) as count").
+		Where("is_active = ?", true).
+		Group("system_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetCandidateQuestions retrieves active questions matching the criteria for exam generation
+func (r *questionRepository) GetCandidateQuestions(criteria QuestionCandidateCriteria) ([]models.Question, error) {
+	query := r.db.Where("is_active = ?", true)
+
+	if criteria.SubjectID > 0 {
+		query = query.Where("system_id IN (?)", r.db.Model(&models.System{}).Select("id").Where("subject_id = ?", criteria.SubjectID))
+	}
+	if criteria.SystemID > 0 {
+		query = query.Where("system_id = ?", criteria.SystemID)
+	}
+	if len(criteria.QuestionTypes) > 0 {
+		query = query.Where("question_type IN ?", criteria.QuestionTypes)
+	}
+
+	// Systems are loaded so exam snapshots can record each question's subject
+	var questions []models.Question
+	if err := query.Preload("System").Order("id ASC").Find(&questions).Error; err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
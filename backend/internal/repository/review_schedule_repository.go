@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReviewScheduleRepository stores users' spaced-repetition schedules
+type ReviewScheduleRepository interface {
+	GetSchedules(userID uint, questionIDs []uint) (map[uint]models.QuestionReviewSchedule, error)
+	SaveSchedules(schedules []models.QuestionReviewSchedule) error
+	GetDueSchedules(userID uint, dueBy time.Time, limit int) ([]models.QuestionReviewSchedule, error)
+	CountDueSchedules(userID uint, dueBy time.Time) (int64, error)
+}
+
+// reviewScheduleRepository implements ReviewScheduleRepository
+type reviewScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewScheduleRepository creates a new review schedule repository
+func NewReviewScheduleRepository(db *gorm.DB) ReviewScheduleRepository {
+	return &reviewScheduleRepository{
+		db: db,
+	}
+}
+
+// GetSchedules returns the user's schedules for the given questions keyed by question ID
+func (r *reviewScheduleRepository) GetSchedules(userID uint, questionIDs []uint) (map[uint]models.QuestionReviewSchedule, error) {
+	schedules := make(map[uint]models.QuestionReviewSchedule)
+	if len(questionIDs) == 0 {
+		return schedules, nil
+	}
+
+	var rows []models.QuestionReviewSchedule
+	if err := r.db.Where("user_id = ? AND question_id IN ?", userID, questionIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get review schedules: %w", err)
+	}
+	for _, row := range rows {
+		schedules[row.QuestionID] = row
+	}
+	return schedules, nil
+}
+
+// SaveSchedules creates new schedules and updates existing ones in one transaction
+func (r *reviewScheduleRepository) SaveSchedules(schedules []models.QuestionReviewSchedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range schedules {
+			if err := tx.Omit("Question").Save(&schedules[i]).Error; err != nil {
+				return fmt.Errorf("failed to save review schedule for question %d: %w", schedules[i].QuestionID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// dueSchedules selects the user's schedules due by the given time for questions still in the bank
+func (r *reviewScheduleRepository) dueSchedules(userID uint, dueBy time.Time) *gorm.DB {
+	return r.db.Model(&models.QuestionReviewSchedule{}).
+		Joins("JOIN questions ON questions.id = question_review_schedules.question_id AND questions.is_active = ? AND questions.deleted_at IS NULL", true).
+		Where("question_review_schedules.user_id = ? AND question_review_schedules.due_at <= ?", userID, dueBy)
+}
+
+// GetDueSchedules returns the user's due schedules, most overdue first, with their questions and systems
+func (r *reviewScheduleRepository) GetDueSchedules(userID uint, dueBy time.Time, limit int) ([]models.QuestionReviewSchedule, error) {
+	var schedules []models.QuestionReviewSchedule
+	err := r.dueSchedules(userID, dueBy).
+		Preload("Question.System").
+		Order("question_review_schedules.due_at ASC, question_review_schedules.ease_factor ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due review schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// CountDueSchedules returns how many of the user's questions are due by the given time
+func (r *reviewScheduleRepository) CountDueSchedules(userID uint, dueBy time.Time) (int64, error) {
+	var count int64
+	if err := r.dueSchedules(userID, dueBy).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count due review schedules: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TopicPerformance is a user's aggregated answers for one subject or system
+type TopicPerformance struct {
+	TopicID        uint
+	TopicName      string
+	SubjectID      uint // Parent subject, only set for systems
+	SubjectName    string
+	Attempts       int // Distinct exam attempts that included the topic
+	Questions      int
+	Answered       int
+	Correct        int
+	Score          float64
+	MaxScore       float64
+	TotalTimeSpent int // Seconds
+}
+
+// TopicTrendPoint is a user's answers for one subject within one week
+type TopicTrendPoint struct {
+	TopicID   uint
+	WeekStart string // Monday of the week, YYYY-MM-DD
+	Questions int
+	Correct   int
+	Score     float64
+	MaxScore  float64
+}
+
+// TopicPerformanceRepository aggregates a user's user_question_answers rows by subject and system
+type TopicPerformanceRepository interface {
+	GetSubjectPerformance(userID uint) ([]TopicPerformance, error)
+	GetSystemPerformance(userID uint) ([]TopicPerformance, error)
+	GetSubjectTrend(userID uint, since time.Time) ([]TopicTrendPoint, error)
+}
+
+// topicPerformanceRepository implements TopicPerformanceRepository
+type topicPerformanceRepository struct {
+	db *gorm.DB
+}
+
+// NewTopicPerformanceRepository creates a new topic performance repository
+func NewTopicPerformanceRepository(db *gorm.DB) TopicPerformanceRepository {
+	return &topicPerformanceRepository{
+		db: db,
+	}
+}
+
+// topicAggregates are the columns shared by the subject and system aggregations
+const topicAggregates = `
+	COUNT(DISTINCT user_question_answers.attempt_id) AS attempts,
+	COUNT(*) AS questions,
+	COALESCE(SUM(CASE WHEN user_question_answers.is_skipped THEN 0 ELSE 1 END), 0) AS answered,
+	COALESCE(SUM(CASE WHEN user_question_answers.is_correct THEN 1 ELSE 0 END), 0) AS correct,
+	COALESCE(SUM(user_question_answers.partial_score), 0) AS score,
+	COALESCE(SUM(user_question_answers.max_score), 0) AS max_score,
+	COALESCE(SUM(user_question_answers.time_spent), 0) AS total_time_spent`
+
+// GetSubjectPerformance returns the user's performance per subject. Unclassified answers are grouped under topic 0.
+func (r *topicPerformanceRepository) GetSubjectPerformance(userID uint) ([]TopicPerformance, error) {
+	var rows []TopicPerformance
+	err := r.db.Model(&models.UserQuestionAnswer{}).
+		Select("user_question_answers.subject_id AS topic_id, COALESCE(subjects.name, '') AS topic_name,"+topicAggregates).
+		Joins("LEFT JOIN subjects ON subjects.id = user_question_answers.subject_id").
+		Where("user_question_answers.user_id = ?", userID).
+		Group("user_question_answers.subject_id, subjects.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subject performance: %w", err)
+	}
+	return rows, nil
+}
+
+// GetSystemPerformance returns the user's performance per system with each system's subject
+func (r *topicPerformanceRepository) GetSystemPerformance(userID uint) ([]TopicPerformance, error) {
+	var rows []TopicPerformance
+	err := r.db.Model(&models.UserQuestionAnswer{}).
+		Select(`user_question_answers.system_id AS topic_id, COALESCE(systems.name, '') AS topic_name,
+			user_question_answers.subject_id, COALESCE(subjects.name, '') AS subject_name,`+topicAggregates).
+		Joins("LEFT JOIN systems ON systems.id = user_question_answers.system_id").
+		Joins("LEFT JOIN subjects ON subjects.id = user_question_answers.subject_id").
+		Where("user_question_answers.user_id = ?", userID).
+		Group("user_question_answers.system_id, systems.name, user_question_answers.subject_id, subjects.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system performance: %w", err)
+	}
+	return rows, nil
+}
+
+// GetSubjectTrend returns the user's weekly performance per subject for answers since the given time, oldest week first
+func (r *topicPerformanceRepository) GetSubjectTrend(userID uint, since time.Time) ([]TopicTrendPoint, error) {
+	const weekStart = "DATE_FORMAT(DATE_SUB(user_question_answers.answered_at, INTERVAL WEEKDAY(user_question_answers.answered_at) DAY), '%Y-%m-%d')"
+
+	var rows []TopicTrendPoint
+	err := r.db.Model(&models.UserQuestionAnswer{}).
+		Select(`user_question_answers.subject_id AS topic_id, `+weekStart+` AS week_start,
+			COUNT(*) AS questions,
+			COALESCE(SUM(CASE WHEN user_question_answers.is_correct THEN 1 ELSE 0 END), 0) AS correct,
+			COALESCE(SUM(user_question_answers.partial_score), 0) AS score,
+			COALESCE(SUM(user_question_answers.max_score), 0) AS max_score`).
+		Where("user_question_answers.user_id = ? AND user_question_answers.answered_at >= ?", userID, since).
+		Group("user_question_answers.subject_id, week_start").
+		Order("week_start ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subject trend: %w", err)
+	}
+	return rows, nil
+}
@@ -34,6 +34,15 @@ func SuccessResponseWithMessage(c *gin.Context, data interface{}, message string
 	})
 }
 
+// CreatedResponse sends a wrapped JSON response for a newly created resource
+func CreatedResponse(c *gin.Context, data interface{}, message string) {
+	c.JSON(http.StatusCreated, WrappedResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // ErrorNotFound sends a not found error response
 func ErrorNotFound(c *gin.Context, message string) {
 	c.JSON(http.StatusNotFound, ErrorResponse{
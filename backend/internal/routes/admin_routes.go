@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"github.com/Mahfuz2811/medecole/backend/internal/database"
+	"github.com/Mahfuz2811/medecole/backend/internal/handlers"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/middleware"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAdminRoutes sets up content management routes for administrators
+func SetupAdminRoutes(router *gin.Engine, db *database.Database, jwtSecret string, authService *service.AuthService) {
+	// Initialize dependencies
+	questionRepo := repository.NewQuestionRepository(db.DB)
+	questionMapper := mapper.NewQuestionMapper()
+	questionService := service.NewQuestionService(questionRepo, questionMapper)
+	questionHandler := handlers.NewQuestionHandler(questionService)
+
+	examAdminRepo := repository.NewExamAdminRepository(db.DB)
+	examBuilderService := service.NewExamBuilderService(examAdminRepo, questionRepo)
+	examAdminHandler := handlers.NewExamAdminHandler(examBuilderService)
+
+	itemAnalysisRepo := repository.NewItemAnalysisRepository(db.DB)
+	itemAnalysisService := service.NewItemAnalysisService(itemAnalysisRepo, examAdminRepo, questionRepo)
+	itemAnalysisHandler := handlers.NewItemAnalysisHandler(itemAnalysisService)
+
+	// Admin API group - all routes require an authenticated admin or editor
+	admin := router.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware(jwtSecret, authService))
+	admin.Use(middleware.RequireRole(models.RoleAdmin, models.RoleEditor))
+	{
+		// Subject/system taxonomy
+		admin.GET("/taxonomy", middleware.RequirePermission(models.PermissionManageQuestions), questionHandler.GetTaxonomy) // GET /api/admin/taxonomy
+
+		// Question bank
+		questions := admin.Group("/questions")
+		questions.Use(middleware.RequirePermission(models.PermissionManageQuestions))
+		{
+			questions.GET("", questionHandler.ListQuestions)         // GET /api/admin/questions?system_id=1&question_type=SBA
+			questions.POST("", questionHandler.CreateQuestion)       // POST /api/admin/questions
+			questions.GET("/:id", questionHandler.GetQuestion)       // GET /api/admin/questions/:id
+			questions.PUT("/:id", questionHandler.UpdateQuestion)    // PUT /api/admin/questions/:id
+			questions.DELETE("/:id", questionHandler.DeleteQuestion) // DELETE /api/admin/questions/:id
+		}
+
+		// Exam authoring
+		exams := admin.Group("/exams")
+		exams.Use(middleware.RequirePermission(models.PermissionManageExams))
+		{
+			exams.POST("/:id/build", examAdminHandler.BuildExam) // POST /api/admin/exams/:id/build
+		}
+
+		// Item analysis reports
+		analytics := admin.Group("/analytics")
+		analytics.Use(middleware.RequirePermission(models.PermissionViewAnalytics))
+		{
+			analytics.GET("/exams/:id/items", itemAnalysisHandler.GetExamItemAnalysis)         // GET /api/admin/analytics/exams/:id/items
+			analytics.GET("/questions/:id/items", itemAnalysisHandler.GetQuestionItemAnalysis) // GET /api/admin/analytics/questions/:id/items
+		}
+	}
+}
@@ -28,6 +28,10 @@ func SetupDashboardRoutes(router *gin.Engine, db *database.Database, jwtSecret s
 	// Create dashboard handler
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
 
+	// Create subject/system performance handler
+	topicPerformanceService := service.NewTopicPerformanceService(repository.NewTopicPerformanceRepository(db.DB))
+	topicPerformanceHandler := handlers.NewTopicPerformanceHandler(topicPerformanceService)
+
 	// Dashboard API group
 	api := router.Group("/api")
 
@@ -37,6 +41,7 @@ func SetupDashboardRoutes(router *gin.Engine, db *database.Database, jwtSecret s
 	{
 		// Main dashboard endpoints
 		dashboard.GET("/summary", dashboardHandler.GetDashboardSummary)
-		dashboard.GET("/enrollments", dashboardHandler.GetDashboardEnrollments) // GET /api/dashboard/enrollments
+		dashboard.GET("/enrollments", dashboardHandler.GetDashboardEnrollments)    // GET /api/dashboard/enrollments
+		dashboard.GET("/performance", topicPerformanceHandler.GetTopicPerformance) // GET /api/dashboard/performance?weeks=12
 	}
 }
@@ -30,6 +30,13 @@ func SetupEnrollmentRoutes(router *gin.Engine, db *database.Database, jwtSecret
 		enrollmentRoutes.POST("", enrollmentHandler.EnrollInPackage)             // POST /api/enrollments
 		enrollmentRoutes.GET("/status", enrollmentHandler.CheckEnrollmentStatus) // GET /api/enrollments/status?package_id=1
 
+		// Trial operations
+		enrollmentRoutes.POST("/:id/extend-trial", enrollmentHandler.ExtendTrial) // POST /api/enrollments/1/extend-trial
+
+		// Renewal operations
+		enrollmentRoutes.POST("/:id/renew", enrollmentHandler.RenewEnrollment)     // POST /api/enrollments/1/renew
+		enrollmentRoutes.GET("/:id/renewals", enrollmentHandler.GetRenewalHistory) // GET /api/enrollments/1/renewals
+
 		// Coupon operations
 		enrollmentRoutes.POST("/validate-coupon", enrollmentHandler.ValidateCoupon) // POST /api/enrollments/validate-coupon
 	}
@@ -31,7 +31,22 @@ func SetupExamRoutes(router *gin.Engine, db *database.Database, cfg *config.Conf
 	examService := service.NewExamService(examRepo, enrollmentRepo, examMapper)
 	examHandler := handlers.NewExamHandler(examService)
 
-	setupRoutes(router, examHandler, jwtSecret, authService)
+	// Practice and review sessions run on generated exams through the same session endpoints
+	reviewRepo := repository.NewReviewScheduleRepository(db.DB)
+	practiceService := service.NewPracticeService(
+		repository.NewPracticeRepository(db.DB),
+		repository.NewTopicPerformanceRepository(db.DB),
+		reviewRepo,
+		examRepo,
+		enrollmentRepo,
+		examMapper,
+	)
+	practiceHandler := handlers.NewPracticeHandler(practiceService, service.NewReviewService(reviewRepo))
+
+	// Bookmarks are made from the results of finished sessions
+	bookmarkHandler := handlers.NewBookmarkHandler(service.NewBookmarkService(repository.NewBookmarkRepository(db.DB), examRepo))
+
+	setupRoutes(router, examHandler, practiceHandler, bookmarkHandler, jwtSecret, authService)
 }
 
 // CreateExamRepository creates an exam repository instance (used by background services)
@@ -49,8 +64,15 @@ func CreateExamRepository(db *database.Database, cfg *config.Config) repository.
 	return repository.NewExamRepository(db.DB, cacheInstance)
 }
 
+// CreateExamService creates an exam service instance on top of an existing exam repository (used by background services)
+func CreateExamService(db *database.Database, examRepo repository.ExamRepository) service.ExamService {
+	enrollmentRepo := repository.NewEnrollmentRepository(db)
+	examMapper := mapper.NewExamMapper()
+	return service.NewExamService(examRepo, enrollmentRepo, examMapper)
+}
+
 // setupRoutes configures the actual route handlers
-func setupRoutes(router *gin.Engine, examHandler *handlers.ExamHandler, jwtSecret string, authService *service.AuthService) { // Package API routes
+func setupRoutes(router *gin.Engine, examHandler *handlers.ExamHandler, practiceHandler *handlers.PracticeHandler, bookmarkHandler *handlers.BookmarkHandler, jwtSecret string, authService *service.AuthService) { // Package API routes
 	api := router.Group("/api")
 	{
 		exams := api.Group("/exams")
@@ -66,12 +88,18 @@ func setupRoutes(router *gin.Engine, examHandler *handlers.ExamHandler, jwtSecre
 				// POST /api/exams/:slug/start - Start a new exam session
 				exams.POST("/:slug/start", examHandler.StartExam)
 
+				// GET /api/exams/:slug/leaderboard?package_slug= - Get the leaderboard of a mock or final exam
+				exams.GET("/:slug/leaderboard", examHandler.GetLeaderboard)
+
 				// GET /api/exams/session/:sessionId - Get exam session data
 				exams.GET("/session/:sessionId", examHandler.GetSession)
 
 				// PUT /api/exams/session/:sessionId/sync - Sync user answers during exam session
 				exams.PUT("/session/:sessionId/sync", examHandler.SyncSession)
 
+				// POST /api/exams/session/:sessionId/check - Check one answer in a tutor mode session
+				exams.POST("/session/:sessionId/check", examHandler.CheckAnswer)
+
 				// POST /api/exams/submit - Submit exam and finalize session
 				exams.POST("/submit", examHandler.SubmitExam)
 
@@ -79,5 +107,38 @@ func setupRoutes(router *gin.Engine, examHandler *handlers.ExamHandler, jwtSecre
 				exams.GET("/results/:sessionId", examHandler.GetExamResults)
 			}
 		}
+
+		practice := api.Group("/practice")
+		practice.Use(middleware.AuthMiddleware(jwtSecret, authService))
+		{
+			// POST /api/practice/start - Start a practice session, from bookmarks with "bookmarked": true (then use the /api/exams/session endpoints)
+			practice.POST("/start", practiceHandler.StartPractice)
+		}
+
+		review := api.Group("/review")
+		review.Use(middleware.AuthMiddleware(jwtSecret, authService))
+		{
+			// GET /api/review/due - Get the questions due for spaced-repetition review today
+			review.GET("/due", practiceHandler.GetReviewQueue)
+
+			// POST /api/review/start - Start a review session on the questions due today
+			review.POST("/start", practiceHandler.StartReview)
+		}
+
+		bookmarks := api.Group("/bookmarks")
+		bookmarks.Use(middleware.AuthMiddleware(jwtSecret, authService))
+		{
+			// GET /api/bookmarks?subject_id=&system_id= - List bookmarked questions with their notes
+			bookmarks.GET("", bookmarkHandler.GetBookmarks)
+
+			// POST /api/bookmarks - Bookmark a question from the results of a finished session
+			bookmarks.POST("", bookmarkHandler.BookmarkQuestion)
+
+			// PUT /api/bookmarks/:questionId - Change the note of a bookmarked question
+			bookmarks.PUT("/:questionId", bookmarkHandler.UpdateNote)
+
+			// DELETE /api/bookmarks/:questionId - Remove a bookmark
+			bookmarks.DELETE("/:questionId", bookmarkHandler.RemoveBookmark)
+		}
 	}
 }
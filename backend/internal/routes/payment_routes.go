@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"log"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/config"
+	"github.com/Mahfuz2811/medecole/backend/internal/database"
+	"github.com/Mahfuz2811/medecole/backend/internal/handlers"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/middleware"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupPaymentRoutes sets up checkout, payment gateway and admin refund routes
+func SetupPaymentRoutes(router *gin.Engine, db *database.Database, cfg *config.Config, jwtSecret string, authService *service.AuthService) {
+	// Refunds go through the same gateway instances the payments were taken with
+	providers := CreatePaymentProviders(cfg.Payment, cfg.Server.GinMode)
+	paymentRepo := repository.NewPaymentRepository(db.DB)
+
+	paymentService := CreatePaymentService(paymentRepo, providers, cfg)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, cfg.CORS.FrontendURL)
+
+	refundService := service.NewRefundService(paymentRepo, repository.NewEnrollmentRepository(db), providers, mapper.NewEnrollmentMapper())
+	refundHandler := handlers.NewRefundHandler(refundService)
+
+	payments := router.Group("/api/payments")
+	{
+		// Gateways call these without a user session, payments are verified with the gateway instead
+		payments.GET("/callback/:provider", paymentHandler.Callback)  // GET /api/payments/callback/bkash
+		payments.POST("/callback/:provider", paymentHandler.Callback) // POST /api/payments/callback/sslcommerz
+		payments.POST("/ipn/:provider", paymentHandler.Notify)        // POST /api/payments/ipn/sslcommerz
+
+		authenticated := payments.Group("")
+		authenticated.Use(middleware.AuthMiddleware(jwtSecret, authService))
+		{
+			authenticated.GET("/providers", paymentHandler.GetProviders)     // GET /api/payments/providers
+			authenticated.POST("/checkout", paymentHandler.InitiateCheckout) // POST /api/payments/checkout
+			authenticated.GET("/:transactionId", paymentHandler.GetPayment)  // GET /api/payments/MED123...
+		}
+	}
+
+	// Admin refunds and cancellations
+	enrollments := router.Group("/api/admin/enrollments")
+	enrollments.Use(middleware.AuthMiddleware(jwtSecret, authService))
+	enrollments.Use(middleware.RequireRole(models.RoleAdmin, models.RoleEditor))
+	enrollments.Use(middleware.RequirePermission(models.PermissionManageEnrollments))
+	{
+		enrollments.POST("/:id/refund", refundHandler.RefundEnrollment) // POST /api/admin/enrollments/:id/refund
+		enrollments.POST("/:id/cancel", refundHandler.CancelEnrollment) // POST /api/admin/enrollments/:id/cancel
+	}
+
+	refunds := router.Group("/api/admin/refunds")
+	refunds.Use(middleware.AuthMiddleware(jwtSecret, authService))
+	refunds.Use(middleware.RequireRole(models.RoleAdmin, models.RoleEditor))
+	refunds.Use(middleware.RequirePermission(models.PermissionManageEnrollments))
+	{
+		refunds.POST("/reconcile", refundHandler.ReconcileRefunds) // POST /api/admin/refunds/reconcile
+	}
+}
+
+// CreatePaymentService creates a payment service with the given gateways
+func CreatePaymentService(paymentRepo repository.PaymentRepository, providers *payment.Registry, cfg *config.Config) service.PaymentService {
+	return service.NewPaymentService(
+		paymentRepo,
+		providers,
+		service.PaymentConfig{
+			Currency:        cfg.Payment.Currency,
+			CallbackBaseURL: cfg.Payment.CallbackBaseURL,
+		},
+	)
+}
+
+// CreatePaymentProviders registers the gateways that have credentials, plus the local fake when enabled.
+// The fake marks checkouts paid without taking money, so it is never offered in release mode.
+func CreatePaymentProviders(cfg config.PaymentConfig, ginMode string) *payment.Registry {
+	registry := payment.NewRegistry()
+
+	if cfg.SSLCommerz.StoreID != "" {
+		registry.Register(payment.NewSSLCommerzProvider(payment.SSLCommerzConfig{
+			StoreID:       cfg.SSLCommerz.StoreID,
+			StorePassword: cfg.SSLCommerz.StorePassword,
+			Sandbox:       cfg.SSLCommerz.Sandbox,
+		}))
+	}
+	if cfg.BKash.AppKey != "" {
+		registry.Register(payment.NewBKashProvider(payment.BKashConfig{
+			AppKey:    cfg.BKash.AppKey,
+			AppSecret: cfg.BKash.AppSecret,
+			Username:  cfg.BKash.Username,
+			Password:  cfg.BKash.Password,
+			Sandbox:   cfg.BKash.Sandbox,
+		}))
+	}
+	switch {
+	case cfg.FakeEnabled && ginMode == gin.ReleaseMode:
+		log.Println("Fake payment provider refused in release mode, PAYMENT_FAKE_ENABLED is ignored")
+	case cfg.FakeEnabled:
+		log.Println("Fake payment provider enabled, checkouts can be paid without a gateway")
+		registry.Register(payment.NewFakeProvider(cfg.FakeAutoApprove))
+	}
+
+	return registry
+}
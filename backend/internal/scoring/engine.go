@@ -0,0 +1,113 @@
+package scoring
+
+import "github.com/Mahfuz2811/medecole/backend/internal/models"
+
+// Result is the outcome of scoring one question
+type Result struct {
+	QuestionID    uint
+	UserAnswer    []string
+	CorrectAnswer interface{} // SBA: option key, TRUE_FALSE: map of option key to expected value
+	Answered      bool
+	IsCorrect     bool
+	PointsEarned  float64 // Net points; scorers return the credit before Penalty and the engine deducts it
+	Penalty       float64 // Points deducted by negative marking
+	MaxPoints     float64
+}
+
+// ExamResult is the outcome of scoring every question of an exam
+type ExamResult struct {
+	Results         []Result // Same order as the questions
+	TotalPoints     float64  // Net points after penalties
+	PenaltyPoints   float64  // Points deducted by negative marking
+	MaxPoints       float64
+	CorrectAnswers  int
+	NegativeMarking bool // Whether the policy could deduct points
+}
+
+// RawPoints returns the points earned before negative marking
+func (r ExamResult) RawPoints() float64 {
+	return r.TotalPoints + r.PenaltyPoints
+}
+
+// Scorer scores answers for one question type. PointsEarned is the credit before
+// penalties; the engine deducts Penalty and applies the policy's score floor.
+type Scorer interface {
+	Score(question Question, answer []string, policy Policy) Result
+}
+
+// Engine scores exam answers with a policy, dispatching to a Scorer per question type
+type Engine struct {
+	policy  Policy
+	scorers map[models.QuestionType]Scorer
+}
+
+// NewEngine creates an engine with the built-in SBA and TRUE_FALSE scorers
+func NewEngine(policy Policy) *Engine {
+	engine := &Engine{
+		policy:  policy,
+		scorers: make(map[models.QuestionType]Scorer),
+	}
+	engine.Register(models.QuestionTypeSBA, &sbaScorer{})
+	engine.Register(models.QuestionTypeTrueFalse, &trueFalseScorer{})
+	return engine
+}
+
+// Register sets the scorer for a question type, replacing any existing one
+func (e *Engine) Register(questionType models.QuestionType, scorer Scorer) {
+	e.scorers[questionType] = scorer
+}
+
+// Policy returns the policy the engine scores with
+func (e *Engine) Policy() Policy {
+	return e.policy
+}
+
+// ScoreQuestion scores a single saved answer. Unsupported question types earn nothing.
+func (e *Engine) ScoreQuestion(question Question, rawAnswer string) Result {
+	answer := ParseAnswer(rawAnswer)
+
+	scorer, ok := e.scorers[question.Type]
+	if !ok {
+		return Result{
+			QuestionID: question.ID,
+			UserAnswer: answer,
+			Answered:   len(answer) > 0,
+			MaxPoints:  float64(question.Points),
+		}
+	}
+
+	result := scorer.Score(question, answer, e.policy)
+	result.QuestionID = question.ID
+	result.UserAnswer = answer
+	result.MaxPoints = float64(question.Points)
+
+	// Deduct penalties, keeping only what was actually taken off when the score is floored
+	result.PointsEarned -= result.Penalty
+	if e.policy.NegativeMarking.FloorQuestionScore && result.PointsEarned < 0 {
+		result.Penalty += result.PointsEarned
+		result.PointsEarned = 0
+	}
+	return result
+}
+
+// ScoreExam scores all questions against the saved answers keyed by question ID
+func (e *Engine) ScoreExam(questions []Question, answers map[uint]string) ExamResult {
+	examResult := ExamResult{
+		Results:         make([]Result, 0, len(questions)),
+		NegativeMarking: e.policy.HasNegativeMarking(),
+	}
+
+	for _, question := range questions {
+		result := e.ScoreQuestion(question, answers[question.ID])
+
+		examResult.Results = append(examResult.Results, result)
+		examResult.TotalPoints += result.PointsEarned
+		examResult.PenaltyPoints += result.Penalty
+		examResult.MaxPoints += result.MaxPoints
+		if result.IsCorrect {
+			examResult.CorrectAnswers++
+		}
+	}
+
+	return examResult
+}
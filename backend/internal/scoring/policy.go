@@ -0,0 +1,101 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+)
+
+// Policy configures how an exam's answers are scored
+type Policy struct {
+	PartialCredit    bool            `json:"partial_credit"`     // TRUE_FALSE earns a share of the points per correct stem
+	UseOptionWeights bool            `json:"use_option_weights"` // Option "weight" values decide the credit instead of equal shares
+	NegativeMarking  NegativeMarking `json:"negative_marking"`
+}
+
+// NegativeMarking configures penalties for wrong answers
+type NegativeMarking struct {
+	Enabled            bool     `json:"enabled"`
+	PenaltyRatio       float64  `json:"penalty_ratio"`        // Fraction of the question (or stem) points deducted per wrong answer
+	SBAPenalty         *float64 `json:"sba_penalty"`          // Points deducted per wrong SBA answer, overrides penalty_ratio
+	TrueFalsePenalty   *float64 `json:"true_false_penalty"`   // Points deducted per wrong TRUE_FALSE stem, overrides penalty_ratio
+	PenalizeSkipped    bool     `json:"penalize_skipped"`     // Unanswered questions and stems are penalised like wrong ones
+	FloorQuestionScore bool     `json:"floor_question_score"` // A question never scores below zero, whatever its penalties
+}
+
+// DefaultPolicy returns the policy used by exams that do not configure one
+func DefaultPolicy() Policy {
+	return Policy{
+		PartialCredit: true,
+	}
+}
+
+// ParsePolicy decodes an exam's scoring policy JSON on top of the default policy.
+// A nil or empty value returns the default policy.
+func ParsePolicy(raw *string) (Policy, error) {
+	policy := DefaultPolicy()
+	if raw == nil || *raw == "" {
+		return policy, nil
+	}
+
+	if err := json.Unmarshal([]byte(*raw), &policy); err != nil {
+		return Policy{}, fmt.Errorf("invalid scoring policy: %w", err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
+// Validate checks the policy values are in range
+func (p Policy) Validate() error {
+	marking := p.NegativeMarking
+	if marking.PenaltyRatio < 0 || marking.PenaltyRatio > 1 {
+		return fmt.Errorf("invalid scoring policy: penalty_ratio must be between 0 and 1, got %v", marking.PenaltyRatio)
+	}
+	if marking.SBAPenalty != nil && *marking.SBAPenalty < 0 {
+		return fmt.Errorf("invalid scoring policy: sba_penalty must not be negative, got %v", *marking.SBAPenalty)
+	}
+	if marking.TrueFalsePenalty != nil && *marking.TrueFalsePenalty < 0 {
+		return fmt.Errorf("invalid scoring policy: true_false_penalty must not be negative, got %v", *marking.TrueFalsePenalty)
+	}
+	return nil
+}
+
+// HasNegativeMarking reports whether any answer can lose points under this policy
+func (p Policy) HasNegativeMarking() bool {
+	marking := p.NegativeMarking
+	if !marking.Enabled {
+		return false
+	}
+	return marking.PenaltyRatio > 0 ||
+		(marking.SBAPenalty != nil && *marking.SBAPenalty > 0) ||
+		(marking.TrueFalsePenalty != nil && *marking.TrueFalsePenalty > 0)
+}
+
+// penalty returns the points deducted for one wrong answer (or stem) worth points
+func (p Policy) penalty(questionType models.QuestionType, points float64) float64 {
+	marking := p.NegativeMarking
+	if !marking.Enabled {
+		return 0
+	}
+
+	switch {
+	case questionType == models.QuestionTypeSBA && marking.SBAPenalty != nil:
+		return *marking.SBAPenalty
+	case questionType == models.QuestionTypeTrueFalse && marking.TrueFalsePenalty != nil:
+		return *marking.TrueFalsePenalty
+	}
+	return marking.PenaltyRatio * points
+}
+
+// skippedPenalty returns the points deducted for one unanswered question (or stem) worth points
+func (p Policy) skippedPenalty(questionType models.QuestionType, points float64) float64 {
+	if !p.NegativeMarking.PenalizeSkipped {
+		return 0
+	}
+	return p.penalty(questionType, points)
+}
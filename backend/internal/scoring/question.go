@@ -0,0 +1,108 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+)
+
+// Option is an answer option of a question snapshot
+type Option struct {
+	Key       string
+	Text      string
+	IsCorrect bool
+	Weight    *float64 // Credit weight, only used when Policy.UseOptionWeights is on
+}
+
+// Question is a question from Exam.QuestionsData in the form scorers work with
+type Question struct {
+	ID          uint
+	Text        string
+	Type        models.QuestionType
+	Options     []Option // Sorted by key
+	Points      int
+	Explanation string
+	Reference   string
+}
+
+// snapshotQuestion mirrors the JSON stored in Exam.QuestionsData
+type snapshotQuestion struct {
+	ID           uint                      `json:"id"`
+	QuestionText string                    `json:"question_text"`
+	QuestionType models.QuestionType       `json:"question_type"`
+	Options      map[string]snapshotOption `json:"options"`
+	Points       int                       `json:"points"`
+	Explanation  *string                   `json:"explanation"`
+	Reference    *string                   `json:"reference"`
+}
+
+// snapshotOption mirrors an option stored in Exam.QuestionsData
+type snapshotOption struct {
+	Text      string   `json:"text"`
+	IsCorrect bool     `json:"is_correct"`
+	Weight    *float64 `json:"weight,omitempty"`
+}
+
+// ParseQuestions decodes an exam's QuestionsData, keeping the exam's question order
+func ParseQuestions(questionsData string) ([]Question, error) {
+	var snapshot []snapshotQuestion
+	if err := json.Unmarshal([]byte(questionsData), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse exam questions: %w", err)
+	}
+
+	questions := make([]Question, 0, len(snapshot))
+	for _, item := range snapshot {
+		question := Question{
+			ID:      item.ID,
+			Text:    item.QuestionText,
+			Type:    item.QuestionType,
+			Options: make([]Option, 0, len(item.Options)),
+			Points:  item.Points,
+		}
+		if item.Explanation != nil {
+			question.Explanation = *item.Explanation
+		}
+		if item.Reference != nil {
+			question.Reference = *item.Reference
+		}
+
+		for key, option := range item.Options {
+			question.Options = append(question.Options, Option{
+				Key:       key,
+				Text:      option.Text,
+				IsCorrect: option.IsCorrect,
+				Weight:    option.Weight,
+			})
+		}
+		sort.Slice(question.Options, func(i, j int) bool {
+			return question.Options[i].Key < question.Options[j].Key
+		})
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+// ParseAnswer decodes a saved answer: a JSON array for TRUE_FALSE ("a:true" entries) or a bare option key for SBA
+func ParseAnswer(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal([]byte(raw), &values); err == nil {
+		return values
+	}
+	return []string{raw}
+}
+
+// optionWeight returns the option's credit weight, defaulting to 1
+func optionWeight(option Option) float64 {
+	if option.Weight == nil {
+		return 1
+	}
+	return *option.Weight
+}
@@ -0,0 +1,106 @@
+package scoring
+
+import "strings"
+
+// sbaScorer scores single best answer questions
+type sbaScorer struct{}
+
+// Score awards full points for the correct option. With option weights, a wrong option
+// earns its weight as a share of the points; negative marking penalises other wrong
+// answers, and skipped ones when the policy says so.
+func (s *sbaScorer) Score(question Question, answer []string, policy Policy) Result {
+	maxPoints := float64(question.Points)
+	result := Result{}
+
+	var selected *Option
+	for i, option := range question.Options {
+		if option.IsCorrect && result.CorrectAnswer == nil {
+			result.CorrectAnswer = option.Key
+		}
+		if len(answer) == 1 && option.Key == answer[0] {
+			selected = &question.Options[i]
+		}
+	}
+
+	if len(answer) == 0 {
+		result.Penalty = policy.skippedPenalty(question.Type, maxPoints)
+		return result
+	}
+	result.Answered = true
+
+	switch {
+	case selected != nil && selected.IsCorrect:
+		result.IsCorrect = true
+		result.PointsEarned = maxPoints
+	case selected != nil && policy.UseOptionWeights && selected.Weight != nil:
+		result.PointsEarned = optionWeight(*selected) * maxPoints
+	default:
+		result.Penalty = policy.penalty(question.Type, maxPoints)
+	}
+
+	return result
+}
+
+// trueFalseScorer scores questions where every stem is marked true or false
+type trueFalseScorer struct{}
+
+// Score awards full points when every stem is right. Otherwise partial credit (if enabled)
+// gives a share per correct stem, equal or by option weight, and negative marking
+// penalises each wrong stem, and each unmarked stem when the policy says so.
+func (s *trueFalseScorer) Score(question Question, answer []string, policy Policy) Result {
+	maxPoints := float64(question.Points)
+	expected := make(map[string]bool, len(question.Options))
+	for _, option := range question.Options {
+		expected[option.Key] = option.IsCorrect
+	}
+	result := Result{CorrectAnswer: expected}
+
+	if len(question.Options) == 0 {
+		return result
+	}
+
+	// Answers are in the form ["a:true", "b:false", ...]
+	marked := make(map[string]bool)
+	for _, value := range answer {
+		parts := strings.Split(value, ":")
+		if len(parts) == 2 {
+			marked[parts[0]] = parts[1] == "true"
+		}
+	}
+	result.Answered = len(marked) > 0
+
+	totalStems := len(question.Options)
+	stemPoints := maxPoints / float64(totalStems)
+	correctStems, wrongStems, skippedStems := 0, 0, 0
+	var totalWeight, earnedWeight float64
+	for _, option := range question.Options {
+		totalWeight += optionWeight(option)
+		value, ok := marked[option.Key]
+		switch {
+		case !ok:
+			skippedStems++
+		case value == option.IsCorrect:
+			correctStems++
+			earnedWeight += optionWeight(option)
+		default:
+			wrongStems++
+		}
+	}
+
+	switch {
+	case correctStems == totalStems:
+		result.IsCorrect = true
+		result.PointsEarned = maxPoints
+	case policy.PartialCredit && correctStems > 0:
+		if policy.UseOptionWeights && totalWeight > 0 {
+			result.PointsEarned = (earnedWeight / totalWeight) * maxPoints
+		} else {
+			result.PointsEarned = (float64(correctStems) / float64(totalStems)) * maxPoints
+		}
+	}
+
+	result.Penalty = float64(wrongStems)*policy.penalty(question.Type, stemPoints) +
+		float64(skippedStems)*policy.skippedPenalty(question.Type, stemPoints)
+
+	return result
+}
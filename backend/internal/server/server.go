@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"github.com/Mahfuz2811/medecole/backend/internal/config"
 	"github.com/Mahfuz2811/medecole/backend/internal/database"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
 	"github.com/Mahfuz2811/medecole/backend/internal/routes"
 	"github.com/Mahfuz2811/medecole/backend/internal/service"
 	"syscall"
@@ -18,9 +19,10 @@ import (
 
 // BackgroundServices holds all background services
 type BackgroundServices struct {
-	cleanupService service.ExamCleanupService
-	ctx            context.Context
-	cancel         context.CancelFunc
+	cleanupService   service.ExamCleanupService
+	analyticsService service.QuestionAnalyticsService
+	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
 // Server represents the HTTP server with background services
@@ -102,7 +104,7 @@ func (s *Server) Shutdown() error {
 
 // initializeBackgroundServices creates and configures all background services
 func initializeBackgroundServices(cfg *config.Config, db *database.Database) *BackgroundServices {
-	if !cfg.Cleanup.Enabled {
+	if !cfg.Cleanup.Enabled && !cfg.Analytics.Enabled {
 		log.Println("Background services disabled by configuration")
 		return nil
 	}
@@ -110,21 +112,35 @@ func initializeBackgroundServices(cfg *config.Config, db *database.Database) *Ba
 	// Create context for background services
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Create exam repository for cleanup service
-	examRepo := routes.CreateExamRepository(db, cfg)
+	backgroundServices := &BackgroundServices{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	if cfg.Cleanup.Enabled {
+		// Create exam repository and service for cleanup service (expired sessions are auto-submitted)
+		examRepo := routes.CreateExamRepository(db, cfg)
+		examService := routes.CreateExamService(db, examRepo)
 
-	// Create cleanup service with configuration
-	cleanupConfig := service.CleanupConfig{
-		CleanupInterval: cfg.Cleanup.CleanupInterval,
-		GracePeriod:     cfg.Cleanup.GracePeriod,
+		// Create cleanup service with configuration
+		cleanupConfig := service.CleanupConfig{
+			CleanupInterval: cfg.Cleanup.CleanupInterval,
+			GracePeriod:     cfg.Cleanup.GracePeriod,
+		}
+		backgroundServices.cleanupService = service.NewExamCleanupService(examRepo, examService, cleanupConfig)
 	}
-	cleanupService := service.NewExamCleanupService(examRepo, cleanupConfig)
 
-	return &BackgroundServices{
-		cleanupService: cleanupService,
-		ctx:            ctx,
-		cancel:         cancel,
+	if cfg.Analytics.Enabled {
+		// Create question analytics worker (builds user_question_answers from finished attempts)
+		analyticsConfig := service.QuestionAnalyticsConfig{
+			ProcessInterval: cfg.Analytics.ProcessInterval,
+			BatchSize:       cfg.Analytics.BatchSize,
+		}
+		reviewService := service.NewReviewService(repository.NewReviewScheduleRepository(db.DB))
+		backgroundServices.analyticsService = service.NewQuestionAnalyticsService(repository.NewQuestionAnswerRepository(db.DB), reviewService, analyticsConfig)
 	}
+
+	return backgroundServices
 }
 
 // startBackgroundServices starts all configured background services
@@ -143,6 +159,16 @@ func (s *Server) startBackgroundServices() {
 		}()
 	}
 
+	// Start question analytics service
+	if s.backgroundServices.analyticsService != nil {
+		go func() {
+			log.Printf("Starting question analytics service")
+			if err := s.backgroundServices.analyticsService.Start(s.backgroundServices.ctx); err != nil && err != context.Canceled {
+				log.Printf("Question analytics service error: %v", err)
+			}
+		}()
+	}
+
 	log.Println("All background services started successfully")
 }
 
@@ -164,5 +190,12 @@ func (s *Server) stopBackgroundServices() {
 		}
 	}
 
+	// Stop question analytics service explicitly
+	if s.backgroundServices.analyticsService != nil {
+		if err := s.backgroundServices.analyticsService.Stop(); err != nil {
+			log.Printf("Error stopping question analytics service: %v", err)
+		}
+	}
+
 	log.Println("All background services stopped successfully")
 }
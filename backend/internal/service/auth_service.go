@@ -58,6 +58,7 @@ func (s *AuthService) Register(req models.RegisterRequest) (*models.AuthResponse
 		MSISDN:   normalizedMSISDN,
 		Password: hashedPassword,
 		IsActive: true,
+		Role:     models.RoleStudent,
 	}
 
 	if err := s.db.Create(&user).Error; err != nil {
@@ -187,6 +188,7 @@ func (s *AuthService) SocialAuth(provider string, userInfo *models.SocialUserInf
 		ProfilePicture: userInfo.ProfilePicture,
 		EmailVerified:  userInfo.EmailVerified,
 		IsActive:       true,
+		Role:           models.RoleStudent,
 		MSISDN:         "", // No phone number for social auth users
 		Password:       "", // No password for social auth users
 	}
@@ -260,3 +262,76 @@ func (s *AuthService) GetUserByEmail(email string) (*models.User, error) {
 
 	return &user, nil
 }
+
+// AssignRole changes the role of an existing user
+func (s *AuthService) AssignRole(userID uint, role models.UserRole) error {
+	if !role.IsValid() {
+		return errors.New("invalid role")
+	}
+
+	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("role", role)
+	if result.Error != nil {
+		return errors.New("database error")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// BootstrapAdmin promotes the user with the given MSISDN to admin, creating the account if it does not exist.
+// It reports whether a new user was created.
+func (s *AuthService) BootstrapAdmin(name, msisdn, password string) (*models.User, bool, error) {
+	if !utils.ValidateMSISDN(msisdn) {
+		return nil, false, errors.New("invalid MSISDN format")
+	}
+
+	normalizedMSISDN := utils.NormalizeMSISDN(msisdn)
+
+	var user models.User
+	err := s.db.Where("msisdn = ?", normalizedMSISDN).First(&user).Error
+	if err == nil {
+		if err := s.db.Model(&user).Updates(map[string]interface{}{
+			"role":      models.RoleAdmin,
+			"is_active": true,
+		}).Error; err != nil {
+			return nil, false, errors.New("failed to promote user")
+		}
+		user.Role = models.RoleAdmin
+		user.IsActive = true
+		return &user, false, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, errors.New("database error")
+	}
+
+	// No such user - create a new admin account
+	if !utils.ValidateName(name) {
+		return nil, false, errors.New("invalid name format")
+	}
+
+	if !utils.ValidatePassword(password) {
+		return nil, false, errors.New("password must be at least 6 characters long")
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, false, errors.New("failed to hash password")
+	}
+
+	user = models.User{
+		Name:     name,
+		MSISDN:   normalizedMSISDN,
+		Password: hashedPassword,
+		IsActive: true,
+		Role:     models.RoleAdmin,
+	}
+
+	if err := s.db.Create(&user).Error; err != nil {
+		return nil, false, errors.New("failed to create user")
+	}
+
+	return &user, true, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+// BookmarkService manages the questions students bookmark from their results and their private notes
+type BookmarkService interface {
+	BookmarkQuestion(userID uint, req dto.BookmarkQuestionRequest) (*dto.BookmarkResponse, error)
+	UpdateNote(userID uint, questionID uint, note string) (*dto.BookmarkResponse, error)
+	RemoveBookmark(userID uint, questionID uint) error
+	GetBookmarks(userID uint, filter repository.BookmarkFilter) (*dto.BookmarkListResponse, error)
+}
+
+// bookmarkService implements BookmarkService
+type bookmarkService struct {
+	bookmarkRepo repository.BookmarkRepository
+	examRepo     repository.ExamRepository
+}
+
+// NewBookmarkService creates a new bookmark service
+func NewBookmarkService(bookmarkRepo repository.BookmarkRepository, examRepo repository.ExamRepository) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		examRepo:     examRepo,
+	}
+}
+
+// BookmarkQuestion bookmarks a question the student answered in one of their finished sessions.
+// Bookmarking a question again moves the bookmark to that attempt and keeps its note unless a new one is given.
+func (s *bookmarkService) BookmarkQuestion(userID uint, req dto.BookmarkQuestionRequest) (*dto.BookmarkResponse, error) {
+	sessionData, err := s.examRepo.GetCompletedSessionByID(req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	// Other students' sessions are reported as missing
+	if sessionData.Attempt.UserID != userID {
+		return nil, repository.ErrAttemptNotFound
+	}
+
+	// Only questions the student was shown can be bookmarked
+	var examQuestions []models.ExamQuestion
+	if err := json.Unmarshal([]byte(sessionData.Exam.QuestionsData), &examQuestions); err != nil {
+		return nil, fmt.Errorf("failed to parse questions data: %w", err)
+	}
+	inExam := false
+	for _, question := range examQuestions {
+		if question.ID == req.QuestionID {
+			inExam = true
+			break
+		}
+	}
+	if !inExam {
+		return nil, errors.NewQuestionNotInExamError(sessionData.Exam.ID, req.QuestionID)
+	}
+
+	bookmark, err := s.bookmarkRepo.GetBookmark(userID, req.QuestionID)
+	if err == repository.ErrBookmarkNotFound {
+		bookmark = &models.QuestionBookmark{UserID: userID, QuestionID: req.QuestionID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	bookmark.AttemptID = sessionData.Attempt.ID
+	if req.Note != "" {
+		bookmark.Note = req.Note
+	}
+	if err := s.bookmarkRepo.SaveBookmark(bookmark); err != nil {
+		return nil, err
+	}
+
+	return s.getBookmarkResponse(userID, req.QuestionID)
+}
+
+// UpdateNote replaces the note of a bookmarked question, an empty note clears it
+func (s *bookmarkService) UpdateNote(userID uint, questionID uint, note string) (*dto.BookmarkResponse, error) {
+	bookmark, err := s.bookmarkRepo.GetBookmark(userID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark.Note = note
+	if err := s.bookmarkRepo.SaveBookmark(bookmark); err != nil {
+		return nil, err
+	}
+
+	response := toBookmarkResponse(*bookmark)
+	return &response, nil
+}
+
+// RemoveBookmark removes a bookmark and its note
+func (s *bookmarkService) RemoveBookmark(userID uint, questionID uint) error {
+	return s.bookmarkRepo.DeleteBookmark(userID, questionID)
+}
+
+// GetBookmarks lists the student's bookmarks, optionally within a subject or system
+func (s *bookmarkService) GetBookmarks(userID uint, filter repository.BookmarkFilter) (*dto.BookmarkListResponse, error) {
+	bookmarks, err := s.bookmarkRepo.GetBookmarks(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.BookmarkListResponse{
+		Total:     len(bookmarks),
+		Bookmarks: make([]dto.BookmarkResponse, 0, len(bookmarks)),
+	}
+	for _, bookmark := range bookmarks {
+		response.Bookmarks = append(response.Bookmarks, toBookmarkResponse(bookmark))
+	}
+	return response, nil
+}
+
+// getBookmarkResponse reloads a saved bookmark with its question, system and subject
+func (s *bookmarkService) getBookmarkResponse(userID uint, questionID uint) (*dto.BookmarkResponse, error) {
+	bookmark, err := s.bookmarkRepo.GetBookmark(userID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	response := toBookmarkResponse(*bookmark)
+	return &response, nil
+}
+
+// toBookmarkResponse converts a bookmark with its question, system and subject to its response
+func toBookmarkResponse(bookmark models.QuestionBookmark) dto.BookmarkResponse {
+	question := bookmark.Question
+	return dto.BookmarkResponse{
+		QuestionID:      bookmark.QuestionID,
+		QuestionText:    question.QuestionText,
+		QuestionType:    string(question.QuestionType),
+		DifficultyLevel: string(question.DifficultyLevel),
+		SystemID:        question.SystemID,
+		SystemName:      question.System.Name,
+		SubjectID:       question.System.SubjectID,
+		SubjectName:     question.System.Subject.Name,
+		Note:            bookmark.Note,
+		AttemptID:       bookmark.AttemptID,
+		CreatedAt:       bookmark.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       bookmark.UpdatedAt.Format(time.RFC3339),
+	}
+}
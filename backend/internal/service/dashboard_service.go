@@ -231,8 +231,8 @@ func (s *dashboardService) GetDashboardEnrollments(userID uint) (*dto.DashboardE
 	activeCount := 0
 
 	for _, enrollment := range enrollments {
-		// Only include enrollments that can access content (not expired)
-		if !enrollment.CanAccessContent() {
+		// Only include enrollments that can access content (not expired) or are waiting to be paid
+		if !enrollment.CanAccessContent() && !enrollment.AwaitsPayment() {
 			continue
 		}
 
@@ -255,21 +255,36 @@ func (s *dashboardService) GetDashboardEnrollments(userID uint) (*dto.DashboardE
 			expiryDate = &formatted
 		}
 
-		// All enrollments that reach this point are active (can access content)
+		// Enrollments that reach this point are active (can access content) unless they still need paying
 		status := "active"
-		activeCount++
+		if enrollment.CanAccessContent() {
+			activeCount++
+			if enrollment.IsOnTrial() {
+				status = "trial"
+			}
+		} else {
+			status = "payment_pending"
+		}
 
 		dashboardEnrollment := dto.DashboardEnrollmentDTO{
-			ID:             enrollment.ID,
-			PackageID:      enrollment.PackageID,
-			PackageName:    enrollment.Package.Name,
-			PackageSlug:    enrollment.Package.Slug,
-			PackageType:    string(enrollment.Package.PackageType),
-			ExpiryDate:     expiryDate,
-			Status:         status,
-			Progress:       progress,
-			TotalExams:     totalExams,
-			CompletedExams: completedExams,
+			ID:              enrollment.ID,
+			PackageID:       enrollment.PackageID,
+			PackageName:     enrollment.Package.Name,
+			PackageSlug:     enrollment.Package.Slug,
+			PackageType:     string(enrollment.Package.PackageType),
+			ExpiryDate:      expiryDate,
+			Status:          status,
+			Progress:        progress,
+			TotalExams:      totalExams,
+			CompletedExams:  completedExams,
+			DaysUntilExpiry: enrollment.DaysUntilExpiry(),
+			CanRenew:        enrollment.CanRenew(),
+		}
+
+		// Enrollments close to expiry are offered a renewal at the package's renewal price
+		if dashboardEnrollment.CanRenew {
+			renewalPrice := enrollment.Package.RenewalCost()
+			dashboardEnrollment.RenewalPrice = &renewalPrice
 		}
 
 		dashboardEnrollments = append(dashboardEnrollments, dashboardEnrollment)
@@ -305,9 +320,10 @@ func (s *dashboardService) calculateEnrollmentProgress(userID, packageID uint) (
 	// Query to get completed exams for this user and package
 	// FIXED: Now uses package_id directly from UserExamAttempt - no JOIN needed!
 	// This ensures attempts are isolated per package context
+	// Practice sessions are generated on demand and are not package exams
 	err = s.db.Model(&models.UserExamAttempt{}).
-		Where("user_id = ? AND package_id = ? AND status IN (?)",
-			userID, packageID, []string{
+		Where("user_id = ? AND package_id = ? AND is_practice = ? AND status IN (?)",
+			userID, packageID, false, []string{
 				string(models.AttemptStatusCompleted),
 				string(models.AttemptStatusAutoSubmitted),
 				string(models.AttemptStatusAbandoned),
@@ -21,6 +21,11 @@ type EnrollmentService interface {
 	// Core enrollment operations
 	EnrollInPackage(ctx context.Context, userID uint, req dto.EnrollmentRequest) (*dto.EnrollmentResponse, error)
 	CheckEnrollmentStatus(ctx context.Context, userID, packageID uint) (*dto.EnrollmentStatusResponse, error)
+	ExtendTrial(ctx context.Context, userID, enrollmentID uint) (*dto.EnrollmentResponse, error)
+
+	// Renewal operations
+	RenewEnrollment(ctx context.Context, userID, enrollmentID uint) (*dto.RenewalResponse, error)
+	GetRenewalHistory(ctx context.Context, userID, enrollmentID uint) (*dto.RenewalHistoryResponse, error)
 
 	// Coupon operations
 	ValidateCoupon(ctx context.Context, req dto.CouponValidationRequest) (*dto.CouponValidationResponse, error)
@@ -98,12 +103,41 @@ func (s *enrollmentService) EnrollInPackage(ctx context.Context, userID uint, re
 		return nil, fmt.Errorf("failed to check existing enrollment: %w", err)
 	}
 
-	if existingEnrollment != nil && existingEnrollment.CanAccessContent() {
+	// An enrollment still awaiting payment is paid for rather than enrolled in again
+	if existingEnrollment != nil && (existingEnrollment.CanAccessContent() || existingEnrollment.AwaitsPayment()) {
 		log.WithField("existing_enrollment_id", existingEnrollment.ID).Warn("User already has active enrollment for this package")
 		tx.Rollback()
 		return nil, errors.NewActiveEnrollmentExistsError(userID, req.PackageID)
 	}
 
+	// Expired enrollments are renewed, keeping attempt history and renewal periods on one enrollment
+	if existingEnrollment != nil && existingEnrollment.CanRenew() {
+		log.WithField("existing_enrollment_id", existingEnrollment.ID).Warn("User has an expired enrollment to renew")
+		tx.Rollback()
+		return nil, errors.NewRenewalRequiredError(existingEnrollment.ID, req.PackageID)
+	}
+
+	// A trial is only offered by paid premium packages and only once per user
+	if req.Trial {
+		if !pkg.OffersTrial() {
+			log.Warn("Attempted trial enrollment in package without a trial")
+			tx.Rollback()
+			return nil, errors.NewTrialNotAvailableError(req.PackageID)
+		}
+
+		usedTrial, err := repoTx.HasUsedTrial(userID, req.PackageID)
+		if err != nil {
+			log.WithError(err).Error("Failed to check previous trials")
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to check previous trials: %w", err)
+		}
+		if usedTrial {
+			log.Warn("User already used the trial for this package")
+			tx.Rollback()
+			return nil, errors.NewTrialAlreadyUsedError(userID, req.PackageID)
+		}
+	}
+
 	// 3. Validate and process coupon if provided
 	var coupon *models.Coupon
 	if req.CouponCode != nil && *req.CouponCode != "" {
@@ -141,7 +175,7 @@ func (s *enrollmentService) EnrollInPackage(ctx context.Context, userID uint, re
 	}).Info("Price calculation completed")
 
 	// 5. Create enrollment record
-	enrollment := s.buildEnrollment(userID, pkg, coupon, priceCalc)
+	enrollment := s.buildEnrollment(userID, pkg, coupon, priceCalc, req.Trial)
 
 	if err := repoTx.CreateEnrollment(enrollment); err != nil {
 		log.WithError(err).Error("Failed to create enrollment record")
@@ -198,13 +232,13 @@ func (s *enrollmentService) EnrollInPackage(ctx context.Context, userID uint, re
 }
 
 // buildEnrollment creates an enrollment model with calculated values
-func (s *enrollmentService) buildEnrollment(userID uint, pkg *models.Package, coupon *models.Coupon, priceCalc *dto.PriceCalculationResult) *models.UserPackageEnrollment {
+func (s *enrollmentService) buildEnrollment(userID uint, pkg *models.Package, coupon *models.Coupon, priceCalc *dto.PriceCalculationResult, trial bool) *models.UserPackageEnrollment {
 	now := time.Now()
 
 	enrollment := &models.UserPackageEnrollment{
 		UserID:              userID,
 		PackageID:           pkg.ID,
-		EnrollmentType:      models.EnrollmentTypeFull, // Always FULL for now (TRIAL will be added later)
+		EnrollmentType:      models.EnrollmentTypeFull,
 		EnrolledAt:          now,
 		EnrolledPackageType: pkg.PackageType,
 		EnrolledPrice:       priceCalc.FinalPrice,
@@ -221,6 +255,15 @@ func (s *enrollmentService) buildEnrollment(userID uint, pkg *models.Package, co
 	// Calculate expiration date based on validity type
 	enrollment.ExpiresAt = s.calculateExpirationDate(pkg)
 
+	// Trials run until the trial ends and stay pending until the package is paid for
+	if trial && priceCalc.FinalPrice > 0 {
+		trialExpiresAt := now.AddDate(0, 0, pkg.TrialDays)
+		enrollment.EnrollmentType = models.EnrollmentTypeTrial
+		enrollment.IsTrialUsed = true
+		enrollment.TrialExpiresAt = &trialExpiresAt
+		enrollment.ExpiresAt = &trialExpiresAt
+	}
+
 	// Set coupon details if used
 	if coupon != nil {
 		enrollment.CouponID = &coupon.ID
@@ -236,29 +279,7 @@ func (s *enrollmentService) buildEnrollment(userID uint, pkg *models.Package, co
 
 // calculateExpirationDate calculates when the enrollment expires
 func (s *enrollmentService) calculateExpirationDate(pkg *models.Package) *time.Time {
-	now := time.Now()
-	var expiresAt time.Time
-
-	switch pkg.ValidityType {
-	case models.ValidityTypeFixed:
-		if pkg.ValidityDate != nil {
-			expiresAt = *pkg.ValidityDate
-		} else {
-			// Fallback: 1 year from now if no fixed date set
-			expiresAt = now.AddDate(1, 0, 0)
-		}
-	case models.ValidityTypeRelative:
-		if pkg.ValidityDays != nil {
-			expiresAt = now.AddDate(0, 0, *pkg.ValidityDays)
-		} else {
-			// Fallback: 1 year from now if no days set
-			expiresAt = now.AddDate(1, 0, 0)
-		}
-	default:
-		// Default: 1 year from now
-		expiresAt = now.AddDate(1, 0, 0)
-	}
-
+	expiresAt := pkg.ExpiryDateFrom(time.Now())
 	return &expiresAt
 }
 
@@ -393,15 +414,192 @@ func (s *enrollmentService) CheckEnrollmentStatus(ctx context.Context, userID, p
 		return nil, fmt.Errorf("failed to check enrollment status: %w", err)
 	}
 
-	hasActiveEnrollment := enrollment != nil && enrollment.CanAccessContent()
+	// Enrollments awaiting payment are reported too, so the student is asked to pay instead of to enroll
+	hasActiveEnrollment := enrollment != nil && (enrollment.CanAccessContent() || enrollment.AwaitsPayment())
 
 	response := &dto.EnrollmentStatusResponse{
 		HasActiveEnrollment: hasActiveEnrollment,
+		CanRenew:            enrollment != nil && enrollment.CanRenew(),
 	}
 
-	if response.HasActiveEnrollment {
+	if response.HasActiveEnrollment || response.CanRenew {
 		response.Enrollment = s.mapper.ToEnrollmentResponse(enrollment)
 	}
 
 	return response, nil
 }
+
+// ExtendTrial extends the user's trial once by the package's trial extension days
+func (s *enrollmentService) ExtendTrial(ctx context.Context, userID, enrollmentID uint) (*dto.EnrollmentResponse, error) {
+	ctx = logger.AddOperationToContext(ctx, "ExtendTrial")
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"user_id":       userID,
+		"enrollment_id": enrollmentID,
+		"operation":     "ExtendTrial",
+	})
+
+	enrollment, err := s.repo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch enrollment")
+		return nil, err
+	}
+
+	// Other users' enrollments are reported as missing
+	if enrollment.UserID != userID {
+		log.Warn("User attempted to extend another user's trial")
+		return nil, repository.ErrEnrollmentNotFound
+	}
+
+	if !enrollment.CanExtendTrial() {
+		log.WithFields(logrus.Fields{
+			"enrollment_type":   enrollment.EnrollmentType,
+			"trial_extended_at": enrollment.TrialExtendedAt,
+		}).Warn("Enrollment trial cannot be extended")
+		return nil, errors.NewTrialNotExtendableError(enrollmentID, "only an active trial can be extended, and only once")
+	}
+
+	if enrollment.Package.TrialExtensionDays <= 0 {
+		log.WithField("package_id", enrollment.PackageID).Warn("Package does not allow trial extensions")
+		return nil, errors.NewTrialNotExtendableError(enrollmentID, "this package does not allow trial extensions")
+	}
+
+	// Extend from the end of the trial, or from now when the trial already ended
+	start := time.Now()
+	if enrollment.TrialExpiresAt != nil && enrollment.TrialExpiresAt.After(start) {
+		start = *enrollment.TrialExpiresAt
+	}
+	trialExpiresAt := start.AddDate(0, 0, enrollment.Package.TrialExtensionDays)
+
+	if err := s.repo.ExtendTrial(enrollmentID, trialExpiresAt); err != nil {
+		if err == repository.ErrTrialAlreadyExtended {
+			log.Warn("Trial was extended concurrently")
+			return nil, errors.NewTrialNotExtendableError(enrollmentID, "only an active trial can be extended, and only once")
+		}
+		log.WithError(err).Error("Failed to extend trial")
+		return nil, fmt.Errorf("failed to extend trial: %w", err)
+	}
+
+	updated, err := s.repo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch extended enrollment")
+		return nil, errors.NewEnrollmentFetchError(enrollmentID, err)
+	}
+
+	log.WithField("trial_expires_at", trialExpiresAt).Info("Trial extended successfully")
+
+	return s.mapper.ToEnrollmentResponse(updated), nil
+}
+
+// RenewEnrollment renews an expired or expiring enrollment for another validity period of its package.
+// Free renewals complete at once; priced ones wait for their payment, an unpaid renewal being reused.
+func (s *enrollmentService) RenewEnrollment(ctx context.Context, userID, enrollmentID uint) (*dto.RenewalResponse, error) {
+	ctx = logger.AddOperationToContext(ctx, "RenewEnrollment")
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"user_id":       userID,
+		"enrollment_id": enrollmentID,
+		"operation":     "RenewEnrollment",
+	})
+
+	enrollment, err := s.repo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch enrollment")
+		return nil, err
+	}
+
+	// Other users' enrollments are reported as missing
+	if enrollment.UserID != userID {
+		log.Warn("User attempted to renew another user's enrollment")
+		return nil, repository.ErrEnrollmentNotFound
+	}
+
+	if !enrollment.CanRenew() {
+		log.WithFields(logrus.Fields{
+			"payment_status": enrollment.PaymentStatus,
+			"expires_at":     enrollment.ExpiresAt,
+		}).Warn("Enrollment cannot be renewed")
+		return nil, errors.NewRenewalNotAvailableError(enrollmentID, fmt.Sprintf("only enrollments that expired or expire within %d days can be renewed", models.RenewalWindowDays))
+	}
+
+	pkg := enrollment.Package
+	if !pkg.IsActive {
+		log.WithField("package_id", pkg.ID).Warn("Attempted renewal of inactive package")
+		return nil, errors.NewRenewalNotAvailableError(enrollmentID, "this package is no longer available")
+	}
+
+	// Fixed validity packages only renew once their validity date has moved past the enrollment's expiry
+	now := time.Now()
+	start, end := pkg.RenewalPeriod(enrollment.ExpiresAt, now)
+	if !end.After(start) {
+		log.WithField("validity_date", pkg.ValidityDate).Warn("Package validity ends before the renewal period")
+		return nil, errors.NewRenewalNotAvailableError(enrollmentID, "this package cannot be renewed until its next validity period")
+	}
+
+	renewal, err := s.repo.GetPendingRenewal(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to check pending renewal")
+		return nil, err
+	}
+	if renewal != nil {
+		log.WithField("renewal_id", renewal.ID).Info("Reusing unpaid renewal")
+		return s.mapper.ToRenewalResponse(renewal), nil
+	}
+
+	renewal = &models.EnrollmentRenewal{
+		EnrollmentID:  enrollment.ID,
+		UserID:        userID,
+		PackageID:     pkg.ID,
+		Price:         pkg.RenewalCost(),
+		OriginalPrice: pkg.Price,
+		Status:        models.RenewalStatusPending,
+	}
+	if err := s.repo.CreateRenewal(renewal); err != nil {
+		log.WithError(err).Error("Failed to create renewal")
+		return nil, err
+	}
+	log = log.WithFields(logrus.Fields{
+		"renewal_id": renewal.ID,
+		"price":      renewal.Price,
+	})
+
+	if renewal.Price > 0 {
+		log.Info("Renewal created, waiting for payment")
+		return s.mapper.ToRenewalResponse(renewal), nil
+	}
+
+	renewal, err = s.repo.CompleteRenewal(renewal.ID, nil, now)
+	if err != nil {
+		log.WithError(err).Error("Failed to complete free renewal")
+		return nil, err
+	}
+
+	log.WithField("period_end", renewal.PeriodEnd).Info("Enrollment renewed")
+	return s.mapper.ToRenewalResponse(renewal), nil
+}
+
+// GetRenewalHistory returns the renewal periods of one of the user's enrollments
+func (s *enrollmentService) GetRenewalHistory(ctx context.Context, userID, enrollmentID uint) (*dto.RenewalHistoryResponse, error) {
+	ctx = logger.AddOperationToContext(ctx, "GetRenewalHistory")
+	log := logger.WithContext(ctx).WithFields(logrus.Fields{
+		"user_id":       userID,
+		"enrollment_id": enrollmentID,
+		"operation":     "GetRenewalHistory",
+	})
+
+	enrollment, err := s.repo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch enrollment")
+		return nil, err
+	}
+	if enrollment.UserID != userID {
+		log.Warn("User attempted to read another user's renewals")
+		return nil, repository.ErrEnrollmentNotFound
+	}
+
+	renewals, err := s.repo.GetRenewalHistory(enrollmentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch renewal history")
+		return nil, err
+	}
+
+	return s.mapper.ToRenewalHistoryResponse(enrollment, renewals), nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+// checkPackageEntitlement is the single check of whether a user may use a package's exams, sessions and
+// results. It returns the entitling enrollment, repository.ErrNotEnrolledInPackage when the user never enrolled,
+// a PaymentRequiredError (402) when paying would restore access and an EntitlementDeniedError (403) otherwise.
+func checkPackageEntitlement(enrollmentRepo repository.EnrollmentRepository, userID, packageID uint) (*models.UserPackageEnrollment, error) {
+	enrollment, err := enrollmentRepo.GetPackageEnrollment(userID, packageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check enrollment: %w", err)
+	}
+	if enrollment == nil {
+		return nil, repository.ErrNotEnrolledInPackage
+	}
+
+	switch denial := enrollment.AccessDenial(); denial {
+	case models.AccessGranted:
+		return enrollment, nil
+	case models.AccessDeniedPaymentRequired, models.AccessDeniedTrialExpired:
+		return nil, errors.NewPaymentRequiredError(packageID, enrollment.ID, string(denial))
+	case models.AccessDeniedExpired:
+		// Priced packages are renewed by paying again, expired free ones are over
+		if enrollment.EnrolledPrice > 0 {
+			return nil, errors.NewPaymentRequiredError(packageID, enrollment.ID, string(denial))
+		}
+		return nil, errors.NewEntitlementDeniedError(packageID, enrollment.ID, string(denial))
+	default:
+		return nil, errors.NewEntitlementDeniedError(packageID, enrollment.ID, string(denial))
+	}
+}
+
+// checkTrialExamAccess keeps students on a trial to the exams the package makes visible during trials
+func checkTrialExamAccess(enrollment *models.UserPackageEnrollment, packageExam *repository.ExamWithUserData) error {
+	if enrollment.IsOnTrial() && !packageExam.IsTrialVisible {
+		return errors.NewPaymentRequiredError(enrollment.PackageID, enrollment.ID, string(models.AccessDeniedTrialExamLocked))
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// difficultyOrder keeps difficulty allocation deterministic
+var difficultyOrder = []models.DifficultyLevel{
+	models.DifficultyEasy,
+	models.DifficultyMedium,
+	models.DifficultyHard,
+}
+
+// ExamBuilderService assembles exam question snapshots from the question bank
+type ExamBuilderService interface {
+	BuildExam(examID uint, blueprint dto.ExamBlueprintRequest) (*dto.ExamBuildResponse, error)
+}
+
+// examBuilderService implements ExamBuilderService
+type examBuilderService struct {
+	examRepo     repository.ExamAdminRepository
+	questionRepo repository.QuestionRepository
+}
+
+// NewExamBuilderService creates a new exam builder service
+func NewExamBuilderService(examRepo repository.ExamAdminRepository, questionRepo repository.QuestionRepository) ExamBuilderService {
+	return &examBuilderService{
+		examRepo:     examRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+// BuildExam picks questions for every blueprint section and writes them into the exam's QuestionsData
+func (s *examBuilderService) BuildExam(examID uint, blueprint dto.ExamBlueprintRequest) (*dto.ExamBuildResponse, error) {
+	log := logger.WithService("ExamBuilderService").WithFields(logrus.Fields{
+		"operation": "BuildExam",
+		"exam_id":   examID,
+	})
+
+	if err := validateBlueprint(blueprint); err != nil {
+		return nil, err
+	}
+
+	exam, err := s.examRepo.GetExamByID(examID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regenerating would invalidate the snapshot existing attempts were scored against
+	if exam.AttemptCount > 0 {
+		return nil, errors.NewExamLockedError(exam.ID, exam.AttemptCount)
+	}
+
+	picked := make([]models.Question, 0)
+	used := make(map[uint]bool)
+
+	for i, section := range blueprint.Sections {
+		candidates, err := s.questionRepo.GetCandidateQuestions(repository.QuestionCandidateCriteria{
+			SubjectID:     section.SubjectID,
+			SystemID:      section.SystemID,
+			QuestionTypes: blueprint.QuestionTypes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candidate questions: %w", err)
+		}
+
+		available := make([]models.Question, 0, len(candidates))
+		for _, question := range candidates {
+			if used[question.ID] {
+				continue
+			}
+			if blueprint.AvoidOverused && question.IsOverused(blueprint.OverusedThreshold) {
+				continue
+			}
+			available = append(available, question)
+		}
+
+		if len(available) < section.Count {
+			return nil, errors.NewInsufficientQuestionsError(i, section.Count, len(available))
+		}
+
+		mix := section.DifficultyMix
+		if len(mix) == 0 {
+			mix = blueprint.DifficultyMix
+		}
+
+		for _, question := range pickQuestions(available, section.Count, mix) {
+			used[question.ID] = true
+			picked = append(picked, question)
+		}
+	}
+
+	points := blueprint.PointsPerQuestion
+	if points <= 0 {
+		points = 1
+	}
+
+	response := &dto.ExamBuildResponse{
+		ExamID:              exam.ID,
+		QuestionIDs:         make([]uint, 0, len(picked)),
+		DifficultyBreakdown: make(map[models.DifficultyLevel]int),
+		TypeBreakdown:       make(map[models.QuestionType]int),
+	}
+
+	snapshot := make([]models.ExamQuestion, 0, len(picked))
+	for i := range picked {
+		examQuestion, err := models.NewExamQuestion(&picked[i], points)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = append(snapshot, examQuestion)
+
+		response.QuestionIDs = append(response.QuestionIDs, picked[i].ID)
+		response.DifficultyBreakdown[picked[i].DifficultyLevel]++
+		response.TypeBreakdown[picked[i].QuestionType]++
+		response.TotalMarks += float64(points)
+	}
+
+	questionsJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode questions data: %w", err)
+	}
+
+	exam.QuestionsData = string(questionsJSON)
+	exam.TotalQuestions = len(snapshot)
+	exam.TotalMarks = response.TotalMarks
+	response.TotalQuestions = exam.TotalQuestions
+
+	if err := s.examRepo.SaveExamQuestions(exam, response.QuestionIDs); err != nil {
+		return nil, fmt.Errorf("failed to save exam questions: %w", err)
+	}
+
+	log.WithFields(logrus.Fields{
+		"total_questions": response.TotalQuestions,
+		"total_marks":     response.TotalMarks,
+	}).Info("Exam questions generated from blueprint")
+
+	return response, nil
+}
+
+// validateBlueprint checks the blueprint before touching the database
+func validateBlueprint(blueprint dto.ExamBlueprintRequest) error {
+	if len(blueprint.Sections) == 0 {
+		return errors.NewBlueprintValidationError("sections", "at least one section is required")
+	}
+
+	for i, section := range blueprint.Sections {
+		if section.Count <= 0 {
+			return errors.NewBlueprintValidationError("sections", fmt.Sprintf("section %d must request at least one question", i))
+		}
+		if err := validateDifficultyMix(section.DifficultyMix); err != nil {
+			return err
+		}
+	}
+
+	for _, questionType := range blueprint.QuestionTypes {
+		if questionType != models.QuestionTypeSBA && questionType != models.QuestionTypeTrueFalse {
+			return errors.NewBlueprintValidationError("question_types", fmt.Sprintf("unsupported question type %q", questionType))
+		}
+	}
+
+	return validateDifficultyMix(blueprint.DifficultyMix)
+}
+
+// validateDifficultyMix checks that an optional difficulty mix is a set of percentages adding up to 100
+func validateDifficultyMix(mix map[models.DifficultyLevel]int) error {
+	if len(mix) == 0 {
+		return nil
+	}
+
+	total := 0
+	for difficulty, percentage := range mix {
+		if difficulty != models.DifficultyEasy && difficulty != models.DifficultyMedium && difficulty != models.DifficultyHard {
+			return errors.NewBlueprintValidationError("difficulty_mix", fmt.Sprintf("unknown difficulty %q", difficulty))
+		}
+		if percentage < 0 {
+			return errors.NewBlueprintValidationError("difficulty_mix", "percentages cannot be negative")
+		}
+		total += percentage
+	}
+
+	if total != 100 {
+		return errors.NewBlueprintValidationError("difficulty_mix", fmt.Sprintf("percentages must add up to 100, got %d", total))
+	}
+
+	return nil
+}
+
+// pickQuestions randomly selects count questions honouring the difficulty mix where the pool allows,
+// topping up from other difficulties when a bucket runs short
+func pickQuestions(available []models.Question, count int, mix map[models.DifficultyLevel]int) []models.Question {
+	pool := make([]models.Question, len(available))
+	copy(pool, available)
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	if len(mix) == 0 {
+		return pool[:count]
+	}
+
+	buckets := make(map[models.DifficultyLevel][]models.Question)
+	for _, question := range pool {
+		buckets[question.DifficultyLevel] = append(buckets[question.DifficultyLevel], question)
+	}
+
+	selected := make([]models.Question, 0, count)
+	taken := make(map[uint]bool)
+	for difficulty, target := range allocateByPercentage(count, mix) {
+		bucket := buckets[difficulty]
+		if target > len(bucket) {
+			target = len(bucket)
+		}
+		for _, question := range bucket[:target] {
+			selected = append(selected, question)
+			taken[question.ID] = true
+		}
+	}
+
+	// Fill any shortfall from whatever is left in the pool
+	for _, question := range pool {
+		if len(selected) >= count {
+			break
+		}
+		if !taken[question.ID] {
+			selected = append(selected, question)
+			taken[question.ID] = true
+		}
+	}
+
+	rand.Shuffle(len(selected), func(i, j int) { selected[i], selected[j] = selected[j], selected[i] })
+	return selected
+}
+
+// allocateByPercentage splits count across difficulties using the largest remainder method
+func allocateByPercentage(count int, mix map[models.DifficultyLevel]int) map[models.DifficultyLevel]int {
+	type remainder struct {
+		difficulty models.DifficultyLevel
+		fraction   int
+	}
+
+	allocation := make(map[models.DifficultyLevel]int)
+	remainders := make([]remainder, 0, len(difficultyOrder))
+	allocated := 0
+
+	for _, difficulty := range difficultyOrder {
+		share := count * mix[difficulty]
+		allocation[difficulty] = share / 100
+		allocated += share / 100
+		remainders = append(remainders, remainder{difficulty: difficulty, fraction: share % 100})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].fraction > remainders[j].fraction
+	})
+
+	for i := 0; allocated < count && i < len(remainders); i++ {
+		if mix[remainders[i].difficulty] == 0 {
+			continue
+		}
+		allocation[remainders[i].difficulty]++
+		allocated++
+	}
+
+	return allocation
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/Mahfuz2811/medecole/backend/internal/logger"
 	"github.com/Mahfuz2811/medecole/backend/internal/repository"
 	"time"
@@ -18,8 +19,11 @@ type ExamCleanupService interface {
 // examCleanupService implements ExamCleanupService
 type examCleanupService struct {
 	examRepo        repository.ExamRepository
+	examService     ExamService
 	cleanupInterval time.Duration
 	gracePeriod     time.Duration
+	abandonAfter    time.Duration
+	batchSize       int
 	stopChan        chan struct{}
 	stopped         bool
 }
@@ -28,10 +32,12 @@ type examCleanupService struct {
 type CleanupConfig struct {
 	CleanupInterval time.Duration // How often to run cleanup (default: 1 minute)
 	GracePeriod     time.Duration // Grace period to avoid race conditions (default: 2 minutes)
+	AbandonAfter    time.Duration // Extra time after the grace period before sessions that keep failing auto-submit are abandoned (default: 30 minutes)
+	BatchSize       int           // Maximum expired sessions auto-submitted per run (default: 200)
 }
 
 // NewExamCleanupService creates a new exam cleanup service
-func NewExamCleanupService(examRepo repository.ExamRepository, config CleanupConfig) ExamCleanupService {
+func NewExamCleanupService(examRepo repository.ExamRepository, examService ExamService, config CleanupConfig) ExamCleanupService {
 	// Set default values if not provided
 	if config.CleanupInterval == 0 {
 		config.CleanupInterval = 1 * time.Minute
@@ -39,11 +45,20 @@ func NewExamCleanupService(examRepo repository.ExamRepository, config CleanupCon
 	if config.GracePeriod == 0 {
 		config.GracePeriod = 2 * time.Minute
 	}
+	if config.AbandonAfter == 0 {
+		config.AbandonAfter = 30 * time.Minute
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 200
+	}
 
 	return &examCleanupService{
 		examRepo:        examRepo,
+		examService:     examService,
 		cleanupInterval: config.CleanupInterval,
 		gracePeriod:     config.GracePeriod,
+		abandonAfter:    config.AbandonAfter,
+		batchSize:       config.BatchSize,
 		stopChan:        make(chan struct{}),
 		stopped:         false,
 	}
@@ -114,17 +129,23 @@ func (s *examCleanupService) performCleanup() {
 		"grace_period_seconds": gracePeriodSeconds,
 	}).Debug("Calculated parameters for expired session cleanup")
 
-	// Find and update expired sessions
-	updatedCount, err := s.examRepo.MarkExpiredSessionsAsAbandoned(currentTime, gracePeriodSeconds)
+	// Score expired sessions from their saved answers
+	submittedCount, failedCount := s.autoSubmitExpiredSessions(currentTime, gracePeriodSeconds)
+
+	// Safety net: sessions that keep failing auto-submit are eventually abandoned
+	abandonAfterSeconds := gracePeriodSeconds + int(s.abandonAfter.Seconds())
+	abandonedCount, err := s.examRepo.MarkExpiredSessionsAsAbandoned(currentTime, abandonAfterSeconds)
 	if err != nil {
-		log.WithError(err).Error("Failed to mark expired sessions as abandoned")
+		log.WithError(err).Error("Failed to mark stale sessions as abandoned")
 		return
 	}
 
 	// Calculate performance metrics
 	duration := time.Since(startTime)
 	log.WithFields(logrus.Fields{
-		"updated_sessions":     updatedCount,
+		"auto_submitted":       submittedCount,
+		"auto_submit_failed":   failedCount,
+		"abandoned_sessions":   abandonedCount,
 		"cleanup_duration":     duration.String(),
 		"cleanup_duration_ms":  duration.Milliseconds(),
 		"current_time":         currentTime.Format(time.RFC3339),
@@ -139,3 +160,30 @@ func (s *examCleanupService) performCleanup() {
 		}).Warn("Cleanup operation took longer than expected")
 	}
 }
+
+// autoSubmitExpiredSessions scores and finalizes a batch of expired sessions, returning submitted and failed counts
+func (s *examCleanupService) autoSubmitExpiredSessions(currentTime time.Time, gracePeriodSeconds int) (int, int) {
+	log := logger.WithService("ExamCleanupService").WithField("operation", "AutoSubmitExpiredSessions")
+
+	sessions, err := s.examRepo.GetExpiredActiveSessions(currentTime, gracePeriodSeconds, s.batchSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch expired sessions")
+		return 0, 0
+	}
+
+	submitted, failed := 0, 0
+	for _, session := range sessions {
+		if _, err := s.examService.AutoSubmitSession(session); err != nil {
+			// The user submitted between the query and the update - nothing left to do
+			if errors.Is(err, repository.ErrExamAlreadySubmitted) {
+				continue
+			}
+			failed++
+			log.WithError(err).WithField("attempt_id", session.Attempt.ID).Error("Failed to auto-submit expired session")
+			continue
+		}
+		submitted++
+	}
+
+	return submitted, failed
+}
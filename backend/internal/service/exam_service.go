@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
 	"github.com/Mahfuz2811/medecole/backend/internal/logger"
 	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
 	"github.com/Mahfuz2811/medecole/backend/internal/models"
 	"github.com/Mahfuz2811/medecole/backend/internal/repository"
-	"strings"
+	"github.com/Mahfuz2811/medecole/backend/internal/scoring"
+	"sort"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -21,12 +23,22 @@ type ExamService interface {
 	StartExam(packageSlug string, examSlug string, userID uint, deviceInfo map[string]string) (dto.StartExamResponse, error)
 	GetSession(sessionID string, userID uint) (dto.ExamSessionResponse, error)
 	SyncSession(sessionID string, userID uint, answers []dto.UserAnswerSync) (dto.SyncSessionResponse, error)
+	CheckAnswer(sessionID string, userID uint, req dto.CheckAnswerRequest) (dto.AnswerFeedback, error)
 	SubmitExam(sessionID string, userID uint) (dto.SubmitExamResponse, error)
+	AutoSubmitSession(sessionData repository.SessionWithExamData) (dto.SubmitExamResponse, error)
 	GetExamBySlug(examSlug string) (*models.Exam, error)
 	GetUserAttemptsByExam(userID uint, examID uint) ([]models.UserExamAttempt, error)
 	GetExamResultsBySession(sessionID string, userID uint) (interface{}, error)
+	GetLeaderboard(packageSlug string, examSlug string, userID uint, limit int) (dto.LeaderboardResponse, error)
 }
 
+const (
+	// defaultLeaderboardLimit is the number of top scorers returned when no limit is given
+	defaultLeaderboardLimit = 10
+	// maxLeaderboardLimit caps the number of top scorers returned
+	maxLeaderboardLimit = 100
+)
+
 // examService implements ExamService
 type examService struct {
 	examRepo       repository.ExamRepository
@@ -87,48 +99,54 @@ func (s *examService) StartExam(packageSlug string, examSlug string, userID uint
 	}
 	packageID := packageData.Package.ID
 
-	// 3. Validate user enrollment (1 DB call)
-	enrolled, err := s.enrollmentRepo.IsUserEnrolledInPackage(userID, packageID)
+	// 3. Validate the user is entitled to the package: enrolled, paid and not expired (1 DB call)
+	enrollment, err := checkPackageEntitlement(s.enrollmentRepo, userID, packageID)
 	if err != nil {
-		return dto.StartExamResponse{}, fmt.Errorf("failed to check enrollment: %w", err)
-	}
-	if !enrolled {
-		return dto.StartExamResponse{}, repository.ErrNotEnrolledInPackage
+		return dto.StartExamResponse{}, err
 	}
 
-	// 4. Verify exam belongs to the specified package
-	examBelongsToPackage := false
-	for _, examData := range packageData.Exams {
-		if examData.ID == exam.ID {
-			examBelongsToPackage = true
+	// 4. Verify exam belongs to the specified package (attempt history comes along with it)
+	var packageExam *repository.ExamWithUserData
+	for i := range packageData.Exams {
+		if packageData.Exams[i].ID == exam.ID {
+			packageExam = &packageData.Exams[i]
 			break
 		}
 	}
-	if !examBelongsToPackage {
+	if packageExam == nil {
 		return dto.StartExamResponse{}, fmt.Errorf("exam does not belong to the specified package")
 	}
 
-	// 5. Check for existing attempt in THIS package context (1 DB call)
-	existingAttempt, err := s.examRepo.GetUserAttemptForExamInPackage(userID, exam.ID, packageID)
-	if err != nil {
+	// Trials only open the exams the package makes visible during a trial
+	if err := checkTrialExamAccess(enrollment, packageExam); err != nil {
 		return dto.StartExamResponse{}, err
 	}
 
-	if existingAttempt != nil {
-		// Check if already completed (single attempt rule per package)
-		if existingAttempt.IsCompleted() {
-			return dto.StartExamResponse{}, repository.ErrExamAlreadySubmitted
-		}
-		// Return active session
+	// 5. Resume an in-progress attempt in THIS package context
+	if activeAttempt := packageExam.ActiveAttempt(); activeAttempt != nil {
 		return dto.StartExamResponse{
-			SessionID: existingAttempt.GetSessionKey(),
-			AttemptID: existingAttempt.ID,
+			SessionID: activeAttempt.GetSessionKey(),
+			AttemptID: activeAttempt.ID,
 			ExamMeta:  s.examMapper.ToExamMetaResponse(*exam),
 		}, nil
 	}
 
-	// 6. Create new attempt - reuse exam data (1 DB call)
-	attempt, err := s.examRepo.CreateExamAttemptWithExam(userID, exam, packageID, deviceInfo)
+	// 6. New attempts need a published exam inside its schedule window, resuming is always allowed
+	if err := checkExamSchedule(exam, time.Now()); err != nil {
+		return dto.StartExamResponse{}, err
+	}
+
+	// 7. Enforce the attempt limit (every previous attempt counts, abandoned ones included)
+	attemptsUsed := packageExam.AttemptsUsed()
+	if !exam.HasUnlimitedAttempts() && attemptsUsed >= exam.MaxAttempts {
+		if exam.MaxAttempts == 1 {
+			return dto.StartExamResponse{}, repository.ErrExamAlreadySubmitted
+		}
+		return dto.StartExamResponse{}, errors.NewMaxAttemptsExceededError(exam.ID, exam.MaxAttempts, attemptsUsed)
+	}
+
+	// 8. Create new attempt - reuse exam data (1 DB call)
+	attempt, err := s.examRepo.CreateExamAttemptWithExam(userID, exam, packageID, packageExam.NextAttemptNumber(), deviceInfo)
 	if err != nil {
 		return dto.StartExamResponse{}, err
 	}
@@ -168,6 +186,12 @@ func (s *examService) GetSession(sessionID string, userID uint) (dto.ExamSession
 		return dto.ExamSessionResponse{}, fmt.Errorf("session does not belong to user")
 	}
 
+	// A session can only be resumed while the enrollment still gives access to the package
+	if _, err := checkPackageEntitlement(s.enrollmentRepo, userID, sessionData.Attempt.PackageID); err != nil {
+		log.WithError(err).Warn("User is no longer entitled to the session's package")
+		return dto.ExamSessionResponse{}, err
+	}
+
 	// Check if session is still valid (not expired)
 	if sessionData.Attempt.IsTimeExpired() {
 		timeRemaining := sessionData.Attempt.GetRemainingTimeSeconds()
@@ -189,22 +213,53 @@ func (s *examService) GetSession(sessionID string, userID uint) (dto.ExamSession
 		savedAnswers = make(map[uint]string)
 	}
 
+	// Answers are stored with the exam's option keys, the student sees the attempt's own keys
+	layout, err := sessionLayout(sessionData)
+	if err != nil {
+		return dto.ExamSessionResponse{}, err
+	}
+
+	// Tutor mode sessions restore the feedback for answers already checked
+	var checkedFeedback []dto.AnswerFeedback
+	if sessionData.Exam.AllowsAnswerChecking() {
+		checked, err := s.examRepo.GetCheckedAnswers(sessionID)
+		if err != nil {
+			log.WithError(err).Warn("Failed to retrieve checked answers - continuing without feedback")
+			checked = make(map[uint]string)
+		}
+		applyCheckedAnswers(savedAnswers, checked)
+
+		if checkedFeedback, err = buildCheckedFeedback(sessionData.Exam, checked, layout); err != nil {
+			log.WithError(err).Warn("Failed to build feedback for checked answers")
+		}
+	}
+
+	// Restore the questions flagged to revisit
+	flagged, err := s.examRepo.GetFlaggedQuestions(sessionID)
+	if err != nil {
+		log.WithError(err).Warn("Failed to retrieve flagged questions - continuing without flags")
+	}
+
 	// Convert saved answers to response format
 	var savedAnswersResponse []dto.UserAnswerResponse
 	for questionID, selectedOption := range savedAnswers {
 		savedAnswersResponse = append(savedAnswersResponse, dto.UserAnswerResponse{
 			QuestionID:     questionID,
-			SelectedOption: selectedOption,
+			SelectedOption: layout.ToDisplayed(questionID, selectedOption),
 		})
 	}
 
 	// Convert to response format with saved answers
 	response := s.examMapper.ToExamSessionResponseWithAnswers(sessionData.Attempt, sessionData.Exam, savedAnswersResponse)
+	response.Session.CheckedAnswers = checkedFeedback
+	response.Session.FlaggedQuestions = flagged
 
 	return response, nil
 }
 
-// SyncSession syncs user answers for an active exam session
+// SyncSession merges a delta of question states into an active exam session. Each question is last-writer-wins
+// on the time the client changed it: a write older than what the session already has is rejected, so a delayed
+// retry or a second tab cannot overwrite newer answers. Questions left out of the delta keep their state.
 func (s *examService) SyncSession(sessionID string, userID uint, answers []dto.UserAnswerSync) (dto.SyncSessionResponse, error) {
 	// Get active session data from repository to validate session and ownership (STARTED status only)
 	sessionData, err := s.examRepo.GetActiveSessionByID(sessionID)
@@ -227,29 +282,476 @@ func (s *examService) SyncSession(sessionID string, userID uint, answers []dto.U
 		return dto.SyncSessionResponse{}, fmt.Errorf("session is not in progress")
 	}
 
-	// Convert answers to map format for repository
-	answersMap := make(map[uint]string)
-	for _, answer := range answers {
-		answersMap[answer.QuestionID] = answer.SelectedOption
+	// Answers arrive with the keys the student sees and are stored with the exam's keys
+	layout, err := sessionLayout(sessionData)
+	if err != nil {
+		return dto.SyncSessionResponse{}, err
+	}
+
+	// Merging reads and writes the session state, concurrent syncs of a session must not interleave on any
+	// server, so the merge runs under the session's lock
+	var response dto.SyncSessionResponse
+	err = s.examRepo.WithSessionLock(sessionID, func(examRepo repository.ExamRepository) error {
+		var err error
+		response, err = s.mergeSession(examRepo, sessionData, layout, answers)
+		return err
+	})
+	if err != nil {
+		return dto.SyncSessionResponse{}, err
 	}
 
-	// Sync answers to cache
-	err = s.examRepo.SyncSessionAnswers(sessionID, answersMap)
+	return response, nil
+}
+
+// mergeSession applies a sync's delta to the session state read from examRepo and writes the result back,
+// see SyncSession. Versions are the times the client changed each question.
+func (s *examService) mergeSession(examRepo repository.ExamRepository, sessionData *repository.SessionWithExamData, layout models.AttemptLayout, answers []dto.UserAnswerSync) (dto.SyncSessionResponse, error) {
+	sessionID := sessionData.Attempt.GetSessionKey()
+
+	// Merging onto a partial state would drop answers, so a failed read fails the sync
+	previousAnswers, err := examRepo.GetSessionAnswers(sessionID)
 	if err != nil {
-		return dto.SyncSessionResponse{}, fmt.Errorf("failed to sync answers: %w", err)
+		return dto.SyncSessionResponse{}, fmt.Errorf("failed to get answers: %w", err)
+	}
+	flagged, err := examRepo.GetFlaggedQuestions(sessionID)
+	if err != nil {
+		return dto.SyncSessionResponse{}, fmt.Errorf("failed to get flags: %w", err)
+	}
+	versions, err := examRepo.GetAnswerVersions(sessionID)
+	if err != nil {
+		return dto.SyncSessionResponse{}, fmt.Errorf("failed to get answer versions: %w", err)
+	}
+
+	// Apply the delta on top of the current state, questions can be flagged without an answer
+	now := time.Now()
+	mergedAnswers := make(map[uint]string, len(previousAnswers))
+	for questionID, option := range previousAnswers {
+		mergedAnswers[questionID] = option
+	}
+	flaggedSet := make(map[uint]bool, len(flagged))
+	for _, questionID := range flagged {
+		flaggedSet[questionID] = true
+	}
+	changedAt := make(map[uint]time.Time, len(answers))
+	rejected := make([]uint, 0)
+	for _, answer := range answers {
+		updatedAt := clampToSession(answer.AnsweredAt, sessionData.Attempt.StartedAt, now)
+		if version, ok := versions[answer.QuestionID]; ok && updatedAt.Before(version) {
+			rejected = append(rejected, answer.QuestionID)
+			continue
+		}
+
+		versions[answer.QuestionID] = updatedAt
+		changedAt[answer.QuestionID] = updatedAt
+		if answer.SelectedOption != "" {
+			mergedAnswers[answer.QuestionID] = layout.ToCanonical(answer.QuestionID, answer.SelectedOption)
+		} else {
+			delete(mergedAnswers, answer.QuestionID)
+		}
+		if answer.Flagged {
+			flaggedSet[answer.QuestionID] = true
+		} else {
+			delete(flaggedSet, answer.QuestionID)
+		}
+	}
+
+	// Checked answers are locked in tutor mode - the client cannot change them
+	if err := lockCheckedAnswers(examRepo, sessionData.Exam, sessionID, mergedAnswers); err != nil {
+		return dto.SyncSessionResponse{}, err
+	}
+	mergedFlags := sortedQuestionIDs(flaggedSet)
+
+	if len(changedAt) > 0 {
+		// Log what changed before overwriting the answers. A retried sync can log a change twice,
+		// repeated entries are dropped when the log is folded on submission.
+		changes := answerChanges(previousAnswers, mergedAnswers, changedAt, now)
+		if len(changes) > 0 {
+			if err := examRepo.AppendAnswerChanges(sessionID, changes); err != nil {
+				return dto.SyncSessionResponse{}, fmt.Errorf("failed to log answer changes: %w", err)
+			}
+		}
+
+		if err := examRepo.SyncSessionAnswers(sessionID, mergedAnswers); err != nil {
+			return dto.SyncSessionResponse{}, fmt.Errorf("failed to sync answers: %w", err)
+		}
+		if err := examRepo.SyncFlaggedQuestions(sessionID, mergedFlags); err != nil {
+			return dto.SyncSessionResponse{}, fmt.Errorf("failed to sync flags: %w", err)
+		}
+		if err := examRepo.SyncAnswerVersions(sessionID, versions); err != nil {
+			return dto.SyncSessionResponse{}, fmt.Errorf("failed to sync answer versions: %w", err)
+		}
+	}
+
+	// Time on screen only grows, so it is merged even from stale writes
+	if err := syncQuestionTimes(examRepo, sessionID, sessionData.Attempt.TimeLimitSeconds, answers); err != nil {
+		return dto.SyncSessionResponse{}, err
 	}
 
-	// Create response (time remaining calculated from original session start time)
+	// Return the authoritative state so the client can replace its own
 	response := dto.SyncSessionResponse{
-		Success:       true,
-		SyncedCount:   len(answers),
-		LastSyncAt:    sessionData.Attempt.StartedAt.Format("2006-01-02T15:04:05Z"), // Use session start time for now
-		TimeRemaining: sessionData.Attempt.GetRemainingTimeSeconds(),
+		Success:             true,
+		SyncedCount:         len(changedAt),
+		RejectedQuestionIDs: rejected,
+		Answers:             toUserAnswerResponses(mergedAnswers, layout),
+		FlaggedQuestions:    mergedFlags,
+		LastSyncAt:          now.UTC().Format("2006-01-02T15:04:05Z"),
+		TimeRemaining:       sessionData.Attempt.GetRemainingTimeSeconds(),
 	}
 
 	return response, nil
 }
 
+// CheckAnswer scores one answer of a tutor mode session and returns the correct answer, explanation and
+// reference. The answer is saved and locked; the attempt is still scored as a whole on submission.
+func (s *examService) CheckAnswer(sessionID string, userID uint, req dto.CheckAnswerRequest) (dto.AnswerFeedback, error) {
+	// Get active session data from repository to validate session and ownership (STARTED status only)
+	sessionData, err := s.examRepo.GetActiveSessionByID(sessionID)
+	if err != nil {
+		return dto.AnswerFeedback{}, err
+	}
+
+	// Verify the session belongs to the requesting user
+	if sessionData.Attempt.UserID != userID {
+		return dto.AnswerFeedback{}, fmt.Errorf("session does not belong to user")
+	}
+
+	// Check if session is still valid (not expired)
+	if sessionData.Attempt.IsTimeExpired() {
+		return dto.AnswerFeedback{}, fmt.Errorf("session has expired")
+	}
+
+	// Check if session is still in progress
+	if !sessionData.Attempt.IsInProgress() {
+		return dto.AnswerFeedback{}, fmt.Errorf("session is not in progress")
+	}
+
+	exam := sessionData.Exam
+	if !exam.AllowsAnswerChecking() {
+		return dto.AnswerFeedback{}, errors.NewAnswerCheckingUnavailableError(exam.ID, string(exam.ExamType))
+	}
+
+	questions, engine, err := parseExamScoring(exam)
+	if err != nil {
+		return dto.AnswerFeedback{}, err
+	}
+
+	var question *scoring.Question
+	for i := range questions {
+		if questions[i].ID == req.QuestionID {
+			question = &questions[i]
+			break
+		}
+	}
+	if question == nil {
+		return dto.AnswerFeedback{}, errors.NewQuestionNotInExamError(exam.ID, req.QuestionID)
+	}
+
+	layout, err := sessionLayout(sessionData)
+	if err != nil {
+		return dto.AnswerFeedback{}, err
+	}
+	selectedOption := layout.ToCanonical(req.QuestionID, req.SelectedOption)
+
+	// Locking an answer reads and writes the session state like a sync does, so it runs under the same lock
+	var feedback dto.AnswerFeedback
+	err = s.examRepo.WithSessionLock(sessionID, func(examRepo repository.ExamRepository) error {
+		var err error
+		feedback, err = checkAnswer(examRepo, sessionID, *question, engine, selectedOption, req, layout)
+		return err
+	})
+	if err != nil {
+		return dto.AnswerFeedback{}, err
+	}
+
+	return feedback, nil
+}
+
+// checkAnswer locks a tutor mode answer in the session state read from examRepo and returns its feedback,
+// see CheckAnswer
+func checkAnswer(examRepo repository.ExamRepository, sessionID string, question scoring.Question, engine *scoring.Engine, selectedOption string, req dto.CheckAnswerRequest, layout models.AttemptLayout) (dto.AnswerFeedback, error) {
+	checked, err := examRepo.GetCheckedAnswers(sessionID)
+	if err != nil {
+		return dto.AnswerFeedback{}, fmt.Errorf("failed to get checked answers: %w", err)
+	}
+
+	// Checking the same answer again returns the same feedback, changing it is not allowed
+	if locked, ok := checked[req.QuestionID]; ok {
+		if locked != selectedOption {
+			return dto.AnswerFeedback{}, errors.NewAnswerLockedError(req.QuestionID, layout.ToDisplayed(req.QuestionID, locked), req.SelectedOption)
+		}
+		return toAnswerFeedback(question, engine.ScoreQuestion(question, locked), locked, layout), nil
+	}
+
+	// The lock and the answer are written in the session lock's transaction, a failed save rolls back both
+	checked[req.QuestionID] = selectedOption
+	if err := examRepo.SaveCheckedAnswers(sessionID, checked); err != nil {
+		return dto.AnswerFeedback{}, fmt.Errorf("failed to lock answer: %w", err)
+	}
+
+	savedAnswers, err := examRepo.GetSessionAnswers(sessionID)
+	if err != nil {
+		return dto.AnswerFeedback{}, fmt.Errorf("failed to get answers: %w", err)
+	}
+	if previous := savedAnswers[req.QuestionID]; previous != selectedOption {
+		now := time.Now().UTC()
+		change := models.SessionAnswerChange{
+			QuestionID: req.QuestionID,
+			From:       previous,
+			To:         selectedOption,
+			ChangedAt:  now,
+			SyncedAt:   now,
+		}
+		if err := examRepo.AppendAnswerChanges(sessionID, []models.SessionAnswerChange{change}); err != nil {
+			return dto.AnswerFeedback{}, fmt.Errorf("failed to log answer change: %w", err)
+		}
+	}
+	savedAnswers[req.QuestionID] = selectedOption
+	if err := examRepo.SyncSessionAnswers(sessionID, savedAnswers); err != nil {
+		return dto.AnswerFeedback{}, fmt.Errorf("failed to save answer: %w", err)
+	}
+
+	return toAnswerFeedback(question, engine.ScoreQuestion(question, selectedOption), selectedOption, layout), nil
+}
+
+// syncQuestionTimes records the time reported for each question. The client reports the total time a
+// question has been on screen, so the largest report wins and a delayed sync cannot lower it.
+func syncQuestionTimes(examRepo repository.ExamRepository, sessionID string, timeLimitSeconds int, answers []dto.UserAnswerSync) error {
+	reported := false
+	for _, answer := range answers {
+		if answer.TimeSpentSeconds > 0 {
+			reported = true
+			break
+		}
+	}
+	if !reported {
+		return nil
+	}
+
+	times, err := examRepo.GetQuestionTimes(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get question times: %w", err)
+	}
+
+	changed := false
+	for _, answer := range answers {
+		seconds := answer.TimeSpentSeconds
+		if seconds > timeLimitSeconds {
+			seconds = timeLimitSeconds
+		}
+		if seconds > times[answer.QuestionID] {
+			times[answer.QuestionID] = seconds
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := examRepo.SyncQuestionTimes(sessionID, times); err != nil {
+		return fmt.Errorf("failed to sync question times: %w", err)
+	}
+	return nil
+}
+
+// answerChanges lists the answers that differ from the previous state in question order, stamped with the
+// time the client changed them or now when unknown
+func answerChanges(previous, current map[uint]string, changedAt map[uint]time.Time, now time.Time) []models.SessionAnswerChange {
+	questionIDs := make([]uint, 0, len(current))
+	for questionID, option := range current {
+		if previous[questionID] != option {
+			questionIDs = append(questionIDs, questionID)
+		}
+	}
+	// Cleared answers are changes too
+	for questionID := range previous {
+		if _, ok := current[questionID]; !ok {
+			questionIDs = append(questionIDs, questionID)
+		}
+	}
+	sort.Slice(questionIDs, func(i, j int) bool { return questionIDs[i] < questionIDs[j] })
+
+	changes := make([]models.SessionAnswerChange, 0, len(questionIDs))
+	for _, questionID := range questionIDs {
+		at, ok := changedAt[questionID]
+		if !ok {
+			at = now
+		}
+		changes = append(changes, models.SessionAnswerChange{
+			QuestionID: questionID,
+			From:       previous[questionID],
+			To:         current[questionID],
+			ChangedAt:  at.UTC(),
+			SyncedAt:   now.UTC(),
+		})
+	}
+	return changes
+}
+
+// clampToSession returns a client timestamp kept between the session start and now, so a wrong device clock
+// cannot move a change outside the session. A missing timestamp is now.
+func clampToSession(clientTime *time.Time, startedAt, now time.Time) time.Time {
+	if clientTime == nil || clientTime.After(now) {
+		return now
+	}
+	if clientTime.Before(startedAt) {
+		return startedAt
+	}
+	return *clientTime
+}
+
+// sortedQuestionIDs returns the question IDs of a set in ascending order
+func sortedQuestionIDs(set map[uint]bool) []uint {
+	questionIDs := make([]uint, 0, len(set))
+	for questionID := range set {
+		questionIDs = append(questionIDs, questionID)
+	}
+	sort.Slice(questionIDs, func(i, j int) bool { return questionIDs[i] < questionIDs[j] })
+	return questionIDs
+}
+
+// toUserAnswerResponses converts saved answers to their response format in question order, with the
+// option keys the student sees
+func toUserAnswerResponses(answers map[uint]string, layout models.AttemptLayout) []dto.UserAnswerResponse {
+	questionIDs := make([]uint, 0, len(answers))
+	for questionID := range answers {
+		questionIDs = append(questionIDs, questionID)
+	}
+	sort.Slice(questionIDs, func(i, j int) bool { return questionIDs[i] < questionIDs[j] })
+
+	responses := make([]dto.UserAnswerResponse, 0, len(answers))
+	for _, questionID := range questionIDs {
+		responses = append(responses, dto.UserAnswerResponse{
+			QuestionID:     questionID,
+			SelectedOption: layout.ToDisplayed(questionID, answers[questionID]),
+		})
+	}
+	return responses
+}
+
+// lockCheckedAnswers overwrites answers with the session's checked answers when the exam is in tutor mode
+func lockCheckedAnswers(examRepo repository.ExamRepository, exam models.Exam, sessionID string, answers map[uint]string) error {
+	if !exam.AllowsAnswerChecking() {
+		return nil
+	}
+
+	checked, err := examRepo.GetCheckedAnswers(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get checked answers: %w", err)
+	}
+	applyCheckedAnswers(answers, checked)
+	return nil
+}
+
+// applyCheckedAnswers copies checked answers over the saved answers
+func applyCheckedAnswers(answers map[uint]string, checked map[uint]string) {
+	for questionID, answer := range checked {
+		answers[questionID] = answer
+	}
+}
+
+// buildCheckedFeedback returns the feedback for each checked answer in exam question order
+func buildCheckedFeedback(exam models.Exam, checked map[uint]string, layout models.AttemptLayout) ([]dto.AnswerFeedback, error) {
+	if len(checked) == 0 {
+		return nil, nil
+	}
+
+	questions, engine, err := parseExamScoring(exam)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := make([]dto.AnswerFeedback, 0, len(checked))
+	for _, question := range questions {
+		answer, ok := checked[question.ID]
+		if !ok {
+			continue
+		}
+		feedback = append(feedback, toAnswerFeedback(question, engine.ScoreQuestion(question, answer), answer, layout))
+	}
+	return feedback, nil
+}
+
+// checkExamSchedule rejects starting an exam that is not published or outside its schedule window
+func checkExamSchedule(exam *models.Exam, now time.Time) error {
+	if exam.Status == models.ExamStatusDraft || exam.Status == models.ExamStatusCompleted {
+		return errors.NewExamNotAvailableError(exam.ID, string(exam.Status))
+	}
+
+	opensAt, closesAt := exam.ScheduleWindow()
+	if opensAt != nil && now.Before(*opensAt) {
+		return errors.NewExamNotOpenError(exam.ID, *opensAt)
+	}
+	if closesAt != nil && !now.Before(*closesAt) {
+		return errors.NewExamClosedError(exam.ID, *closesAt)
+	}
+	return nil
+}
+
+// sessionLayout returns the display order of a session's attempt. Attempts that do not shuffle keep the exam's
+// order and keys, so their questions are not parsed.
+func sessionLayout(sessionData *repository.SessionWithExamData) (models.AttemptLayout, error) {
+	if sessionData.Attempt.ShuffleSeed == 0 {
+		return models.AttemptLayout{}, nil
+	}
+
+	var questions []models.ExamQuestion
+	if err := json.Unmarshal([]byte(sessionData.Exam.QuestionsData), &questions); err != nil {
+		return models.AttemptLayout{}, fmt.Errorf("failed to parse questions data: %w", err)
+	}
+	return sessionData.Attempt.Layout(questions), nil
+}
+
+// parseExamScoring returns the exam's question snapshot and an engine with its scoring policy
+func parseExamScoring(exam models.Exam) ([]scoring.Question, *scoring.Engine, error) {
+	policy, err := scoring.ParsePolicy(exam.ScoringPolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	questions, err := scoring.ParseQuestions(exam.QuestionsData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return questions, scoring.NewEngine(policy), nil
+}
+
+// toAnswerFeedback reveals the correct answer, explanation and reference for a checked answer
+func toAnswerFeedback(question scoring.Question, result scoring.Result, selectedOption string, layout models.AttemptLayout) dto.AnswerFeedback {
+	options := make([]dto.AnswerFeedbackOption, 0, len(question.Options))
+	for _, option := range question.Options {
+		options = append(options, dto.AnswerFeedbackOption{
+			Key:       layout.DisplayedKey(question.ID, option.Key),
+			Text:      option.Text,
+			IsCorrect: option.IsCorrect,
+		})
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].Key < options[j].Key })
+
+	// Feedback uses the keys the student sees
+	correctAnswer := result.CorrectAnswer
+	switch expected := result.CorrectAnswer.(type) {
+	case string:
+		correctAnswer = layout.DisplayedKey(question.ID, expected)
+	case map[string]bool:
+		displayed := make(map[string]bool, len(expected))
+		for key, value := range expected {
+			displayed[layout.DisplayedKey(question.ID, key)] = value
+		}
+		correctAnswer = displayed
+	}
+
+	return dto.AnswerFeedback{
+		QuestionID:     question.ID,
+		SelectedOption: layout.ToDisplayed(question.ID, selectedOption),
+		IsCorrect:      result.IsCorrect,
+		CorrectAnswer:  correctAnswer,
+		Options:        options,
+		Explanation:    question.Explanation,
+		Reference:      question.Reference,
+	}
+}
+
 // GetExamBySlug retrieves exam details by slug
 func (s *examService) GetExamBySlug(examSlug string) (*models.Exam, error) {
 	return s.examRepo.GetExamBySlug(examSlug)
@@ -278,262 +780,448 @@ func (s *examService) SubmitExam(sessionID string, userID uint) (dto.SubmitExamR
 		return dto.SubmitExamResponse{}, fmt.Errorf("exam already submitted")
 	}
 
-	// Retrieve saved answers from cache
-	savedAnswers, err := s.examRepo.GetSessionAnswers(sessionID)
+	// Read, score and finalize under the session lock, so a sync cannot commit answers the score does not include
+	submissionTime := time.Now()
+	var result *sessionScore
+	err = s.examRepo.WithSessionLock(sessionID, func(examRepo repository.ExamRepository) error {
+		// A session with no answers yet gets an empty map, an error means the answers could not be read
+		savedAnswers, err := examRepo.GetSessionAnswers(sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve answers: %w", err)
+		}
+		if err := lockCheckedAnswers(examRepo, sessionData.Exam, sessionID, savedAnswers); err != nil {
+			return err
+		}
+
+		// Flags, change log and timing are kept with the attempt's answers, losing them does not stop the submission
+		activity := getSessionActivity(examRepo, sessionID)
+
+		// Score the saved answers against the exam snapshot
+		if result, err = scoreSession(sessionData.Exam, savedAnswers, activity, submissionTime); err != nil {
+			return err
+		}
+
+		// Update attempt status to completed with answers data
+		err = examRepo.CompleteExamAttemptWithAnswers(sessionData.Attempt.ID, result.Score, result.Passed, result.AnswersData, result.CorrectAnswers)
+		if err != nil {
+			return fmt.Errorf("failed to complete exam attempt: %w", err)
+		}
+		return nil
+	})
+	if err == repository.ErrAttemptNotFound {
+		// The attempt was finalized after the session was read
+		return dto.SubmitExamResponse{}, repository.ErrExamAlreadySubmitted
+	}
 	if err != nil {
-		// Continue even if answers not found - user might submit with no answers
-		savedAnswers = make(map[uint]string)
+		return dto.SubmitExamResponse{}, err
 	}
 
-	// Parse questions from the exam's JSON data
-	var examQuestions []map[string]interface{} // Change to handle raw JSON with explanation
-	err = json.Unmarshal([]byte(sessionData.Exam.QuestionsData), &examQuestions)
-	if err != nil {
-		return dto.SubmitExamResponse{}, fmt.Errorf("failed to parse exam questions: %w", err)
+	// Calculate time taken
+	timeTaken := sessionData.Attempt.GetTimeSpentSeconds()
+
+	// Create response
+	response := dto.SubmitExamResponse{
+		SessionID:        sessionID,
+		Score:            result.Score,
+		RawScore:         result.RawScore,
+		PenaltyPoints:    result.PenaltyPoints,
+		Passed:           result.Passed,
+		TotalQuestions:   result.TotalQuestions,
+		CorrectAnswers:   result.CorrectAnswers,
+		TimeTakenSeconds: timeTaken,
+		SubmittedAt:      submissionTime.Format("2006-01-02T15:04:05Z"),
 	}
 
-	// Calculate score and build detailed answer data
-	totalQuestions := len(examQuestions)
-	correctAnswers := 0
-	submissionTime := time.Now()
+	return response, nil
+}
 
-	// Structure to store detailed answers for AnswersData field
-	type OptionDetail struct {
-		Key       string `json:"key"`
-		Text      string `json:"text"`
-		IsCorrect bool   `json:"is_correct"`
-	}
-
-	type AnswerDetail struct {
-		QuestionID    uint           `json:"question_id"`
-		QuestionText  string         `json:"question_text"`
-		QuestionType  string         `json:"question_type"`
-		UserAnswer    []string       `json:"user_answer"`
-		CorrectAnswer interface{}    `json:"correct_answer"`
-		IsCorrect     bool           `json:"is_correct"`
-		PointsEarned  float64        `json:"points_earned"`
-		MaxPoints     int            `json:"max_points"`
-		Explanation   string         `json:"explanation"`
-		Options       []OptionDetail `json:"options"`
-	}
-
-	type AnswersDataStructure struct {
-		SubmissionTimestamp string         `json:"submission_timestamp"`
-		Answers             []AnswerDetail `json:"answers"`
-		ExamSnapshot        struct {
-			TotalQuestions  int     `json:"total_questions"`
-			PassingScore    float64 `json:"passing_score"`
-			DurationMinutes int     `json:"duration_minutes"`
-		} `json:"exam_snapshot"`
-	}
-
-	answersData := AnswersDataStructure{
-		SubmissionTimestamp: submissionTime.Format("2006-01-02T15:04:05Z"),
-		Answers:             make([]AnswerDetail, 0),
+// AutoSubmitSession scores an expired session from its saved answers and stores it as AUTO_SUBMITTED
+func (s *examService) AutoSubmitSession(sessionData repository.SessionWithExamData) (dto.SubmitExamResponse, error) {
+	attempt := sessionData.Attempt
+	sessionID := attempt.GetSessionKey()
+
+	log := logger.WithService("ExamService").WithFields(logrus.Fields{
+		"operation":  "AutoSubmitSession",
+		"attempt_id": attempt.ID,
+		"session_id": sessionID,
+		"user_id":    attempt.UserID,
+	})
+
+	if !attempt.IsInProgress() {
+		return dto.SubmitExamResponse{}, repository.ErrExamAlreadySubmitted
 	}
 
-	// Set exam snapshot
-	answersData.ExamSnapshot.TotalQuestions = totalQuestions
-	answersData.ExamSnapshot.PassingScore = sessionData.Exam.PassingScore
-	answersData.ExamSnapshot.DurationMinutes = sessionData.Exam.DurationMinutes
-
-	// Process each question for scoring and detailed storage
-	var totalPointsEarned float64 = 0
-	var totalMaxPoints float64 = 0
-
-	for _, questionData := range examQuestions {
-		// Extract question fields from map
-		questionID := uint(questionData["id"].(float64))
-		questionText := questionData["question_text"].(string)
-		questionType := questionData["question_type"].(string)
-		options := questionData["options"].(map[string]interface{})
-		points := int(questionData["points"].(float64))
-		explanation := ""
-		if exp, exists := questionData["explanation"]; exists && exp != nil {
-			explanation = exp.(string)
-		}
-
-		// Build options array for answer detail
-		optionDetails := make([]OptionDetail, 0, len(options))
-		for key, value := range options {
-			if optData, ok := value.(map[string]interface{}); ok {
-				text := ""
-				if t, ok := optData["text"].(string); ok {
-					text = t
-				}
-				isCorrect := false
-				if c, ok := optData["is_correct"].(bool); ok {
-					isCorrect = c
-				}
-				optionDetails = append(optionDetails, OptionDetail{
-					Key:       key,
-					Text:      text,
-					IsCorrect: isCorrect,
-				})
-			}
-		}
+	// The attempt ended when its time limit ran out, not when the cleanup job noticed
+	submissionTime := attempt.StartedAt.Add(time.Duration(attempt.TimeLimitSeconds) * time.Second)
 
-		userAnswer, exists := savedAnswers[questionID]
+	// Read, score and finalize under the session lock, so a late sync cannot commit answers the score does not include
+	var result *sessionScore
+	answeredCount := 0
+	err := s.examRepo.WithSessionLock(sessionID, func(examRepo repository.ExamRepository) error {
+		// Answers synced before the deadline are all we have. If they cannot be read the session is
+		// retried on the next run rather than scored as unanswered.
+		savedAnswers, err := examRepo.GetSessionAnswers(sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve answers: %w", err)
+		}
+		if err := lockCheckedAnswers(examRepo, sessionData.Exam, sessionID, savedAnswers); err != nil {
+			log.WithError(err).Warn("Failed to retrieve checked answers - scoring synced answers only")
+		}
+		answeredCount = len(savedAnswers)
+		activity := getSessionActivity(examRepo, sessionID)
 
-		// Parse user answer (handle both single and multiple selections)
-		var userAnswerArray []string
-		if exists && userAnswer != "" {
-			// Try to parse as JSON array first (for TRUE_FALSE multiple selections)
-			var parsedArray []string
-			if err := json.Unmarshal([]byte(userAnswer), &parsedArray); err == nil {
-				userAnswerArray = parsedArray
-			} else {
-				// Single answer (for SBA questions)
-				userAnswerArray = []string{userAnswer}
-			}
+		if result, err = scoreSession(sessionData.Exam, savedAnswers, activity, submissionTime); err != nil {
+			return err
 		}
+		return examRepo.AutoSubmitExamAttempt(attempt.ID, result.Score, result.Passed, result.AnswersData, result.CorrectAnswers)
+	})
+	if err == repository.ErrAttemptNotFound {
+		// The user submitted after the expired sessions were read
+		return dto.SubmitExamResponse{}, repository.ErrExamAlreadySubmitted
+	}
+	if err != nil {
+		return dto.SubmitExamResponse{}, err
+	}
 
-		// Find correct answers and check if user answer is correct
-		var correctAnswerArray []string
-		var correctAnswersMap map[string]bool // for TRUE_FALSE questions
-		isCorrect := false
-		pointsEarned := 0.0
-		maxPoints := float64(points)
-		totalMaxPoints += maxPoints
-
-		if questionType == string(models.QuestionTypeSBA) {
-			// Parse options to find correct answer
-			for key, value := range options {
-				if optData, ok := value.(map[string]interface{}); ok {
-					if isCorrectOption, exists := optData["is_correct"].(bool); exists && isCorrectOption {
-						correctAnswerArray = append(correctAnswerArray, key)
-						// Check if user's answer matches
-						if len(userAnswerArray) == 1 && userAnswerArray[0] == key {
-							isCorrect = true
-							pointsEarned = maxPoints
-							correctAnswers++
-						}
-					}
-				}
-			}
-		} else if questionType == string(models.QuestionTypeTrueFalse) {
-			// For TRUE_FALSE questions, extract correct answers
-			correctAnswersMap = make(map[string]bool) // key -> true/false (expected answer)
-			for key, value := range options {
-				if optData, ok := value.(map[string]interface{}); ok {
-					if isCorrectOption, exists := optData["is_correct"].(bool); exists {
-						correctAnswersMap[key] = isCorrectOption
-						// For backward compatibility, also populate the old array format
-						if isCorrectOption {
-							correctAnswerArray = append(correctAnswerArray, key)
-						}
-					}
-				}
-			}
+	log.WithFields(logrus.Fields{
+		"score":           result.Score,
+		"passed":          result.Passed,
+		"answered_count":  answeredCount,
+		"correct_answers": result.CorrectAnswers,
+	}).Info("Expired exam session auto-submitted")
 
-			// Parse user's TRUE_FALSE answers in format ["a:true", "b:false", "c:true"]
-			userTrueFalseAnswers := make(map[string]bool)
+	return dto.SubmitExamResponse{
+		SessionID:        sessionID,
+		Score:            result.Score,
+		RawScore:         result.RawScore,
+		PenaltyPoints:    result.PenaltyPoints,
+		Passed:           result.Passed,
+		TotalQuestions:   result.TotalQuestions,
+		CorrectAnswers:   result.CorrectAnswers,
+		TimeTakenSeconds: attempt.TimeLimitSeconds,
+		SubmittedAt:      submissionTime.Format("2006-01-02T15:04:05Z"),
+	}, nil
+}
 
-			for _, userAns := range userAnswerArray {
-				parts := strings.Split(userAns, ":")
-				if len(parts) == 2 {
-					optionKey := parts[0]
-					answerValue := parts[1] == "true"
-					userTrueFalseAnswers[optionKey] = answerValue
-				}
-			}
+// sessionActivity is what a session records besides its answers
+type sessionActivity struct {
+	Flagged   []uint
+	Changes   []models.SessionAnswerChange // Oldest first
+	TimeSpent map[uint]int                 // Seconds per question
+}
 
-			// Calculate score based on correct true/false answers
-			if len(userTrueFalseAnswers) > 0 && len(correctAnswersMap) > 0 {
-				correctMatches := 0
-				totalOptions := len(correctAnswersMap)
-
-				// Count how many of user's answers are correct
-				for optionKey, expectedAnswer := range correctAnswersMap {
-					if userAnswer, exists := userTrueFalseAnswers[optionKey]; exists {
-						if userAnswer == expectedAnswer {
-							correctMatches++
-						}
-					}
-				}
-
-				// Calculate score based on the proportion of correct answers
-				if correctMatches == totalOptions {
-					// Perfect answer: all options marked correctly
-					isCorrect = true
-					pointsEarned = maxPoints
-					correctAnswers++
-				} else if correctMatches > 0 {
-					// Partial credit: (correct matches / total options) * max points
-					pointsEarned = (float64(correctMatches) / float64(totalOptions)) * maxPoints
-				}
-			}
-		}
+// getSessionActivity reads a session's flags, answer change log and question times. Missing parts are
+// logged and left empty so the attempt can still be scored.
+func getSessionActivity(examRepo repository.ExamRepository, sessionID string) sessionActivity {
+	log := logger.WithService("ExamService").WithFields(logrus.Fields{
+		"operation":  "GetSessionActivity",
+		"session_id": sessionID,
+	})
 
-		totalPointsEarned += pointsEarned
+	var activity sessionActivity
+	var err error
+	if activity.Flagged, err = examRepo.GetFlaggedQuestions(sessionID); err != nil {
+		log.WithError(err).Warn("Failed to retrieve flagged questions - storing answers without flags")
+	}
+	if activity.Changes, err = examRepo.GetAnswerChanges(sessionID); err != nil {
+		log.WithError(err).Warn("Failed to retrieve answer changes - storing answers without change history")
+	}
+	if activity.TimeSpent, err = examRepo.GetQuestionTimes(sessionID); err != nil {
+		log.WithError(err).Warn("Failed to retrieve question times - storing answers without timing")
+	}
+	return activity
+}
 
-		// Build answer detail with proper correct answer
-		var correctAnswerInterface interface{}
-		if questionType == string(models.QuestionTypeSBA) {
-			// For SBA, return single correct answer
-			if len(correctAnswerArray) > 0 {
-				correctAnswerInterface = correctAnswerArray[0]
-			}
-		} else {
-			// For TRUE_FALSE, return map of correct true/false answers
-			if questionType == string(models.QuestionTypeTrueFalse) && correctAnswersMap != nil {
-				correctAnswerInterface = correctAnswersMap
-			} else {
-				// Legacy fallback for other question types
-				correctAnswerInterface = correctAnswerArray
-			}
-		}
+// GetLeaderboard returns the top scorers of a MOCK or FINAL exam within a package and the user's own standing
+func (s *examService) GetLeaderboard(packageSlug string, examSlug string, userID uint, limit int) (dto.LeaderboardResponse, error) {
+	exam, err := s.examRepo.GetExamBySlug(examSlug)
+	if err != nil {
+		return dto.LeaderboardResponse{}, err
+	}
+	if !exam.HasLeaderboard() {
+		return dto.LeaderboardResponse{}, errors.NewLeaderboardUnavailableError(exam.ID, string(exam.ExamType))
+	}
 
-		// Create detailed answer record
-		answerDetail := AnswerDetail{
-			QuestionID:    questionID,
-			QuestionText:  questionText,
-			QuestionType:  questionType,
-			UserAnswer:    userAnswerArray,
-			CorrectAnswer: correctAnswerInterface,
-			IsCorrect:     isCorrect,
-			PointsEarned:  pointsEarned,
-			MaxPoints:     int(maxPoints),
-			Explanation:   explanation, // Store explanation for this question
-			Options:       optionDetails,
+	packageData, err := s.examRepo.GetPackageWithExamsBySlug(packageSlug, userID)
+	if err != nil {
+		return dto.LeaderboardResponse{}, fmt.Errorf("failed to get package: %w", err)
+	}
+	packageID := packageData.Package.ID
+
+	// Leaderboards are only visible to students of the package
+	if _, err := checkPackageEntitlement(s.enrollmentRepo, userID, packageID); err != nil {
+		return dto.LeaderboardResponse{}, err
+	}
+
+	inPackage := false
+	for _, packageExam := range packageData.Exams {
+		if packageExam.ID == exam.ID {
+			inPackage = true
+			break
 		}
+	}
+	if !inPackage {
+		return dto.LeaderboardResponse{}, repository.ErrExamNotFound
+	}
+
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	if limit > maxLeaderboardLimit {
+		limit = maxLeaderboardLimit
+	}
+
+	entries, total, err := s.examRepo.GetLeaderboard(exam.ID, packageID, limit)
+	if err != nil {
+		return dto.LeaderboardResponse{}, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	response := dto.LeaderboardResponse{
+		ExamID:            exam.ID,
+		ExamTitle:         exam.Title,
+		PackageSlug:       packageSlug,
+		TotalParticipants: total,
+		Entries:           make([]dto.LeaderboardEntryResponse, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, dto.LeaderboardEntryResponse{
+			Rank:          entry.Rank,
+			UserID:        entry.UserID,
+			Name:          entry.UserName,
+			Score:         entry.Score,
+			TimeSpent:     entry.TimeSpent,
+			IsCurrentUser: entry.UserID == userID,
+		})
+	}
+
+	rank, err := s.examRepo.GetLeaderboardRank(exam.ID, packageID, userID)
+	if err != nil && err != repository.ErrNotRanked {
+		return dto.LeaderboardResponse{}, fmt.Errorf("failed to get leaderboard rank: %w", err)
+	}
+	if rank != nil {
+		response.CurrentUser = toLeaderboardRankResponse(rank)
+	}
+
+	return response, nil
+}
+
+// toLeaderboardRankResponse converts a leaderboard rank to its response
+func toLeaderboardRankResponse(rank *repository.LeaderboardRank) *dto.LeaderboardRankResponse {
+	return &dto.LeaderboardRankResponse{
+		Rank:              rank.Rank,
+		TotalParticipants: rank.TotalParticipants,
+		Percentile:        rank.Percentile,
+		Score:             rank.Score,
+		TimeSpent:         rank.TimeSpent,
+	}
+}
+
+// optionDetail is an answer option stored in AnswersData
+type optionDetail struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// answerDetail is the scored answer for a single question stored in AnswersData
+type answerDetail struct {
+	QuestionID    uint           `json:"question_id"`
+	QuestionText  string         `json:"question_text"`
+	QuestionType  string         `json:"question_type"`
+	UserAnswer    []string       `json:"user_answer"`
+	CorrectAnswer interface{}    `json:"correct_answer"`
+	IsCorrect     bool           `json:"is_correct"`
+	PointsEarned  float64        `json:"points_earned"` // Net of penalties, negative under negative marking
+	Penalty       float64        `json:"penalty"`
+	MaxPoints     int            `json:"max_points"`
+	Explanation   string         `json:"explanation"`
+	Options       []optionDetail `json:"options"`
+	Flagged       bool           `json:"flagged,omitempty"` // Flagged to revisit during the session
+
+	TimeSpentSeconds   int            `json:"time_spent_seconds"`             // Time on screen reported by the client, 0 when not tracked
+	AnsweredAt         string         `json:"answered_at,omitempty"`          // When the final answer was chosen
+	ChangeCount        int            `json:"change_count"`                   // Times an answer was replaced or cleared
+	Changes            []answerChange `json:"changes,omitempty"`              // Answer history, oldest first
+	ChangedFromCorrect bool           `json:"changed_from_correct,omitempty"` // A correct answer was replaced by a wrong one
+}
+
+// answerChange is an entry of a question's answer history stored in AnswersData
+type answerChange struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ChangedAt string `json:"changed_at"`
+	IsCorrect bool   `json:"is_correct"` // Whether the new answer was correct
+}
+
+// answersDataStructure is the JSON document stored in UserExamAttempt.AnswersData
+type answersDataStructure struct {
+	SubmissionTimestamp string         `json:"submission_timestamp"`
+	Answers             []answerDetail `json:"answers"`
+	ScoreBreakdown      scoreBreakdown `json:"score_breakdown"`
+	ExamSnapshot        struct {
+		TotalQuestions  int     `json:"total_questions"`
+		PassingScore    float64 `json:"passing_score"`
+		DurationMinutes int     `json:"duration_minutes"`
+	} `json:"exam_snapshot"`
+}
+
+// scoreBreakdown records how negative marking changed the score
+type scoreBreakdown struct {
+	RawScore        float64 `json:"raw_score"`      // Points earned before penalties
+	PenaltyPoints   float64 `json:"penalty_points"` // Points deducted by negative marking
+	NetScore        float64 `json:"net_score"`      // Score used for pass/fail
+	NegativeMarking bool    `json:"negative_marking"`
+}
 
-		answersData.Answers = append(answersData.Answers, answerDetail)
+// sessionScore is the outcome of scoring a session's answers
+type sessionScore struct {
+	Score          float64 // Net score after penalties
+	RawScore       float64
+	PenaltyPoints  float64
+	Passed         bool
+	TotalQuestions int
+	CorrectAnswers int
+	AnswersData    string // JSON encoded answersDataStructure
+}
+
+// scoreSession scores the saved answers of a session against the exam's question snapshot and folds the
+// session's flags, answer change log and question times into them. Shared by manual submission and
+// server-side auto-submit so both store identical AnswersData.
+func scoreSession(exam models.Exam, savedAnswers map[uint]string, activity sessionActivity, submissionTime time.Time) (*sessionScore, error) {
+	questions, engine, err := parseExamScoring(exam)
+	if err != nil {
+		return nil, err
 	}
+	examResult := engine.ScoreExam(questions, savedAnswers)
 
-	// Calculate score based on points earned
-	score := totalPointsEarned
+	details := buildAnswerDetails(questions, examResult)
+	applySessionActivity(details, questions, engine, activity)
 
-	// Determine if passed
-	passed := score >= sessionData.Exam.PassingScore
+	// Score is the net points earned, passed is decided against the exam's passing score.
+	// Practice sessions are not graded, they never pass.
+	score := examResult.TotalPoints
+	passed := !exam.IsPractice() && score >= exam.PassingScore
+
+	answersData := answersDataStructure{
+		SubmissionTimestamp: submissionTime.Format("2006-01-02T15:04:05Z"),
+		Answers:             details,
+		ScoreBreakdown: scoreBreakdown{
+			RawScore:        examResult.RawPoints(),
+			PenaltyPoints:   examResult.PenaltyPoints,
+			NetScore:        score,
+			NegativeMarking: examResult.NegativeMarking,
+		},
+	}
+
+	// Set exam snapshot
+	answersData.ExamSnapshot.TotalQuestions = len(questions)
+	answersData.ExamSnapshot.PassingScore = exam.PassingScore
+	answersData.ExamSnapshot.DurationMinutes = exam.DurationMinutes
 
 	// Convert answers data to JSON string for storage
 	answersDataJSON, err := json.Marshal(answersData)
 	if err != nil {
-		return dto.SubmitExamResponse{}, fmt.Errorf("failed to marshal answers data: %w", err)
+		return nil, fmt.Errorf("failed to marshal answers data: %w", err)
 	}
 
-	// Update attempt status to completed with answers data
-	err = s.examRepo.CompleteExamAttemptWithAnswers(sessionData.Attempt.ID, score, passed, string(answersDataJSON), correctAnswers)
+	return &sessionScore{
+		Score:          score,
+		RawScore:       examResult.RawPoints(),
+		PenaltyPoints:  examResult.PenaltyPoints,
+		Passed:         passed,
+		TotalQuestions: len(questions),
+		CorrectAnswers: examResult.CorrectAnswers,
+		AnswersData:    string(answersDataJSON),
+	}, nil
+}
+
+// scoreExamAnswers runs the exam's scoring policy over its question snapshot
+func scoreExamAnswers(exam models.Exam, savedAnswers map[uint]string) ([]scoring.Question, scoring.ExamResult, error) {
+	questions, engine, err := parseExamScoring(exam)
 	if err != nil {
-		return dto.SubmitExamResponse{}, fmt.Errorf("failed to complete exam attempt: %w", err)
+		return nil, scoring.ExamResult{}, err
 	}
 
-	// Calculate time taken
-	timeTaken := sessionData.Attempt.GetTimeSpentSeconds()
+	return questions, engine.ScoreExam(questions, savedAnswers), nil
+}
 
-	// Create response
-	response := dto.SubmitExamResponse{
-		SessionID:        sessionID,
-		Score:            score,
-		Passed:           passed,
-		TotalQuestions:   totalQuestions,
-		CorrectAnswers:   correctAnswers,
-		TimeTakenSeconds: timeTaken,
-		SubmittedAt:      submissionTime.Format("2006-01-02T15:04:05Z"),
+// buildAnswerDetails converts scoring results into the AnswersData answer format
+func buildAnswerDetails(questions []scoring.Question, examResult scoring.ExamResult) []answerDetail {
+	details := make([]answerDetail, 0, len(questions))
+	for i, question := range questions {
+		result := examResult.Results[i]
+
+		options := make([]optionDetail, 0, len(question.Options))
+		for _, option := range question.Options {
+			options = append(options, optionDetail{
+				Key:       option.Key,
+				Text:      option.Text,
+				IsCorrect: option.IsCorrect,
+			})
+		}
+
+		details = append(details, answerDetail{
+			QuestionID:    question.ID,
+			QuestionText:  question.Text,
+			QuestionType:  string(question.Type),
+			UserAnswer:    result.UserAnswer,
+			CorrectAnswer: result.CorrectAnswer,
+			IsCorrect:     result.IsCorrect,
+			PointsEarned:  result.PointsEarned,
+			Penalty:       result.Penalty,
+			MaxPoints:     question.Points,
+			Explanation:   question.Explanation,
+			Options:       options,
+		})
 	}
+	return details
+}
 
-	return response, nil
+// applySessionActivity adds the flags, timing and answer history recorded during the session to the
+// answer details. Details and questions are in the same order.
+func applySessionActivity(details []answerDetail, questions []scoring.Question, engine *scoring.Engine, activity sessionActivity) {
+	flaggedSet := make(map[uint]bool, len(activity.Flagged))
+	for _, questionID := range activity.Flagged {
+		flaggedSet[questionID] = true
+	}
+	changesByQuestion := make(map[uint][]models.SessionAnswerChange)
+	for _, change := range activity.Changes {
+		changesByQuestion[change.QuestionID] = append(changesByQuestion[change.QuestionID], change)
+	}
+
+	for i := range details {
+		questionID := details[i].QuestionID
+		details[i].Flagged = flaggedSet[questionID]
+		details[i].TimeSpentSeconds = activity.TimeSpent[questionID]
+
+		current := ""
+		wasCorrect := false
+		for _, change := range changesByQuestion[questionID] {
+			// A retried sync logs the same change again
+			if change.To == current {
+				continue
+			}
+			isCorrect := change.To != "" && engine.ScoreQuestion(questions[i], change.To).IsCorrect
+			if current != "" {
+				details[i].ChangeCount++
+			}
+			wasCorrect = wasCorrect || isCorrect
+
+			details[i].Changes = append(details[i].Changes, answerChange{
+				From:      current,
+				To:        change.To,
+				ChangedAt: change.ChangedAt.Format(time.RFC3339),
+				IsCorrect: isCorrect,
+			})
+			current = change.To
+			if change.To != "" {
+				details[i].AnsweredAt = change.ChangedAt.Format(time.RFC3339)
+			} else {
+				details[i].AnsweredAt = ""
+			}
+		}
+		details[i].ChangedFromCorrect = wasCorrect && !details[i].IsCorrect
+	}
 }
 
 // GetExamResultsBySession returns raw exam attempt data by session ID for frontend processing
@@ -559,6 +1247,12 @@ func (s *examService) GetExamResultsBySession(sessionID string, userID uint) (in
 		"exam_id":        attempt.ExamID,
 	})
 
+	// Results are part of the package content
+	if _, err := checkPackageEntitlement(s.enrollmentRepo, userID, attempt.PackageID); err != nil {
+		log.WithError(err).Warn("User is no longer entitled to the attempt's package")
+		return nil, err
+	}
+
 	// Verify the attempt is in a final state (completed, auto-submitted, or abandoned)
 	validStatuses := []models.AttemptStatus{
 		models.AttemptStatusCompleted,
@@ -589,6 +1283,7 @@ func (s *examService) GetExamResultsBySession(sessionID string, userID uint) (in
 	// Parse the raw answers_data JSON to extract just the answers array
 	var answersDataJson map[string]interface{}
 	var answersArray interface{}
+	var sessionData *repository.SessionWithExamData
 
 	if attempt.AnswersData != "" && attempt.AnswersData != "{}" {
 		err = json.Unmarshal([]byte(attempt.AnswersData), &answersDataJson)
@@ -609,14 +1304,14 @@ func (s *examService) GetExamResultsBySession(sessionID string, userID uint) (in
 		log.Warn("No answers data found - reconstructing question details from exam data")
 
 		// Get the completed session data to access exam questions (final states only)
-		sessionData, err := s.examRepo.GetCompletedSessionByID(sessionID)
+		sessionData, err = s.examRepo.GetCompletedSessionByID(sessionID)
 		if err != nil {
 			log.WithError(err).Error("Failed to retrieve completed session data for answer reconstruction")
 			return nil, fmt.Errorf("failed to get completed session data: %w", err)
 		}
 
 		// Parse the exam questions to create answer details with correct answers
-		reconstructedAnswers, err := s.reconstructAnswerDetails(sessionData.Exam.QuestionsData, sessionID)
+		reconstructedAnswers, err := s.reconstructAnswerDetails(sessionData.Exam, sessionID)
 		if err != nil {
 			log.WithError(err).Error("Failed to reconstruct answer details from exam questions")
 			return nil, fmt.Errorf("failed to reconstruct answer details: %w", err)
@@ -641,19 +1336,34 @@ func (s *examService) GetExamResultsBySession(sessionID string, userID uint) (in
 		"submission_timestamp": attempt.CompletedAt, // authoritative completion time
 	}
 
+	// Negative marking details are only stored for scored submissions
+	if breakdown, ok := answersDataJson["score_breakdown"]; ok {
+		response["score_breakdown"] = breakdown
+	}
+
+	// Mock and final exams also show where the user's best attempt ranks within the package
+	if attempt.Status != models.AttemptStatusAbandoned {
+		if sessionData == nil {
+			if sessionData, err = s.examRepo.GetCompletedSessionByID(sessionID); err != nil {
+				log.WithError(err).Warn("Failed to load exam for ranking - returning results without rank")
+			}
+		}
+		if sessionData != nil && sessionData.Exam.HasLeaderboard() {
+			rank, err := s.examRepo.GetLeaderboardRank(attempt.ExamID, attempt.PackageID, userID)
+			if err != nil {
+				log.WithError(err).Warn("Failed to get leaderboard rank - returning results without rank")
+			} else {
+				response["ranking"] = toLeaderboardRankResponse(rank)
+			}
+		}
+	}
+
 	return response, nil
 }
 
 // reconstructAnswerDetails creates answer details from exam questions when no answers data exists
 // This is used for abandoned or incomplete exams where detailed scoring wasn't performed
-func (s *examService) reconstructAnswerDetails(questionsData string, sessionID string) ([]map[string]interface{}, error) {
-	// Parse questions from the exam's JSON data
-	var examQuestions []map[string]interface{}
-	err := json.Unmarshal([]byte(questionsData), &examQuestions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse exam questions: %w", err)
-	}
-
+func (s *examService) reconstructAnswerDetails(exam models.Exam, sessionID string) ([]answerDetail, error) {
 	// Get any saved answers from cache (user might have answered some questions)
 	savedAnswers, err := s.examRepo.GetSessionAnswers(sessionID)
 	if err != nil {
@@ -661,144 +1371,11 @@ func (s *examService) reconstructAnswerDetails(questionsData string, sessionID s
 		savedAnswers = make(map[uint]string)
 	}
 
-	var reconstructedAnswers []map[string]interface{}
-
-	// Process each question to create answer details
-	for _, questionData := range examQuestions {
-		// Extract question fields from map
-		questionID := uint(questionData["id"].(float64))
-		questionText := questionData["question_text"].(string)
-		questionType := questionData["question_type"].(string)
-		options := questionData["options"].(map[string]interface{})
-		points := int(questionData["points"].(float64))
-		explanation := ""
-		if exp, exists := questionData["explanation"]; exists && exp != nil {
-			explanation = exp.(string)
-		}
-
-		// Build options array for answer detail
-		type OptionDetail struct {
-			Key       string `json:"key"`
-			Text      string `json:"text"`
-			IsCorrect bool   `json:"is_correct"`
-		}
-
-		optionDetails := make([]OptionDetail, 0, len(options))
-		for key, value := range options {
-			if optData, ok := value.(map[string]interface{}); ok {
-				text := ""
-				if t, ok := optData["text"].(string); ok {
-					text = t
-				}
-				isCorrect := false
-				if c, ok := optData["is_correct"].(bool); ok {
-					isCorrect = c
-				}
-				optionDetails = append(optionDetails, OptionDetail{
-					Key:       key,
-					Text:      text,
-					IsCorrect: isCorrect,
-				})
-			}
-		}
-
-		// Get user's answer if they provided one
-		userAnswer, hasAnswer := savedAnswers[questionID]
-		var userAnswerArray []string
-		if hasAnswer && userAnswer != "" {
-			// Try to parse as JSON array first (for TRUE_FALSE multiple selections)
-			var parsedArray []string
-			if err := json.Unmarshal([]byte(userAnswer), &parsedArray); err == nil {
-				userAnswerArray = parsedArray
-			} else {
-				// Single answer (for SBA questions)
-				userAnswerArray = []string{userAnswer}
-			}
-		}
-
-		// Find correct answers
-		var correctAnswerInterface interface{}
-		if questionType == string(models.QuestionTypeSBA) {
-			// For SBA, find the single correct answer
-			for key, value := range options {
-				if optData, ok := value.(map[string]interface{}); ok {
-					if isCorrectOption, exists := optData["is_correct"].(bool); exists && isCorrectOption {
-						correctAnswerInterface = key
-						break
-					}
-				}
-			}
-		} else if questionType == string(models.QuestionTypeTrueFalse) {
-			// For TRUE_FALSE, create map of correct true/false answers
-			correctAnswersMap := make(map[string]bool)
-			for key, value := range options {
-				if optData, ok := value.(map[string]interface{}); ok {
-					if isCorrectOption, exists := optData["is_correct"].(bool); exists {
-						correctAnswersMap[key] = isCorrectOption
-					}
-				}
-			}
-			correctAnswerInterface = correctAnswersMap
-		}
-
-		// Calculate if user's answer is correct (basic scoring for display)
-		isCorrect := false
-		pointsEarned := 0.0
-		if hasAnswer {
-			if questionType == string(models.QuestionTypeSBA) {
-				if len(userAnswerArray) == 1 && correctAnswerInterface != nil {
-					if userAnswerArray[0] == correctAnswerInterface.(string) {
-						isCorrect = true
-						pointsEarned = float64(points)
-					}
-				}
-			} else if questionType == string(models.QuestionTypeTrueFalse) {
-				if correctAnswersMap, ok := correctAnswerInterface.(map[string]bool); ok {
-					// Parse user's TRUE_FALSE answers
-					userTrueFalseAnswers := make(map[string]bool)
-					for _, userAns := range userAnswerArray {
-						parts := strings.Split(userAns, ":")
-						if len(parts) == 2 {
-							optionKey := parts[0]
-							answerValue := parts[1] == "true"
-							userTrueFalseAnswers[optionKey] = answerValue
-						}
-					}
-
-					// Check if all answers match
-					if len(userTrueFalseAnswers) == len(correctAnswersMap) {
-						allCorrect := true
-						for key, expected := range correctAnswersMap {
-							if userAnswer, exists := userTrueFalseAnswers[key]; !exists || userAnswer != expected {
-								allCorrect = false
-								break
-							}
-						}
-						if allCorrect {
-							isCorrect = true
-							pointsEarned = float64(points)
-						}
-					}
-				}
-			}
-		}
-
-		// Create answer detail
-		answerDetail := map[string]interface{}{
-			"question_id":    questionID,
-			"question_text":  questionText,
-			"question_type":  questionType,
-			"user_answer":    userAnswerArray,
-			"correct_answer": correctAnswerInterface,
-			"is_correct":     isCorrect,
-			"points_earned":  pointsEarned,
-			"max_points":     points,
-			"explanation":    explanation,
-			"options":        optionDetails,
-		}
-
-		reconstructedAnswers = append(reconstructedAnswers, answerDetail)
+	// Score with the same engine and policy as a submission so results are consistent
+	questions, examResult, err := scoreExamAnswers(exam, savedAnswers)
+	if err != nil {
+		return nil, err
 	}
 
-	return reconstructedAnswers, nil
+	return buildAnswerDetails(questions, examResult), nil
 }
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+const (
+	// itemAnalysisGroupRatio is the share of attempts in the upper and lower groups for the discrimination index
+	itemAnalysisGroupRatio = 0.27
+	// itemAnalysisMinFlagResponses is the number of responses needed before a question is flagged
+	itemAnalysisMinFlagResponses = 10
+)
+
+// Item analysis flags raised for questions editors should review
+const (
+	ItemFlagTooEasy                = "TOO_EASY"                // Difficulty index above 0.90
+	ItemFlagTooHard                = "TOO_HARD"                // Difficulty index below 0.20
+	ItemFlagLowDiscrimination      = "LOW_DISCRIMINATION"      // Discrimination index below 0.20
+	ItemFlagNegativeDiscrimination = "NEGATIVE_DISCRIMINATION" // Weaker candidates do better than stronger ones
+)
+
+// ItemAnalysisService computes psychometric statistics of questions from analysed answers
+type ItemAnalysisService interface {
+	GetExamItemAnalysis(examID uint) (*dto.ExamItemAnalysisResponse, error)
+	GetQuestionItemAnalysis(questionID uint) (*dto.QuestionItemAnalysisResponse, error)
+}
+
+// itemAnalysisService implements ItemAnalysisService
+type itemAnalysisService struct {
+	repo         repository.ItemAnalysisRepository
+	examRepo     repository.ExamAdminRepository
+	questionRepo repository.QuestionRepository
+}
+
+// NewItemAnalysisService creates a new item analysis service
+func NewItemAnalysisService(repo repository.ItemAnalysisRepository, examRepo repository.ExamAdminRepository, questionRepo repository.QuestionRepository) ItemAnalysisService {
+	return &itemAnalysisService{
+		repo:         repo,
+		examRepo:     examRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+// GetExamItemAnalysis returns statistics for every question of an exam, in exam order
+func (s *itemAnalysisService) GetExamItemAnalysis(examID uint) (*dto.ExamItemAnalysisResponse, error) {
+	exam, err := s.examRepo.GetExamByID(examID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.repo.GetExamItemResponses(examID)
+	if err != nil {
+		return nil, err
+	}
+
+	byQuestion := make(map[uint][]repository.ItemResponse)
+	attempts := make(map[uint]bool)
+	var answeredOrder []uint
+	for _, response := range responses {
+		if _, ok := byQuestion[response.QuestionID]; !ok {
+			answeredOrder = append(answeredOrder, response.QuestionID)
+		}
+		byQuestion[response.QuestionID] = append(byQuestion[response.QuestionID], response)
+		attempts[response.AttemptID] = true
+	}
+
+	// Follow the exam's question order, then any answered question no longer in the snapshot
+	var examQuestions []models.ExamQuestion
+	if exam.QuestionsData != "" {
+		_ = json.Unmarshal([]byte(exam.QuestionsData), &examQuestions)
+	}
+
+	items := make([]dto.ExamItemAnalysis, 0, len(examQuestions))
+	included := make(map[uint]bool, len(examQuestions))
+	for _, question := range examQuestions {
+		included[question.ID] = true
+		items = append(items, dto.ExamItemAnalysis{
+			QuestionID:   question.ID,
+			QuestionText: question.QuestionText,
+			QuestionType: string(question.QuestionType),
+			ItemStats:    computeItemStats(byQuestion[question.ID]),
+		})
+	}
+	for _, questionID := range answeredOrder {
+		if included[questionID] {
+			continue
+		}
+		first := byQuestion[questionID][0]
+		items = append(items, dto.ExamItemAnalysis{
+			QuestionID:   questionID,
+			QuestionText: first.QuestionText,
+			QuestionType: string(first.QuestionType),
+			ItemStats:    computeItemStats(byQuestion[questionID]),
+		})
+	}
+
+	return &dto.ExamItemAnalysisResponse{
+		ExamID:   exam.ID,
+		Title:    exam.Title,
+		Attempts: len(attempts),
+		Items:    items,
+	}, nil
+}
+
+// GetQuestionItemAnalysis returns statistics for a question bank question over all exams and per exam.
+// Attempts are ranked by score percentage so attempts of different exams are comparable.
+func (s *itemAnalysisService) GetQuestionItemAnalysis(questionID uint) (*dto.QuestionItemAnalysisResponse, error) {
+	question, err := s.questionRepo.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.repo.GetQuestionItemResponses(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	byExam := make(map[uint][]repository.ItemResponse)
+	var examOrder []uint
+	for _, response := range responses {
+		if _, ok := byExam[response.ExamID]; !ok {
+			examOrder = append(examOrder, response.ExamID)
+		}
+		byExam[response.ExamID] = append(byExam[response.ExamID], response)
+	}
+	sort.Slice(examOrder, func(i, j int) bool { return examOrder[i] < examOrder[j] })
+
+	perExam := make([]dto.QuestionExamItemAnalysis, 0, len(examOrder))
+	for _, examID := range examOrder {
+		perExam = append(perExam, dto.QuestionExamItemAnalysis{
+			ExamID:    examID,
+			ItemStats: computeItemStats(byExam[examID]),
+		})
+	}
+
+	return &dto.QuestionItemAnalysisResponse{
+		QuestionID:   question.ID,
+		QuestionText: question.QuestionText,
+		QuestionType: string(question.QuestionType),
+		Overall:      computeItemStats(responses),
+		ByExam:       perExam,
+	}, nil
+}
+
+// computeItemStats computes the statistics of one question from its responses (one per attempt)
+func computeItemStats(responses []repository.ItemResponse) dto.ItemStats {
+	stats := dto.ItemStats{
+		Responses:   len(responses),
+		Distractors: []dto.ItemDistractorStats{},
+		Flags:       []string{},
+	}
+	if len(responses) == 0 {
+		return stats
+	}
+
+	n := len(responses)
+	totals := make([]float64, n)
+	var scoreSum, timeSum float64
+	timed := 0
+	for i, response := range responses {
+		totals[i] = attemptScoreRatio(response)
+		scoreSum += response.PartialScore
+		if response.IsCorrect {
+			stats.CorrectCount++
+		}
+		if response.IsSkipped {
+			stats.SkippedCount++
+		}
+		if response.TimeSpent > 0 {
+			timeSum += float64(response.TimeSpent)
+			timed++
+		}
+	}
+
+	p := float64(stats.CorrectCount) / float64(n)
+	stats.DifficultyIndex = roundedPtr(p)
+	stats.AverageScore = roundStat(scoreSum / float64(n))
+	if timed > 0 {
+		stats.AverageTimeSeconds = roundedPtr(timeSum / float64(timed))
+	}
+
+	// Rank attempts by score, best first, to form the upper and lower groups
+	ranked := make([]int, n)
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return totals[ranked[i]] > totals[ranked[j]] })
+
+	groupSize := int(math.Round(itemAnalysisGroupRatio * float64(n)))
+	if groupSize > n/2 {
+		groupSize = n / 2
+	}
+	upper := make(map[int]bool, groupSize)
+	lower := make(map[int]bool, groupSize)
+	if groupSize > 0 {
+		upperCorrect, lowerCorrect := 0, 0
+		for _, index := range ranked[:groupSize] {
+			upper[index] = true
+			if responses[index].IsCorrect {
+				upperCorrect++
+			}
+		}
+		for _, index := range ranked[n-groupSize:] {
+			lower[index] = true
+			if responses[index].IsCorrect {
+				lowerCorrect++
+			}
+		}
+		stats.DiscriminationIndex = roundedPtr(float64(upperCorrect-lowerCorrect) / float64(groupSize))
+	}
+
+	stats.PointBiserial = pointBiserial(responses, totals, p)
+	stats.Distractors = distractorStats(responses, upper, lower)
+	stats.Flags = itemFlags(stats)
+
+	return stats
+}
+
+// attemptScoreRatio returns the attempt score as a fraction of its maximum
+func attemptScoreRatio(response repository.ItemResponse) float64 {
+	if response.AttemptMaxScore == 0 {
+		return 0
+	}
+	return response.AttemptScore / response.AttemptMaxScore
+}
+
+// pointBiserial correlates answering correctly with the attempt score; nil when either is constant
+func pointBiserial(responses []repository.ItemResponse, totals []float64, p float64) *float64 {
+	if p == 0 || p == 1 {
+		return nil
+	}
+
+	n := float64(len(totals))
+	var mean float64
+	for _, total := range totals {
+		mean += total
+	}
+	mean /= n
+
+	var variance, correctSum, incorrectSum float64
+	correctCount := 0
+	for i, total := range totals {
+		variance += (total - mean) * (total - mean)
+		if responses[i].IsCorrect {
+			correctSum += total
+			correctCount++
+		} else {
+			incorrectSum += total
+		}
+	}
+	stdDev := math.Sqrt(variance / n)
+	if stdDev == 0 {
+		return nil
+	}
+
+	correctMean := correctSum / float64(correctCount)
+	incorrectMean := incorrectSum / float64(len(totals)-correctCount)
+	return roundedPtr((correctMean - incorrectMean) / stdDev * math.Sqrt(p*(1-p)))
+}
+
+// distractorStats counts selections per option, including correct options nobody selected
+func distractorStats(responses []repository.ItemResponse, upper, lower map[int]bool) []dto.ItemDistractorStats {
+	byOption := make(map[string]*dto.ItemDistractorStats)
+	option := func(key string) *dto.ItemDistractorStats {
+		if _, ok := byOption[key]; !ok {
+			byOption[key] = &dto.ItemDistractorStats{Option: key}
+		}
+		return byOption[key]
+	}
+
+	for i, response := range responses {
+		var correct []string
+		_ = json.Unmarshal([]byte(response.CorrectOptions), &correct)
+		for _, key := range correct {
+			option(key).IsCorrect = true
+		}
+
+		var selected []string
+		_ = json.Unmarshal([]byte(response.SelectedOptions), &selected)
+		for _, key := range selected {
+			stats := option(key)
+			stats.Count++
+			if upper[i] {
+				stats.UpperCount++
+			}
+			if lower[i] {
+				stats.LowerCount++
+			}
+		}
+	}
+
+	distractors := make([]dto.ItemDistractorStats, 0, len(byOption))
+	for _, stats := range byOption {
+		stats.Proportion = roundStat(float64(stats.Count) / float64(len(responses)))
+		distractors = append(distractors, *stats)
+	}
+	sort.Slice(distractors, func(i, j int) bool { return distractors[i].Option < distractors[j].Option })
+	return distractors
+}
+
+// itemFlags raises review flags once a question has enough responses
+func itemFlags(stats dto.ItemStats) []string {
+	flags := []string{}
+	if stats.Responses < itemAnalysisMinFlagResponses {
+		return flags
+	}
+
+	if stats.DifficultyIndex != nil {
+		switch {
+		case *stats.DifficultyIndex > 0.9:
+			flags = append(flags, ItemFlagTooEasy)
+		case *stats.DifficultyIndex < 0.2:
+			flags = append(flags, ItemFlagTooHard)
+		}
+	}
+	if stats.DiscriminationIndex != nil {
+		switch {
+		case *stats.DiscriminationIndex < 0:
+			flags = append(flags, ItemFlagNegativeDiscrimination)
+		case *stats.DiscriminationIndex < 0.2:
+			flags = append(flags, ItemFlagLowDiscrimination)
+		}
+	}
+	return flags
+}
+
+// roundStat rounds a statistic to four decimal places
+func roundStat(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// roundedPtr returns a pointer to the rounded statistic
+func roundedPtr(value float64) *float64 {
+	rounded := roundStat(value)
+	return &rounded
+}
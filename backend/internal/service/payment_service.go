@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PaymentConfig holds the settings checkouts are started with
+type PaymentConfig struct {
+	Currency        string // ISO currency of package prices (default: BDT)
+	CallbackBaseURL string // Public URL of this API that gateways return students and notifications to
+}
+
+// PaymentService takes payments for pending enrollments through the configured gateways
+type PaymentService interface {
+	GetProviders() *dto.PaymentProvidersResponse
+	InitiateCheckout(ctx context.Context, userID uint, req dto.CheckoutRequest) (*dto.CheckoutResponse, error)
+	HandleCallback(ctx context.Context, provider string, values url.Values) (*dto.PaymentStatusResponse, error)
+	VerifyPayment(ctx context.Context, userID uint, transactionID string) (*dto.PaymentStatusResponse, error)
+}
+
+// paymentService implements PaymentService
+type paymentService struct {
+	paymentRepo repository.PaymentRepository
+	providers   *payment.Registry
+	config      PaymentConfig
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(paymentRepo repository.PaymentRepository, providers *payment.Registry, config PaymentConfig) PaymentService {
+	if config.Currency == "" {
+		config.Currency = "BDT"
+	}
+	config.CallbackBaseURL = strings.TrimRight(config.CallbackBaseURL, "/")
+
+	return &paymentService{
+		paymentRepo: paymentRepo,
+		providers:   providers,
+		config:      config,
+	}
+}
+
+// GetProviders lists the gateways a student can pay with
+func (s *paymentService) GetProviders() *dto.PaymentProvidersResponse {
+	return &dto.PaymentProvidersResponse{
+		Providers: s.providers.Names(),
+		Currency:  s.config.Currency,
+	}
+}
+
+// InitiateCheckout records a new transaction for a pending enrollment and starts it at the gateway.
+// Every checkout gets its own transaction, so a student can retry after a failed or abandoned payment.
+func (s *paymentService) InitiateCheckout(ctx context.Context, userID uint, req dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	log := logger.WithService("PaymentService").WithFields(logrus.Fields{
+		"operation":     "InitiateCheckout",
+		"user_id":       userID,
+		"enrollment_id": req.EnrollmentID,
+		"provider":      req.Provider,
+	})
+
+	provider, err := s.providers.Get(req.Provider)
+	if err != nil {
+		return nil, errors.NewPaymentProviderUnavailableError(req.Provider)
+	}
+
+	enrollment, err := s.paymentRepo.GetEnrollmentForCheckout(req.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	// Other students' enrollments are reported as missing
+	if enrollment.UserID != userID {
+		return nil, repository.ErrEnrollmentNotFound
+	}
+
+	// A renewal is paid at its renewal price, the enrollment itself at the price it was enrolled at
+	amount := enrollment.EnrolledPrice
+	if req.RenewalID != nil {
+		renewal, err := s.paymentRepo.GetRenewalForCheckout(*req.RenewalID)
+		if err != nil {
+			return nil, err
+		}
+		if renewal.EnrollmentID != enrollment.ID {
+			return nil, repository.ErrRenewalNotFound
+		}
+		if !renewal.AwaitsPayment() {
+			return nil, errors.NewPaymentNotRequiredError(enrollment.ID, string(renewal.Status))
+		}
+		amount = renewal.Price
+	} else if !enrollment.AwaitsPayment() {
+		return nil, errors.NewPaymentNotRequiredError(enrollment.ID, string(enrollment.PaymentStatus))
+	}
+
+	transaction := &models.PaymentTransaction{
+		TransactionID: newPaymentTransactionID(),
+		EnrollmentID:  enrollment.ID,
+		RenewalID:     req.RenewalID,
+		UserID:        userID,
+		Provider:      provider.Name(),
+		Amount:        amount,
+		Currency:      s.config.Currency,
+		Status:        models.PaymentTransactionInitiated,
+	}
+	if err := s.paymentRepo.CreateTransaction(transaction); err != nil {
+		return nil, err
+	}
+	log = log.WithField("transaction_id", transaction.TransactionID)
+
+	checkout, err := provider.InitiateCheckout(ctx, payment.CheckoutRequest{
+		TransactionID: transaction.TransactionID,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Description:   enrollment.Package.Name,
+		CustomerName:  enrollment.User.Name,
+		CustomerEmail: enrollment.User.Email,
+		CustomerPhone: enrollment.User.MSISDN,
+		ReturnURL:     s.config.CallbackBaseURL + "/api/payments/callback/" + provider.Name(),
+		NotifyURL:     s.config.CallbackBaseURL + "/api/payments/ipn/" + provider.Name(),
+	})
+	if err != nil {
+		log.WithError(err).Error("Gateway rejected checkout")
+		// The enrollment keeps its status, nothing was charged
+		if _, _, settleErr := s.paymentRepo.SettleTransaction(transaction.TransactionID, repository.PaymentSettlement{
+			Status:        models.PaymentTransactionFailed,
+			FailureReason: truncateReason(err.Error()),
+		}); settleErr != nil {
+			log.WithError(settleErr).Warn("Failed to mark rejected checkout as failed")
+		}
+		return nil, errors.NewPaymentGatewayError(provider.Name(), "checkout", err)
+	}
+
+	if err := s.paymentRepo.SetProviderReference(transaction.TransactionID, checkout.ProviderReference); err != nil {
+		return nil, err
+	}
+
+	log.WithField("provider_reference", checkout.ProviderReference).Info("Checkout started")
+	return &dto.CheckoutResponse{
+		TransactionID: transaction.TransactionID,
+		Provider:      provider.Name(),
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		RedirectURL:   checkout.RedirectURL,
+	}, nil
+}
+
+// HandleCallback processes a gateway redirect or notification. The callback only says which payment it is
+// about; its status is verified with the gateway, so repeated or forged callbacks cannot mark anything paid.
+func (s *paymentService) HandleCallback(ctx context.Context, providerName string, values url.Values) (*dto.PaymentStatusResponse, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return nil, errors.NewPaymentProviderUnavailableError(providerName)
+	}
+
+	callback, err := provider.ParseCallback(values)
+	if err != nil {
+		return nil, errors.NewPaymentGatewayError(provider.Name(), "callback", err)
+	}
+
+	var transaction *models.PaymentTransaction
+	if callback.TransactionID != "" {
+		transaction, err = s.paymentRepo.GetTransaction(callback.TransactionID)
+	} else {
+		transaction, err = s.paymentRepo.GetTransactionByProviderReference(provider.Name(), callback.ProviderReference)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Provider != provider.Name() {
+		return nil, repository.ErrPaymentTransactionNotFound
+	}
+
+	transaction, err = s.verifyTransaction(ctx, provider, transaction)
+	if err != nil {
+		return nil, err
+	}
+	return toPaymentStatusResponse(transaction), nil
+}
+
+// VerifyPayment returns the student's transaction, asking the gateway first while it is not paid
+func (s *paymentService) VerifyPayment(ctx context.Context, userID uint, transactionID string) (*dto.PaymentStatusResponse, error) {
+	transaction, err := s.paymentRepo.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID != userID {
+		return nil, repository.ErrPaymentTransactionNotFound
+	}
+
+	provider, err := s.providers.Get(transaction.Provider)
+	if err != nil {
+		return nil, errors.NewPaymentProviderUnavailableError(transaction.Provider)
+	}
+
+	transaction, err = s.verifyTransaction(ctx, provider, transaction)
+	if err != nil {
+		return nil, err
+	}
+	return toPaymentStatusResponse(transaction), nil
+}
+
+// verifyTransaction asks the gateway about a transaction and settles it once the gateway has decided
+func (s *paymentService) verifyTransaction(ctx context.Context, provider payment.Provider, transaction *models.PaymentTransaction) (*models.PaymentTransaction, error) {
+	if transaction.Status == models.PaymentTransactionPaid {
+		return transaction, nil
+	}
+
+	log := logger.WithService("PaymentService").WithFields(logrus.Fields{
+		"operation":      "VerifyTransaction",
+		"transaction_id": transaction.TransactionID,
+		"enrollment_id":  transaction.EnrollmentID,
+		"provider":       transaction.Provider,
+	})
+
+	reference := ""
+	if transaction.ProviderReference != nil {
+		reference = *transaction.ProviderReference
+	}
+	verification, err := provider.Verify(ctx, transaction.TransactionID, reference)
+	if err != nil {
+		log.WithError(err).Error("Failed to verify payment with gateway")
+		return nil, errors.NewPaymentGatewayError(provider.Name(), "verify", err)
+	}
+
+	settlement, settled := settlementFor(transaction, verification)
+	if !settled {
+		if err := s.paymentRepo.RecordVerification(transaction.TransactionID, verification.Raw); err != nil {
+			log.WithError(err).Warn("Failed to record payment verification")
+		}
+		return transaction, nil
+	}
+
+	updated, applied, err := s.paymentRepo.SettleTransaction(transaction.TransactionID, settlement)
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		log.WithField("status", updated.Status).Info("Payment settled")
+		if updated.Duplicate {
+			log.WithField("amount", updated.Amount).Warn("Payment was for an enrollment already paid - refund the duplicate")
+		}
+	}
+	return updated, nil
+}
+
+// settlementFor turns a verification into a settlement, reporting false while the gateway has not decided.
+// A paid amount or currency that differs from the transaction is treated as a failed payment.
+func settlementFor(transaction *models.PaymentTransaction, verification *payment.Verification) (repository.PaymentSettlement, bool) {
+	settlement := repository.PaymentSettlement{
+		ProviderTransactionID: verification.ProviderTransactionID,
+		PaidAt:                verification.PaidAt,
+		FailureReason:         truncateReason(verification.FailureReason),
+		GatewayData:           verification.Raw,
+	}
+
+	switch verification.Status {
+	case payment.StatusPaid:
+		settlement.Status = models.PaymentTransactionPaid
+		if verification.Amount+0.005 < transaction.Amount {
+			settlement.Status = models.PaymentTransactionFailed
+			settlement.FailureReason = fmt.Sprintf("gateway reported %.2f paid of %.2f", verification.Amount, transaction.Amount)
+		} else if verification.Currency != "" && !strings.EqualFold(verification.Currency, transaction.Currency) {
+			settlement.Status = models.PaymentTransactionFailed
+			settlement.FailureReason = fmt.Sprintf("gateway reported payment in %s instead of %s", verification.Currency, transaction.Currency)
+		}
+	case payment.StatusFailed:
+		settlement.Status = models.PaymentTransactionFailed
+	case payment.StatusCancelled:
+		settlement.Status = models.PaymentTransactionCancelled
+	default:
+		return settlement, false
+	}
+	return settlement, true
+}
+
+// toPaymentStatusResponse converts a transaction to its response
+func toPaymentStatusResponse(transaction *models.PaymentTransaction) *dto.PaymentStatusResponse {
+	response := &dto.PaymentStatusResponse{
+		TransactionID: transaction.TransactionID,
+		EnrollmentID:  transaction.EnrollmentID,
+		RenewalID:     transaction.RenewalID,
+		Provider:      transaction.Provider,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Status:        string(transaction.Status),
+		FailureReason: transaction.FailureReason,
+		Duplicate:     transaction.Duplicate,
+	}
+	if transaction.PaidAt != nil {
+		paidAt := transaction.PaidAt.UTC().Format(time.RFC3339)
+		response.PaidAt = &paidAt
+	}
+	return response
+}
+
+// newPaymentTransactionID returns a unique reference for a checkout
+func newPaymentTransactionID() string {
+	return "MED" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", ""))
+}
+
+// truncateReason keeps failure reasons within the stored column size
+func truncateReason(reason string) string {
+	if len(reason) > 255 {
+		return reason[:255]
+	}
+	return reason
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPracticeQuestions is the session length when the student does not pick one
+	defaultPracticeQuestions = 20
+	// maxPracticeQuestions caps the session length
+	maxPracticeQuestions = 100
+	// practiceSecondsPerQuestion sets the default time limit
+	practiceSecondsPerQuestion = 90
+	// maxPracticeDurationMinutes caps the time limit a student can ask for
+	maxPracticeDurationMinutes = 300
+)
+
+// defaultPracticeDifficultyMix weights practice questions towards medium difficulty
+var defaultPracticeDifficultyMix = map[models.DifficultyLevel]int{
+	models.DifficultyEasy:   30,
+	models.DifficultyMedium: 50,
+	models.DifficultyHard:   20,
+}
+
+// PracticeService starts on-demand practice sessions drawn from the question bank
+type PracticeService interface {
+	StartPractice(userID uint, req dto.StartPracticeRequest) (*dto.StartPracticeResponse, error)
+	StartReview(userID uint, req dto.StartReviewRequest) (*dto.StartReviewResponse, error)
+}
+
+// practiceService implements PracticeService
+type practiceService struct {
+	practiceRepo   repository.PracticeRepository
+	topicRepo      repository.TopicPerformanceRepository
+	reviewRepo     repository.ReviewScheduleRepository
+	examRepo       repository.ExamRepository
+	enrollmentRepo repository.EnrollmentRepository
+	examMapper     mapper.ExamMapper
+}
+
+// NewPracticeService creates a new practice service
+func NewPracticeService(
+	practiceRepo repository.PracticeRepository,
+	topicRepo repository.TopicPerformanceRepository,
+	reviewRepo repository.ReviewScheduleRepository,
+	examRepo repository.ExamRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	examMapper mapper.ExamMapper,
+) PracticeService {
+	return &practiceService{
+		practiceRepo:   practiceRepo,
+		topicRepo:      topicRepo,
+		reviewRepo:     reviewRepo,
+		examRepo:       examRepo,
+		enrollmentRepo: enrollmentRepo,
+		examMapper:     examMapper,
+	}
+}
+
+// StartPractice draws questions the student has not seen or got wrong, or their bookmarked questions,
+// weighted by difficulty, and starts a session on a practice exam generated for them. The session then goes
+// through the regular exam session, sync and submit flow. Practice attempts do not count towards package progress.
+func (s *practiceService) StartPractice(userID uint, req dto.StartPracticeRequest) (*dto.StartPracticeResponse, error) {
+	log := logger.WithService("PracticeService").WithFields(logrus.Fields{
+		"operation":    "StartPractice",
+		"user_id":      userID,
+		"package_slug": req.PackageSlug,
+	})
+
+	if err := validatePracticeRequest(req); err != nil {
+		return nil, err
+	}
+
+	packageID, err := s.enrolledPackageID(userID, req.PackageSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	criteria := repository.PracticeCriteria{
+		SubjectIDs:    req.SubjectIDs,
+		SystemIDs:     req.SystemIDs,
+		QuestionTypes: req.QuestionTypes,
+		Bookmarked:    req.Bookmarked,
+	}
+
+	// Let the system choose: target the weakest systems, or the whole bank until there is enough history
+	targetsWeakAreas := false
+	if len(req.SubjectIDs) == 0 && len(req.SystemIDs) == 0 && !req.Bookmarked {
+		criteria.SystemIDs, err = s.weakestSystemIDs(userID)
+		if err != nil {
+			return nil, err
+		}
+		targetsWeakAreas = len(criteria.SystemIDs) > 0
+	}
+
+	count := req.QuestionCount
+	if count <= 0 {
+		count = defaultPracticeQuestions
+	}
+
+	// Up to count questions of each difficulty are sampled, enough to fill any difficulty mix
+	candidates, available, err := s.practiceRepo.GetPracticeCandidates(userID, criteria, count)
+	if err != nil {
+		return nil, err
+	}
+	if available == 0 && targetsWeakAreas {
+		log.Info("Weak areas have no questions left to practice - drawing from the whole bank")
+		criteria.SystemIDs = nil
+		targetsWeakAreas = false
+		if candidates, available, err = s.practiceRepo.GetPracticeCandidates(userID, criteria, count); err != nil {
+			return nil, err
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.NewNoPracticeQuestionsError(userID)
+	}
+
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	mix := req.DifficultyMix
+	if len(mix) == 0 {
+		mix = defaultPracticeDifficultyMix
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ExamModeTimed
+	}
+
+	exam, err := buildPracticeExam(userID, pickQuestions(candidates, count, mix), req.DurationMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err := s.startSession(userID, exam, packageID, req.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"exam_id":            exam.ID,
+		"attempt_id":         attempt.ID,
+		"question_count":     count,
+		"available":          available,
+		"targets_weak_areas": targetsWeakAreas,
+	}).Info("Practice session started")
+
+	systemIDs := criteria.SystemIDs
+	if systemIDs == nil {
+		systemIDs = []uint{}
+	}
+
+	return &dto.StartPracticeResponse{
+		StartExamResponse: dto.StartExamResponse{
+			SessionID: attempt.GetSessionKey(),
+			AttemptID: attempt.ID,
+			ExamMeta:  s.examMapper.ToExamMetaResponse(*exam),
+		},
+		QuestionCount:      count,
+		SystemIDs:          systemIDs,
+		TargetsWeakAreas:   targetsWeakAreas,
+		AvailableQuestions: available,
+	}, nil
+}
+
+// StartReview starts a practice session on the student's spaced-repetition questions due today, most
+// overdue first. The review schedule is updated from the answers once the session is submitted.
+func (s *practiceService) StartReview(userID uint, req dto.StartReviewRequest) (*dto.StartReviewResponse, error) {
+	log := logger.WithService("PracticeService").WithFields(logrus.Fields{
+		"operation":    "StartReview",
+		"user_id":      userID,
+		"package_slug": req.PackageSlug,
+	})
+
+	if req.QuestionCount < 0 || req.QuestionCount > maxPracticeQuestions {
+		return nil, errors.NewPracticeValidationError("question_count", fmt.Sprintf("must be between 1 and %d", maxPracticeQuestions))
+	}
+	if req.Mode != "" && !req.Mode.IsValid() {
+		return nil, errors.NewPracticeValidationError("mode", fmt.Sprintf("unsupported mode %q", req.Mode))
+	}
+
+	packageID, err := s.enrolledPackageID(userID, req.PackageSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	count := req.QuestionCount
+	if count <= 0 {
+		count = defaultPracticeQuestions
+	}
+
+	dueBy := endOfDay(time.Now())
+	dueCount, err := s.reviewRepo.CountDueSchedules(userID, dueBy)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.reviewRepo.GetDueSchedules(userID, dueBy, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, errors.NewNoDueReviewsError(userID)
+	}
+
+	questions := make([]models.Question, 0, len(schedules))
+	for _, schedule := range schedules {
+		questions = append(questions, schedule.Question)
+	}
+
+	// Reviewing mistakes is most useful with the answer shown straight away
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ExamModeTutor
+	}
+
+	exam, err := buildPracticeExam(userID, questions, 0, mode)
+	if err != nil {
+		return nil, err
+	}
+	exam.Title = fmt.Sprintf("Review - %s", time.Now().Format("2 Jan 2006 15:04"))
+
+	attempt, err := s.startSession(userID, exam, packageID, req.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"exam_id":        exam.ID,
+		"attempt_id":     attempt.ID,
+		"question_count": len(questions),
+		"due_count":      dueCount,
+	}).Info("Review session started")
+
+	return &dto.StartReviewResponse{
+		StartExamResponse: dto.StartExamResponse{
+			SessionID: attempt.GetSessionKey(),
+			AttemptID: attempt.ID,
+			ExamMeta:  s.examMapper.ToExamMetaResponse(*exam),
+		},
+		QuestionCount: len(questions),
+		DueCount:      dueCount,
+	}, nil
+}
+
+// enrolledPackageID returns the ID of the package, practice being open to students enrolled in it
+func (s *practiceService) enrolledPackageID(userID uint, packageSlug string) (uint, error) {
+	packageData, err := s.examRepo.GetPackageWithExamsBySlug(packageSlug, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get package: %w", err)
+	}
+	packageID := packageData.Package.ID
+
+	if _, err := checkPackageEntitlement(s.enrollmentRepo, userID, packageID); err != nil {
+		return 0, err
+	}
+	return packageID, nil
+}
+
+// startSession stores a generated practice exam and starts its only attempt
+func (s *practiceService) startSession(userID uint, exam *models.Exam, packageID uint, deviceInfo map[string]string) (*models.UserExamAttempt, error) {
+	if err := s.practiceRepo.CreatePracticeExam(exam); err != nil {
+		return nil, err
+	}
+	return s.examRepo.CreateExamAttemptWithExam(userID, exam, packageID, 1, deviceInfo)
+}
+
+// weakestSystemIDs returns the student's weakest systems, the same ones the performance report highlights
+func (s *practiceService) weakestSystemIDs(userID uint) ([]uint, error) {
+	systems, err := s.topicRepo.GetSystemPerformance(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	performance := make([]dto.TopicPerformanceDTO, 0, len(systems))
+	for _, system := range systems {
+		performance = append(performance, toTopicPerformanceDTO(system))
+	}
+	sortWeakestFirst(performance)
+
+	systemIDs := make([]uint, 0, weakAreaLimit)
+	for _, system := range weakestAreas(performance) {
+		systemIDs = append(systemIDs, system.ID)
+	}
+	return systemIDs, nil
+}
+
+// validatePracticeRequest checks the request before touching the database
+func validatePracticeRequest(req dto.StartPracticeRequest) error {
+	if req.QuestionCount < 0 || req.QuestionCount > maxPracticeQuestions {
+		return errors.NewPracticeValidationError("question_count", fmt.Sprintf("must be between 1 and %d", maxPracticeQuestions))
+	}
+	if req.DurationMinutes < 0 || req.DurationMinutes > maxPracticeDurationMinutes {
+		return errors.NewPracticeValidationError("duration_minutes", fmt.Sprintf("must be between 1 and %d", maxPracticeDurationMinutes))
+	}
+
+	if req.Mode != "" && !req.Mode.IsValid() {
+		return errors.NewPracticeValidationError("mode", fmt.Sprintf("unsupported mode %q", req.Mode))
+	}
+
+	for _, questionType := range req.QuestionTypes {
+		if questionType != models.QuestionTypeSBA && questionType != models.QuestionTypeTrueFalse {
+			return errors.NewPracticeValidationError("question_types", fmt.Sprintf("unsupported question type %q", questionType))
+		}
+	}
+
+	// The practice mix follows the same rules as an exam blueprint mix
+	if err := validateDifficultyMix(req.DifficultyMix); err != nil {
+		if blueprintErr, ok := err.(*errors.BlueprintValidationError); ok {
+			return errors.NewPracticeValidationError(blueprintErr.Field, blueprintErr.Reason)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// buildPracticeExam creates the exam a practice session is taken on, one point per question. It is not part of
+// any package; admin tools and item analysis skip PRACTICE exams, and their attempts are not graded.
+func buildPracticeExam(userID uint, questions []models.Question, durationMinutes int, mode models.ExamMode) (*models.Exam, error) {
+	snapshot := make([]models.ExamQuestion, 0, len(questions))
+	for i := range questions {
+		examQuestion, err := models.NewExamQuestion(&questions[i], 1)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = append(snapshot, examQuestion)
+	}
+
+	questionsJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode questions data: %w", err)
+	}
+
+	if durationMinutes <= 0 {
+		durationMinutes = (len(questions)*practiceSecondsPerQuestion + 59) / 60
+	}
+
+	now := time.Now()
+	return &models.Exam{
+		Title:           fmt.Sprintf("Practice - %s", now.Format("2 Jan 2006 15:04")),
+		Slug:            fmt.Sprintf("practice-%d-%d", userID, now.UnixNano()),
+		ExamType:        models.ExamTypePractice,
+		TotalQuestions:  len(snapshot),
+		DurationMinutes: durationMinutes,
+		TotalMarks:      float64(len(snapshot)),
+		MaxAttempts:     1,
+		Mode:            mode,
+		QuestionsData:   string(questionsJSON),
+		Status:          models.ExamStatusActive,
+		IsActive:        true,
+		CreatedBy:       &userID,
+	}, nil
+}
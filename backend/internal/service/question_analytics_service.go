@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QuestionAnalyticsService turns the AnswersData of finished attempts into UserQuestionAnswer rows
+type QuestionAnalyticsService interface {
+	Start(ctx context.Context) error
+	Stop() error
+	ProcessPendingAttempts() (int, error)
+	Backfill(fromAttemptID uint, reprocess bool, progress func(lastAttemptID uint, processed int)) (int, error)
+}
+
+// questionAnalyticsService implements QuestionAnalyticsService
+type questionAnalyticsService struct {
+	repo            repository.QuestionAnswerRepository
+	reviewService   ReviewService
+	processInterval time.Duration
+	batchSize       int
+	stopChan        chan struct{}
+	stopped         bool
+}
+
+// QuestionAnalyticsConfig holds configuration for the question analytics worker
+type QuestionAnalyticsConfig struct {
+	ProcessInterval time.Duration // How often to look for unprocessed attempts (default: 1 minute)
+	BatchSize       int           // Attempts loaded per query (default: 100)
+}
+
+// NewQuestionAnalyticsService creates a new question analytics service
+func NewQuestionAnalyticsService(repo repository.QuestionAnswerRepository, reviewService ReviewService, config QuestionAnalyticsConfig) QuestionAnalyticsService {
+	// Set default values if not provided
+	if config.ProcessInterval == 0 {
+		config.ProcessInterval = 1 * time.Minute
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
+	}
+
+	return &questionAnalyticsService{
+		repo:            repo,
+		reviewService:   reviewService,
+		processInterval: config.ProcessInterval,
+		batchSize:       config.BatchSize,
+		stopChan:        make(chan struct{}),
+		stopped:         false,
+	}
+}
+
+// Start processes unprocessed attempts periodically until the context is cancelled or Stop is called
+func (s *questionAnalyticsService) Start(ctx context.Context) error {
+	log := logger.WithService("QuestionAnalyticsService").WithFields(logrus.Fields{
+		"operation":        "Start",
+		"process_interval": s.processInterval.String(),
+		"batch_size":       s.batchSize,
+	})
+
+	log.Info("Starting question analytics background service")
+
+	ticker := time.NewTicker(s.processInterval)
+	defer ticker.Stop()
+
+	// Catch up on anything finished while the service was down
+	s.runOnce()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Context cancelled, stopping question analytics service")
+			return ctx.Err()
+		case <-s.stopChan:
+			log.Info("Stop signal received, stopping question analytics service")
+			return nil
+		case <-ticker.C:
+			s.runOnce()
+		}
+	}
+}
+
+// Stop stops the question analytics service
+func (s *questionAnalyticsService) Stop() error {
+	if s.stopped {
+		return nil
+	}
+
+	log := logger.WithService("QuestionAnalyticsService").WithField("operation", "Stop")
+	log.Info("Stopping question analytics service")
+
+	s.stopped = true
+	close(s.stopChan)
+	return nil
+}
+
+// runOnce processes pending attempts and logs the outcome
+func (s *questionAnalyticsService) runOnce() {
+	startTime := time.Now()
+	log := logger.WithService("QuestionAnalyticsService").WithField("operation", "RunOnce")
+
+	processed, err := s.ProcessPendingAttempts()
+	if err != nil {
+		log.WithError(err).Error("Failed to process pending attempts")
+		return
+	}
+
+	if processed > 0 {
+		log.WithFields(logrus.Fields{
+			"processed_attempts": processed,
+			"duration_ms":        time.Since(startTime).Milliseconds(),
+		}).Info("Processed question analytics for finished attempts")
+	}
+}
+
+// ProcessPendingAttempts processes every finished attempt whose analytics rows have not been built yet,
+// scanning from the oldest attempt that may still need them. Attempts that fail are recorded and picked up
+// again on the next run, until they have failed MaxAnalyticsFailures times.
+func (s *questionAnalyticsService) ProcessPendingAttempts() (int, error) {
+	afterID, err := s.repo.GetAnalyticsScanStart()
+	if err != nil {
+		return 0, err
+	}
+	return s.processFrom(afterID, false, nil)
+}
+
+// Backfill processes finished attempts with an ID above fromAttemptID. With reprocess set, attempts that were
+// already processed are rebuilt as well. Progress is reported after every batch so an interrupted
+// backfill can be resumed from the last attempt ID.
+func (s *questionAnalyticsService) Backfill(fromAttemptID uint, reprocess bool, progress func(lastAttemptID uint, processed int)) (int, error) {
+	return s.processFrom(fromAttemptID, reprocess, progress)
+}
+
+// processFrom walks finished attempts in ID order, batch by batch, building their analytics rows
+func (s *questionAnalyticsService) processFrom(afterID uint, includeProcessed bool, progress func(lastAttemptID uint, processed int)) (int, error) {
+	log := logger.WithService("QuestionAnalyticsService").WithField("operation", "ProcessAttempts")
+
+	processed := 0
+	for {
+		attempts, err := s.repo.GetAttemptsForAnalytics(afterID, s.batchSize, includeProcessed)
+		if err != nil {
+			return processed, err
+		}
+
+		for _, attempt := range attempts {
+			afterID = attempt.ID
+
+			if err := s.processAttempt(attempt); err != nil {
+				failures := attempt.AnalyticsFailures + 1
+				attemptLog := log.WithError(err).WithFields(logrus.Fields{
+					"attempt_id": attempt.ID,
+					"failures":   failures,
+				})
+				if failures >= models.MaxAnalyticsFailures {
+					attemptLog.Error("Failed to build question analytics for attempt, giving up on it")
+				} else {
+					attemptLog.Error("Failed to build question analytics for attempt")
+				}
+
+				if recordErr := s.repo.RecordAnalyticsFailure(attempt.ID, err.Error()); recordErr != nil {
+					log.WithError(recordErr).WithField("attempt_id", attempt.ID).Warn("Failed to record analytics failure")
+				}
+				continue
+			}
+			processed++
+		}
+
+		if progress != nil && len(attempts) > 0 {
+			progress(afterID, processed)
+		}
+
+		if len(attempts) < s.batchSize {
+			return processed, nil
+		}
+	}
+}
+
+// processAttempt replaces the analytics rows of one attempt and updates the user's review schedules
+func (s *questionAnalyticsService) processAttempt(attempt models.UserExamAttempt) error {
+	answers, err := buildQuestionAnswers(attempt)
+	if err != nil {
+		return err
+	}
+	if err := s.fillMissingTopics(answers); err != nil {
+		return err
+	}
+
+	// Schedules go first so a failure leaves the attempt unprocessed; answers already applied are skipped on the retry
+	if err := s.reviewService.RecordAnswers(answers); err != nil {
+		return err
+	}
+	return s.repo.ReplaceQuestionAnswers(attempt.ID, answers)
+}
+
+// fillMissingTopics looks up the system and subject of questions from exams built before
+// snapshots recorded them. Questions removed from the bank for good stay unclassified.
+func (s *questionAnalyticsService) fillMissingTopics(answers []models.UserQuestionAnswer) error {
+	missing := make([]uint, 0)
+	for _, answer := range answers {
+		if answer.SystemID == 0 {
+			missing = append(missing, answer.QuestionID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	topics, err := s.repo.GetQuestionTopics(missing)
+	if err != nil {
+		return err
+	}
+	for i := range answers {
+		if topic, ok := topics[answers[i].QuestionID]; ok && answers[i].SystemID == 0 {
+			answers[i].SystemID = topic.SystemID
+			answers[i].SubjectID = topic.SubjectID
+		}
+	}
+	return nil
+}
+
+// buildQuestionAnswers converts an attempt's AnswersData into one UserQuestionAnswer per question.
+// Difficulty, system and subject come from the exam's question snapshot since AnswersData does not
+// store them. Time, answer time and changes come from the session's tracking; attempts submitted before
+// tracking split the attempt's time evenly across questions and use the completion time.
+func buildQuestionAnswers(attempt models.UserExamAttempt) ([]models.UserQuestionAnswer, error) {
+	if attempt.AnswersData == "" || attempt.AnswersData == "{}" {
+		return nil, nil
+	}
+
+	var answersData answersDataStructure
+	if err := json.Unmarshal([]byte(attempt.AnswersData), &answersData); err != nil {
+		return nil, fmt.Errorf("failed to parse answers data: %w", err)
+	}
+
+	snapshot := make(map[uint]models.ExamQuestion)
+	var examQuestions []models.ExamQuestion
+	if err := json.Unmarshal([]byte(attempt.Exam.QuestionsData), &examQuestions); err == nil {
+		for _, question := range examQuestions {
+			snapshot[question.ID] = question
+		}
+	}
+
+	timePerQuestion := 0
+	if len(answersData.Answers) > 0 {
+		timePerQuestion = attempt.ActualTimeSpent / len(answersData.Answers)
+	}
+
+	answeredAt := attempt.StartedAt
+	if attempt.CompletedAt != nil {
+		answeredAt = *attempt.CompletedAt
+	}
+
+	answers := make([]models.UserQuestionAnswer, 0, len(answersData.Answers))
+	for index, detail := range answersData.Answers {
+		selectedOptions := detail.UserAnswer
+		if selectedOptions == nil {
+			selectedOptions = []string{}
+		}
+		selectedJSON, err := json.Marshal(selectedOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode selected options: %w", err)
+		}
+
+		correctJSON, err := json.Marshal(correctOptions(detail))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode correct options: %w", err)
+		}
+
+		timeSpent := timePerQuestion
+		if detail.TimeSpentSeconds > 0 {
+			timeSpent = detail.TimeSpentSeconds
+		}
+		questionAnsweredAt := answeredAt
+		if detail.AnsweredAt != "" {
+			if parsed, err := time.Parse(time.RFC3339, detail.AnsweredAt); err == nil {
+				questionAnsweredAt = parsed
+			}
+		}
+
+		answers = append(answers, models.UserQuestionAnswer{
+			AttemptID:          attempt.ID,
+			UserID:             attempt.UserID,
+			ExamID:             attempt.ExamID,
+			QuestionID:         detail.QuestionID,
+			QuestionType:       models.QuestionType(detail.QuestionType),
+			QuestionText:       detail.QuestionText,
+			DifficultyLevel:    snapshot[detail.QuestionID].DifficultyLevel,
+			QuestionIndex:      index,
+			SystemID:           snapshot[detail.QuestionID].SystemID,
+			SubjectID:          snapshot[detail.QuestionID].SubjectID,
+			SelectedOptions:    string(selectedJSON),
+			CorrectOptions:     string(correctJSON),
+			IsCorrect:          detail.IsCorrect,
+			PartialScore:       detail.PointsEarned,
+			MaxScore:           float64(detail.MaxPoints),
+			TimeSpent:          timeSpent,
+			AnsweredAt:         questionAnsweredAt,
+			IsSkipped:          len(detail.UserAnswer) == 0,
+			ChangeCount:        detail.ChangeCount,
+			ChangedFromCorrect: detail.ChangedFromCorrect,
+			IsLastAnswer:       true,
+		})
+	}
+
+	return answers, nil
+}
+
+// correctOptions returns the correct answer in the same form as the user's answer:
+// ["b"] for SBA and ["a:true", "b:false", ...] for TRUE_FALSE
+func correctOptions(detail answerDetail) []string {
+	options := make([]string, 0, len(detail.Options))
+	for _, option := range detail.Options {
+		switch models.QuestionType(detail.QuestionType) {
+		case models.QuestionTypeTrueFalse:
+			options = append(options, fmt.Sprintf("%s:%t", option.Key, option.IsCorrect))
+		default:
+			if option.IsCorrect {
+				options = append(options, option.Key)
+			}
+		}
+	}
+	return options
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+const (
+	defaultQuestionPageSize = 20
+	maxQuestionPageSize     = 100
+	minQuestionOptions      = 2
+)
+
+// QuestionService handles business logic for the admin question bank
+type QuestionService interface {
+	CreateQuestion(req dto.QuestionRequest, createdBy uint) (*dto.QuestionResponse, error)
+	UpdateQuestion(id uint, req dto.QuestionRequest) (*dto.QuestionResponse, error)
+	DeleteQuestion(id uint) error
+	GetQuestion(id uint) (*dto.QuestionResponse, error)
+	ListQuestions(req dto.QuestionListRequest) (*dto.QuestionListResponse, error)
+	GetTaxonomy() (*dto.TaxonomyResponse, error)
+}
+
+// questionService implements QuestionService
+type questionService struct {
+	repo   repository.QuestionRepository
+	mapper *mapper.QuestionMapper
+}
+
+// NewQuestionService creates a new question service
+func NewQuestionService(repo repository.QuestionRepository, mapper *mapper.QuestionMapper) QuestionService {
+	return &questionService{
+		repo:   repo,
+		mapper: mapper,
+	}
+}
+
+// CreateQuestion validates and stores a new question in the bank
+func (s *questionService) CreateQuestion(req dto.QuestionRequest, createdBy uint) (*dto.QuestionResponse, error) {
+	question := &models.Question{IsActive: true}
+	if err := s.applyRequest(question, req); err != nil {
+		return nil, err
+	}
+	question.CreatedBy = &createdBy
+
+	if err := s.repo.CreateQuestion(question); err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	return s.GetQuestion(question.ID)
+}
+
+// UpdateQuestion validates and replaces the editable fields of a question
+func (s *questionService) UpdateQuestion(id uint, req dto.QuestionRequest) (*dto.QuestionResponse, error) {
+	question, err := s.repo.GetQuestionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(question, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQuestion(question); err != nil {
+		return nil, fmt.Errorf("failed to update question: %w", err)
+	}
+
+	return s.GetQuestion(id)
+}
+
+// DeleteQuestion soft deletes a question
+func (s *questionService) DeleteQuestion(id uint) error {
+	return s.repo.DeleteQuestion(id)
+}
+
+// GetQuestion retrieves a single question with its taxonomy
+func (s *questionService) GetQuestion(id uint) (*dto.QuestionResponse, error) {
+	question, err := s.repo.GetQuestionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapper.ToQuestionResponse(question), nil
+}
+
+// ListQuestions retrieves a filtered, paginated list of questions
+func (s *questionService) ListQuestions(req dto.QuestionListRequest) (*dto.QuestionListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultQuestionPageSize
+	}
+	if limit > maxQuestionPageSize {
+		limit = maxQuestionPageSize
+	}
+
+	filters := repository.QuestionFilters{
+		SubjectID:       req.SubjectID,
+		SystemID:        req.SystemID,
+		QuestionType:    models.QuestionType(req.QuestionType),
+		DifficultyLevel: models.DifficultyLevel(req.DifficultyLevel),
+		Tags:            normalizeTags(strings.Split(req.Tags, ",")),
+		UsageLevel:      req.UsageLevel,
+		IsActive:        req.IsActive,
+		Search:          strings.TrimSpace(req.Search),
+		Offset:          (page - 1) * limit,
+		Limit:           limit,
+	}
+
+	questions, total, err := s.repo.ListQuestions(filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list questions: %w", err)
+	}
+
+	return s.mapper.ToQuestionListResponse(questions, total, page, limit), nil
+}
+
+// GetTaxonomy retrieves subjects with their systems and question counts
+func (s *questionService) GetTaxonomy() (*dto.TaxonomyResponse, error) {
+	subjects, err := s.repo.GetSubjectsWithSystems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %w", err)
+	}
+
+	counts, err := s.repo.GetQuestionCountsBySystem()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count questions: %w", err)
+	}
+
+	return s.mapper.ToTaxonomyResponse(subjects, counts), nil
+}
+
+// applyRequest validates the request and copies it onto the question model
+func (s *questionService) applyRequest(question *models.Question, req dto.QuestionRequest) error {
+	if strings.TrimSpace(req.QuestionText) == "" {
+		return errors.NewQuestionValidationError("question_text", "question text is required")
+	}
+
+	if err := ValidateQuestionOptions(req.QuestionType, req.Options); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetSystemByID(req.SystemID); err != nil {
+		return err
+	}
+
+	options := make(map[string]dto.QuestionOptionResponse, len(req.Options))
+	for key, option := range req.Options {
+		options[key] = dto.QuestionOptionResponse{
+			Text:      strings.TrimSpace(option.Text),
+			IsCorrect: option.IsCorrect != nil && *option.IsCorrect,
+			Weight:    option.Weight,
+		}
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to encode options: %w", err)
+	}
+
+	tagsJSON, err := json.Marshal(normalizeTags(req.Tags))
+	if err != nil {
+		return fmt.Errorf("failed to encode tags: %w", err)
+	}
+
+	difficulty := req.DifficultyLevel
+	if difficulty == "" {
+		difficulty = models.DifficultyMedium
+	}
+
+	question.SystemID = req.SystemID
+	question.QuestionText = strings.TrimSpace(req.QuestionText)
+	question.QuestionType = req.QuestionType
+	question.DifficultyLevel = difficulty
+	question.Options = string(optionsJSON)
+	question.Explanation = req.Explanation
+	question.Reference = req.Reference
+	question.Tags = string(tagsJSON)
+	if req.IsActive != nil {
+		question.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// ValidateQuestionOptions checks that the options are consistent with the question type.
+// SBA questions need exactly one correct option, TRUE_FALSE questions need an explicit
+// true/false flag on every stem.
+func ValidateQuestionOptions(questionType models.QuestionType, options map[string]dto.QuestionOptionInput) error {
+	if len(options) < minQuestionOptions {
+		return errors.NewQuestionValidationError("options", fmt.Sprintf("at least %d options are required", minQuestionOptions))
+	}
+
+	correctCount := 0
+	for key, option := range options {
+		if len(key) != 1 || key[0] < 'a' || key[0] > 'z' {
+			return errors.NewQuestionValidationError("options", fmt.Sprintf("option key %q must be a single lowercase letter", key))
+		}
+		if strings.TrimSpace(option.Text) == "" {
+			return errors.NewQuestionValidationError("options", fmt.Sprintf("option %q must have text", key))
+		}
+		if option.Weight != nil && (*option.Weight < 0 || *option.Weight > 1) {
+			return errors.NewQuestionValidationError("options", fmt.Sprintf("option %q weight must be between 0 and 1", key))
+		}
+
+		switch questionType {
+		case models.QuestionTypeTrueFalse:
+			if option.IsCorrect == nil {
+				return errors.NewQuestionValidationError("options", fmt.Sprintf("option %q must be marked true or false", key))
+			}
+		case models.QuestionTypeSBA:
+			if option.IsCorrect != nil && *option.IsCorrect {
+				correctCount++
+			}
+		default:
+			return errors.NewQuestionValidationError("question_type", fmt.Sprintf("unsupported question type %q", questionType))
+		}
+	}
+
+	if questionType == models.QuestionTypeSBA && correctCount != 1 {
+		return errors.NewQuestionValidationError("options", fmt.Sprintf("SBA questions must have exactly one correct option, got %d", correctCount))
+	}
+
+	return nil
+}
+
+// normalizeTags trims and de-duplicates tags while keeping their order
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"math"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/logger"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RefundService lets admins refund enrollment payments and cancel enrollments
+type RefundService interface {
+	RefundEnrollment(ctx context.Context, adminID, enrollmentID uint, req dto.RefundRequest) (*dto.RefundResponse, error)
+	ReconcileRefunds(ctx context.Context) (*dto.RefundReconciliationResponse, error)
+	CancelEnrollment(ctx context.Context, adminID, enrollmentID uint, req dto.CancelEnrollmentRequest) (*dto.EnrollmentResponse, error)
+}
+
+// refundReconcileBatchSize is the number of pending refunds checked with their gateways per reconciliation
+const refundReconcileBatchSize = 100
+
+// refundService implements RefundService
+type refundService struct {
+	paymentRepo    repository.PaymentRepository
+	enrollmentRepo repository.EnrollmentRepository
+	providers      *payment.Registry
+	mapper         *mapper.EnrollmentMapper
+}
+
+// NewRefundService creates a new refund service. The providers must be the ones payments were taken with.
+func NewRefundService(
+	paymentRepo repository.PaymentRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	providers *payment.Registry,
+	mapper *mapper.EnrollmentMapper,
+) RefundService {
+	return &refundService{
+		paymentRepo:    paymentRepo,
+		enrollmentRepo: enrollmentRepo,
+		providers:      providers,
+		mapper:         mapper,
+	}
+}
+
+// RefundEnrollment returns all or part of a paid transaction of the enrollment through its gateway. The
+// refund is reserved before the gateway is called, so concurrent refunds cannot return more than was paid.
+// Once the gateway has processed it, a refund of everything paid cancels the enrollment, a partial refund
+// only when asked to. Refunding a renewal's payment takes only that renewal's period off, the enrollment
+// stays active. Refunds the gateway is still processing, or whose answer was lost, stay PENDING until
+// ReconcileRefunds finds out what became of them.
+func (s *refundService) RefundEnrollment(ctx context.Context, adminID, enrollmentID uint, req dto.RefundRequest) (*dto.RefundResponse, error) {
+	log := logger.WithService("RefundService").WithFields(logrus.Fields{
+		"operation":     "RefundEnrollment",
+		"admin_id":      adminID,
+		"enrollment_id": enrollmentID,
+	})
+
+	enrollment, err := s.enrollmentRepo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.refundableTransaction(enrollment, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	log = log.WithField("transaction_id", transaction.TransactionID)
+
+	provider, err := s.providers.Get(transaction.Provider)
+	if err != nil {
+		return nil, errors.NewPaymentProviderUnavailableError(transaction.Provider)
+	}
+
+	refund := &models.PaymentRefund{
+		TransactionID:    transaction.TransactionID,
+		EnrollmentID:     enrollment.ID,
+		UserID:           transaction.UserID,
+		Provider:         provider.Name(),
+		Currency:         transaction.Currency,
+		CancelEnrollment: req.CancelEnrollment,
+		Reason:           req.Reason,
+		RefundedBy:       adminID,
+	}
+	refundable, err := s.paymentRepo.ReserveRefund(refund, req.Amount)
+	if err == repository.ErrInvalidRefundAmount {
+		if refundable <= 0 {
+			return nil, errors.NewRefundNotAllowedError(enrollment.ID, "payment has already been refunded in full")
+		}
+		amount := refundable
+		if req.Amount != nil {
+			amount = roundAmount(*req.Amount)
+		}
+		return nil, errors.NewInvalidRefundAmountError(amount, refundable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Refund(ctx, refundRequest(transaction, refund))
+	switch {
+	case stderrors.Is(err, payment.ErrRefundDeclined):
+		log.WithError(err).Error("Gateway rejected refund")
+		if releaseErr := s.paymentRepo.ReleaseRefund(refund.ID); releaseErr != nil {
+			log.WithError(releaseErr).WithField("refund_id", refund.ID).Error("Failed to release refund reservation")
+		}
+		return nil, errors.NewPaymentGatewayError(provider.Name(), "refund", err)
+	case err != nil:
+		// The gateway may have made the refund without us hearing back, so the reservation is kept
+		log.WithError(err).WithField("refund_id", refund.ID).Warn("Refund outcome unknown - kept pending until reconciled")
+		refund.Status = models.PaymentRefundPending
+		gatewayData := err.Error()
+		refund.GatewayData = &gatewayData
+	default:
+		refund.Status = refundStatus(result.Status)
+		if result.RefundReference != "" {
+			refund.RefundReference = &result.RefundReference
+		}
+		if result.Raw != "" {
+			refund.GatewayData = &result.Raw
+		}
+	}
+
+	// The money may already have left at the gateway. The reservation stays held, so it is not refunded twice,
+	// and the reference is logged so the refund can be recorded by hand.
+	if _, err := s.paymentRepo.SettleRefund(refund); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"refund_id":        refund.ID,
+			"refund_reference": refund.RefundReference,
+		}).Error("Failed to record refund made at gateway")
+		return nil, err
+	}
+
+	outcome := refundOutcome{}
+	if refund.Status == models.PaymentRefundProcessed {
+		if outcome, err = s.applyRefund(refund, transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	remaining := roundAmount(refundable - refund.Amount)
+	log.WithFields(logrus.Fields{
+		"amount":           refund.Amount,
+		"status":           refund.Status,
+		"remaining":        remaining,
+		"cancelled":        outcome.Cancelled,
+		"renewal_refunded": outcome.RenewalRefunded,
+	}).Info("Enrollment refunded")
+
+	enrollment, err = s.enrollmentRepo.GetEnrollmentByID(enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RefundResponse{
+		ID:                  refund.ID,
+		TransactionID:       refund.TransactionID,
+		EnrollmentID:        refund.EnrollmentID,
+		Provider:            refund.Provider,
+		RefundReference:     refund.RefundReference,
+		Amount:              refund.Amount,
+		Currency:            refund.Currency,
+		Status:              string(refund.Status),
+		Reason:              refund.Reason,
+		RefundedBy:          refund.RefundedBy,
+		RemainingRefundable: remaining,
+		EnrollmentCancelled: outcome.Cancelled,
+		RenewalID:           transaction.RenewalID,
+		RenewalRefunded:     outcome.RenewalRefunded,
+		Enrollment:          s.mapper.ToEnrollmentResponse(enrollment),
+		CreatedAt:           refund.CreatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// ReconcileRefunds asks the gateways what became of pending refunds. Processed refunds cancel the enrollment or
+// take the renewal off as RefundEnrollment would have; refunds that fell through, or that the gateway never
+// made, are FAILED so their amount can be refunded again. Refunds the gateway cannot answer for stay pending.
+func (s *refundService) ReconcileRefunds(ctx context.Context) (*dto.RefundReconciliationResponse, error) {
+	log := logger.WithService("RefundService").WithField("operation", "ReconcileRefunds")
+
+	refunds, err := s.paymentRepo.GetPendingRefunds(refundReconcileBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RefundReconciliationResponse{Checked: len(refunds)}
+	for i := range refunds {
+		refund := &refunds[i]
+		refundLog := log.WithFields(logrus.Fields{
+			"refund_id":      refund.ID,
+			"transaction_id": refund.TransactionID,
+		})
+
+		status, err := s.reconcileRefund(ctx, refund)
+		if err != nil {
+			refundLog.WithError(err).Warn("Failed to reconcile refund - still pending")
+		}
+		switch status {
+		case models.PaymentRefundProcessed:
+			response.Processed++
+		case models.PaymentRefundFailed:
+			refundLog.Warn("Refund fell through at the gateway - amount can be refunded again")
+			response.Failed++
+		default:
+			response.Pending++
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"checked":   response.Checked,
+		"processed": response.Processed,
+		"failed":    response.Failed,
+	}).Info("Refunds reconciled")
+	return response, nil
+}
+
+// reconcileRefund checks one pending refund with its gateway and returns the refund's status afterwards
+func (s *refundService) reconcileRefund(ctx context.Context, refund *models.PaymentRefund) (models.PaymentRefundStatus, error) {
+	transaction, err := s.paymentRepo.GetTransaction(refund.TransactionID)
+	if err != nil {
+		return refund.Status, err
+	}
+	provider, err := s.providers.Get(refund.Provider)
+	if err != nil {
+		return refund.Status, errors.NewPaymentProviderUnavailableError(refund.Provider)
+	}
+
+	reference := ""
+	if refund.RefundReference != nil {
+		reference = *refund.RefundReference
+	}
+	result, err := provider.RefundStatus(ctx, refundRequest(transaction, refund), reference)
+	switch {
+	case stderrors.Is(err, payment.ErrRefundNotFound):
+		refund.Status = models.PaymentRefundFailed
+	case err != nil:
+		return refund.Status, err
+	default:
+		refund.Status = refundStatus(result.Status)
+		if refund.Status == models.PaymentRefundPending {
+			return refund.Status, nil
+		}
+		if result.RefundReference != "" {
+			refund.RefundReference = &result.RefundReference
+		}
+		if result.Raw != "" {
+			refund.GatewayData = &result.Raw
+		}
+	}
+
+	// Another reconciliation may have settled the refund first, only one of them acts on it
+	settled, err := s.paymentRepo.SettleRefund(refund)
+	if err != nil || !settled {
+		return models.PaymentRefundPending, err
+	}
+	if refund.Status == models.PaymentRefundProcessed {
+		if _, err := s.applyRefund(refund, transaction); err != nil {
+			return refund.Status, err
+		}
+	}
+	return refund.Status, nil
+}
+
+// refundOutcome is what a processed refund changed about the enrollment
+type refundOutcome struct {
+	Cancelled       bool
+	RenewalRefunded bool
+}
+
+// applyRefund ends what a processed refund paid for. Once everything paid is refunded, or when the admin asked
+// for it, a renewal's period is taken off or the enrollment is cancelled. A duplicate payment bought nothing,
+// so refunding it changes nothing.
+func (s *refundService) applyRefund(refund *models.PaymentRefund, transaction *models.PaymentTransaction) (refundOutcome, error) {
+	var outcome refundOutcome
+	if transaction.Duplicate {
+		return outcome, nil
+	}
+
+	totals, err := s.paymentRepo.GetRefundTotals(transaction.TransactionID)
+	if err != nil {
+		return outcome, err
+	}
+	fullyRefunded := roundAmount(transaction.Amount-totals.Processed) <= 0
+	if !fullyRefunded && !refund.CancelEnrollment {
+		return outcome, nil
+	}
+
+	if transaction.RenewalID != nil {
+		// Only the renewal's period was paid for by this transaction
+		err := s.enrollmentRepo.RefundRenewal(*transaction.RenewalID)
+		if err != nil && err != repository.ErrRenewalNotCompleted {
+			return outcome, err
+		}
+		outcome.RenewalRefunded = err == nil
+		return outcome, nil
+	}
+
+	err = s.enrollmentRepo.CancelEnrollment(refund.EnrollmentID, repository.EnrollmentCancellation{
+		CancelledBy: refund.RefundedBy,
+		Reason:      refund.Reason,
+		Refunded:    fullyRefunded,
+	})
+	if err != nil && err != repository.ErrEnrollmentNotActive {
+		return outcome, err
+	}
+	outcome.Cancelled = err == nil
+	return outcome, nil
+}
+
+// CancelEnrollment deactivates an enrollment without returning any money
+func (s *refundService) CancelEnrollment(ctx context.Context, adminID, enrollmentID uint, req dto.CancelEnrollmentRequest) (*dto.EnrollmentResponse, error) {
+	err := s.enrollmentRepo.CancelEnrollment(enrollmentID, repository.EnrollmentCancellation{
+		CancelledBy: adminID,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithService("RefundService").WithFields(logrus.Fields{
+		"operation":     "CancelEnrollment",
+		"admin_id":      adminID,
+		"enrollment_id": enrollmentID,
+	}).Info("Enrollment cancelled")
+
+	enrollment, err := s.enrollmentRepo.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapper.ToEnrollmentResponse(enrollment), nil
+}
+
+// refundableTransaction returns the requested paid transaction of the enrollment, or its latest paid one
+func (s *refundService) refundableTransaction(enrollment *models.UserPackageEnrollment, transactionID string) (*models.PaymentTransaction, error) {
+	if transactionID == "" {
+		transaction, err := s.paymentRepo.GetLatestPaidTransaction(enrollment.ID)
+		if err == repository.ErrPaymentTransactionNotFound {
+			return nil, errors.NewRefundNotAllowedError(enrollment.ID, "enrollment has no paid payment")
+		}
+		return transaction, err
+	}
+
+	transaction, err := s.paymentRepo.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.EnrollmentID != enrollment.ID {
+		return nil, repository.ErrPaymentTransactionNotFound
+	}
+	if transaction.Status != models.PaymentTransactionPaid {
+		return nil, errors.NewRefundNotAllowedError(enrollment.ID, "payment is "+string(transaction.Status))
+	}
+	return transaction, nil
+}
+
+// refundRequest describes a refund of the transaction to its gateway
+func refundRequest(transaction *models.PaymentTransaction, refund *models.PaymentRefund) payment.RefundRequest {
+	req := payment.RefundRequest{
+		TransactionID: transaction.TransactionID,
+		Amount:        refund.Amount,
+		Reason:        refund.Reason,
+	}
+	if transaction.ProviderReference != nil {
+		req.ProviderReference = *transaction.ProviderReference
+	}
+	if transaction.ProviderTransactionID != nil {
+		req.ProviderTransactionID = *transaction.ProviderTransactionID
+	}
+	return req
+}
+
+// refundStatus maps a gateway's refund status to the stored one
+func refundStatus(status payment.Status) models.PaymentRefundStatus {
+	switch status {
+	case payment.StatusPaid:
+		return models.PaymentRefundProcessed
+	case payment.StatusFailed, payment.StatusCancelled:
+		return models.PaymentRefundFailed
+	default:
+		return models.PaymentRefundPending
+	}
+}
+
+// roundAmount rounds a money amount to whole paisa, so repeated partial refunds add up exactly
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
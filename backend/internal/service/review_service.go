@@ -0,0 +1,160 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+const (
+	// defaultReviewQueueLimit is how many due questions are listed when no limit is given
+	defaultReviewQueueLimit = 50
+	// maxReviewQueueLimit caps the number of due questions listed
+	maxReviewQueueLimit = 200
+)
+
+// Review grades (SM-2 quality, 0-5) given to an answer
+const (
+	reviewQualitySkipped = 0
+	reviewQualityWrong   = 1
+	reviewQualityPartial = 2
+	reviewQualityCorrect = 4
+)
+
+// ReviewService keeps students' spaced-repetition schedules and lists the questions due for review
+type ReviewService interface {
+	RecordAnswers(answers []models.UserQuestionAnswer) error
+	GetDueQueue(userID uint, limit int) (*dto.ReviewQueueResponse, error)
+}
+
+// reviewService implements ReviewService
+type reviewService struct {
+	repo repository.ReviewScheduleRepository
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(repo repository.ReviewScheduleRepository) ReviewService {
+	return &reviewService{
+		repo: repo,
+	}
+}
+
+// RecordAnswers updates review schedules with answers from a finished exam or practice session.
+// Missed questions start a schedule; questions already scheduled are graded whether they were missed or not.
+// Answers at or before a schedule's last review are ignored, so processing an attempt again changes nothing.
+func (s *reviewService) RecordAnswers(answers []models.UserQuestionAnswer) error {
+	byUser := make(map[uint][]models.UserQuestionAnswer)
+	userIDs := make([]uint, 0)
+	for _, answer := range answers {
+		if _, ok := byUser[answer.UserID]; !ok {
+			userIDs = append(userIDs, answer.UserID)
+		}
+		byUser[answer.UserID] = append(byUser[answer.UserID], answer)
+	}
+
+	for _, userID := range userIDs {
+		userAnswers := byUser[userID]
+
+		questionIDs := make([]uint, 0, len(userAnswers))
+		for _, answer := range userAnswers {
+			questionIDs = append(questionIDs, answer.QuestionID)
+		}
+
+		existing, err := s.repo.GetSchedules(userID, questionIDs)
+		if err != nil {
+			return err
+		}
+
+		updated := make([]models.QuestionReviewSchedule, 0, len(userAnswers))
+		for _, answer := range userAnswers {
+			schedule, ok := existing[answer.QuestionID]
+			if !ok {
+				// Only missed questions enter the review queue
+				if answer.IsCorrect {
+					continue
+				}
+				schedule = models.NewQuestionReviewSchedule(userID, answer.QuestionID)
+			} else if !answer.AnsweredAt.After(schedule.LastReviewedAt) {
+				continue
+			}
+
+			if answer.SystemID != 0 {
+				schedule.SystemID = answer.SystemID
+				schedule.SubjectID = answer.SubjectID
+			}
+			schedule.LastAttemptID = answer.AttemptID
+			schedule.ApplyReview(reviewQuality(answer), answer.AnsweredAt)
+			updated = append(updated, schedule)
+		}
+
+		if err := s.repo.SaveSchedules(updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDueQueue returns the questions due for review by the end of today, most overdue first
+func (s *reviewService) GetDueQueue(userID uint, limit int) (*dto.ReviewQueueResponse, error) {
+	if limit <= 0 {
+		limit = defaultReviewQueueLimit
+	}
+	if limit > maxReviewQueueLimit {
+		limit = maxReviewQueueLimit
+	}
+
+	dueBy := endOfDay(time.Now())
+	dueCount, err := s.repo.CountDueSchedules(userID, dueBy)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.GetDueSchedules(userID, dueBy, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReviewQueueResponse{
+		DueCount: dueCount,
+		DueBy:    dueBy.Format(time.RFC3339),
+		Items:    make([]dto.ReviewQueueItem, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		response.Items = append(response.Items, dto.ReviewQueueItem{
+			QuestionID:     schedule.QuestionID,
+			QuestionText:   schedule.Question.QuestionText,
+			QuestionType:   string(schedule.Question.QuestionType),
+			SystemID:       schedule.SystemID,
+			SubjectID:      schedule.SubjectID,
+			DueAt:          schedule.DueAt.Format(time.RFC3339),
+			IntervalDays:   schedule.IntervalDays,
+			EaseFactor:     schedule.EaseFactor,
+			Repetitions:    schedule.Repetitions,
+			Lapses:         schedule.Lapses,
+			LastReviewedAt: schedule.LastReviewedAt.Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
+
+// reviewQuality grades an answer for SM-2: correct answers pass, partial credit and wrong answers fail
+func reviewQuality(answer models.UserQuestionAnswer) int {
+	switch {
+	case answer.IsSkipped:
+		return reviewQualitySkipped
+	case answer.IsCorrect:
+		return reviewQualityCorrect
+	case answer.PartialScore > 0:
+		return reviewQualityPartial
+	default:
+		return reviewQualityWrong
+	}
+}
+
+// endOfDay returns the last second of the day t falls on
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+}
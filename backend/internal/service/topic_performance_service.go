@@ -0,0 +1,211 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+)
+
+const (
+	// defaultTrendWeeks is how many weeks of trend data are returned when no period is given
+	defaultTrendWeeks = 12
+	// maxTrendWeeks caps the trend period
+	maxTrendWeeks = 52
+	// weakAreaMinQuestions is how many answers a system needs before it can be called a weak area
+	weakAreaMinQuestions = 10
+	// weakAreaLimit is how many weak areas are highlighted
+	weakAreaLimit = 3
+	// trendSteadyMargin is the accuracy change, in percentage points, still treated as steady
+	trendSteadyMargin = 5.0
+	// unclassifiedTopicName names answers to questions without a known subject or system
+	unclassifiedTopicName = "Unclassified"
+)
+
+// Trend directions
+const (
+	TrendImproving = "improving"
+	TrendDeclining = "declining"
+	TrendSteady    = "steady"
+)
+
+// TopicPerformanceService reports a student's strengths and weaknesses by subject and system
+type TopicPerformanceService interface {
+	GetTopicPerformance(userID uint, weeks int) (*dto.TopicPerformanceResponse, error)
+}
+
+// topicPerformanceService implements TopicPerformanceService
+type topicPerformanceService struct {
+	repo repository.TopicPerformanceRepository
+}
+
+// NewTopicPerformanceService creates a new topic performance service
+func NewTopicPerformanceService(repo repository.TopicPerformanceRepository) TopicPerformanceService {
+	return &topicPerformanceService{
+		repo: repo,
+	}
+}
+
+// GetTopicPerformance returns the student's accuracy, attempts and time per subject and system,
+// their weakest systems and weekly subject trends over the given number of weeks
+func (s *topicPerformanceService) GetTopicPerformance(userID uint, weeks int) (*dto.TopicPerformanceResponse, error) {
+	if weeks <= 0 {
+		weeks = defaultTrendWeeks
+	}
+	if weeks > maxTrendWeeks {
+		weeks = maxTrendWeeks
+	}
+
+	subjects, err := s.repo.GetSubjectPerformance(userID)
+	if err != nil {
+		return nil, err
+	}
+	systems, err := s.repo.GetSystemPerformance(userID)
+	if err != nil {
+		return nil, err
+	}
+	trend, err := s.repo.GetSubjectTrend(userID, trendStart(time.Now(), weeks))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TopicPerformanceResponse{
+		Subjects:   make([]dto.TopicPerformanceDTO, 0, len(subjects)),
+		Systems:    make([]dto.TopicPerformanceDTO, 0, len(systems)),
+		TrendWeeks: weeks,
+	}
+
+	subjectNames := make(map[uint]string, len(subjects))
+	for _, subject := range subjects {
+		performance := toTopicPerformanceDTO(subject)
+		subjectNames[performance.ID] = performance.Name
+		response.Subjects = append(response.Subjects, performance)
+	}
+	for _, system := range systems {
+		response.Systems = append(response.Systems, toTopicPerformanceDTO(system))
+	}
+
+	sortWeakestFirst(response.Subjects)
+	sortWeakestFirst(response.Systems)
+	response.WeakestAreas = weakestAreas(response.Systems)
+
+	response.Trends = buildSubjectTrends(trend, subjectNames)
+	return response, nil
+}
+
+// sortWeakestFirst orders topics by lowest accuracy, then by the topic the student has answered most
+func sortWeakestFirst(topics []dto.TopicPerformanceDTO) {
+	sort.SliceStable(topics, func(i, j int) bool {
+		if topics[i].AccuracyRate != topics[j].AccuracyRate {
+			return topics[i].AccuracyRate < topics[j].AccuracyRate
+		}
+		return topics[i].Questions > topics[j].Questions
+	})
+}
+
+// weakestAreas returns the first classified topics with enough answers to judge, from topics sorted weakest first
+func weakestAreas(sorted []dto.TopicPerformanceDTO) []dto.TopicPerformanceDTO {
+	weakest := make([]dto.TopicPerformanceDTO, 0, weakAreaLimit)
+	for _, topic := range sorted {
+		if len(weakest) == weakAreaLimit {
+			break
+		}
+		if topic.ID == 0 || topic.Questions < weakAreaMinQuestions {
+			continue
+		}
+		weakest = append(weakest, topic)
+	}
+	return weakest
+}
+
+// toTopicPerformanceDTO converts aggregated answers to rates rounded to 2 decimal places
+func toTopicPerformanceDTO(topic repository.TopicPerformance) dto.TopicPerformanceDTO {
+	performance := dto.TopicPerformanceDTO{
+		ID:             topic.TopicID,
+		Name:           topic.TopicName,
+		SubjectID:      topic.SubjectID,
+		SubjectName:    topic.SubjectName,
+		Attempts:       topic.Attempts,
+		Questions:      topic.Questions,
+		Answered:       topic.Answered,
+		CorrectAnswers: topic.Correct,
+		AccuracyRate:   percentage(float64(topic.Correct), float64(topic.Questions)),
+		ScoreRate:      percentage(topic.Score, topic.MaxScore),
+	}
+	if topic.TopicID == 0 || performance.Name == "" {
+		performance.Name = unclassifiedTopicName
+	}
+	if topic.Questions > 0 {
+		performance.AverageTimeSpent = roundRate(float64(topic.TotalTimeSpent) / float64(topic.Questions))
+	}
+	return performance
+}
+
+// buildSubjectTrends groups weekly points by subject and compares the first week with the last
+func buildSubjectTrends(points []repository.TopicTrendPoint, subjectNames map[uint]string) []dto.SubjectTrendDTO {
+	trends := make([]dto.SubjectTrendDTO, 0)
+	index := make(map[uint]int)
+
+	for _, point := range points {
+		i, ok := index[point.TopicID]
+		if !ok {
+			name := subjectNames[point.TopicID]
+			if point.TopicID == 0 || name == "" {
+				name = unclassifiedTopicName
+			}
+			trends = append(trends, dto.SubjectTrendDTO{
+				SubjectID:   point.TopicID,
+				SubjectName: name,
+				Points:      make([]dto.TopicTrendPointDTO, 0),
+			})
+			i = len(trends) - 1
+			index[point.TopicID] = i
+		}
+		trends[i].Points = append(trends[i].Points, dto.TopicTrendPointDTO{
+			WeekStart:    point.WeekStart,
+			Questions:    point.Questions,
+			AccuracyRate: percentage(float64(point.Correct), float64(point.Questions)),
+			ScoreRate:    percentage(point.Score, point.MaxScore),
+		})
+	}
+
+	for i := range trends {
+		trend := &trends[i]
+		trend.Direction = TrendSteady
+		if len(trend.Points) < 2 {
+			continue
+		}
+
+		trend.AccuracyChange = roundRate(trend.Points[len(trend.Points)-1].AccuracyRate - trend.Points[0].AccuracyRate)
+		switch {
+		case trend.AccuracyChange > trendSteadyMargin:
+			trend.Direction = TrendImproving
+		case trend.AccuracyChange < -trendSteadyMargin:
+			trend.Direction = TrendDeclining
+		}
+	}
+
+	return trends
+}
+
+// trendStart returns midnight on the Monday that starts the trend period ending in the current week
+func trendStart(now time.Time, weeks int) time.Time {
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	monday := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, now.Location())
+	return monday.AddDate(0, 0, -7*(weeks-1))
+}
+
+// percentage returns part as a percentage of whole, rounded to 2 decimal places
+func percentage(part, whole float64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return roundRate(part / whole * 100)
+}
+
+// roundRate rounds a rate to two decimal places
+func roundRate(value float64) float64 {
+	return math.Round(value*100) / 100
+}
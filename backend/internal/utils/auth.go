@@ -27,7 +27,8 @@ func CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
-// GenerateJWT generates a JWT token for a user
+// GenerateJWT generates a JWT token for a user. Role and permissions are not part of the token, the
+// middleware reads them from the database on every request so changes apply to existing tokens.
 func GenerateJWT(userID uint, msisdn, secret string) (string, error) {
 	claims := JWTClaims{
 		UserID: userID,
@@ -72,8 +72,10 @@ func main() {
 	routes.SetupAuthRoutes(r, authHandler, oauthHandler, cfg.JWT.Secret, authService)
 	routes.SetupPackageRoutes(r, db, cfg.JWT.Secret, authService)
 	routes.SetupEnrollmentRoutes(r, db, cfg.JWT.Secret, authService)
+	routes.SetupPaymentRoutes(r, db, cfg, cfg.JWT.Secret, authService)
 	routes.SetupDashboardRoutes(r, db, cfg.JWT.Secret, authService)
 	routes.SetupExamRoutes(r, db, cfg, cfg.JWT.Secret, authService)
+	routes.SetupAdminRoutes(r, db, cfg.JWT.Secret, authService)
 
 	// Create and start server with background services
 	srv := server.NewServer(cfg, db, r)
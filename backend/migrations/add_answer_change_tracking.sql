@@ -0,0 +1,8 @@
+-- Migration: Per-question time and answer change tracking
+-- Date: 2026-10-17
+-- Description: Session syncs carry client timestamps and time spent per question. Answer changes are logged
+-- in the cache during the session and folded into user_exam_attempts.answers_data on submission, the
+-- question analytics rows now take their time spent, answer time and change count from it.
+
+ALTER TABLE user_question_answers
+ADD COLUMN changed_from_correct BOOLEAN DEFAULT FALSE COMMENT 'Whether a correct answer was changed to a wrong one' AFTER change_count;
@@ -0,0 +1,9 @@
+-- Migration: Analytics failures of exam attempts
+-- Date: 2026-10-17
+-- Description: The analytics worker records failed runs of an attempt and gives up after 5, instead of
+-- retrying an attempt that can never be processed on every run
+
+ALTER TABLE user_exam_attempts
+    ADD COLUMN analytics_failures INT NOT NULL DEFAULT 0 COMMENT 'Failed analytics runs, the worker gives up at MaxAnalyticsFailures' AFTER is_passed,
+    ADD COLUMN analytics_failed_at DATETIME(3) NULL COMMENT 'Last failed analytics run' AFTER analytics_failures,
+    ADD COLUMN analytics_error VARCHAR(255) NULL COMMENT 'Error of the last failed analytics run' AFTER analytics_failed_at;
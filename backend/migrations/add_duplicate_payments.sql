@@ -0,0 +1,7 @@
+-- Migration: Duplicate payment tracking
+-- Date: 2026-10-17
+-- Description: A payment that settles after its enrollment or renewal was already paid by another checkout
+-- is kept as PAID and flagged as a duplicate, so it can be found and refunded.
+
+ALTER TABLE payment_transactions
+ADD COLUMN duplicate BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Paid for what another transaction had already paid, to be refunded' AFTER paid_at;
@@ -0,0 +1,16 @@
+-- Migration: Durable session answers
+-- Date: 2026-10-17
+-- Description: Session answers are written through from the cache to user_session_answers and recovered from
+-- it when the cache misses. Rows are removed once the attempt is submitted, auto-submitted or abandoned.
+
+-- One row per question of an attempt, updated in place
+ALTER TABLE user_session_answers
+MODIFY COLUMN selected_option TEXT NOT NULL COMMENT 'Selected option key (a, b, c, d, e) or JSON for multiple options',
+ADD UNIQUE INDEX idx_attempt_question (attempt_id, question_id),
+DROP INDEX idx_attempt_id,
+DROP INDEX idx_question_id;
+
+-- Answers of attempts that already ended are in user_exam_attempts.answers_data
+DELETE usa FROM user_session_answers usa
+INNER JOIN user_exam_attempts uea ON usa.attempt_id = uea.id
+WHERE uea.status <> 'STARTED';
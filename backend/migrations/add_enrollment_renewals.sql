@@ -0,0 +1,33 @@
+-- Migration: Enrollment renewals
+-- Date: 2026-10-17
+-- Description: Expired or expiring enrollments are renewed in place instead of enrolling again. Each renewal is
+-- one row; completed renewals record the validity period they added. Packages can set a discounted renewal
+-- price, and payment transactions can pay for a renewal.
+
+ALTER TABLE packages
+    ADD COLUMN renewal_price DECIMAL(10,2) NULL COMMENT 'Discounted price of renewing an enrollment, package price when null' AFTER validity_date;
+
+CREATE TABLE IF NOT EXISTS enrollment_renewals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    enrollment_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    package_id BIGINT UNSIGNED NOT NULL,
+    price DECIMAL(10,2) NOT NULL COMMENT 'Renewal price charged',
+    original_price DECIMAL(10,2) NOT NULL COMMENT 'Package price at renewal',
+    status ENUM('PENDING','COMPLETED','FAILED') NOT NULL DEFAULT 'PENDING',
+    previous_expires_at DATETIME(3) NULL COMMENT 'Enrollment expiry before the renewal',
+    period_start DATETIME(3) NULL,
+    period_end DATETIME(3) NULL,
+    payment_reference VARCHAR(100) NULL COMMENT 'Payment gateway reference',
+    renewed_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    INDEX idx_enrollment_id (enrollment_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_package_id (package_id),
+    INDEX idx_status (status)
+);
+
+ALTER TABLE payment_transactions
+    ADD COLUMN renewal_id BIGINT UNSIGNED NULL COMMENT 'Set when the checkout pays for a renewal' AFTER enrollment_id,
+    ADD INDEX idx_renewal_id (renewal_id);
@@ -0,0 +1,7 @@
+-- Migration: Scheduled exam windows
+-- Date: 2026-10-17
+-- Description: Starting an exam now requires a published status and the current time inside its schedule
+-- window. Sessions started late end when the exam closes unless the exam keeps the full duration for them.
+
+ALTER TABLE exams
+ADD COLUMN late_start_full_duration BOOLEAN DEFAULT FALSE COMMENT 'Late starters keep the full duration past the scheduled end' AFTER scheduled_end_date;
@@ -0,0 +1,6 @@
+-- Migration: Add exam leaderboards
+-- Date: 2026-10-16
+-- Description: Mock and final exam leaderboards are cached as sorted sets and rebuilt from finished attempts on a cache miss; this index keeps the rebuild query cheap
+
+ALTER TABLE user_exam_attempts
+ADD INDEX idx_exam_package_status (exam_id, package_id, status);
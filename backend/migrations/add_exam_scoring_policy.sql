@@ -0,0 +1,9 @@
+-- Migration: Add per-exam scoring policy
+-- Date: 2026-10-16
+-- Description: Exams choose partial credit, option weights and negative marking; NULL keeps the default policy (partial credit on TRUE_FALSE, no penalties)
+
+ALTER TABLE exams
+ADD COLUMN scoring_policy JSON NULL COMMENT 'Partial credit, option weights and negative marking settings' AFTER attempt_score_policy;
+
+-- Example:
+-- UPDATE exams SET scoring_policy = '{"partial_credit": false, "negative_marking": {"enabled": true, "penalty_ratio": 0.25}}' WHERE slug = '...';
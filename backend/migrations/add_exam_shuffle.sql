@@ -0,0 +1,14 @@
+-- Migration: Per-attempt question and option order
+-- Date: 2026-10-17
+-- Description: Exams can shuffle their question order and/or option order. Each attempt snapshots the
+-- settings with its own seed so a resumed session sees the same order. Answers, scoring and results keep
+-- using the exam's option keys.
+
+ALTER TABLE exams
+ADD COLUMN shuffle_questions BOOLEAN DEFAULT FALSE COMMENT 'Whether each attempt shows the questions in its own order' AFTER mode,
+ADD COLUMN shuffle_options BOOLEAN DEFAULT FALSE COMMENT 'Whether each attempt shows the options in its own order' AFTER shuffle_questions;
+
+ALTER TABLE user_exam_attempts
+ADD COLUMN shuffle_seed BIGINT DEFAULT 0 COMMENT 'Seed of the attempt order, 0 keeps the exam order' AFTER passing_score,
+ADD COLUMN shuffle_questions BOOLEAN DEFAULT FALSE COMMENT 'Question order shuffled when the attempt started' AFTER shuffle_seed,
+ADD COLUMN shuffle_options BOOLEAN DEFAULT FALSE COMMENT 'Option order shuffled when the attempt started' AFTER shuffle_questions;
@@ -0,0 +1,10 @@
+-- Migration: Tutor mode for review and practice exams
+-- Date: 2026-10-17
+-- Description: REVIEW and PRACTICE exams in TUTOR mode let students check each answer during the session
+-- (POST /api/exams/session/:sessionId/check). Checked answers are locked and the attempt is scored on submission.
+
+ALTER TABLE exams
+ADD COLUMN mode ENUM('TIMED', 'TUTOR') NOT NULL DEFAULT 'TIMED' COMMENT 'TUTOR gives per-question feedback on REVIEW and PRACTICE exams' AFTER max_attempts;
+
+-- Question references are copied into exam snapshots from now on. Rebuild a review exam from its
+-- blueprint to include references for questions that were added to it earlier.
@@ -0,0 +1,17 @@
+-- Migration: Allow multiple attempts per exam in a package
+-- Date: 2026-10-16
+-- Description: Attempts become unique per attempt_number instead of per user/exam/package, and packages/exams choose which attempt score counts
+
+-- Create the new unique index first so the foreign key on user_id keeps an index
+ALTER TABLE user_exam_attempts
+ADD UNIQUE INDEX idx_user_exam_package_attempt (user_id, exam_id, package_id, attempt_number);
+
+ALTER TABLE user_exam_attempts
+DROP INDEX idx_user_exam_package;
+
+-- Which attempt score counts when an exam allows retakes
+ALTER TABLE packages
+ADD COLUMN attempt_score_policy ENUM('BEST','LATEST','AVERAGE') DEFAULT 'BEST' COMMENT 'Which attempt score counts when exams allow retakes' AFTER validity_date;
+
+ALTER TABLE exams
+ADD COLUMN attempt_score_policy ENUM('BEST','LATEST','AVERAGE') NULL COMMENT 'Overrides package attempt_score_policy when set' AFTER max_attempts;
@@ -0,0 +1,11 @@
+-- Migration: Free trials for premium packages
+-- Date: 2026-10-17
+-- Description: Premium packages can offer a free trial of trial_days, extendable once by trial_extension_days.
+-- During a trial only the package exams marked is_trial_visible can be started.
+
+ALTER TABLE packages
+    ADD COLUMN trial_days INT DEFAULT 0 COMMENT 'Length of the free trial in days, 0 means no trial' AFTER validity_days,
+    ADD COLUMN trial_extension_days INT DEFAULT 0 COMMENT 'Days a trial can be extended by once, 0 means no extension' AFTER trial_days;
+
+ALTER TABLE package_exams
+    ADD COLUMN is_trial_visible BOOLEAN DEFAULT FALSE COMMENT 'Whether students on a trial can take this exam';
@@ -0,0 +1,39 @@
+-- Migration: Payment refunds and enrollment cancellation
+-- Date: 2026-10-17
+-- Description: Admins can refund a paid transaction in full or in part through its gateway, each refund is one
+-- row. A refund is RESERVED before the gateway is called, so concurrent refunds cannot add up to more than
+-- was paid. The enrollment is only cancelled once the gateway has PROCESSED the refund; PENDING refunds are
+-- reconciled with the gateway, a refund that falls through is FAILED and its amount can be refunded again.
+-- Cancelled enrollments record who cancelled them, when and why; the coupon use and the package's
+-- enrollment count are given back in the same step. Refunding a renewal's payment only takes its period off.
+
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    transaction_id VARCHAR(64) NOT NULL COMMENT 'Refunded payment transaction',
+    enrollment_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT 'Student the money is returned to',
+    provider VARCHAR(30) NOT NULL,
+    refund_reference VARCHAR(100) NULL COMMENT 'Gateway refund ID',
+    gateway_data TEXT NULL COMMENT 'Gateway refund response',
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'BDT',
+    status ENUM('PROCESSED','PENDING','RESERVED','FAILED') NOT NULL DEFAULT 'PENDING',
+    cancel_enrollment BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Cancel the enrollment once the refund is processed, even if partial',
+    reason VARCHAR(500) NOT NULL,
+    refunded_by BIGINT UNSIGNED NOT NULL COMMENT 'Admin who made the refund',
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_enrollment_id (enrollment_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_refunded_by (refunded_by),
+    INDEX idx_status (status)
+);
+
+ALTER TABLE user_package_enrollments
+    ADD COLUMN cancelled_at DATETIME(3) NULL AFTER final_price,
+    ADD COLUMN cancelled_by BIGINT UNSIGNED NULL COMMENT 'Admin who cancelled the enrollment' AFTER cancelled_at,
+    ADD COLUMN cancellation_reason VARCHAR(500) NULL AFTER cancelled_by;
+
+ALTER TABLE enrollment_renewals
+    MODIFY COLUMN status ENUM('PENDING','COMPLETED','FAILED','REFUNDED') NOT NULL DEFAULT 'PENDING';
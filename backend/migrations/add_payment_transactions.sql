@@ -0,0 +1,28 @@
+-- Migration: Payment gateway transactions
+-- Date: 2026-10-17
+-- Description: Each checkout of a pending enrollment at bKash, SSLCommerz or the local fake gateway is one
+-- transaction. Verified payments move the enrollment's payment_status to PAID, failed ones to FAILED.
+
+CREATE TABLE IF NOT EXISTS payment_transactions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    transaction_id VARCHAR(64) NOT NULL COMMENT 'Our reference sent to the gateway',
+    enrollment_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    provider_reference VARCHAR(100) NULL COMMENT 'Gateway session or payment ID',
+    provider_transaction_id VARCHAR(100) NULL COMMENT 'Gateway transaction ID, used for refunds',
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'BDT',
+    status ENUM('INITIATED','PAID','FAILED','CANCELLED') NOT NULL DEFAULT 'INITIATED',
+    failure_reason VARCHAR(255) NULL,
+    gateway_data TEXT COMMENT 'Last gateway verification response',
+    paid_at DATETIME(3) NULL,
+    verified_at DATETIME(3) NULL COMMENT 'When the gateway was last asked',
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_payment_transactions_transaction_id (transaction_id),
+    INDEX idx_enrollment_id (enrollment_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_provider_reference (provider, provider_reference),
+    INDEX idx_status (status)
+);
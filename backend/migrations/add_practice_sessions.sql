@@ -0,0 +1,10 @@
+-- Migration: Adaptive practice sessions
+-- Date: 2026-10-16
+-- Description: Practice sessions run on exams generated from the question bank. Their attempts are flagged so they do not count towards package progress or attempt history.
+
+ALTER TABLE exams
+MODIFY COLUMN exam_type ENUM('DAILY', 'MOCK', 'REVIEW', 'FINAL', 'PRACTICE') NOT NULL DEFAULT 'DAILY';
+
+ALTER TABLE user_exam_attempts
+ADD COLUMN is_practice BOOLEAN DEFAULT FALSE COMMENT 'Practice sessions do not count towards package progress' AFTER attempt_number,
+ADD INDEX idx_practice (is_practice);
@@ -0,0 +1,14 @@
+-- Migration: Build per-question analytics in the background
+-- Date: 2026-10-16
+-- Description: user_question_answers rows are written once per attempt/question by the analytics worker; is_scored now means those rows exist
+
+-- One analytics row per question of an attempt (the worker replaces rows when reprocessing)
+ALTER TABLE user_question_answers
+ADD UNIQUE INDEX idx_attempt_question (attempt_id, question_id);
+
+-- Historical attempts were flagged as scored inline without analytics rows; queue them for the worker
+-- (or run: go run ./scripts/backfill_question_answers -reprocess)
+UPDATE user_exam_attempts ua
+SET ua.is_scored = false
+WHERE ua.status IN ('COMPLETED', 'AUTO_SUBMITTED')
+  AND NOT EXISTS (SELECT 1 FROM user_question_answers uqa WHERE uqa.attempt_id = ua.id);
@@ -0,0 +1,15 @@
+-- Migration: Question bookmarks and personal notes
+-- Date: 2026-10-17
+-- Description: Students bookmark questions from their results and attach private notes. Questions flagged
+-- during a session are stored with the attempt in user_exam_attempts.answers_data, no schema change needed.
+
+CREATE TABLE IF NOT EXISTS question_bookmarks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    question_id BIGINT UNSIGNED NOT NULL,
+    attempt_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Attempt the question was bookmarked from',
+    note TEXT COMMENT 'Private note, only visible to the user',
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_user_question (user_id, question_id)
+);
@@ -0,0 +1,28 @@
+-- Migration: Spaced-repetition review schedules
+-- Date: 2026-10-17
+-- Description: One SM-2 schedule per user and question. The analytics worker creates a schedule when a question
+-- is missed in any exam or practice session and updates it every time the question is answered again.
+
+CREATE TABLE IF NOT EXISTS question_review_schedules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    question_id BIGINT UNSIGNED NOT NULL,
+    system_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'System of the question, 0 when unknown',
+    subject_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Subject of the system, 0 when unknown',
+    ease_factor DECIMAL(4,2) NOT NULL DEFAULT 2.50,
+    interval_days INT NOT NULL DEFAULT 0 COMMENT 'Days between the last review and the next',
+    repetitions INT NOT NULL DEFAULT 0 COMMENT 'Consecutive reviews answered correctly',
+    due_at DATETIME(3) NOT NULL,
+    lapses INT DEFAULT 0 COMMENT 'Times the question was missed again',
+    total_reviews INT DEFAULT 0,
+    last_quality INT DEFAULT 0 COMMENT 'Last review grade, 0-5',
+    last_reviewed_at DATETIME(3) NULL COMMENT 'When the question was last answered, reviews at or before this are ignored',
+    last_attempt_id BIGINT UNSIGNED DEFAULT 0,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_user_question (user_id, question_id),
+    INDEX idx_user_due (user_id, due_at)
+);
+
+-- Build schedules from existing answers (attempts are replayed oldest first):
+-- go run ./scripts/backfill_question_answers -reprocess
@@ -0,0 +1,7 @@
+-- Migration: Durable tutor mode answer locks
+-- Date: 2026-10-17
+-- Description: Answers checked in tutor mode are locked until submission. The lock is written through to the
+-- answer's row in user_session_answers and recovered from it when the cache misses.
+
+ALTER TABLE user_session_answers
+ADD COLUMN checked_at DATETIME(3) NULL COMMENT 'When tutor mode checked the answer, which locks it' AFTER answered_at;
@@ -0,0 +1,8 @@
+-- Migration: Durable flagged questions
+-- Date: 2026-10-17
+-- Description: Questions flagged to revisit during a session are written through to their row in
+-- user_session_answers and recovered from it when the cache misses. A question flagged without an
+-- answer gets a row with an empty option.
+
+ALTER TABLE user_session_answers
+ADD COLUMN flagged BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Flagged to revisit before submitting' AFTER selected_option;
@@ -0,0 +1,8 @@
+-- Migration: Durable session answer versions
+-- Date: 2026-10-17
+-- Description: Session syncs are last-writer-wins on the time the student changed each question on their
+-- device. That version is written through to the question's row in user_session_answers and recovered from
+-- it when the cache misses. Cleared answers keep their row with an empty option so their version survives.
+
+ALTER TABLE user_session_answers
+ADD COLUMN changed_at DATETIME(3) NULL COMMENT 'When the student last changed the question on their device, its sync version' AFTER answered_at;
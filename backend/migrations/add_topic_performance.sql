@@ -0,0 +1,20 @@
+-- Migration: Track subject and system on per-question analytics
+-- Date: 2026-10-16
+-- Description: Exam snapshots now record each question's system_id and subject_id, and the analytics worker copies them onto user_question_answers for the dashboard performance report
+
+ALTER TABLE user_question_answers
+ADD COLUMN system_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'System of the question, 0 when unknown' AFTER question_index,
+ADD COLUMN subject_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Subject of the system, 0 when unknown' AFTER system_id,
+ADD INDEX idx_user_system (user_id, system_id),
+ADD INDEX idx_user_subject (user_id, subject_id);
+
+-- Classify existing rows from the question bank (exams built earlier do not carry system_id in their snapshot)
+UPDATE user_question_answers uqa
+JOIN questions q ON q.id = uqa.question_id
+JOIN systems s ON s.id = q.system_id
+SET uqa.system_id = q.system_id,
+    uqa.subject_id = s.subject_id
+WHERE uqa.system_id = 0;
+
+-- Existing rows were written without per-question time; rebuild them to apportion attempt time
+-- (run: go run ./scripts/backfill_question_answers -reprocess)
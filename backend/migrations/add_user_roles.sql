@@ -0,0 +1,11 @@
+-- Migration: Add role-based access control to users
+-- Date: 2026-10-16
+-- Description: Adds a role column (ADMIN, EDITOR, STUDENT) and optional extra permission grants to users
+
+ALTER TABLE users
+ADD COLUMN role ENUM('ADMIN','EDITOR','STUDENT') NOT NULL DEFAULT 'STUDENT' AFTER email_verified,
+ADD COLUMN permissions JSON NULL COMMENT 'Extra permissions granted on top of the role' AFTER role,
+ADD INDEX idx_users_role (role);
+
+-- Bootstrap the first admin with:
+--   go run ./scripts/create_admin -msisdn <msisdn> -name "<name>" -password <password>
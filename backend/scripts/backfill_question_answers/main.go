@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/config"
+	"github.com/Mahfuz2811/medecole/backend/internal/database"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// Builds user_question_answers rows for historical attempts:
+//
+//	go run ./scripts/backfill_question_answers -reprocess
+//
+// Progress is logged after every batch. An interrupted run is resumed with -from-id set to the
+// last attempt ID logged; processing an attempt again replaces its rows instead of duplicating them.
+// Spaced-repetition review schedules are built from the same answers, oldest attempt first.
+// Attempts the worker gave up on after repeated failures are only retried with -reprocess.
+func main() {
+	fromID := flag.Uint("from-id", 0, "Only process attempts with an ID above this one")
+	batchSize := flag.Int("batch-size", 500, "Attempts loaded per query")
+	reprocess := flag.Bool("reprocess", false, "Also rebuild attempts that were already processed or given up on")
+	flag.Parse()
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	db, err := database.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Run auto migration so the analytics indexes exist
+	if err := db.AutoMigrate(); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	analyticsService := service.NewQuestionAnalyticsService(
+		repository.NewQuestionAnswerRepository(db.DB),
+		service.NewReviewService(repository.NewReviewScheduleRepository(db.DB)),
+		service.QuestionAnalyticsConfig{BatchSize: *batchSize},
+	)
+
+	processed, err := analyticsService.Backfill(*fromID, *reprocess, func(lastAttemptID uint, processed int) {
+		log.Printf("Processed %d attempts, last attempt ID: %d", processed, lastAttemptID)
+	})
+	if err != nil {
+		log.Fatal("Backfill failed:", err)
+	}
+
+	log.Printf("Backfill complete: %d attempts processed", processed)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"github.com/Mahfuz2811/medecole/backend/internal/config"
+	"github.com/Mahfuz2811/medecole/backend/internal/database"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// Bootstraps the first admin account:
+//
+//	go run ./scripts/create_admin -msisdn 01712345678 -name "Site Admin" -password secret123
+//
+// An existing user with the MSISDN is promoted to admin; otherwise a new admin user is created.
+func main() {
+	msisdn := flag.String("msisdn", "", "MSISDN of the admin user (required)")
+	name := flag.String("name", "Administrator", "Name used when creating a new user")
+	password := flag.String("password", "", "Password used when creating a new user")
+	flag.Parse()
+
+	if *msisdn == "" {
+		flag.Usage()
+		log.Fatal("-msisdn is required")
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	db, err := database.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Run auto migration so the role column exists
+	if err := db.AutoMigrate(); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	authService := service.NewAuthService(db.DB, cfg.JWT.Secret)
+	user, created, err := authService.BootstrapAdmin(*name, *msisdn, *password)
+	if err != nil {
+		log.Fatal("Failed to bootstrap admin:", err)
+	}
+
+	if created {
+		log.Printf("Created admin user %s (ID: %d)", user.MSISDN, user.ID)
+	} else {
+		log.Printf("Promoted existing user %s (ID: %d) to admin", user.MSISDN, user.ID)
+	}
+}
@@ -22,18 +22,21 @@ func (s *UserSeeder) Seed(db *database.Database) []models.User {
 			MSISDN:   "1234567890",
 			Password: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi", // password
 			IsActive: true,
+			Role:     models.RoleAdmin,
 		},
 		{
 			Name:     "John Doe",
 			MSISDN:   "9876543210",
 			Password: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi", // password
 			IsActive: true,
+			Role:     models.RoleStudent,
 		},
 		{
 			Name:     "Jane Smith",
 			MSISDN:   "5555551234",
 			Password: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi", // password
 			IsActive: true,
+			Role:     models.RoleStudent,
 		},
 	}
 
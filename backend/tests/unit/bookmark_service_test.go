@@ -0,0 +1,253 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockBookmarkRepository is a mock implementation of BookmarkRepository
+type MockBookmarkRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookmarkRepository) GetBookmarks(userID uint, filter repository.BookmarkFilter) ([]models.QuestionBookmark, error) {
+	args := m.Called(userID, filter)
+	return args.Get(0).([]models.QuestionBookmark), args.Error(1)
+}
+
+func (m *MockBookmarkRepository) GetBookmark(userID uint, questionID uint) (*models.QuestionBookmark, error) {
+	args := m.Called(userID, questionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QuestionBookmark), args.Error(1)
+}
+
+func (m *MockBookmarkRepository) SaveBookmark(bookmark *models.QuestionBookmark) error {
+	args := m.Called(bookmark)
+	return args.Error(0)
+}
+
+func (m *MockBookmarkRepository) DeleteBookmark(userID uint, questionID uint) error {
+	args := m.Called(userID, questionID)
+	return args.Error(0)
+}
+
+// createBookmarkedQuestion returns a bookmark with its question, system and subject loaded
+func createBookmarkedQuestion(questionID uint, note string) *models.QuestionBookmark {
+	return &models.QuestionBookmark{
+		ID:         7,
+		UserID:     5,
+		QuestionID: questionID,
+		AttemptID:  50,
+		Note:       note,
+		CreatedAt:  time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC),
+		Question: models.Question{
+			ID:              questionID,
+			SystemID:        3,
+			QuestionText:    "Q1",
+			QuestionType:    models.QuestionTypeSBA,
+			DifficultyLevel: models.DifficultyHard,
+			System: models.System{
+				ID:        3,
+				SubjectID: 1,
+				Name:      "Cardiovascular",
+				Subject:   models.Subject{ID: 1, Name: "Medicine"},
+			},
+		},
+	}
+}
+
+func TestBookmarkService_BookmarkQuestion(t *testing.T) {
+	mockBookmarkRepo := &MockBookmarkRepository{}
+	mockExamRepo := &MockExamRepository{}
+	bookmarkService := service.NewBookmarkService(mockBookmarkRepo, mockExamRepo)
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	session.Attempt.Status = models.AttemptStatusCompleted
+	mockExamRepo.On("GetCompletedSessionByID", "tutor_session").Return(session, nil)
+	mockBookmarkRepo.On("GetBookmark", uint(5), uint(1)).Return(nil, repository.ErrBookmarkNotFound).Once()
+	mockBookmarkRepo.On("SaveBookmark", mock.MatchedBy(func(bookmark *models.QuestionBookmark) bool {
+		return bookmark.ID == 0 && bookmark.UserID == 5 && bookmark.QuestionID == 1 &&
+			bookmark.AttemptID == 50 && bookmark.Note == "Check the murmur"
+	})).Return(nil)
+	mockBookmarkRepo.On("GetBookmark", uint(5), uint(1)).Return(createBookmarkedQuestion(1, "Check the murmur"), nil).Once()
+
+	result, err := bookmarkService.BookmarkQuestion(5, dto.BookmarkQuestionRequest{
+		SessionID:  "tutor_session",
+		QuestionID: 1,
+		Note:       "Check the murmur",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Check the murmur", result.Note)
+	assert.Equal(t, "Cardiovascular", result.SystemName)
+	assert.Equal(t, uint(1), result.SubjectID)
+	assert.Equal(t, "Medicine", result.SubjectName)
+	assert.Equal(t, "HARD", result.DifficultyLevel)
+	mockBookmarkRepo.AssertExpectations(t)
+}
+
+func TestBookmarkService_BookmarkQuestion_KeepsNote(t *testing.T) {
+	mockBookmarkRepo := &MockBookmarkRepository{}
+	mockExamRepo := &MockExamRepository{}
+	bookmarkService := service.NewBookmarkService(mockBookmarkRepo, mockExamRepo)
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	session.Attempt.ID = 51
+	mockExamRepo.On("GetCompletedSessionByID", "tutor_session").Return(session, nil)
+	mockBookmarkRepo.On("GetBookmark", uint(5), uint(2)).Return(createBookmarkedQuestion(2, "Old note"), nil)
+	mockBookmarkRepo.On("SaveBookmark", mock.MatchedBy(func(bookmark *models.QuestionBookmark) bool {
+		return bookmark.ID == 7 && bookmark.AttemptID == 51 && bookmark.Note == "Old note"
+	})).Return(nil)
+
+	_, err := bookmarkService.BookmarkQuestion(5, dto.BookmarkQuestionRequest{SessionID: "tutor_session", QuestionID: 2})
+
+	assert.NoError(t, err)
+	mockBookmarkRepo.AssertExpectations(t)
+}
+
+func TestBookmarkService_BookmarkQuestion_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     uint
+		questionID uint
+		check      func(error) bool
+	}{
+		{"another student's session", 6, 1, func(err error) bool { return err == repository.ErrAttemptNotFound }},
+		{"question outside the session", 5, 99, apperrors.IsQuestionNotInExamError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookmarkRepo := &MockBookmarkRepository{}
+			mockExamRepo := &MockExamRepository{}
+			bookmarkService := service.NewBookmarkService(mockBookmarkRepo, mockExamRepo)
+			mockExamRepo.On("GetCompletedSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+
+			_, err := bookmarkService.BookmarkQuestion(tt.userID, dto.BookmarkQuestionRequest{SessionID: "tutor_session", QuestionID: tt.questionID})
+
+			assert.True(t, tt.check(err), "unexpected error: %v", err)
+			mockBookmarkRepo.AssertNotCalled(t, "SaveBookmark", mock.Anything)
+		})
+	}
+}
+
+func TestBookmarkService_UpdateNote(t *testing.T) {
+	mockBookmarkRepo := &MockBookmarkRepository{}
+	bookmarkService := service.NewBookmarkService(mockBookmarkRepo, &MockExamRepository{})
+
+	mockBookmarkRepo.On("GetBookmark", uint(5), uint(1)).Return(createBookmarkedQuestion(1, "Old note"), nil)
+	mockBookmarkRepo.On("GetBookmark", uint(5), uint(2)).Return(nil, repository.ErrBookmarkNotFound)
+	mockBookmarkRepo.On("SaveBookmark", mock.AnythingOfType("*models.QuestionBookmark")).Return(nil)
+
+	result, err := bookmarkService.UpdateNote(5, 1, "")
+	assert.NoError(t, err)
+	assert.Empty(t, result.Note)
+
+	_, err = bookmarkService.UpdateNote(5, 2, "New note")
+	assert.ErrorIs(t, err, repository.ErrBookmarkNotFound)
+	mockBookmarkRepo.AssertNumberOfCalls(t, "SaveBookmark", 1)
+}
+
+func TestBookmarkService_GetBookmarks(t *testing.T) {
+	mockBookmarkRepo := &MockBookmarkRepository{}
+	bookmarkService := service.NewBookmarkService(mockBookmarkRepo, &MockExamRepository{})
+
+	filter := repository.BookmarkFilter{SubjectID: 1}
+	mockBookmarkRepo.On("GetBookmarks", uint(5), filter).Return([]models.QuestionBookmark{
+		*createBookmarkedQuestion(2, ""),
+		*createBookmarkedQuestion(1, "Check the murmur"),
+	}, nil)
+
+	result, err := bookmarkService.GetBookmarks(5, filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, uint(2), result.Bookmarks[0].QuestionID)
+	assert.Equal(t, "Check the murmur", result.Bookmarks[1].Note)
+	assert.Equal(t, "2026-10-02T09:00:00Z", result.Bookmarks[1].UpdatedAt)
+}
+
+func TestPracticeService_StartPractice_FromBookmarks(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	criteria := repository.PracticeCriteria{SystemIDs: []uint{3}, Bookmarked: true}
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), criteria, 20).Return(buildBankQuestions(1, 2, models.DifficultyMedium, 0), 2, nil)
+
+	var exam *models.Exam
+	mocks.expectPracticeAttempt(&exam)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{PackageSlug: "cardiology", SystemIDs: []uint{3}, Bookmarked: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.QuestionCount)
+	assert.False(t, result.TargetsWeakAreas)
+	mocks.topicRepo.AssertNotCalled(t, "GetSystemPerformance", mock.Anything)
+	mocks.practiceRepo.AssertExpectations(t)
+}
+
+func TestExamService_SyncSession_StoresFlags(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{}, nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", mock.AnythingOfType("map[uint]time.Time")).Return(nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.AnythingOfType("[]models.SessionAnswerChange")).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "a"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{1, 2}).Return(nil)
+
+	// Question 2 is flagged without an answer
+	result, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{
+		{QuestionID: 1, SelectedOption: "a", Flagged: true},
+		{QuestionID: 2, Flagged: true},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_SubmitExam_StoresFlagsWithAnswers(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	var answersData string
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "a"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{2}, nil)
+	mockExamRepo.On("GetAnswerChanges", "tutor_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(50), 1.0, true, mock.AnythingOfType("string"), 1).
+		Run(func(args mock.Arguments) { answersData = args.String(3) }).
+		Return(nil)
+
+	_, err := examService.SubmitExam("tutor_session", 5)
+
+	assert.NoError(t, err)
+	var stored struct {
+		Answers []struct {
+			QuestionID uint `json:"question_id"`
+			Flagged    bool `json:"flagged"`
+		} `json:"answers"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(answersData), &stored))
+	if assert.Len(t, stored.Answers, 2) {
+		assert.False(t, stored.Answers[0].Flagged)
+		assert.Equal(t, uint(2), stored.Answers[1].QuestionID)
+		assert.True(t, stored.Answers[1].Flagged)
+	}
+}
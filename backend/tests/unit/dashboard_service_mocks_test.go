@@ -83,6 +83,63 @@ func (m *MockDashboardEnrollmentRepository) IsUserEnrolledInPackage(userID, pack
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockDashboardEnrollmentRepository) HasUsedTrial(userID, packageID uint) (bool, error) {
+	args := m.Called(userID, packageID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDashboardEnrollmentRepository) ExtendTrial(enrollmentID uint, trialExpiresAt time.Time) error {
+	args := m.Called(enrollmentID, trialExpiresAt)
+	return args.Error(0)
+}
+
+func (m *MockDashboardEnrollmentRepository) CreateRenewal(renewal *models.EnrollmentRenewal) error {
+	args := m.Called(renewal)
+	return args.Error(0)
+}
+
+func (m *MockDashboardEnrollmentRepository) GetPendingRenewal(enrollmentID uint) (*models.EnrollmentRenewal, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockDashboardEnrollmentRepository) CompleteRenewal(renewalID uint, paymentReference *string, renewedAt time.Time) (*models.EnrollmentRenewal, error) {
+	args := m.Called(renewalID, paymentReference, renewedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockDashboardEnrollmentRepository) GetRenewalHistory(enrollmentID uint) ([]models.EnrollmentRenewal, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockDashboardEnrollmentRepository) CancelEnrollment(enrollmentID uint, cancellation repository.EnrollmentCancellation) error {
+	args := m.Called(enrollmentID, cancellation)
+	return args.Error(0)
+}
+
+func (m *MockDashboardEnrollmentRepository) RefundRenewal(renewalID uint) error {
+	args := m.Called(renewalID)
+	return args.Error(0)
+}
+
+func (m *MockDashboardEnrollmentRepository) GetPackageEnrollment(userID, packageID uint) (*models.UserPackageEnrollment, error) {
+	args := m.Called(userID, packageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPackageEnrollment), args.Error(1)
+}
+
 // MockUserExamAttemptRepository mocks the UserExamAttemptRepository interface
 type MockUserExamAttemptRepository struct {
 	mock.Mock
@@ -0,0 +1,213 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// createExpiringEnrollment returns a paid enrollment of a 90 day package that expires in daysLeft days
+func createExpiringEnrollment(userID uint, daysLeft int) *models.UserPackageEnrollment {
+	validityDays := 90
+	renewalPrice := 1000.0
+	enrollment := createPaidEnrollment(userID, 3)
+	expiresAt := time.Now().AddDate(0, 0, daysLeft)
+	enrollment.ExpiresAt = &expiresAt
+	enrollment.Package = models.Package{
+		ID:           3,
+		PackageType:  models.PackageTypePremium,
+		Price:        1500,
+		RenewalPrice: &renewalPrice,
+		ValidityType: models.ValidityTypeRelative,
+		ValidityDays: &validityDays,
+		IsActive:     true,
+	}
+	return enrollment
+}
+
+func TestPackage_RenewalCost(t *testing.T) {
+	pkg := models.Package{Price: 1500}
+	assert.Equal(t, 1500.0, pkg.RenewalCost())
+
+	renewalPrice := 1000.0
+	pkg.RenewalPrice = &renewalPrice
+	assert.Equal(t, 1000.0, pkg.RenewalCost())
+}
+
+func TestPackage_RenewalPeriod(t *testing.T) {
+	validityDays := 90
+	pkg := models.Package{ValidityType: models.ValidityTypeRelative, ValidityDays: &validityDays}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	// Renewing early adds the period after the current expiry
+	expiresAt := now.AddDate(0, 0, 10)
+	start, end := pkg.RenewalPeriod(&expiresAt, now)
+	assert.Equal(t, expiresAt, start)
+	assert.Equal(t, expiresAt.AddDate(0, 0, 90), end)
+
+	// Renewing after expiry starts from the renewal
+	expiredAt := now.AddDate(0, 0, -10)
+	start, end = pkg.RenewalPeriod(&expiredAt, now)
+	assert.Equal(t, now, start)
+	assert.Equal(t, now.AddDate(0, 0, 90), end)
+
+	// Fixed validity ends at the package's date
+	validityDate := now.AddDate(0, 6, 0)
+	fixed := models.Package{ValidityType: models.ValidityTypeFixed, ValidityDate: &validityDate}
+	_, end = fixed.RenewalPeriod(&expiredAt, now)
+	assert.Equal(t, validityDate, end)
+}
+
+func TestUserPackageEnrollment_CanRenew(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(e *models.UserPackageEnrollment)
+		want   bool
+	}{
+		{"expires within window", func(e *models.UserPackageEnrollment) {}, true},
+		{"expires after window", func(e *models.UserPackageEnrollment) {
+			expiresAt := time.Now().AddDate(0, 0, models.RenewalWindowDays+5)
+			e.ExpiresAt = &expiresAt
+		}, false},
+		{"expired", func(e *models.UserPackageEnrollment) {
+			expiresAt := time.Now().AddDate(0, 0, -5)
+			e.ExpiresAt = &expiresAt
+		}, true},
+		{"marked expired", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusExpired }, true},
+		{"never expires", func(e *models.UserPackageEnrollment) { e.ExpiresAt = nil }, false},
+		{"payment pending", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusPending }, false},
+		{"refunded", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusRefunded }, false},
+		{"deactivated", func(e *models.UserPackageEnrollment) { e.IsActive = false }, false},
+		{"trial", func(e *models.UserPackageEnrollment) { e.EnrollmentType = models.EnrollmentTypeTrial }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollment := createExpiringEnrollment(1, 10)
+			tt.modify(enrollment)
+
+			assert.Equal(t, tt.want, enrollment.CanRenew())
+		})
+	}
+}
+
+func TestUserPackageEnrollment_MarkedExpiredNeedsRenewal(t *testing.T) {
+	enrollment := createPaidEnrollment(1, 3)
+	enrollment.PaymentStatus = models.PaymentStatusExpired
+
+	assert.Equal(t, models.AccessDeniedExpired, enrollment.AccessDenial())
+	assert.False(t, enrollment.AwaitsPayment())
+}
+
+func TestEnrollmentService_RenewEnrollment_WaitsForPayment(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	mockRepo.On("GetEnrollmentByID", uint(30)).Return(createExpiringEnrollment(1, 10), nil)
+	mockRepo.On("GetPendingRenewal", uint(30)).Return(nil, nil)
+	mockRepo.On("CreateRenewal", mock.MatchedBy(func(renewal *models.EnrollmentRenewal) bool {
+		return renewal.EnrollmentID == 30 && renewal.Price == 1000 && renewal.OriginalPrice == 1500 &&
+			renewal.Status == models.RenewalStatusPending
+	})).Return(nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	result, err := enrollmentService.RenewEnrollment(context.Background(), 1, 30)
+
+	assert.NoError(t, err)
+	assert.True(t, result.PaymentRequired)
+	assert.Equal(t, 1000.0, result.Price)
+	mockRepo.AssertNotCalled(t, "CompleteRenewal", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnrollmentService_RenewEnrollment_FreeRenewalCompletes(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	enrollment := createExpiringEnrollment(1, -3)
+	free := 0.0
+	enrollment.Package.RenewalPrice = &free
+	mockRepo.On("GetEnrollmentByID", uint(30)).Return(enrollment, nil)
+	mockRepo.On("GetPendingRenewal", uint(30)).Return(nil, nil)
+	mockRepo.On("CreateRenewal", mock.AnythingOfType("*models.EnrollmentRenewal")).Return(nil)
+
+	periodEnd := time.Now().AddDate(0, 0, 90)
+	mockRepo.On("CompleteRenewal", uint(0), (*string)(nil), mock.AnythingOfType("time.Time")).
+		Return(&models.EnrollmentRenewal{EnrollmentID: 30, Status: models.RenewalStatusCompleted, PeriodEnd: &periodEnd}, nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	result, err := enrollmentService.RenewEnrollment(context.Background(), 1, 30)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(models.RenewalStatusCompleted), result.Status)
+	assert.False(t, result.PaymentRequired)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEnrollmentService_RenewEnrollment_ReusesUnpaidRenewal(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	mockRepo.On("GetEnrollmentByID", uint(30)).Return(createExpiringEnrollment(1, 10), nil)
+	mockRepo.On("GetPendingRenewal", uint(30)).
+		Return(&models.EnrollmentRenewal{ID: 8, EnrollmentID: 30, Price: 1000, Status: models.RenewalStatusFailed}, nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	result, err := enrollmentService.RenewEnrollment(context.Background(), 1, 30)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), result.ID)
+	assert.True(t, result.PaymentRequired)
+	mockRepo.AssertNotCalled(t, "CreateRenewal", mock.Anything)
+}
+
+func TestEnrollmentService_RenewEnrollment_OutsideWindow(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	mockRepo.On("GetEnrollmentByID", uint(30)).Return(createExpiringEnrollment(1, 60), nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	_, err := enrollmentService.RenewEnrollment(context.Background(), 1, 30)
+
+	assert.True(t, apperrors.IsRenewalNotAvailableError(err))
+	mockRepo.AssertNotCalled(t, "CreateRenewal", mock.Anything)
+}
+
+func TestEnrollmentService_RenewEnrollment_FixedValidityPassed(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	enrollment := createExpiringEnrollment(1, -3)
+	enrollment.Package.ValidityType = models.ValidityTypeFixed
+	enrollment.Package.ValidityDate = enrollment.ExpiresAt
+	mockRepo.On("GetEnrollmentByID", uint(30)).Return(enrollment, nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	_, err := enrollmentService.RenewEnrollment(context.Background(), 1, 30)
+
+	assert.True(t, apperrors.IsRenewalNotAvailableError(err))
+}
+
+func TestPaymentService_InitiateCheckout_Renewal(t *testing.T) {
+	mockRepo := new(MockPaymentRepository)
+	paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+
+	enrollment := createPendingEnrollment(5)
+	enrollment.PaymentStatus = models.PaymentStatusPaid
+	renewalID := uint(8)
+	mockRepo.On("GetEnrollmentForCheckout", uint(40)).Return(enrollment, nil)
+	mockRepo.On("GetRenewalForCheckout", renewalID).
+		Return(&models.EnrollmentRenewal{ID: renewalID, EnrollmentID: 40, Price: 1000, Status: models.RenewalStatusPending}, nil)
+	mockRepo.On("CreateTransaction", mock.MatchedBy(func(transaction *models.PaymentTransaction) bool {
+		return transaction.RenewalID != nil && *transaction.RenewalID == renewalID && transaction.Amount == 1000
+	})).Return(nil)
+	mockRepo.On("SetProviderReference", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+	checkout, err := paymentService.InitiateCheckout(context.Background(), 5,
+		dto.CheckoutRequest{EnrollmentID: 40, RenewalID: &renewalID, Provider: "fake"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000.0, checkout.Amount)
+	mockRepo.AssertExpectations(t)
+}
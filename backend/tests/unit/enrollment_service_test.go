@@ -89,6 +89,63 @@ func (m *MockEnrollmentRepository) IsUserEnrolledInPackage(userID, packageID uin
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockEnrollmentRepository) HasUsedTrial(userID, packageID uint) (bool, error) {
+	args := m.Called(userID, packageID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) ExtendTrial(enrollmentID uint, trialExpiresAt time.Time) error {
+	args := m.Called(enrollmentID, trialExpiresAt)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentRepository) CreateRenewal(renewal *models.EnrollmentRenewal) error {
+	args := m.Called(renewal)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentRepository) GetPendingRenewal(enrollmentID uint) (*models.EnrollmentRenewal, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) CompleteRenewal(renewalID uint, paymentReference *string, renewedAt time.Time) (*models.EnrollmentRenewal, error) {
+	args := m.Called(renewalID, paymentReference, renewedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) GetRenewalHistory(enrollmentID uint) ([]models.EnrollmentRenewal, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) CancelEnrollment(enrollmentID uint, cancellation repository.EnrollmentCancellation) error {
+	args := m.Called(enrollmentID, cancellation)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentRepository) RefundRenewal(renewalID uint) error {
+	args := m.Called(renewalID)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentRepository) GetPackageEnrollment(userID, packageID uint) (*models.UserPackageEnrollment, error) {
+	args := m.Called(userID, packageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPackageEnrollment), args.Error(1)
+}
+
 // Test data generators
 func createEnrollmentTestPackage() *models.Package {
 	return &models.Package{
@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// createTrialEnrollment returns a running trial of a premium package that allows one 5 day extension
+func createTrialEnrollment(userID, packageID uint) *models.UserPackageEnrollment {
+	trialExpiresAt := time.Now().AddDate(0, 0, 2)
+	return &models.UserPackageEnrollment{
+		ID:                  31,
+		UserID:              userID,
+		PackageID:           packageID,
+		EnrollmentType:      models.EnrollmentTypeTrial,
+		EnrolledPackageType: models.PackageTypePremium,
+		EnrolledPrice:       1500,
+		PaymentStatus:       models.PaymentStatusPending,
+		IsTrialUsed:         true,
+		TrialExpiresAt:      &trialExpiresAt,
+		ExpiresAt:           &trialExpiresAt,
+		IsActive:            true,
+		Package: models.Package{
+			ID:                 packageID,
+			PackageType:        models.PackageTypePremium,
+			Price:              1500,
+			TrialDays:          7,
+			TrialExtensionDays: 5,
+		},
+	}
+}
+
+func TestPackage_OffersTrial(t *testing.T) {
+	premium := models.Package{PackageType: models.PackageTypePremium, Price: 1500, TrialDays: 7}
+	assert.True(t, premium.OffersTrial())
+
+	noTrialDays := premium
+	noTrialDays.TrialDays = 0
+	assert.False(t, noTrialDays.OffersTrial())
+
+	free := models.Package{PackageType: models.PackageTypeFree, TrialDays: 7}
+	assert.False(t, free.OffersTrial())
+}
+
+func TestPackage_ExpiryDateFrom(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := 90
+
+	relative := models.Package{ValidityType: models.ValidityTypeRelative, ValidityDays: &days}
+	assert.Equal(t, start.AddDate(0, 0, 90), relative.ExpiryDateFrom(start))
+
+	unconfigured := models.Package{ValidityType: models.ValidityTypeRelative}
+	assert.Equal(t, start.AddDate(1, 0, 0), unconfigured.ExpiryDateFrom(start))
+}
+
+func TestUserPackageEnrollment_TrialStates(t *testing.T) {
+	trial := createTrialEnrollment(1, 3)
+	assert.True(t, trial.IsOnTrial())
+	assert.True(t, trial.CanAccessContent())
+	assert.True(t, trial.CanExtendTrial())
+
+	past := time.Now().Add(-time.Hour)
+	trial.TrialExpiresAt = &past
+	assert.False(t, trial.CanAccessContent())
+	assert.True(t, trial.AwaitsPayment())
+
+	trial.TrialExtendedAt = &past
+	assert.False(t, trial.CanExtendTrial())
+
+	upgraded := createPaidEnrollment(1, 3)
+	upgraded.EnrollmentType = models.EnrollmentTypeUpgrade
+	upgraded.PaymentStatus = models.PaymentStatusUpgraded
+	assert.False(t, upgraded.IsOnTrial())
+	assert.True(t, upgraded.CanAccessContent())
+}
+
+func TestExamService_StartExam_TrialLocksHiddenExams(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+	mockExamRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(1)).Return(createRetakeTestPackageData(exam, nil), nil)
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(createTrialEnrollment(1, 3), nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, &MockExamMapper{})
+	_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	var paymentErr *apperrors.PaymentRequiredError
+	if assert.True(t, errors.As(err, &paymentErr)) {
+		assert.Equal(t, string(models.AccessDeniedTrialExamLocked), paymentErr.Reason)
+		assert.Equal(t, uint(31), paymentErr.EnrollmentID)
+	}
+	mockExamRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_StartExam_TrialVisibleExam(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockExamMapper := &MockExamMapper{}
+
+	packageData := createRetakeTestPackageData(exam, nil)
+	packageData.Exams[0].IsTrialVisible = true
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+	mockExamRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(1)).Return(packageData, nil)
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(createTrialEnrollment(1, 3), nil)
+	mockExamMapper.On("ToExamMetaResponse", mock.Anything).Return(dto.ExamMetaResponse{ID: exam.ID})
+
+	sessionID := "session-1"
+	mockExamRepo.On("CreateExamAttemptWithExam", uint(1), exam, uint(3), 1, map[string]string(nil)).
+		Return(&models.UserExamAttempt{ID: 12, AttemptNumber: 1, SessionID: &sessionID}, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), result.AttemptID)
+}
+
+func TestEnrollmentService_ExtendTrial(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	trial := createTrialEnrollment(1, 3)
+	expectedEnd := trial.TrialExpiresAt.AddDate(0, 0, 5)
+
+	extended := createTrialEnrollment(1, 3)
+	now := time.Now()
+	extended.TrialExpiresAt = &expectedEnd
+	extended.ExpiresAt = &expectedEnd
+	extended.TrialExtendedAt = &now
+
+	mockRepo.On("GetEnrollmentByID", uint(31)).Return(trial, nil).Once()
+	mockRepo.On("ExtendTrial", uint(31), expectedEnd).Return(nil)
+	mockRepo.On("GetEnrollmentByID", uint(31)).Return(extended, nil).Once()
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	result, err := enrollmentService.ExtendTrial(context.Background(), 1, 31)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedEnd, *result.TrialExpiresAt)
+	assert.False(t, result.CanExtendTrial)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEnrollmentService_ExtendTrial_OnlyOnce(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	trial := createTrialEnrollment(1, 3)
+	extendedAt := time.Now().AddDate(0, 0, -1)
+	trial.TrialExtendedAt = &extendedAt
+	mockRepo.On("GetEnrollmentByID", uint(31)).Return(trial, nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	_, err := enrollmentService.ExtendTrial(context.Background(), 1, 31)
+
+	assert.True(t, apperrors.IsTrialNotExtendableError(err))
+	mockRepo.AssertNotCalled(t, "ExtendTrial", mock.Anything, mock.Anything)
+}
+
+func TestEnrollmentService_ExtendTrial_OtherUsersEnrollment(t *testing.T) {
+	mockRepo := &MockEnrollmentRepository{}
+	mockRepo.On("GetEnrollmentByID", uint(31)).Return(createTrialEnrollment(2, 3), nil)
+
+	enrollmentService := service.NewEnrollmentService(mockRepo, mapper.NewEnrollmentMapper(), &gorm.DB{})
+	_, err := enrollmentService.ExtendTrial(context.Background(), 1, 31)
+
+	assert.ErrorIs(t, err, repository.ErrEnrollmentNotFound)
+	mockRepo.AssertNotCalled(t, "ExtendTrial", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,188 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func createRetakeTestExam(maxAttempts int) *models.Exam {
+	return &models.Exam{
+		ID:              7,
+		Title:           "Cardiology Mock",
+		Slug:            "cardiology-mock",
+		ExamType:        models.ExamTypeMock,
+		TotalQuestions:  2,
+		DurationMinutes: 30,
+		PassingScore:    60,
+		MaxAttempts:     maxAttempts,
+	}
+}
+
+func createRetakeTestPackageData(exam *models.Exam, attempts []models.UserExamAttempt) *repository.PackageWithExamsData {
+	return &repository.PackageWithExamsData{
+		Package: models.Package{ID: 3, Slug: "cardiology", AttemptScorePolicy: models.AttemptScorePolicyBest},
+		Exams: []repository.ExamWithUserData{
+			{Exam: *exam, Attempts: attempts},
+		},
+	}
+}
+
+func finishedAttempt(id uint, number int, score float64) models.UserExamAttempt {
+	return models.UserExamAttempt{
+		ID:            id,
+		AttemptNumber: number,
+		Status:        models.AttemptStatusCompleted,
+		Score:         float64Ptr(score),
+	}
+}
+
+func setupStartExamMocks(exam *models.Exam, attempts []models.UserExamAttempt) (*MockExamRepository, *MockEnrollmentRepository, *MockExamMapper) {
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockExamMapper := &MockExamMapper{}
+
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+	mockExamRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(1)).Return(createRetakeTestPackageData(exam, attempts), nil)
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(createPaidEnrollment(1, 3), nil)
+	mockExamMapper.On("ToExamMetaResponse", mock.Anything).Return(dto.ExamMetaResponse{ID: exam.ID})
+
+	return mockExamRepo, mockEnrollmentRepo, mockExamMapper
+}
+
+func TestExamService_StartExam_NumbersRetakes(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	attempts := []models.UserExamAttempt{finishedAttempt(10, 1, 40), finishedAttempt(11, 2, 55)}
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, attempts)
+
+	sessionID := "session-3"
+	mockExamRepo.On("CreateExamAttemptWithExam", uint(1), exam, uint(3), 3, map[string]string(nil)).
+		Return(&models.UserExamAttempt{ID: 12, AttemptNumber: 3, SessionID: &sessionID}, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), result.AttemptID)
+	assert.Equal(t, sessionID, result.SessionID)
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_StartExam_MaxAttemptsExceeded(t *testing.T) {
+	exam := createRetakeTestExam(2)
+	attempts := []models.UserExamAttempt{finishedAttempt(10, 1, 40), finishedAttempt(11, 2, 55)}
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, attempts)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	var maxAttemptsErr *apperrors.MaxAttemptsExceededError
+	assert.True(t, errors.As(err, &maxAttemptsErr))
+	assert.Equal(t, 2, maxAttemptsErr.MaxAttempts)
+	assert.Equal(t, 2, maxAttemptsErr.AttemptedCount)
+	mockExamRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_StartExam_SingleAttemptAlreadySubmitted(t *testing.T) {
+	exam := createRetakeTestExam(1)
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, []models.UserExamAttempt{finishedAttempt(10, 1, 70)})
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.ErrorIs(t, err, repository.ErrExamAlreadySubmitted)
+}
+
+func TestExamService_StartExam_ResumesActiveAttempt(t *testing.T) {
+	exam := createRetakeTestExam(2)
+	sessionID := "active-session"
+	attempts := []models.UserExamAttempt{
+		finishedAttempt(10, 1, 40),
+		{ID: 11, AttemptNumber: 2, Status: models.AttemptStatusStarted, SessionID: &sessionID},
+	}
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, attempts)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(11), result.AttemptID)
+	assert.Equal(t, sessionID, result.SessionID)
+	mockExamRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_StartExam_UnlimitedAttempts(t *testing.T) {
+	exam := createRetakeTestExam(0)
+	attempts := []models.UserExamAttempt{finishedAttempt(10, 1, 40), finishedAttempt(11, 2, 55), finishedAttempt(12, 3, 90)}
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, attempts)
+
+	mockExamRepo.On("CreateExamAttemptWithExam", uint(1), exam, uint(3), 4, map[string]string(nil)).
+		Return(&models.UserExamAttempt{ID: 13, AttemptNumber: 4}, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(13), result.AttemptID)
+}
+
+func TestAttemptScorePolicy_AggregateScore(t *testing.T) {
+	attempts := []models.UserExamAttempt{
+		finishedAttempt(1, 1, 50),
+		finishedAttempt(2, 2, 80),
+		finishedAttempt(3, 3, 65),
+		{ID: 4, AttemptNumber: 4, Status: models.AttemptStatusAbandoned},
+	}
+
+	tests := []struct {
+		policy   models.AttemptScorePolicy
+		expected float64
+	}{
+		{models.AttemptScorePolicyBest, 80},
+		{models.AttemptScorePolicyLatest, 65},
+		{models.AttemptScorePolicyAverage, 65},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			score := tt.policy.AggregateScore(attempts)
+			assert.NotNil(t, score)
+			assert.InDelta(t, tt.expected, *score, 0.001)
+		})
+	}
+
+	assert.Nil(t, models.AttemptScorePolicyBest.AggregateScore(nil))
+}
+
+func TestExamMapper_ToExamResponse_AttemptHistory(t *testing.T) {
+	latest := models.AttemptScorePolicyLatest
+	exam := createRetakeTestExam(3)
+	exam.AttemptScorePolicy = &latest
+
+	response := mapper.NewExamMapper().ToExamResponse(repository.ExamWithUserData{
+		Exam:                 *exam,
+		EffectiveScorePolicy: latest,
+		Attempts:             []models.UserExamAttempt{finishedAttempt(10, 1, 90), finishedAttempt(11, 2, 60)},
+	})
+
+	assert.Equal(t, 2, response.AttemptsUsed)
+	assert.Equal(t, 1, *response.RemainingAttempts)
+	assert.True(t, response.CanRetake)
+	assert.Equal(t, "LATEST", response.AttemptScorePolicy)
+	assert.InDelta(t, 60.0, *response.EffectiveScore, 0.001)
+	assert.Len(t, response.AttemptHistory, 2)
+	assert.Equal(t, 2, response.AttemptHistory[1].AttemptNumber)
+}
@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func createExpiredSession() repository.SessionWithExamData {
+	sessionID := "expired_session"
+	questionsJSON := `[
+		{"id": 1, "question_text": "Q1", "question_type": "SBA", "points": 1,
+		 "options": {"a": {"text": "Right", "is_correct": true}, "b": {"text": "Wrong", "is_correct": false}}},
+		{"id": 2, "question_text": "Q2", "question_type": "SBA", "points": 1,
+		 "options": {"a": {"text": "Wrong", "is_correct": false}, "b": {"text": "Right", "is_correct": true}}}
+	]`
+
+	return repository.SessionWithExamData{
+		Attempt: models.UserExamAttempt{
+			ID:               42,
+			UserID:           5,
+			ExamID:           1,
+			PackageID:        1,
+			Status:           models.AttemptStatusStarted,
+			StartedAt:        time.Now().Add(-2 * time.Hour),
+			TimeLimitSeconds: 1800,
+			SessionID:        &sessionID,
+		},
+		Exam: models.Exam{
+			ID:              1,
+			PassingScore:    1,
+			DurationMinutes: 30,
+			QuestionsData:   questionsJSON,
+		},
+	}
+}
+
+func TestExamService_AutoSubmitSession_ScoresSavedAnswers(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	session := createExpiredSession()
+
+	mockExamRepo.On("GetSessionAnswers", "expired_session").Return(map[uint]string{1: "a"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "expired_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "expired_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "expired_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("AutoSubmitExamAttempt", uint(42), 1.0, true, mock.AnythingOfType("string"), 1).Return(nil)
+
+	result, err := examService.AutoSubmitSession(session)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, result.Score)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 2, result.TotalQuestions)
+	assert.Equal(t, 1800, result.TimeTakenSeconds)
+
+	// AnswersData uses the same structure as a manual submission
+	answersData := mockExamRepo.Calls[4].Arguments.String(3)
+	var stored map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(answersData), &stored))
+	assert.Len(t, stored["answers"], 2)
+	assert.Contains(t, stored, "exam_snapshot")
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_AutoSubmitSession_AlreadyFinished(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	session := createExpiredSession()
+	session.Attempt.Status = models.AttemptStatusCompleted
+
+	_, err := examService.AutoSubmitSession(session)
+
+	assert.ErrorIs(t, err, repository.ErrExamAlreadySubmitted)
+	mockExamRepo.AssertNotCalled(t, "AutoSubmitExamAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_AutoSubmitSession_UnreadableAnswers(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	mockExamRepo.On("GetSessionAnswers", "expired_session").Return(nil, errors.New("database unavailable"))
+
+	// The session stays in progress for the next run instead of being scored as unanswered
+	_, err := examService.AutoSubmitSession(createExpiredSession())
+
+	assert.Error(t, err)
+	mockExamRepo.AssertNotCalled(t, "AutoSubmitExamAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamCleanupService_AutoSubmitsBeforeAbandoning(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	cleanupService := service.NewExamCleanupService(mockExamRepo, examService, service.CleanupConfig{
+		CleanupInterval: time.Hour,
+		GracePeriod:     2 * time.Minute,
+		AbandonAfter:    30 * time.Minute,
+		BatchSize:       50,
+	})
+
+	mockExamRepo.On("GetExpiredActiveSessions", mock.AnythingOfType("time.Time"), 120, 50).
+		Return([]repository.SessionWithExamData{createExpiredSession()}, nil)
+	mockExamRepo.On("GetSessionAnswers", "expired_session").Return(map[uint]string{1: "a", 2: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "expired_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "expired_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "expired_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("AutoSubmitExamAttempt", uint(42), 2.0, true, mock.AnythingOfType("string"), 2).Return(nil)
+	mockExamRepo.On("MarkExpiredSessionsAsAbandoned", mock.AnythingOfType("time.Time"), 1920).Return(int64(0), nil)
+
+	// A cancelled context runs the initial cleanup pass only
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = cleanupService.Start(ctx)
+
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_AutoSubmitSession_NegativeMarkingDecidesPass(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	session := createExpiredSession()
+	session.Exam.ScoringPolicy = stringPtr(`{"negative_marking": {"enabled": true, "sba_penalty": 0.25}}`)
+
+	// One right and one wrong answer: 1 raw point, 0.75 net, below the passing score of 1
+	mockExamRepo.On("GetSessionAnswers", "expired_session").Return(map[uint]string{1: "a", 2: "a"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "expired_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "expired_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "expired_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("AutoSubmitExamAttempt", uint(42), 0.75, false, mock.AnythingOfType("string"), 1).Return(nil)
+
+	result, err := examService.AutoSubmitSession(session)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.75, result.Score)
+	assert.Equal(t, 1.0, result.RawScore)
+	assert.Equal(t, 0.25, result.PenaltyPoints)
+	assert.False(t, result.Passed)
+
+	var stored map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(mockExamRepo.Calls[4].Arguments.String(3)), &stored))
+	answers := stored["answers"].([]interface{})
+	assert.Equal(t, -0.25, answers[1].(map[string]interface{})["points_earned"])
+	assert.Equal(t, 0.25, stored["score_breakdown"].(map[string]interface{})["penalty_points"])
+	mockExamRepo.AssertExpectations(t)
+}
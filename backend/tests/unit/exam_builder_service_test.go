@@ -0,0 +1,162 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExamAdminRepository mocks the ExamAdminRepository interface
+type MockExamAdminRepository struct {
+	mock.Mock
+}
+
+func (m *MockExamAdminRepository) GetExamByID(id uint) (*models.Exam, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Exam), args.Error(1)
+}
+
+func (m *MockExamAdminRepository) SaveExamQuestions(exam *models.Exam, questionIDs []uint) error {
+	args := m.Called(exam, questionIDs)
+	return args.Error(0)
+}
+
+// buildBankQuestions creates count questions of the given difficulty starting at firstID
+func buildBankQuestions(firstID uint, count int, difficulty models.DifficultyLevel, usage int) []models.Question {
+	questions := make([]models.Question, count)
+	for i := range questions {
+		id := firstID + uint(i)
+		questions[i] = models.Question{
+			ID:              id,
+			SystemID:        1,
+			System:          models.System{ID: 1, SubjectID: 2},
+			QuestionText:    fmt.Sprintf("Question %d", id),
+			QuestionType:    models.QuestionTypeSBA,
+			DifficultyLevel: difficulty,
+			Options:         `{"a":{"text":"A","is_correct":true},"b":{"text":"B","is_correct":false}}`,
+			UsageCount:      usage,
+			IsActive:        true,
+		}
+	}
+	return questions
+}
+
+func TestExamBuilderService_BuildExam_DifficultyMix(t *testing.T) {
+	mockExamRepo := new(MockExamAdminRepository)
+	mockQuestionRepo := new(MockQuestionRepository)
+	builder := service.NewExamBuilderService(mockExamRepo, mockQuestionRepo)
+
+	bank := append(buildBankQuestions(1, 10, models.DifficultyEasy, 0), buildBankQuestions(11, 10, models.DifficultyHard, 0)...)
+
+	mockExamRepo.On("GetExamByID", uint(5)).Return(&models.Exam{ID: 5}, nil)
+	mockQuestionRepo.On("GetCandidateQuestions", repository.QuestionCandidateCriteria{SystemID: 1}).Return(bank, nil)
+	mockExamRepo.On("SaveExamQuestions", mock.AnythingOfType("*models.Exam"), mock.AnythingOfType("[]uint")).Return(nil)
+
+	result, err := builder.BuildExam(5, dto.ExamBlueprintRequest{
+		Sections:          []dto.ExamBlueprintSection{{SystemID: 1, Count: 5}},
+		DifficultyMix:     map[models.DifficultyLevel]int{models.DifficultyEasy: 60, models.DifficultyHard: 40},
+		PointsPerQuestion: 2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.TotalQuestions)
+	assert.Equal(t, 10.0, result.TotalMarks)
+	assert.Equal(t, 3, result.DifficultyBreakdown[models.DifficultyEasy])
+	assert.Equal(t, 2, result.DifficultyBreakdown[models.DifficultyHard])
+	assert.Len(t, result.QuestionIDs, 5)
+
+	saved := mockExamRepo.Calls[1].Arguments.Get(0).(*models.Exam)
+	assert.Equal(t, 5, saved.TotalQuestions)
+	assert.Equal(t, 10.0, saved.TotalMarks)
+
+	var snapshot []models.ExamQuestion
+	assert.NoError(t, json.Unmarshal([]byte(saved.QuestionsData), &snapshot))
+	assert.Len(t, snapshot, 5)
+	for _, question := range snapshot {
+		assert.Equal(t, 2, question.Points)
+		assert.Contains(t, question.Options, "a")
+		assert.Equal(t, uint(1), question.SystemID)
+		assert.Equal(t, uint(2), question.SubjectID)
+	}
+
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamBuilderService_BuildExam_AvoidOverused(t *testing.T) {
+	mockExamRepo := new(MockExamAdminRepository)
+	mockQuestionRepo := new(MockQuestionRepository)
+	builder := service.NewExamBuilderService(mockExamRepo, mockQuestionRepo)
+
+	fresh := buildBankQuestions(1, 3, models.DifficultyMedium, 2)
+	overused := buildBankQuestions(10, 5, models.DifficultyMedium, 80)
+
+	mockExamRepo.On("GetExamByID", uint(5)).Return(&models.Exam{ID: 5}, nil)
+	mockQuestionRepo.On("GetCandidateQuestions", mock.Anything).Return(append(fresh, overused...), nil)
+	mockExamRepo.On("SaveExamQuestions", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := builder.BuildExam(5, dto.ExamBlueprintRequest{
+		Sections:      []dto.ExamBlueprintSection{{SubjectID: 2, Count: 3}},
+		AvoidOverused: true,
+	})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uint{1, 2, 3}, result.QuestionIDs)
+
+	// Asking for more than the non-overused pool fails
+	_, err = builder.BuildExam(5, dto.ExamBlueprintRequest{
+		Sections:      []dto.ExamBlueprintSection{{SubjectID: 2, Count: 4}},
+		AvoidOverused: true,
+	})
+	assert.True(t, apperrors.IsInsufficientQuestionsError(err))
+}
+
+func TestExamBuilderService_BuildExam_SectionsDoNotRepeatQuestions(t *testing.T) {
+	mockExamRepo := new(MockExamAdminRepository)
+	mockQuestionRepo := new(MockQuestionRepository)
+	builder := service.NewExamBuilderService(mockExamRepo, mockQuestionRepo)
+
+	bank := buildBankQuestions(1, 4, models.DifficultyMedium, 0)
+
+	mockExamRepo.On("GetExamByID", uint(5)).Return(&models.Exam{ID: 5}, nil)
+	mockQuestionRepo.On("GetCandidateQuestions", mock.Anything).Return(bank, nil)
+	mockExamRepo.On("SaveExamQuestions", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := builder.BuildExam(5, dto.ExamBlueprintRequest{
+		Sections: []dto.ExamBlueprintSection{{SubjectID: 1, Count: 2}, {SystemID: 1, Count: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uint{1, 2, 3, 4}, result.QuestionIDs)
+}
+
+func TestExamBuilderService_BuildExam_Validation(t *testing.T) {
+	mockExamRepo := new(MockExamAdminRepository)
+	mockQuestionRepo := new(MockQuestionRepository)
+	builder := service.NewExamBuilderService(mockExamRepo, mockQuestionRepo)
+
+	_, err := builder.BuildExam(5, dto.ExamBlueprintRequest{
+		Sections:      []dto.ExamBlueprintSection{{SystemID: 1, Count: 5}},
+		DifficultyMix: map[models.DifficultyLevel]int{models.DifficultyEasy: 50, models.DifficultyHard: 30},
+	})
+	assert.True(t, apperrors.IsBlueprintValidationError(err))
+
+	mockExamRepo.On("GetExamByID", uint(6)).Return(&models.Exam{ID: 6, AttemptCount: 3}, nil)
+	_, err = builder.BuildExam(6, dto.ExamBlueprintRequest{
+		Sections: []dto.ExamBlueprintSection{{SystemID: 1, Count: 5}},
+	})
+	assert.True(t, apperrors.IsExamLockedError(err))
+
+	mockQuestionRepo.AssertNotCalled(t, "GetCandidateQuestions", mock.Anything)
+}
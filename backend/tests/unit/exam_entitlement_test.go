@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// createPaidEnrollment returns an active, paid enrollment that has not expired
+func createPaidEnrollment(userID, packageID uint) *models.UserPackageEnrollment {
+	expiresAt := time.Now().AddDate(0, 3, 0)
+	return &models.UserPackageEnrollment{
+		ID:                  30,
+		UserID:              userID,
+		PackageID:           packageID,
+		EnrollmentType:      models.EnrollmentTypeFull,
+		EnrolledPackageType: models.PackageTypePremium,
+		EnrolledPrice:       1500,
+		PaymentStatus:       models.PaymentStatusPaid,
+		ExpiresAt:           &expiresAt,
+		IsActive:            true,
+	}
+}
+
+func TestUserPackageEnrollment_AccessDenial(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		modify func(e *models.UserPackageEnrollment)
+		want   models.AccessDenial
+	}{
+		{"paid", func(e *models.UserPackageEnrollment) {}, models.AccessGranted},
+		{"payment pending", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusPending }, models.AccessDeniedPaymentRequired},
+		{"payment failed", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusFailed }, models.AccessDeniedPaymentRequired},
+		{"paid but expired", func(e *models.UserPackageEnrollment) { e.ExpiresAt = &past }, models.AccessDeniedExpired},
+		{"deactivated", func(e *models.UserPackageEnrollment) { e.IsActive = false }, models.AccessDeniedInactive},
+		{"refunded", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusRefunded }, models.AccessDeniedRefunded},
+		{"upgraded from trial", func(e *models.UserPackageEnrollment) { e.PaymentStatus = models.PaymentStatusUpgraded }, models.AccessGranted},
+		{"free", func(e *models.UserPackageEnrollment) {
+			e.PaymentStatus = models.PaymentStatusFree
+			e.EnrolledPrice = 0
+		}, models.AccessGranted},
+		{"free but expired", func(e *models.UserPackageEnrollment) {
+			e.PaymentStatus = models.PaymentStatusFree
+			e.EnrolledPrice = 0
+			e.ExpiresAt = &past
+		}, models.AccessDeniedExpired},
+		{"trial running", func(e *models.UserPackageEnrollment) {
+			e.EnrollmentType = models.EnrollmentTypeTrial
+			e.PaymentStatus = models.PaymentStatusPending
+			e.TrialExpiresAt = &future
+		}, models.AccessGranted},
+		{"trial over", func(e *models.UserPackageEnrollment) {
+			e.EnrollmentType = models.EnrollmentTypeTrial
+			e.PaymentStatus = models.PaymentStatusPending
+			e.TrialExpiresAt = &past
+		}, models.AccessDeniedTrialExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollment := createPaidEnrollment(1, 3)
+			tt.modify(enrollment)
+
+			assert.Equal(t, tt.want, enrollment.AccessDenial())
+			assert.Equal(t, tt.want == models.AccessGranted, enrollment.CanAccessContent())
+		})
+	}
+}
+
+func TestExamService_StartExam_RequiresPayment(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+	mockExamRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(1)).Return(createRetakeTestPackageData(exam, nil), nil)
+
+	pending := createPaidEnrollment(1, 3)
+	pending.PaymentStatus = models.PaymentStatusPending
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(pending, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, &MockExamMapper{})
+	_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	var paymentErr *apperrors.PaymentRequiredError
+	if assert.True(t, errors.As(err, &paymentErr)) {
+		assert.Equal(t, string(models.AccessDeniedPaymentRequired), paymentErr.Reason)
+		assert.Equal(t, uint(3), paymentErr.PackageID)
+		assert.Equal(t, uint(30), paymentErr.EnrollmentID)
+	}
+	mockExamRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_StartExam_NotEnrolled(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+	mockExamRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(1)).Return(createRetakeTestPackageData(exam, nil), nil)
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(nil, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, &MockExamMapper{})
+	_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.ErrorIs(t, err, repository.ErrNotEnrolledInPackage)
+}
+
+func TestExamService_GetSession_DeniedAfterRefund(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	session := createTutorSession(models.ExamTypeReview, models.ExamModeTutor)
+	session.Attempt.PackageID = 3
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+
+	refunded := createPaidEnrollment(5, 3)
+	refunded.PaymentStatus = models.PaymentStatusRefunded
+	refunded.IsActive = false
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(5), uint(3)).Return(refunded, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, &MockExamMapper{})
+	_, err := examService.GetSession("tutor_session", 5)
+
+	var deniedErr *apperrors.EntitlementDeniedError
+	if assert.True(t, errors.As(err, &deniedErr)) {
+		assert.Equal(t, string(models.AccessDeniedInactive), deniedErr.Reason)
+	}
+	mockExamRepo.AssertNotCalled(t, "GetSessionAnswers", mock.Anything)
+}
+
+func TestExamService_GetExamResultsBySession_ExpiredPaidEnrollmentNeedsRenewal(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	attempt := finishedAttempt(10, 1, 70)
+	attempt.UserID = 1
+	attempt.PackageID = 3
+	mockExamRepo.On("GetAttemptBySessionAndUser", "session-1", uint(1)).Return(&attempt, nil)
+
+	expired := createPaidEnrollment(1, 3)
+	past := time.Now().AddDate(0, 0, -1)
+	expired.ExpiresAt = &past
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(expired, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, &MockExamMapper{})
+	_, err := examService.GetExamResultsBySession("session-1", 1)
+
+	var paymentErr *apperrors.PaymentRequiredError
+	if assert.True(t, errors.As(err, &paymentErr)) {
+		assert.Equal(t, string(models.AccessDeniedExpired), paymentErr.Reason)
+	}
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func TestExamService_StartExam_EnforcesSchedule(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-2 * time.Hour)
+	recent := now.Add(-10 * time.Minute)
+	future := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name   string
+		status models.ExamStatus
+		start  *time.Time
+		end    *time.Time
+		check  func(error) bool
+	}{
+		{"draft exam", models.ExamStatusDraft, nil, nil, apperrors.IsExamNotAvailableError},
+		{"completed exam", models.ExamStatusCompleted, nil, nil, apperrors.IsExamNotAvailableError},
+		{"before it opens", models.ExamStatusScheduled, &future, nil, apperrors.IsExamNotOpenError},
+		{"after it closes", models.ExamStatusActive, &past, &recent, apperrors.IsExamClosedError},
+		{"after its duration without an end date", models.ExamStatusActive, &past, nil, apperrors.IsExamClosedError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exam := createRetakeTestExam(0)
+			exam.Status = tt.status
+			exam.ScheduledStartDate = tt.start
+			exam.ScheduledEndDate = tt.end
+			mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, nil)
+
+			examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+			_, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+			assert.True(t, tt.check(err), "unexpected error: %v", err)
+			mockExamRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExamService_StartExam_ResumesAfterClose(t *testing.T) {
+	exam := createRetakeTestExam(1)
+	closed := time.Now().Add(-time.Minute)
+	opened := closed.Add(-time.Hour)
+	exam.Status = models.ExamStatusActive
+	exam.ScheduledStartDate = &opened
+	exam.ScheduledEndDate = &closed
+
+	sessionID := "live-session"
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, []models.UserExamAttempt{
+		{ID: 11, AttemptNumber: 1, Status: models.AttemptStatusStarted, SessionID: &sessionID},
+	})
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.StartExam("cardiology", exam.Slug, 1, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, result.SessionID)
+}
+
+func TestExam_SessionTimeLimitSeconds(t *testing.T) {
+	opened := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	closes := opened.Add(time.Hour)
+	exam := models.Exam{DurationMinutes: 30, ScheduledStartDate: &opened, ScheduledEndDate: &closes}
+
+	// Early starters get the full duration, late starters only the time left
+	assert.Equal(t, 1800, exam.SessionTimeLimitSeconds(opened.Add(10*time.Minute)))
+	assert.Equal(t, 600, exam.SessionTimeLimitSeconds(closes.Add(-10*time.Minute)))
+
+	// Without an end date the exam closes one duration after it opens
+	exam.ScheduledEndDate = nil
+	assert.Equal(t, 300, exam.SessionTimeLimitSeconds(opened.Add(25*time.Minute)))
+
+	exam.LateStartFullDuration = true
+	assert.Equal(t, 1800, exam.SessionTimeLimitSeconds(opened.Add(25*time.Minute)))
+
+	// Unscheduled exams always get the full duration
+	assert.Equal(t, 1800, (&models.Exam{DurationMinutes: 30}).SessionTimeLimitSeconds(closes))
+}
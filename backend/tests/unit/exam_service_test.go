@@ -86,6 +86,61 @@ func (m *MockExamRepository) SyncSessionAnswers(sessionID string, answers map[ui
 	return args.Error(0)
 }
 
+func (m *MockExamRepository) GetCheckedAnswers(sessionID string) (map[uint]string, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).(map[uint]string), args.Error(1)
+}
+
+func (m *MockExamRepository) SaveCheckedAnswers(sessionID string, checked map[uint]string) error {
+	args := m.Called(sessionID, checked)
+	return args.Error(0)
+}
+
+func (m *MockExamRepository) GetFlaggedQuestions(sessionID string) ([]uint, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockExamRepository) SyncFlaggedQuestions(sessionID string, questionIDs []uint) error {
+	args := m.Called(sessionID, questionIDs)
+	return args.Error(0)
+}
+
+func (m *MockExamRepository) AppendAnswerChanges(sessionID string, changes []models.SessionAnswerChange) error {
+	args := m.Called(sessionID, changes)
+	return args.Error(0)
+}
+
+func (m *MockExamRepository) GetAnswerChanges(sessionID string) ([]models.SessionAnswerChange, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).([]models.SessionAnswerChange), args.Error(1)
+}
+
+func (m *MockExamRepository) GetQuestionTimes(sessionID string) (map[uint]int, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+
+func (m *MockExamRepository) SyncQuestionTimes(sessionID string, times map[uint]int) error {
+	args := m.Called(sessionID, times)
+	return args.Error(0)
+}
+
+func (m *MockExamRepository) GetAnswerVersions(sessionID string) (map[uint]time.Time, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).(map[uint]time.Time), args.Error(1)
+}
+
+func (m *MockExamRepository) SyncAnswerVersions(sessionID string, versions map[uint]time.Time) error {
+	args := m.Called(sessionID, versions)
+	return args.Error(0)
+}
+
+// WithSessionLock runs fn against the mock itself, so the calls made under the lock use the same expectations
+func (m *MockExamRepository) WithSessionLock(sessionID string, fn func(repo repository.ExamRepository) error) error {
+	return fn(m)
+}
+
 func (m *MockExamRepository) GetSessionAnswers(sessionID string) (map[uint]string, error) {
 	args := m.Called(sessionID)
 	if args.Get(0) == nil {
@@ -129,8 +184,8 @@ func (m *MockExamRepository) GetUserAttemptForExamInPackage(userID uint, examID
 	return args.Get(0).(*models.UserExamAttempt), args.Error(1)
 }
 
-func (m *MockExamRepository) CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, deviceInfo map[string]string) (*models.UserExamAttempt, error) {
-	args := m.Called(userID, exam, packageID, deviceInfo)
+func (m *MockExamRepository) CreateExamAttemptWithExam(userID uint, exam *models.Exam, packageID uint, attemptNumber int, deviceInfo map[string]string) (*models.UserExamAttempt, error) {
+	args := m.Called(userID, exam, packageID, attemptNumber, deviceInfo)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -147,6 +202,35 @@ func (m *MockExamRepository) MarkExpiredSessionsAsAbandoned(currentTime time.Tim
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockExamRepository) GetExpiredActiveSessions(currentTime time.Time, gracePeriodSeconds int, limit int) ([]repository.SessionWithExamData, error) {
+	args := m.Called(currentTime, gracePeriodSeconds, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.SessionWithExamData), args.Error(1)
+}
+
+func (m *MockExamRepository) AutoSubmitExamAttempt(attemptID uint, score float64, passed bool, answersData string, correctAnswers int) error {
+	args := m.Called(attemptID, score, passed, answersData, correctAnswers)
+	return args.Error(0)
+}
+
+func (m *MockExamRepository) GetLeaderboard(examID, packageID uint, limit int) ([]repository.LeaderboardEntry, int64, error) {
+	args := m.Called(examID, packageID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]repository.LeaderboardEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockExamRepository) GetLeaderboardRank(examID, packageID, userID uint) (*repository.LeaderboardRank, error) {
+	args := m.Called(examID, packageID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.LeaderboardRank), args.Error(1)
+}
+
 // MockExamMapper is a mock implementation of mapper.ExamMapper
 type MockExamMapper struct {
 	mock.Mock
@@ -1325,6 +1409,9 @@ func TestExamService_SubmitExam_Success(t *testing.T) {
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", sessionID).Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", sessionID).Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", sessionID).Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", sessionID).Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", sessionID).Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), 1.0, false, mock.AnythingOfType("string"), 1).Return(nil)
 
 	// Execute
@@ -1417,6 +1504,9 @@ func TestExamService_SubmitExam_SBA_CorrectAnswer_RealStructure(t *testing.T) {
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", "test_session").Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", "test_session").Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "test_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "test_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "test_session").Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), 1.0, false, mock.AnythingOfType("string"), 1).Return(nil)
 
 	// Execute
@@ -1429,7 +1519,7 @@ func TestExamService_SubmitExam_SBA_CorrectAnswer_RealStructure(t *testing.T) {
 	assert.Equal(t, 1, result.CorrectAnswers)
 
 	// Verify the answers data contains correct information
-	call := mockExamRepo.Calls[2] // CompleteExamAttemptWithAnswers call
+	call := mockExamRepo.Calls[5] // CompleteExamAttemptWithAnswers call
 	answersDataJSON := call.Arguments[3].(string)
 
 	var answersData map[string]interface{}
@@ -1491,6 +1581,9 @@ func TestExamService_SubmitExam_TrueFalse_PerfectAnswer_RealStructure(t *testing
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", "test_session").Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", "test_session").Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "test_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "test_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "test_session").Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), 2.0, false, mock.AnythingOfType("string"), 1).Return(nil)
 
 	// Execute
@@ -1503,7 +1596,7 @@ func TestExamService_SubmitExam_TrueFalse_PerfectAnswer_RealStructure(t *testing
 	assert.Equal(t, 1, result.CorrectAnswers)
 
 	// Verify the answers data
-	call := mockExamRepo.Calls[2]
+	call := mockExamRepo.Calls[5]
 	answersDataJSON := call.Arguments[3].(string)
 
 	var answersData map[string]interface{}
@@ -1574,6 +1667,9 @@ func TestExamService_SubmitExam_TrueFalse_PartialAnswer_YourSample(t *testing.T)
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", "test_session").Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", "test_session").Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "test_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "test_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "test_session").Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), expectedPartialPoints, false, mock.AnythingOfType("string"), 0).Return(nil)
 
 	// Execute
@@ -1585,7 +1681,7 @@ func TestExamService_SubmitExam_TrueFalse_PartialAnswer_YourSample(t *testing.T)
 	assert.Equal(t, 0, result.CorrectAnswers) // Not counted as fully correct
 
 	// Verify the answers data
-	call := mockExamRepo.Calls[2]
+	call := mockExamRepo.Calls[5]
 	answersDataJSON := call.Arguments[3].(string)
 
 	var answersData map[string]interface{}
@@ -1649,6 +1745,9 @@ func TestExamService_SubmitExam_TrueFalse_WrongAnswerWithPenalty_YourSample(t *t
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", "test_session").Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", "test_session").Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "test_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "test_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "test_session").Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), expectedPartialPoints, false, mock.AnythingOfType("string"), 0).Return(nil)
 
 	// Execute
@@ -1775,6 +1874,9 @@ func TestExamService_SubmitExam_YourExactExamScenario(t *testing.T) {
 	// Setup expectations
 	mockExamRepo.On("GetActiveSessionByID", "test_session").Return(sessionData, nil)
 	mockExamRepo.On("GetSessionAnswers", "test_session").Return(userAnswers, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "test_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "test_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "test_session").Return(map[uint]int{}, nil)
 	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(1), expectedScore, false, mock.AnythingOfType("string"), expectedCorrectAnswers).Return(nil)
 
 	// Execute
@@ -1788,7 +1890,7 @@ func TestExamService_SubmitExam_YourExactExamScenario(t *testing.T) {
 	assert.Equal(t, 5, result.TotalQuestions)
 
 	// Verify the answers data contains proper structure
-	call := mockExamRepo.Calls[2]
+	call := mockExamRepo.Calls[5]
 	answersDataJSON := call.Arguments[3].(string)
 
 	var answersData map[string]interface{}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func TestExamService_SyncSession_MergesDeltaLastWriterWins(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	older := session.Attempt.StartedAt.Add(time.Minute)
+	newer := session.Attempt.StartedAt.Add(2 * time.Minute)
+
+	// Another tab already changed question 1 after this client did
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "b", 3: "a"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{3}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{1: newer, 3: older}, nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.MatchedBy(func(changes []models.SessionAnswerChange) bool {
+		return len(changes) == 1 && changes[0].QuestionID == 2 && changes[0].To == "b" && changes[0].ChangedAt.Equal(newer)
+	})).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "b", 2: "b", 3: "a"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{2, 3}).Return(nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", map[uint]time.Time{1: newer, 2: newer, 3: older}).Return(nil)
+
+	result, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{
+		{QuestionID: 1, SelectedOption: "a", AnsweredAt: &older},
+		{QuestionID: 2, SelectedOption: "b", Flagged: true, AnsweredAt: &newer},
+	})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertExpectations(t)
+	assert.Equal(t, 1, result.SyncedCount)
+	assert.Equal(t, []uint{1}, result.RejectedQuestionIDs)
+	assert.Equal(t, []dto.UserAnswerResponse{
+		{QuestionID: 1, SelectedOption: "b"},
+		{QuestionID: 2, SelectedOption: "b"},
+		{QuestionID: 3, SelectedOption: "a"},
+	}, result.Answers)
+	assert.Equal(t, []uint{2, 3}, result.FlaggedQuestions)
+
+	lastSyncAt, err := time.Parse(time.RFC3339, result.LastSyncAt)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), lastSyncAt, 5*time.Second)
+}
+
+func TestExamService_SyncSession_StaleRetryChangesNothing(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	older := session.Attempt.StartedAt.Add(time.Minute)
+	newer := session.Attempt.StartedAt.Add(2 * time.Minute)
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{1: newer}, nil)
+
+	// A delayed retry of an earlier sync
+	result, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{
+		{QuestionID: 1, SelectedOption: "a", AnsweredAt: &older},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.SyncedCount)
+	assert.Equal(t, []uint{1}, result.RejectedQuestionIDs)
+	assert.Equal(t, []dto.UserAnswerResponse{{QuestionID: 1, SelectedOption: "b"}}, result.Answers)
+	mockExamRepo.AssertNotCalled(t, "SyncSessionAnswers", mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "AppendAnswerChanges", mock.Anything, mock.Anything)
+}
+
+func TestExamService_UnreadableAnswersFailInsteadOfOverwriting(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(nil, errors.New("database unavailable"))
+
+	_, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{{QuestionID: 1, SelectedOption: "a"}})
+	assert.Error(t, err)
+
+	_, err = examService.SubmitExam("tutor_session", 5)
+	assert.Error(t, err)
+
+	mockExamRepo.AssertNotCalled(t, "SyncSessionAnswers", mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "CompleteExamAttemptWithAnswers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// submittedSessionRepository cannot lock its session, as when the attempt was submitted on another server
+// after a sync was validated
+type submittedSessionRepository struct {
+	*MockExamRepository
+}
+
+func (r submittedSessionRepository) WithSessionLock(sessionID string, fn func(repo repository.ExamRepository) error) error {
+	return repository.ErrAttemptNotFound
+}
+
+func TestExamService_SyncSession_MergesOnlyUnderSessionLock(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(submittedSessionRepository{mockExamRepo}, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+
+	_, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{{QuestionID: 1, SelectedOption: "a"}})
+	assert.ErrorIs(t, err, repository.ErrAttemptNotFound)
+
+	_, err = examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: 1, SelectedOption: "a"})
+	assert.ErrorIs(t, err, repository.ErrAttemptNotFound)
+
+	mockExamRepo.AssertNotCalled(t, "GetSessionAnswers", mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "SaveCheckedAnswers", mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "SyncSessionAnswers", mock.Anything, mock.Anything)
+}
+
+func TestExamService_SubmitExam_FinalizesOnlyUnderSessionLock(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(submittedSessionRepository{mockExamRepo}, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+
+	_, err := examService.SubmitExam("tutor_session", 5)
+	assert.ErrorIs(t, err, repository.ErrExamAlreadySubmitted)
+
+	expired := createExpiredSession()
+	_, err = examService.AutoSubmitSession(expired)
+	assert.ErrorIs(t, err, repository.ErrExamAlreadySubmitted)
+
+	mockExamRepo.AssertNotCalled(t, "GetSessionAnswers", mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "CompleteExamAttemptWithAnswers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "AutoSubmitExamAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,198 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/cache"
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func TestExamService_SyncSession_LogsChangesAndTimes(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	beforeStart := session.Attempt.StartedAt.Add(-time.Hour)
+
+	var changes []models.SessionAnswerChange
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "a", 2: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{2}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{}, nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", mock.AnythingOfType("map[uint]time.Time")).Return(nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.AnythingOfType("[]models.SessionAnswerChange")).
+		Run(func(args mock.Arguments) { changes = args.Get(1).([]models.SessionAnswerChange) }).
+		Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "b"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{}).Return(nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{1: 30}, nil)
+	mockExamRepo.On("SyncQuestionTimes", "tutor_session", map[uint]int{1: 30, 2: 45}).Return(nil)
+
+	// Question 1 is changed with a clock behind the session start, question 2 is cleared
+	_, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{
+		{QuestionID: 1, SelectedOption: "b", AnsweredAt: &beforeStart, TimeSpentSeconds: 20},
+		{QuestionID: 2, TimeSpentSeconds: 45},
+	})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertExpectations(t)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, models.SessionAnswerChange{
+			QuestionID: 1, From: "a", To: "b", ChangedAt: session.Attempt.StartedAt.UTC(), SyncedAt: changes[0].SyncedAt,
+		}, changes[0])
+		assert.Equal(t, uint(2), changes[1].QuestionID)
+		assert.Equal(t, "b", changes[1].From)
+		assert.Empty(t, changes[1].To)
+		assert.Equal(t, changes[1].SyncedAt, changes[1].ChangedAt)
+	}
+}
+
+func TestExamService_SyncSession_NothingChanged(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "a"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{}, nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", mock.AnythingOfType("map[uint]time.Time")).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "a"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{}).Return(nil)
+
+	_, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{{QuestionID: 1, SelectedOption: "a"}})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertNotCalled(t, "AppendAnswerChanges", mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "GetQuestionTimes", mock.Anything)
+}
+
+func TestExamService_SubmitExam_FoldsAnswerHistory(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	firstAnswer := time.Date(2026, 10, 1, 10, 1, 0, 0, time.UTC)
+	secondAnswer := firstAnswer.Add(2 * time.Minute)
+	changes := []models.SessionAnswerChange{
+		{QuestionID: 1, To: "a", ChangedAt: firstAnswer},
+		{QuestionID: 2, To: "b", ChangedAt: firstAnswer.Add(time.Minute)},
+		{QuestionID: 1, From: "a", To: "b", ChangedAt: secondAnswer},
+		{QuestionID: 1, From: "a", To: "b", ChangedAt: secondAnswer}, // Logged again by a retried sync
+	}
+
+	var answersData string
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeMock, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "b", 2: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "tutor_session").Return(changes, nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{1: 40, 2: 15}, nil)
+	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(50), 1.0, true, mock.AnythingOfType("string"), 1).
+		Run(func(args mock.Arguments) { answersData = args.String(3) }).
+		Return(nil)
+
+	_, err := examService.SubmitExam("tutor_session", 5)
+
+	assert.NoError(t, err)
+	var stored struct {
+		Answers []struct {
+			TimeSpentSeconds   int    `json:"time_spent_seconds"`
+			AnsweredAt         string `json:"answered_at"`
+			ChangeCount        int    `json:"change_count"`
+			ChangedFromCorrect bool   `json:"changed_from_correct"`
+			Changes            []struct {
+				From      string `json:"from"`
+				To        string `json:"to"`
+				IsCorrect bool   `json:"is_correct"`
+			} `json:"changes"`
+		} `json:"answers"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(answersData), &stored))
+	if assert.Len(t, stored.Answers, 2) {
+		changed := stored.Answers[0]
+		assert.Equal(t, 40, changed.TimeSpentSeconds)
+		assert.Equal(t, "2026-10-01T10:03:00Z", changed.AnsweredAt)
+		assert.Equal(t, 1, changed.ChangeCount)
+		assert.True(t, changed.ChangedFromCorrect)
+		if assert.Len(t, changed.Changes, 2) {
+			assert.True(t, changed.Changes[0].IsCorrect)
+			assert.Equal(t, "a", changed.Changes[1].From)
+			assert.False(t, changed.Changes[1].IsCorrect)
+		}
+
+		kept := stored.Answers[1]
+		assert.Equal(t, 15, kept.TimeSpentSeconds)
+		assert.Equal(t, 0, kept.ChangeCount)
+		assert.False(t, kept.ChangedFromCorrect)
+	}
+}
+
+func TestQuestionAnalyticsService_UsesTrackedTimes(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	attempt := createAnalyticsTestAttempt(101)
+	attempt.AnswersData = `{
+		"answers": [
+			{"question_id": 11, "question_type": "SBA", "user_answer": ["b"], "correct_answer": "a", "is_correct": false, "max_points": 1,
+			 "time_spent_seconds": 95, "answered_at": "2026-10-01T10:12:00Z", "change_count": 2, "changed_from_correct": true},
+			{"question_id": 12, "question_type": "SBA", "user_answer": ["a"], "correct_answer": "a", "is_correct": true, "max_points": 1}
+		]
+	}`
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(0), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(0), 10, false).Return([]models.UserExamAttempt{attempt}, nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(101), mock.Anything).Return(nil)
+
+	_, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	answers := mockRepo.Calls[2].Arguments.Get(1).([]models.UserQuestionAnswer)
+	if assert.Len(t, answers, 2) {
+		assert.Equal(t, 95, answers[0].TimeSpent)
+		assert.Equal(t, time.Date(2026, 10, 1, 10, 12, 0, 0, time.UTC), answers[0].AnsweredAt.UTC())
+		assert.Equal(t, 2, answers[0].ChangeCount)
+		assert.True(t, answers[0].ChangedFromCorrect)
+
+		// Untracked answers fall back to the even split and the completion time
+		assert.Equal(t, 450, answers[1].TimeSpent)
+		assert.Equal(t, *attempt.CompletedAt, answers[1].AnsweredAt)
+		assert.False(t, answers[1].ChangedFromCorrect)
+	}
+}
+
+func TestMemoryCache_ListAppend(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(1, 100)
+
+	assert.NoError(t, memoryCache.RPush("changes", []string{"a", "b"}, time.Minute))
+	assert.NoError(t, memoryCache.RPush("changes", []string{"c"}, time.Minute))
+
+	values, err := memoryCache.LRange("changes", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+
+	values, err = memoryCache.LRange("changes", -2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, values)
+
+	assert.NoError(t, memoryCache.Delete("changes"))
+	values, err = memoryCache.LRange("changes", 0, -1)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestUserExamAttempt_SessionStateTTL(t *testing.T) {
+	grace := time.Hour
+
+	// A five hour exam started an hour ago keeps its state for the four hours left plus the grace period
+	long := models.UserExamAttempt{StartedAt: time.Now().Add(-time.Hour), TimeLimitSeconds: 5 * 3600}
+	assert.InDelta(t, (5 * time.Hour).Seconds(), long.SessionStateTTL(grace).Seconds(), 5)
+
+	// Past its deadline the state is still kept for the grace period
+	expired := models.UserExamAttempt{StartedAt: time.Now().Add(-3 * time.Hour), TimeLimitSeconds: 3600}
+	assert.Equal(t, grace, expired.SessionStateTTL(grace))
+}
@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func shuffleTestQuestions() []models.ExamQuestion {
+	options := func() map[string]interface{} {
+		return map[string]interface{}{"a": "first", "b": "second"}
+	}
+	return []models.ExamQuestion{
+		{ID: 1, Options: options()},
+		{ID: 2, Options: options()},
+		{ID: 3, Options: options()},
+	}
+}
+
+func TestAttemptLayout_SeededPerAttempt(t *testing.T) {
+	questions := shuffleTestQuestions()
+	attempt := models.UserExamAttempt{ShuffleSeed: 10, ShuffleQuestions: true, ShuffleOptions: true}
+
+	shown := attempt.Layout(questions).Apply(questions)
+	if assert.Len(t, shown, 3) {
+		assert.Equal(t, []uint{3, 1, 2}, []uint{shown[0].ID, shown[1].ID, shown[2].ID})
+		assert.Equal(t, "second", shown[1].Options["a"])
+	}
+
+	// A resumed session sees the same order
+	resumed := models.UserExamAttempt{ShuffleSeed: 10, ShuffleQuestions: true, ShuffleOptions: true}
+	assert.Equal(t, shown, resumed.Layout(questions).Apply(questions))
+
+	// Attempts that do not shuffle keep the exam order and keys
+	unshuffled := models.UserExamAttempt{}
+	assert.Equal(t, questions, unshuffled.Layout(questions).Apply(questions))
+}
+
+func TestAttemptLayout_TranslatesAnswerKeys(t *testing.T) {
+	questions := shuffleTestQuestions()
+	attempt := models.UserExamAttempt{ShuffleSeed: 7, ShuffleOptions: true}
+	layout := attempt.Layout(questions)
+
+	assert.Equal(t, "a", layout.ToCanonical(1, "b"))
+	assert.Equal(t, "b", layout.ToDisplayed(1, "a"))
+	assert.Equal(t, `["b:true","a:false"]`, layout.ToCanonical(1, `["a:true","b:false"]`))
+	assert.Equal(t, `["a:true","b:false"]`, layout.ToDisplayed(1, layout.ToCanonical(1, `["a:true","b:false"]`)))
+
+	// Unknown keys and questions are left alone
+	assert.Equal(t, "z", layout.ToCanonical(1, "z"))
+	assert.Equal(t, "b", layout.ToCanonical(99, "b"))
+}
+
+func TestExamSnapshotShuffle(t *testing.T) {
+	attempt := models.UserExamAttempt{}
+	attempt.SnapshotShuffle(&models.Exam{ShuffleOptions: true})
+	assert.NotZero(t, attempt.ShuffleSeed)
+	assert.True(t, attempt.ShuffleOptions)
+	assert.False(t, attempt.ShuffleQuestions)
+
+	attempt.SnapshotShuffle(&models.Exam{})
+	assert.Zero(t, attempt.ShuffleSeed)
+}
+
+func TestExamService_CheckAnswer_ShuffledOptions(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	// Seed 7 shows the options of both questions swapped
+	session := createTutorSession(models.ExamTypeReview, models.ExamModeTutor)
+	session.Attempt.ShuffleSeed = 7
+	session.Attempt.ShuffleOptions = true
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("SaveCheckedAnswers", "tutor_session", map[uint]string{1: "a"}).Return(nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.MatchedBy(func(changes []models.SessionAnswerChange) bool {
+		return len(changes) == 1 && changes[0].To == "a"
+	})).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "a"}).Return(nil)
+
+	feedback, err := examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: 1, SelectedOption: "b"})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertExpectations(t)
+	assert.True(t, feedback.IsCorrect)
+	assert.Equal(t, "b", feedback.SelectedOption)
+	assert.Equal(t, "b", feedback.CorrectAnswer)
+	assert.Equal(t, []dto.AnswerFeedbackOption{
+		{Key: "a", Text: "Wrong", IsCorrect: false},
+		{Key: "b", Text: "Right", IsCorrect: true},
+	}, feedback.Options)
+}
+
+func TestExamService_SyncSession_ShuffledOptions(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	session := createTutorSession(models.ExamTypeMock, models.ExamModeTimed)
+	session.Attempt.ShuffleSeed = 7
+	session.Attempt.ShuffleOptions = true
+
+	// The student picks the option shown as "a", stored under the exam's key
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(session, nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{}, nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.AnythingOfType("[]models.SessionAnswerChange")).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{2: "b"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{}).Return(nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", mock.AnythingOfType("map[uint]time.Time")).Return(nil)
+
+	result, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{{QuestionID: 2, SelectedOption: "a"}})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertExpectations(t)
+	assert.Equal(t, []dto.UserAnswerResponse{{QuestionID: 2, SelectedOption: "a"}}, result.Answers)
+}
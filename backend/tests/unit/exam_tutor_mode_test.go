@@ -0,0 +1,210 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+func createTutorSession(examType models.ExamType, mode models.ExamMode) *repository.SessionWithExamData {
+	sessionID := "tutor_session"
+	questionsJSON := `[
+		{"id": 1, "question_text": "Q1", "question_type": "SBA", "points": 1,
+		 "explanation": "A is right", "reference": "Harrison, ch. 3",
+		 "options": {"a": {"text": "Right", "is_correct": true}, "b": {"text": "Wrong", "is_correct": false}}},
+		{"id": 2, "question_text": "Q2", "question_type": "SBA", "points": 1,
+		 "options": {"a": {"text": "Wrong", "is_correct": false}, "b": {"text": "Right", "is_correct": true}}}
+	]`
+
+	return &repository.SessionWithExamData{
+		Attempt: models.UserExamAttempt{
+			ID:               50,
+			UserID:           5,
+			ExamID:           9,
+			Status:           models.AttemptStatusStarted,
+			StartedAt:        time.Now().Add(-5 * time.Minute),
+			TimeLimitSeconds: 1800,
+			SessionID:        &sessionID,
+		},
+		Exam: models.Exam{
+			ID:              9,
+			ExamType:        examType,
+			Mode:            mode,
+			PassingScore:    1,
+			DurationMinutes: 30,
+			QuestionsData:   questionsJSON,
+		},
+	}
+}
+
+func TestExam_SessionMode(t *testing.T) {
+	tests := []struct {
+		examType models.ExamType
+		mode     models.ExamMode
+		expected models.ExamMode
+	}{
+		{models.ExamTypeReview, models.ExamModeTutor, models.ExamModeTutor},
+		{models.ExamTypePractice, models.ExamModeTutor, models.ExamModeTutor},
+		{models.ExamTypeReview, models.ExamModeTimed, models.ExamModeTimed},
+		{models.ExamTypeMock, models.ExamModeTutor, models.ExamModeTimed},
+		{models.ExamTypeFinal, models.ExamModeTutor, models.ExamModeTimed},
+		{models.ExamTypeReview, "", models.ExamModeTimed},
+	}
+
+	for _, tt := range tests {
+		exam := models.Exam{ExamType: tt.examType, Mode: tt.mode}
+		assert.Equal(t, tt.expected, exam.SessionMode(), "%s exam in %q mode", tt.examType, tt.mode)
+	}
+}
+
+func TestExamService_CheckAnswer_RevealsFeedbackAndLocksAnswer(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("SaveCheckedAnswers", "tutor_session", map[uint]string{1: "b"}).Return(nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{2: "b"}, nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.MatchedBy(func(changes []models.SessionAnswerChange) bool {
+		return len(changes) == 1 && changes[0].QuestionID == 1 && changes[0].From == "" && changes[0].To == "b"
+	})).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "b", 2: "b"}).Return(nil)
+
+	feedback, err := examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: 1, SelectedOption: "b"})
+
+	assert.NoError(t, err)
+	assert.False(t, feedback.IsCorrect)
+	assert.Equal(t, "b", feedback.SelectedOption)
+	assert.Equal(t, "a", feedback.CorrectAnswer)
+	assert.Equal(t, "A is right", feedback.Explanation)
+	assert.Equal(t, "Harrison, ch. 3", feedback.Reference)
+	if assert.Len(t, feedback.Options, 2) {
+		assert.True(t, feedback.Options[0].IsCorrect)
+		assert.Equal(t, "Right", feedback.Options[0].Text)
+	}
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_CheckAnswer_LockedAnswer(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypePractice, models.ExamModeTutor), nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{1: "a"}, nil)
+
+	// Checking the same answer again returns its feedback
+	feedback, err := examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: 1, SelectedOption: "a"})
+	assert.NoError(t, err)
+	assert.True(t, feedback.IsCorrect)
+
+	// Changing it is rejected
+	_, err = examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: 1, SelectedOption: "b"})
+	assert.True(t, apperrors.IsAnswerLockedError(err))
+
+	mockExamRepo.AssertNotCalled(t, "SaveCheckedAnswers", mock.Anything, mock.Anything)
+	mockExamRepo.AssertNotCalled(t, "SyncSessionAnswers", mock.Anything, mock.Anything)
+}
+
+func TestExamService_CheckAnswer_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		examType   models.ExamType
+		mode       models.ExamMode
+		questionID uint
+		check      func(error) bool
+	}{
+		{"timed review exam", models.ExamTypeReview, models.ExamModeTimed, 1, apperrors.IsAnswerCheckingUnavailableError},
+		{"mock exam in tutor mode", models.ExamTypeMock, models.ExamModeTutor, 1, apperrors.IsAnswerCheckingUnavailableError},
+		{"question outside the exam", models.ExamTypeReview, models.ExamModeTutor, 99, apperrors.IsQuestionNotInExamError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExamRepo := &MockExamRepository{}
+			examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+			mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(tt.examType, tt.mode), nil)
+
+			_, err := examService.CheckAnswer("tutor_session", 5, dto.CheckAnswerRequest{QuestionID: tt.questionID, SelectedOption: "a"})
+
+			assert.True(t, tt.check(err), "unexpected error: %v", err)
+			mockExamRepo.AssertNotCalled(t, "SaveCheckedAnswers", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExamService_SyncSession_KeepsCheckedAnswers(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{1: "b"}, nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerVersions", "tutor_session").Return(map[uint]time.Time{}, nil)
+	mockExamRepo.On("SyncAnswerVersions", "tutor_session", mock.AnythingOfType("map[uint]time.Time")).Return(nil)
+	mockExamRepo.On("AppendAnswerChanges", "tutor_session", mock.AnythingOfType("[]models.SessionAnswerChange")).Return(nil)
+	mockExamRepo.On("SyncSessionAnswers", "tutor_session", map[uint]string{1: "b", 2: "b"}).Return(nil)
+	mockExamRepo.On("SyncFlaggedQuestions", "tutor_session", []uint{}).Return(nil)
+
+	_, err := examService.SyncSession("tutor_session", 5, []dto.UserAnswerSync{
+		{QuestionID: 1, SelectedOption: "a"},
+		{QuestionID: 2, SelectedOption: "b"},
+	})
+
+	assert.NoError(t, err)
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_SubmitExam_ScoresCheckedAnswers(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{2: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "tutor_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{1: "a"}, nil)
+	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(50), 2.0, true, mock.AnythingOfType("string"), 2).Return(nil)
+
+	result, err := examService.SubmitExam("tutor_session", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.CorrectAnswers)
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_GetSession_RestoresCheckedFeedback(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	mockExamMapper := &MockExamMapper{}
+	mockEnrollmentRepo := &MockEnrollmentRepository{}
+	mockEnrollmentRepo.On("GetPackageEnrollment", uint(5), uint(0)).Return(createPaidEnrollment(5, 0), nil)
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypeReview, models.ExamModeTutor), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "tutor_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("GetCheckedAnswers", "tutor_session").Return(map[uint]string{2: "a"}, nil)
+	mockExamMapper.On("ToExamSessionResponseWithAnswers", mock.Anything, mock.Anything,
+		[]dto.UserAnswerResponse{{QuestionID: 2, SelectedOption: "a"}}).Return(dto.ExamSessionResponse{})
+
+	result, err := examService.GetSession("tutor_session", 5)
+
+	assert.NoError(t, err)
+	if assert.Len(t, result.Session.CheckedAnswers, 1) {
+		assert.Equal(t, uint(2), result.Session.CheckedAnswers[0].QuestionID)
+		assert.False(t, result.Session.CheckedAnswers[0].IsCorrect)
+		assert.Equal(t, "b", result.Session.CheckedAnswers[0].CorrectAnswer)
+	}
+	mockExamMapper.AssertExpectations(t)
+}
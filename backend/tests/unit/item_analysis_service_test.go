@@ -0,0 +1,162 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockItemAnalysisRepository is a mock implementation of ItemAnalysisRepository
+type MockItemAnalysisRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemAnalysisRepository) GetExamItemResponses(examID uint) ([]repository.ItemResponse, error) {
+	args := m.Called(examID)
+	return args.Get(0).([]repository.ItemResponse), args.Error(1)
+}
+
+func (m *MockItemAnalysisRepository) GetQuestionItemResponses(questionID uint) ([]repository.ItemResponse, error) {
+	args := m.Called(questionID)
+	return args.Get(0).([]repository.ItemResponse), args.Error(1)
+}
+
+// createItemResponses builds ten attempts scoring 10%..100%. Question 11 is answered correctly by the
+// five strongest attempts, question 12 only by the two weakest.
+func createItemResponses(examID uint) []repository.ItemResponse {
+	responses := make([]repository.ItemResponse, 0, 20)
+	for i := 1; i <= 10; i++ {
+		attempt := repository.ItemResponse{
+			AttemptID:       uint(i),
+			ExamID:          examID,
+			AttemptScore:    float64(i),
+			AttemptMaxScore: 10,
+			MaxScore:        1,
+		}
+
+		strong := attempt
+		strong.QuestionID = 11
+		strong.QuestionType = models.QuestionTypeSBA
+		strong.CorrectOptions = `["a"]`
+		switch {
+		case i >= 6:
+			strong.IsCorrect = true
+			strong.PartialScore = 1
+			strong.SelectedOptions = `["a"]`
+		case i == 1:
+			strong.IsSkipped = true
+			strong.SelectedOptions = `[]`
+		default:
+			strong.SelectedOptions = `["b"]`
+		}
+
+		weak := attempt
+		weak.QuestionID = 12
+		weak.QuestionType = models.QuestionTypeSBA
+		weak.CorrectOptions = `["c"]`
+		weak.SelectedOptions = `["d"]`
+		if i <= 2 {
+			weak.IsCorrect = true
+			weak.PartialScore = 1
+			weak.SelectedOptions = `["c"]`
+		}
+
+		responses = append(responses, strong, weak)
+	}
+	return responses
+}
+
+func TestItemAnalysisService_GetExamItemAnalysis(t *testing.T) {
+	mockRepo := &MockItemAnalysisRepository{}
+	mockExamRepo := &MockExamAdminRepository{}
+	itemAnalysisService := service.NewItemAnalysisService(mockRepo, mockExamRepo, &MockQuestionRepository{})
+
+	exam := &models.Exam{
+		ID:            4,
+		Title:         "Cardiology Mock",
+		QuestionsData: `[{"id": 12, "question_text": "Weak", "question_type": "SBA"}, {"id": 11, "question_text": "Strong", "question_type": "SBA"}, {"id": 13, "question_text": "Unanswered", "question_type": "SBA"}]`,
+	}
+	mockExamRepo.On("GetExamByID", uint(4)).Return(exam, nil)
+	mockRepo.On("GetExamItemResponses", uint(4)).Return(createItemResponses(4), nil)
+
+	report, err := itemAnalysisService.GetExamItemAnalysis(4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, report.Attempts)
+	assert.Len(t, report.Items, 3)
+
+	// Items follow the exam's question order
+	weak, strong, unanswered := report.Items[0], report.Items[1], report.Items[2]
+	assert.Equal(t, uint(12), weak.QuestionID)
+	assert.Equal(t, "Strong", strong.QuestionText)
+
+	assert.Equal(t, 10, strong.Responses)
+	assert.Equal(t, 5, strong.CorrectCount)
+	assert.Equal(t, 1, strong.SkippedCount)
+	assert.Equal(t, 0.5, *strong.DifficultyIndex)
+	assert.Equal(t, 1.0, *strong.DiscriminationIndex)
+	assert.Equal(t, 0.8704, *strong.PointBiserial)
+	assert.Nil(t, strong.AverageTimeSeconds)
+	assert.Empty(t, strong.Flags)
+
+	assert.Len(t, strong.Distractors, 2)
+	assert.Equal(t, "a", strong.Distractors[0].Option)
+	assert.True(t, strong.Distractors[0].IsCorrect)
+	assert.Equal(t, 5, strong.Distractors[0].Count)
+	assert.Equal(t, 3, strong.Distractors[0].UpperCount)
+	assert.Equal(t, "b", strong.Distractors[1].Option)
+	assert.Equal(t, 0.4, strong.Distractors[1].Proportion)
+	assert.Equal(t, 2, strong.Distractors[1].LowerCount)
+
+	assert.Equal(t, -0.6667, *weak.DiscriminationIndex)
+	assert.Less(t, *weak.PointBiserial, 0.0)
+	assert.Equal(t, []string{service.ItemFlagNegativeDiscrimination}, weak.Flags)
+
+	assert.Equal(t, 0, unanswered.Responses)
+	assert.Nil(t, unanswered.DifficultyIndex)
+}
+
+func TestItemAnalysisService_GetExamItemAnalysis_ExamNotFound(t *testing.T) {
+	mockExamRepo := &MockExamAdminRepository{}
+	itemAnalysisService := service.NewItemAnalysisService(&MockItemAnalysisRepository{}, mockExamRepo, &MockQuestionRepository{})
+
+	mockExamRepo.On("GetExamByID", uint(99)).Return(nil, repository.ErrExamNotFound)
+
+	report, err := itemAnalysisService.GetExamItemAnalysis(99)
+
+	assert.Nil(t, report)
+	assert.ErrorIs(t, err, repository.ErrExamNotFound)
+}
+
+func TestItemAnalysisService_GetQuestionItemAnalysis(t *testing.T) {
+	mockRepo := &MockItemAnalysisRepository{}
+	mockQuestionRepo := &MockQuestionRepository{}
+	itemAnalysisService := service.NewItemAnalysisService(mockRepo, &MockExamAdminRepository{}, mockQuestionRepo)
+
+	var responses []repository.ItemResponse
+	for _, examID := range []uint{8, 3} {
+		for _, response := range createItemResponses(examID) {
+			if response.QuestionID == 11 {
+				responses = append(responses, response)
+			}
+		}
+	}
+	mockQuestionRepo.On("GetQuestionByID", uint(11)).Return(&models.Question{ID: 11, QuestionText: "Strong", QuestionType: models.QuestionTypeSBA}, nil)
+	mockRepo.On("GetQuestionItemResponses", uint(11)).Return(responses, nil)
+
+	report, err := itemAnalysisService.GetQuestionItemAnalysis(11)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SBA", report.QuestionType)
+	assert.Equal(t, 20, report.Overall.Responses)
+	assert.Equal(t, 0.5, *report.Overall.DifficultyIndex)
+	assert.Len(t, report.ByExam, 2)
+	assert.Equal(t, uint(3), report.ByExam[0].ExamID)
+	assert.Equal(t, 10, report.ByExam[1].Responses)
+	assert.Equal(t, 1.0, *report.ByExam[1].DiscriminationIndex)
+}
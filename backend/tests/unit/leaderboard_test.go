@@ -0,0 +1,167 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/cache"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// setupLeaderboardCache creates a memory cache holding a leaderboard for exam 7 in package 3,
+// seeded with the given users at the lowest ranking score so recorded attempts replace them
+func setupLeaderboardCache(t *testing.T, userIDs ...string) *cache.MemoryCache {
+	memoryCache := cache.NewMemoryCache(0, 0)
+	t.Cleanup(func() { memoryCache.Close() })
+
+	members := make([]cache.SortedSetMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, cache.SortedSetMember{Member: userID, Score: 0})
+	}
+	assert.NoError(t, memoryCache.ZReplace("leaderboard:package:3:exam:7", members, time.Hour))
+	return memoryCache
+}
+
+func leaderboardAttempt(userID uint, score float64, timeSpent int) models.UserExamAttempt {
+	return models.UserExamAttempt{
+		UserID:          userID,
+		ExamID:          7,
+		PackageID:       3,
+		Status:          models.AttemptStatusCompleted,
+		Score:           float64Ptr(score),
+		ActualTimeSpent: timeSpent,
+	}
+}
+
+func TestLeaderboardRepository_GetUserRank_BreaksTiesOnTimeSpent(t *testing.T) {
+	memoryCache := setupLeaderboardCache(t, "1", "2", "3", "4")
+	leaderboardRepo := repository.NewLeaderboardRepository(nil, memoryCache)
+
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(1, 80, 1200)))
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(2, 80, 900)))
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(3, 95, 1500)))
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(4, 40, 600)))
+
+	rank, err := leaderboardRepo.GetUserRank(7, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rank.Rank, "equal scores rank the faster attempt higher")
+	assert.Equal(t, int64(4), rank.TotalParticipants)
+	assert.Equal(t, 62.5, rank.Percentile)
+	assert.Equal(t, 80.0, rank.Score)
+	assert.Equal(t, 900, rank.TimeSpent)
+
+	rank, err = leaderboardRepo.GetUserRank(7, 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), rank.Rank)
+	assert.Equal(t, 37.5, rank.Percentile)
+}
+
+func TestLeaderboardRepository_RecordAttempt_KeepsBestAttempt(t *testing.T) {
+	memoryCache := setupLeaderboardCache(t, "1")
+	leaderboardRepo := repository.NewLeaderboardRepository(nil, memoryCache)
+
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(1, 90, 1000)))
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(1, 70, 500)))
+
+	rank, err := leaderboardRepo.GetUserRank(7, 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 90.0, rank.Score)
+	assert.Equal(t, 1000, rank.TimeSpent)
+	assert.Equal(t, int64(1), rank.TotalParticipants)
+}
+
+func TestLeaderboardRepository_GetUserRank_NotRanked(t *testing.T) {
+	memoryCache := setupLeaderboardCache(t, "1")
+	leaderboardRepo := repository.NewLeaderboardRepository(nil, memoryCache)
+
+	rank, err := leaderboardRepo.GetUserRank(7, 3, 99)
+	assert.Nil(t, rank)
+	assert.Equal(t, repository.ErrNotRanked, err)
+}
+
+func TestLeaderboardRepository_EmptyLeaderboardIsCached(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(0, 0)
+	t.Cleanup(func() { memoryCache.Close() })
+	assert.NoError(t, memoryCache.Set("leaderboard:package:3:exam:7:empty", true, time.Minute))
+
+	// No database: the read must be answered from the cached empty leaderboard
+	leaderboardRepo := repository.NewLeaderboardRepository(nil, memoryCache)
+	entries, total, err := leaderboardRepo.GetTopEntries(7, 3, 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestLeaderboardRepository_RecordAttempt_ResetsEmptyLeaderboard(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(0, 0)
+	t.Cleanup(func() { memoryCache.Close() })
+	assert.NoError(t, memoryCache.Set("leaderboard:package:3:exam:7:empty", true, time.Minute))
+
+	leaderboardRepo := repository.NewLeaderboardRepository(nil, memoryCache)
+	assert.NoError(t, leaderboardRepo.RecordAttempt(leaderboardAttempt(1, 90, 1000)))
+
+	assert.False(t, memoryCache.Exists("leaderboard:package:3:exam:7:empty"), "the next read rebuilds with the new attempt")
+}
+
+func TestExamService_GetLeaderboard_RejectsDailyExam(t *testing.T) {
+	exam := createRetakeTestExam(1)
+	exam.ExamType = models.ExamTypeDaily
+	mockExamRepo := &MockExamRepository{}
+	mockExamRepo.On("GetExamBySlug", exam.Slug).Return(exam, nil)
+
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+	_, err := examService.GetLeaderboard("cardiology", exam.Slug, 1, 10)
+
+	assert.True(t, apperrors.IsLeaderboardUnavailableError(err))
+	mockExamRepo.AssertNotCalled(t, "GetLeaderboard")
+}
+
+func TestExamService_GetLeaderboard_ReturnsTopEntriesAndCurrentUser(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, nil)
+
+	entries := []repository.LeaderboardEntry{
+		{Rank: 1, UserID: 5, UserName: "Rahim", Score: 95, TimeSpent: 1500},
+		{Rank: 2, UserID: 1, UserName: "Karim", Score: 80, TimeSpent: 900},
+	}
+	mockExamRepo.On("GetLeaderboard", uint(7), uint(3), 100).Return(entries, int64(40), nil)
+	mockExamRepo.On("GetLeaderboardRank", uint(7), uint(3), uint(1)).Return(&repository.LeaderboardRank{
+		Rank: 2, TotalParticipants: 40, Percentile: 96.25, Score: 80, TimeSpent: 900,
+	}, nil)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.GetLeaderboard("cardiology", exam.Slug, 1, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40), result.TotalParticipants)
+	assert.Len(t, result.Entries, 2)
+	assert.False(t, result.Entries[0].IsCurrentUser)
+	assert.True(t, result.Entries[1].IsCurrentUser)
+	assert.Equal(t, "Karim", result.Entries[1].Name)
+	if assert.NotNil(t, result.CurrentUser) {
+		assert.Equal(t, int64(2), result.CurrentUser.Rank)
+		assert.Equal(t, 96.25, result.CurrentUser.Percentile)
+	}
+	mockExamRepo.AssertExpectations(t)
+}
+
+func TestExamService_GetLeaderboard_UnrankedUser(t *testing.T) {
+	exam := createRetakeTestExam(3)
+	mockExamRepo, mockEnrollmentRepo, mockExamMapper := setupStartExamMocks(exam, nil)
+
+	mockExamRepo.On("GetLeaderboard", uint(7), uint(3), 10).Return([]repository.LeaderboardEntry{}, int64(0), nil)
+	mockExamRepo.On("GetLeaderboardRank", uint(7), uint(3), uint(1)).Return(nil, repository.ErrNotRanked)
+
+	examService := service.NewExamService(mockExamRepo, mockEnrollmentRepo, mockExamMapper)
+	result, err := examService.GetLeaderboard("cardiology", exam.Slug, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.CurrentUser)
+	assert.Empty(t, result.Entries)
+}
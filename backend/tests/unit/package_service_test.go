@@ -75,6 +75,52 @@ func (m *MockCache) Close() error {
 	return args.Error(0)
 }
 
+func (m *MockCache) ZReplace(key string, members []cache.SortedSetMember, ttl time.Duration) error {
+	args := m.Called(key, members, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCache) ZAddIfExists(key string, member cache.SortedSetMember) (bool, error) {
+	args := m.Called(key, member)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCache) ZScore(key string, member string) (float64, error) {
+	args := m.Called(key, member)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCache) ZCard(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCache) ZCount(key string, min, max string) (int64, error) {
+	args := m.Called(key, min, max)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCache) ZRevRangeWithScores(key string, start, stop int64) ([]cache.SortedSetMember, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]cache.SortedSetMember), args.Error(1)
+}
+
+func (m *MockCache) RPush(key string, values []string, ttl time.Duration) error {
+	args := m.Called(key, values, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCache) LRange(key string, start, stop int64) ([]string, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // Helper functions to create test data
 func createTestPackage() models.Package {
 	now := time.Now()
@@ -0,0 +1,363 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockPaymentRepository is a mock implementation of PaymentRepository
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) GetEnrollmentForCheckout(enrollmentID uint) (*models.UserPackageEnrollment, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPackageEnrollment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetRenewalForCheckout(renewalID uint) (*models.EnrollmentRenewal, error) {
+	args := m.Called(renewalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentRenewal), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CreateTransaction(transaction *models.PaymentTransaction) error {
+	args := m.Called(transaction)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) SetProviderReference(transactionID string, reference string) error {
+	args := m.Called(transactionID, reference)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetTransaction(transactionID string) (*models.PaymentTransaction, error) {
+	args := m.Called(transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentTransaction), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetTransactionByProviderReference(provider, reference string) (*models.PaymentTransaction, error) {
+	args := m.Called(provider, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentTransaction), args.Error(1)
+}
+
+func (m *MockPaymentRepository) RecordVerification(transactionID string, gatewayData string) error {
+	args := m.Called(transactionID, gatewayData)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) SettleTransaction(transactionID string, settlement repository.PaymentSettlement) (*models.PaymentTransaction, bool, error) {
+	args := m.Called(transactionID, settlement)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.PaymentTransaction), args.Bool(1), args.Error(2)
+}
+
+func (m *MockPaymentRepository) GetLatestPaidTransaction(enrollmentID uint) (*models.PaymentTransaction, error) {
+	args := m.Called(enrollmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaymentTransaction), args.Error(1)
+}
+
+// ReserveRefund also accepts a function in place of its return values, for tests that keep their own ledger
+func (m *MockPaymentRepository) ReserveRefund(refund *models.PaymentRefund, amount *float64) (float64, error) {
+	args := m.Called(refund, amount)
+	if reserve, ok := args.Get(0).(func(*models.PaymentRefund, *float64) (float64, error)); ok {
+		return reserve(refund, amount)
+	}
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockPaymentRepository) SettleRefund(refund *models.PaymentRefund) (bool, error) {
+	args := m.Called(refund)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPaymentRepository) ReleaseRefund(refundID uint) error {
+	args := m.Called(refundID)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetPendingRefunds(limit int) ([]models.PaymentRefund, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.PaymentRefund), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetRefundTotals(transactionID string) (*repository.RefundTotals, error) {
+	args := m.Called(transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.RefundTotals), args.Error(1)
+}
+
+// createPendingEnrollment returns an active paid-package enrollment that has not been paid yet
+func createPendingEnrollment(userID uint) *models.UserPackageEnrollment {
+	return &models.UserPackageEnrollment{
+		ID:            40,
+		UserID:        userID,
+		PackageID:     4,
+		EnrolledPrice: 1500,
+		PaymentStatus: models.PaymentStatusPending,
+		IsActive:      true,
+		User:          models.User{ID: userID, Name: "Student", MSISDN: "01700000000"},
+		Package:       models.Package{ID: 4, Name: "FCPS Part 1"},
+	}
+}
+
+// createInitiatedTransaction returns a fake gateway transaction awaiting verification
+func createInitiatedTransaction(transactionID string, amount float64) *models.PaymentTransaction {
+	reference := "FAKE-" + transactionID
+	return &models.PaymentTransaction{
+		ID:                1,
+		TransactionID:     transactionID,
+		EnrollmentID:      40,
+		UserID:            5,
+		Provider:          payment.FakeProviderName,
+		ProviderReference: &reference,
+		Amount:            amount,
+		Currency:          "BDT",
+		Status:            models.PaymentTransactionInitiated,
+	}
+}
+
+func newTestPaymentService(repo *MockPaymentRepository, providers ...payment.Provider) service.PaymentService {
+	return service.NewPaymentService(repo, payment.NewRegistry(providers...), service.PaymentConfig{
+		CallbackBaseURL: "https://api.medecole.test/",
+	})
+}
+
+func TestPaymentService_InitiateCheckout(t *testing.T) {
+	mockRepo := new(MockPaymentRepository)
+	paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+
+	mockRepo.On("GetEnrollmentForCheckout", uint(40)).Return(createPendingEnrollment(5), nil)
+	mockRepo.On("CreateTransaction", mock.MatchedBy(func(transaction *models.PaymentTransaction) bool {
+		return transaction.EnrollmentID == 40 && transaction.UserID == 5 && transaction.Amount == 1500 &&
+			transaction.Currency == "BDT" && transaction.Provider == payment.FakeProviderName &&
+			transaction.Status == models.PaymentTransactionInitiated
+	})).Return(nil)
+	mockRepo.On("SetProviderReference", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+	checkout, err := paymentService.InitiateCheckout(context.Background(), 5, dto.CheckoutRequest{EnrollmentID: 40, Provider: "fake"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1500.0, checkout.Amount)
+	assert.Equal(t, "BDT", checkout.Currency)
+	assert.Regexp(t, `^MED[0-9A-F]{32}$`, checkout.TransactionID)
+
+	redirect, err := url.Parse(checkout.RedirectURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/payments/callback/fake", redirect.Path)
+	assert.Equal(t, checkout.TransactionID, redirect.Query().Get("tran_id"))
+	mockRepo.AssertCalled(t, "SetProviderReference", checkout.TransactionID, "FAKE-"+checkout.TransactionID)
+}
+
+func TestPaymentService_InitiateCheckout_Rejected(t *testing.T) {
+	t.Run("unknown provider", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+
+		_, err := paymentService.InitiateCheckout(context.Background(), 5, dto.CheckoutRequest{EnrollmentID: 40, Provider: "paypal"})
+
+		assert.True(t, apperrors.IsPaymentProviderUnavailableError(err))
+		mockRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+	})
+
+	t.Run("another student's enrollment", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+		mockRepo.On("GetEnrollmentForCheckout", uint(40)).Return(createPendingEnrollment(6), nil)
+
+		_, err := paymentService.InitiateCheckout(context.Background(), 5, dto.CheckoutRequest{EnrollmentID: 40, Provider: "fake"})
+
+		assert.ErrorIs(t, err, repository.ErrEnrollmentNotFound)
+		mockRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+	})
+
+	t.Run("already paid", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+		enrollment := createPendingEnrollment(5)
+		enrollment.PaymentStatus = models.PaymentStatusPaid
+		mockRepo.On("GetEnrollmentForCheckout", uint(40)).Return(enrollment, nil)
+
+		_, err := paymentService.InitiateCheckout(context.Background(), 5, dto.CheckoutRequest{EnrollmentID: 40, Provider: "fake"})
+
+		assert.True(t, apperrors.IsPaymentNotRequiredError(err))
+		mockRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+	})
+}
+
+func TestPaymentService_HandleCallback_SettlesVerifiedPayment(t *testing.T) {
+	mockRepo := new(MockPaymentRepository)
+	fake := payment.NewFakeProvider(true)
+	paymentService := newTestPaymentService(mockRepo, fake)
+
+	_, err := fake.InitiateCheckout(context.Background(), payment.CheckoutRequest{
+		TransactionID: "MEDTEST1", Amount: 1500, Currency: "BDT", ReturnURL: "https://api.medecole.test/cb",
+	})
+	assert.NoError(t, err)
+
+	paid := createInitiatedTransaction("MEDTEST1", 1500)
+	paid.Status = models.PaymentTransactionPaid
+	mockRepo.On("GetTransaction", "MEDTEST1").Return(createInitiatedTransaction("MEDTEST1", 1500), nil)
+	mockRepo.On("SettleTransaction", "MEDTEST1", mock.MatchedBy(func(settlement repository.PaymentSettlement) bool {
+		return settlement.Status == models.PaymentTransactionPaid && settlement.ProviderTransactionID == "FAKE-MEDTEST1" &&
+			settlement.PaidAt != nil
+	})).Return(paid, true, nil)
+
+	status, err := paymentService.HandleCallback(context.Background(), "fake", url.Values{"tran_id": {"MEDTEST1"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PAID", status.Status)
+	assert.Equal(t, uint(40), status.EnrollmentID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_HandleCallback_ReportsDuplicatePayment(t *testing.T) {
+	mockRepo := new(MockPaymentRepository)
+	fake := payment.NewFakeProvider(true)
+	paymentService := newTestPaymentService(mockRepo, fake)
+
+	_, err := fake.InitiateCheckout(context.Background(), payment.CheckoutRequest{
+		TransactionID: "MEDTEST6", Amount: 1500, Currency: "BDT", ReturnURL: "https://api.medecole.test/cb",
+	})
+	assert.NoError(t, err)
+
+	// Another checkout of the same enrollment was paid first
+	duplicate := createInitiatedTransaction("MEDTEST6", 1500)
+	duplicate.Status = models.PaymentTransactionPaid
+	duplicate.Duplicate = true
+	mockRepo.On("GetTransaction", "MEDTEST6").Return(createInitiatedTransaction("MEDTEST6", 1500), nil)
+	mockRepo.On("SettleTransaction", "MEDTEST6", mock.Anything).Return(duplicate, true, nil)
+
+	status, err := paymentService.HandleCallback(context.Background(), "fake", url.Values{"tran_id": {"MEDTEST6"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PAID", status.Status)
+	assert.True(t, status.Duplicate)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_HandleCallback_UnderpaidIsFailed(t *testing.T) {
+	mockRepo := new(MockPaymentRepository)
+	fake := payment.NewFakeProvider(true)
+	paymentService := newTestPaymentService(mockRepo, fake)
+
+	// The gateway took less than the enrollment costs
+	_, err := fake.InitiateCheckout(context.Background(), payment.CheckoutRequest{
+		TransactionID: "MEDTEST2", Amount: 15, Currency: "BDT", ReturnURL: "https://api.medecole.test/cb",
+	})
+	assert.NoError(t, err)
+
+	failed := createInitiatedTransaction("MEDTEST2", 1500)
+	failed.Status = models.PaymentTransactionFailed
+	mockRepo.On("GetTransaction", "MEDTEST2").Return(createInitiatedTransaction("MEDTEST2", 1500), nil)
+	mockRepo.On("SettleTransaction", "MEDTEST2", mock.MatchedBy(func(settlement repository.PaymentSettlement) bool {
+		return settlement.Status == models.PaymentTransactionFailed && settlement.FailureReason == "gateway reported 15.00 paid of 1500.00"
+	})).Return(failed, true, nil)
+
+	status, err := paymentService.HandleCallback(context.Background(), "fake", url.Values{"tran_id": {"MEDTEST2"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FAILED", status.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_VerifyPayment(t *testing.T) {
+	t.Run("pending payment is recorded without settling", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		fake := payment.NewFakeProvider(false)
+		paymentService := newTestPaymentService(mockRepo, fake)
+		_, err := fake.InitiateCheckout(context.Background(), payment.CheckoutRequest{
+			TransactionID: "MEDTEST3", Amount: 1500, Currency: "BDT", ReturnURL: "https://api.medecole.test/cb",
+		})
+		assert.NoError(t, err)
+
+		mockRepo.On("GetTransaction", "MEDTEST3").Return(createInitiatedTransaction("MEDTEST3", 1500), nil)
+		mockRepo.On("RecordVerification", "MEDTEST3", mock.Anything).Return(nil)
+
+		status, err := paymentService.VerifyPayment(context.Background(), 5, "MEDTEST3")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INITIATED", status.Status)
+		mockRepo.AssertNotCalled(t, "SettleTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("paid transaction is not verified again", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+		paid := createInitiatedTransaction("MEDTEST4", 1500)
+		paid.Status = models.PaymentTransactionPaid
+		mockRepo.On("GetTransaction", "MEDTEST4").Return(paid, nil)
+
+		status, err := paymentService.VerifyPayment(context.Background(), 5, "MEDTEST4")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PAID", status.Status)
+		mockRepo.AssertNotCalled(t, "SettleTransaction", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "RecordVerification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("another student's transaction", func(t *testing.T) {
+		mockRepo := new(MockPaymentRepository)
+		paymentService := newTestPaymentService(mockRepo, payment.NewFakeProvider(true))
+		mockRepo.On("GetTransaction", "MEDTEST5").Return(createInitiatedTransaction("MEDTEST5", 1500), nil)
+
+		_, err := paymentService.VerifyPayment(context.Background(), 6, "MEDTEST5")
+
+		assert.ErrorIs(t, err, repository.ErrPaymentTransactionNotFound)
+	})
+}
+
+func TestSSLCommerzProvider_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/validator/api/merchantTransIDvalidationAPI.php", r.URL.Path)
+		assert.Equal(t, "MEDTEST6", r.URL.Query().Get("tran_id"))
+		assert.Equal(t, "store", r.URL.Query().Get("store_id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"APIConnect":"DONE","no_of_trans_found":2,"element":[
+			{"status":"FAILED","tran_id":"MEDTEST6","amount":"1500.00","currency":"BDT","error":"Insufficient balance"},
+			{"status":"VALID","tran_id":"MEDTEST6","tran_date":"2026-10-17 12:30:00","amount":"1500.00","currency":"BDT","bank_tran_id":"BANK1"}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := payment.NewSSLCommerzProvider(payment.SSLCommerzConfig{StoreID: "store", StorePassword: "secret", BaseURL: server.URL})
+
+	verification, err := provider.Verify(context.Background(), "MEDTEST6", "SESSION1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, payment.StatusPaid, verification.Status)
+	assert.Equal(t, "BANK1", verification.ProviderTransactionID)
+	assert.Equal(t, 1500.0, verification.Amount)
+	assert.Equal(t, "2026-10-17T06:30:00Z", verification.PaidAt.Format("2006-01-02T15:04:05Z07:00"))
+}
@@ -0,0 +1,240 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockPracticeRepository is a mock implementation of PracticeRepository
+type MockPracticeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPracticeRepository) GetPracticeCandidates(userID uint, criteria repository.PracticeCriteria, perDifficulty int) ([]models.Question, int, error) {
+	args := m.Called(userID, criteria, perDifficulty)
+	return args.Get(0).([]models.Question), args.Int(1), args.Error(2)
+}
+
+func (m *MockPracticeRepository) CreatePracticeExam(exam *models.Exam) error {
+	args := m.Called(exam)
+	return args.Error(0)
+}
+
+type practiceTestMocks struct {
+	practiceRepo   *MockPracticeRepository
+	topicRepo      *MockTopicPerformanceRepository
+	reviewRepo     *MockReviewScheduleRepository
+	examRepo       *MockExamRepository
+	enrollmentRepo *MockEnrollmentRepository
+	examMapper     *MockExamMapper
+}
+
+func setupPracticeMocks(enrolled bool) *practiceTestMocks {
+	mocks := &practiceTestMocks{
+		practiceRepo:   &MockPracticeRepository{},
+		topicRepo:      &MockTopicPerformanceRepository{},
+		reviewRepo:     &MockReviewScheduleRepository{},
+		examRepo:       &MockExamRepository{},
+		enrollmentRepo: &MockEnrollmentRepository{},
+		examMapper:     &MockExamMapper{},
+	}
+
+	mocks.examRepo.On("GetPackageWithExamsBySlug", "cardiology", uint(0)).
+		Return(&repository.PackageWithExamsData{Package: models.Package{ID: 3, Slug: "cardiology"}}, nil)
+	if enrolled {
+		mocks.enrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(createPaidEnrollment(1, 3), nil)
+	} else {
+		mocks.enrollmentRepo.On("GetPackageEnrollment", uint(1), uint(3)).Return(nil, nil)
+	}
+	mocks.examMapper.On("ToExamMetaResponse", mock.Anything).Return(dto.ExamMetaResponse{ID: 90})
+
+	return mocks
+}
+
+func (m *practiceTestMocks) service() service.PracticeService {
+	return service.NewPracticeService(m.practiceRepo, m.topicRepo, m.reviewRepo, m.examRepo, m.enrollmentRepo, m.examMapper)
+}
+
+// expectPracticeAttempt expects a single practice attempt and captures the generated exam
+func (m *practiceTestMocks) expectPracticeAttempt(captured **models.Exam) {
+	sessionID := "practice-session"
+	m.practiceRepo.On("CreatePracticeExam", mock.AnythingOfType("*models.Exam")).Return(nil)
+	m.examRepo.On("CreateExamAttemptWithExam", uint(1), mock.AnythingOfType("*models.Exam"), uint(3), 1, map[string]string(nil)).
+		Run(func(args mock.Arguments) { *captured = args.Get(1).(*models.Exam) }).
+		Return(&models.UserExamAttempt{ID: 40, AttemptNumber: 1, IsPractice: true, SessionID: &sessionID}, nil)
+}
+
+func TestPracticeService_StartPractice_TargetsWeakestSystems(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	mocks.topicRepo.On("GetSystemPerformance", uint(1)).Return(createTopicPerformanceSystems(), nil)
+
+	// Respiratory, Endocrine and Cardiovascular are the weakest systems with enough answers
+	weakAreas := repository.PracticeCriteria{SystemIDs: []uint{4, 8, 3}}
+	bank := append(buildBankQuestions(1, 6, models.DifficultyEasy, 0), buildBankQuestions(7, 6, models.DifficultyHard, 0)...)
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), weakAreas, 5).Return(bank, 12, nil)
+
+	var exam *models.Exam
+	mocks.expectPracticeAttempt(&exam)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{
+		PackageSlug:   "cardiology",
+		QuestionCount: 5,
+		DifficultyMix: map[models.DifficultyLevel]int{models.DifficultyEasy: 60, models.DifficultyHard: 40},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "practice-session", result.SessionID)
+	assert.Equal(t, uint(40), result.AttemptID)
+	assert.Equal(t, 5, result.QuestionCount)
+	assert.Equal(t, 12, result.AvailableQuestions)
+	assert.True(t, result.TargetsWeakAreas)
+	assert.Equal(t, []uint{4, 8, 3}, result.SystemIDs)
+
+	if assert.NotNil(t, exam) {
+		assert.Equal(t, models.ExamTypePractice, exam.ExamType)
+		assert.True(t, exam.IsPractice())
+		assert.Equal(t, 5, exam.TotalQuestions)
+		assert.Equal(t, 5.0, exam.TotalMarks)
+		assert.Equal(t, 8, exam.DurationMinutes)
+
+		var snapshot []models.ExamQuestion
+		assert.NoError(t, json.Unmarshal([]byte(exam.QuestionsData), &snapshot))
+		easy := 0
+		for _, question := range snapshot {
+			if question.ID <= 6 {
+				easy++
+			}
+		}
+		assert.Equal(t, 3, easy)
+	}
+	mocks.practiceRepo.AssertExpectations(t)
+	mocks.examRepo.AssertExpectations(t)
+}
+
+func TestPracticeService_StartPractice_FallsBackToWholeBank(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	mocks.topicRepo.On("GetSystemPerformance", uint(1)).Return(createTopicPerformanceSystems(), nil)
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), repository.PracticeCriteria{SystemIDs: []uint{4, 8, 3}}, 20).
+		Return([]models.Question{}, 0, nil)
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), repository.PracticeCriteria{}, 20).
+		Return(buildBankQuestions(1, 4, models.DifficultyMedium, 0), 4, nil)
+
+	var exam *models.Exam
+	mocks.expectPracticeAttempt(&exam)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{PackageSlug: "cardiology"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.TargetsWeakAreas)
+	assert.Empty(t, result.SystemIDs)
+	// The default 20 questions are capped at what is left in the bank
+	assert.Equal(t, 4, result.QuestionCount)
+	assert.Equal(t, 4, exam.TotalQuestions)
+	mocks.practiceRepo.AssertExpectations(t)
+}
+
+func TestPracticeService_StartPractice_UsesSelectedTopics(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	criteria := repository.PracticeCriteria{
+		SubjectIDs:    []uint{2},
+		SystemIDs:     []uint{7},
+		QuestionTypes: []models.QuestionType{models.QuestionTypeSBA},
+	}
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), criteria, mock.Anything).Return(buildBankQuestions(1, 10, models.DifficultyMedium, 0), 10, nil)
+
+	var exam *models.Exam
+	mocks.expectPracticeAttempt(&exam)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{
+		PackageSlug:     "cardiology",
+		SubjectIDs:      []uint{2},
+		SystemIDs:       []uint{7},
+		QuestionTypes:   []models.QuestionType{models.QuestionTypeSBA},
+		QuestionCount:   10,
+		DurationMinutes: 20,
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, result.TargetsWeakAreas)
+	assert.Equal(t, 20, exam.DurationMinutes)
+	mocks.topicRepo.AssertNotCalled(t, "GetSystemPerformance", mock.Anything)
+}
+
+func TestPracticeService_StartPractice_ValidatesRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   dto.StartPracticeRequest
+		field string
+	}{
+		{"too many questions", dto.StartPracticeRequest{QuestionCount: 101}, "question_count"},
+		{"duration too long", dto.StartPracticeRequest{DurationMinutes: 301}, "duration_minutes"},
+		{"unknown question type", dto.StartPracticeRequest{QuestionTypes: []models.QuestionType{"ESSAY"}}, "question_types"},
+		{"unknown mode", dto.StartPracticeRequest{Mode: "OPEN_BOOK"}, "mode"},
+		{"mix does not add up", dto.StartPracticeRequest{DifficultyMix: map[models.DifficultyLevel]int{models.DifficultyEasy: 50}}, "difficulty_mix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := setupPracticeMocks(true)
+			tt.req.PackageSlug = "cardiology"
+
+			result, err := mocks.service().StartPractice(1, tt.req)
+
+			assert.Nil(t, result)
+			assert.True(t, apperrors.IsPracticeValidationError(err))
+			assert.Equal(t, tt.field, err.(*apperrors.PracticeValidationError).Field)
+			mocks.examRepo.AssertNotCalled(t, "GetPackageWithExamsBySlug", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPracticeService_StartPractice_NotEnrolled(t *testing.T) {
+	mocks := setupPracticeMocks(false)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{PackageSlug: "cardiology"})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrNotEnrolledInPackage)
+	mocks.practiceRepo.AssertNotCalled(t, "GetPracticeCandidates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPracticeService_StartPractice_NoQuestionsLeft(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	mocks.practiceRepo.On("GetPracticeCandidates", uint(1), repository.PracticeCriteria{SystemIDs: []uint{7}}, 20).
+		Return([]models.Question{}, 0, nil)
+
+	result, err := mocks.service().StartPractice(1, dto.StartPracticeRequest{PackageSlug: "cardiology", SystemIDs: []uint{7}})
+
+	assert.Nil(t, result)
+	assert.True(t, apperrors.IsNoPracticeQuestionsError(err))
+	mocks.practiceRepo.AssertNotCalled(t, "CreatePracticeExam", mock.Anything)
+	mocks.examRepo.AssertNotCalled(t, "CreateExamAttemptWithExam", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExamService_SubmitExam_PracticeIsNotGraded(t *testing.T) {
+	mockExamRepo := &MockExamRepository{}
+	examService := service.NewExamService(mockExamRepo, &MockEnrollmentRepository{}, &MockExamMapper{})
+
+	mockExamRepo.On("GetActiveSessionByID", "tutor_session").Return(createTutorSession(models.ExamTypePractice, models.ExamModeTimed), nil)
+	mockExamRepo.On("GetSessionAnswers", "tutor_session").Return(map[uint]string{1: "a", 2: "b"}, nil)
+	mockExamRepo.On("GetFlaggedQuestions", "tutor_session").Return([]uint{}, nil)
+	mockExamRepo.On("GetAnswerChanges", "tutor_session").Return([]models.SessionAnswerChange{}, nil)
+	mockExamRepo.On("GetQuestionTimes", "tutor_session").Return(map[uint]int{}, nil)
+	mockExamRepo.On("CompleteExamAttemptWithAnswers", uint(50), 2.0, false, mock.AnythingOfType("string"), 2).Return(nil)
+
+	result, err := examService.SubmitExam("tutor_session", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, result.Score)
+	assert.False(t, result.Passed, "practice sessions have no passing score")
+	mockExamRepo.AssertExpectations(t)
+}
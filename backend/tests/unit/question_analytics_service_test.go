@@ -0,0 +1,227 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockQuestionAnswerRepository is a mock implementation of QuestionAnswerRepository
+type MockQuestionAnswerRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuestionAnswerRepository) GetAttemptsForAnalytics(afterID uint, limit int, includeProcessed bool) ([]models.UserExamAttempt, error) {
+	args := m.Called(afterID, limit, includeProcessed)
+	return args.Get(0).([]models.UserExamAttempt), args.Error(1)
+}
+
+func (m *MockQuestionAnswerRepository) GetAnalyticsScanStart() (uint, error) {
+	args := m.Called()
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockQuestionAnswerRepository) RecordAnalyticsFailure(attemptID uint, reason string) error {
+	args := m.Called(attemptID, reason)
+	return args.Error(0)
+}
+
+func (m *MockQuestionAnswerRepository) ReplaceQuestionAnswers(attemptID uint, answers []models.UserQuestionAnswer) error {
+	args := m.Called(attemptID, answers)
+	return args.Error(0)
+}
+
+func (m *MockQuestionAnswerRepository) GetQuestionTopics(questionIDs []uint) (map[uint]repository.QuestionTopic, error) {
+	args := m.Called(questionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]repository.QuestionTopic), args.Error(1)
+}
+
+func createAnalyticsTestAttempt(id uint) models.UserExamAttempt {
+	completedAt := time.Date(2026, 10, 1, 10, 30, 0, 0, time.UTC)
+	return models.UserExamAttempt{
+		ID:              id,
+		UserID:          5,
+		ExamID:          9,
+		Status:          models.AttemptStatusCompleted,
+		StartedAt:       completedAt.Add(-30 * time.Minute),
+		CompletedAt:     &completedAt,
+		ActualTimeSpent: 900,
+		AnswersData: `{
+			"submission_timestamp": "2026-10-01T10:30:00Z",
+			"answers": [
+				{"question_id": 11, "question_text": "Q1", "question_type": "SBA", "user_answer": ["b"], "correct_answer": "a",
+				 "is_correct": false, "points_earned": -0.25, "penalty": 0.25, "max_points": 1,
+				 "options": [{"key": "a", "text": "A", "is_correct": true}, {"key": "b", "text": "B", "is_correct": false}]},
+				{"question_id": 12, "question_text": "Q2", "question_type": "TRUE_FALSE", "user_answer": ["a:true", "b:false"], "correct_answer": {"a": true, "b": false},
+				 "is_correct": true, "points_earned": 2, "max_points": 2,
+				 "options": [{"key": "a", "text": "A", "is_correct": true}, {"key": "b", "text": "B", "is_correct": false}]},
+				{"question_id": 13, "question_text": "Q3", "question_type": "SBA", "user_answer": null, "correct_answer": "a",
+				 "is_correct": false, "points_earned": 0, "max_points": 1,
+				 "options": [{"key": "a", "text": "A", "is_correct": true}]}
+			]
+		}`,
+		Exam: models.Exam{
+			ID:            9,
+			QuestionsData: `[{"id": 11, "difficulty_level": "HARD", "system_id": 3, "subject_id": 1}, {"id": 12, "difficulty_level": "EASY", "system_id": 4, "subject_id": 1}, {"id": 13, "system_id": 7, "subject_id": 2}]`,
+		},
+	}
+}
+
+func TestQuestionAnalyticsService_ProcessPendingAttempts(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	attempt := createAnalyticsTestAttempt(100)
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(0), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(0), 10, false).Return([]models.UserExamAttempt{attempt}, nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(100), mock.Anything).Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	answers := mockRepo.Calls[2].Arguments.Get(1).([]models.UserQuestionAnswer)
+	assert.Len(t, answers, 3)
+
+	sba := answers[0]
+	assert.Equal(t, uint(100), sba.AttemptID)
+	assert.Equal(t, uint(5), sba.UserID)
+	assert.Equal(t, uint(9), sba.ExamID)
+	assert.Equal(t, uint(11), sba.QuestionID)
+	assert.Equal(t, models.DifficultyHard, sba.DifficultyLevel)
+	assert.Equal(t, 0, sba.QuestionIndex)
+	assert.Equal(t, `["b"]`, sba.SelectedOptions)
+	assert.Equal(t, `["a"]`, sba.CorrectOptions)
+	assert.Equal(t, -0.25, sba.PartialScore)
+	assert.Equal(t, 1.0, sba.MaxScore)
+	assert.False(t, sba.IsSkipped)
+	assert.Equal(t, *attempt.CompletedAt, sba.AnsweredAt)
+	assert.Equal(t, uint(3), sba.SystemID)
+	assert.Equal(t, uint(1), sba.SubjectID)
+	assert.Equal(t, 300, sba.TimeSpent)
+
+	trueFalse := answers[1]
+	assert.Equal(t, models.QuestionTypeTrueFalse, trueFalse.QuestionType)
+	assert.Equal(t, `["a:true","b:false"]`, trueFalse.CorrectOptions)
+	assert.True(t, trueFalse.IsCorrect)
+	assert.Equal(t, 2.0, trueFalse.PartialScore)
+
+	skipped := answers[2]
+	assert.True(t, skipped.IsSkipped)
+	assert.Equal(t, `[]`, skipped.SelectedOptions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuestionAnalyticsService_SkipsBrokenAttemptsAndPages(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 2})
+
+	broken := createAnalyticsTestAttempt(1)
+	broken.AnswersData = `{not json`
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(0), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(0), 2, false).Return([]models.UserExamAttempt{broken, createAnalyticsTestAttempt(2)}, nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(2), 2, false).Return([]models.UserExamAttempt{createAnalyticsTestAttempt(3)}, nil)
+	mockRepo.On("RecordAnalyticsFailure", uint(1), mock.AnythingOfType("string")).Return(nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(2), mock.Anything).Return(nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(3), mock.Anything).Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	mockRepo.AssertNotCalled(t, "ReplaceQuestionAnswers", uint(1), mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuestionAnalyticsService_ScansFromOldestPendingAttempt(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(99), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(99), 10, false).Return([]models.UserExamAttempt{createAnalyticsTestAttempt(100)}, nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(100), mock.Anything).Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	mockRepo.AssertNotCalled(t, "GetAttemptsForAnalytics", uint(0), mock.Anything, mock.Anything)
+}
+
+func TestQuestionAnalyticsService_RecordsFailureOfAttemptGivenUpOn(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	broken := createAnalyticsTestAttempt(7)
+	broken.AnswersData = `{not json`
+	broken.AnalyticsFailures = models.MaxAnalyticsFailures - 1
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(6), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(6), 10, false).Return([]models.UserExamAttempt{broken}, nil)
+	mockRepo.On("RecordAnalyticsFailure", uint(7), mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "failed to parse answers data")
+	})).Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuestionAnalyticsService_BackfillResumesFromAttemptID(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 1})
+
+	mockRepo.On("GetAttemptsForAnalytics", uint(40), 1, true).Return([]models.UserExamAttempt{createAnalyticsTestAttempt(41)}, nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(41), 1, true).Return([]models.UserExamAttempt{}, nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(41), mock.Anything).Return(nil)
+
+	var lastReported uint
+	processed, err := analyticsService.Backfill(40, true, func(lastAttemptID uint, processed int) {
+		lastReported = lastAttemptID
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, uint(41), lastReported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuestionAnalyticsService_LooksUpTopicsMissingFromSnapshot(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, acceptingReviewService(), service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	// Exams built before snapshots recorded systems
+	attempt := createAnalyticsTestAttempt(100)
+	attempt.Exam.QuestionsData = `[{"id": 11}, {"id": 12, "system_id": 4, "subject_id": 1}, {"id": 13}]`
+
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(0), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(0), 10, false).Return([]models.UserExamAttempt{attempt}, nil)
+	mockRepo.On("GetQuestionTopics", []uint{11, 13}).Return(map[uint]repository.QuestionTopic{
+		11: {QuestionID: 11, SystemID: 5, SubjectID: 2},
+	}, nil)
+	mockRepo.On("ReplaceQuestionAnswers", uint(100), mock.Anything).Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	answers := mockRepo.Calls[3].Arguments.Get(1).([]models.UserQuestionAnswer)
+	assert.Equal(t, uint(5), answers[0].SystemID)
+	assert.Equal(t, uint(2), answers[0].SubjectID)
+	assert.Equal(t, uint(4), answers[1].SystemID)
+	assert.Equal(t, uint(0), answers[2].SystemID, "questions no longer in the bank stay unclassified")
+	mockRepo.AssertExpectations(t)
+}
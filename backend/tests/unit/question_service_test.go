@@ -0,0 +1,246 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQuestionRepository mocks the QuestionRepository interface
+type MockQuestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuestionRepository) CreateQuestion(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) UpdateQuestion(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) DeleteQuestion(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockQuestionRepository) GetQuestionByID(id uint) (*models.Question, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepository) ListQuestions(filters repository.QuestionFilters) ([]models.Question, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]models.Question), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockQuestionRepository) GetSystemByID(id uint) (*models.System, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.System), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetSubjectsWithSystems() ([]models.Subject, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Subject), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetQuestionCountsBySystem() ([]repository.SystemQuestionCount, error) {
+	args := m.Called()
+	return args.Get(0).([]repository.SystemQuestionCount), args.Error(1)
+}
+
+func (m *MockQuestionRepository) GetCandidateQuestions(criteria repository.QuestionCandidateCriteria) ([]models.Question, error) {
+	args := m.Called(criteria)
+	return args.Get(0).([]models.Question), args.Error(1)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestValidateQuestionOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		questionType models.QuestionType
+		options      map[string]dto.QuestionOptionInput
+		expectError  bool
+	}{
+		{
+			name:         "valid SBA with single correct option",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Option A", IsCorrect: boolPtr(false)},
+				"b": {Text: "Option B", IsCorrect: boolPtr(true)},
+				"c": {Text: "Option C"},
+			},
+			expectError: false,
+		},
+		{
+			name:         "SBA with two correct options",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Option A", IsCorrect: boolPtr(true)},
+				"b": {Text: "Option B", IsCorrect: boolPtr(true)},
+			},
+			expectError: true,
+		},
+		{
+			name:         "SBA without correct option",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Option A", IsCorrect: boolPtr(false)},
+				"b": {Text: "Option B"},
+			},
+			expectError: true,
+		},
+		{
+			name:         "valid TRUE_FALSE with flags on every stem",
+			questionType: models.QuestionTypeTrueFalse,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Stem A", IsCorrect: boolPtr(true)},
+				"b": {Text: "Stem B", IsCorrect: boolPtr(false)},
+				"c": {Text: "Stem C", IsCorrect: boolPtr(true)},
+			},
+			expectError: false,
+		},
+		{
+			name:         "TRUE_FALSE with missing flag",
+			questionType: models.QuestionTypeTrueFalse,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Stem A", IsCorrect: boolPtr(true)},
+				"b": {Text: "Stem B"},
+			},
+			expectError: true,
+		},
+		{
+			name:         "invalid option key",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"A":  {Text: "Option A", IsCorrect: boolPtr(true)},
+				"b2": {Text: "Option B", IsCorrect: boolPtr(false)},
+			},
+			expectError: true,
+		},
+		{
+			name:         "empty option text",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Option A", IsCorrect: boolPtr(true)},
+				"b": {Text: "  ", IsCorrect: boolPtr(false)},
+			},
+			expectError: true,
+		},
+		{
+			name:         "too few options",
+			questionType: models.QuestionTypeSBA,
+			options: map[string]dto.QuestionOptionInput{
+				"a": {Text: "Option A", IsCorrect: boolPtr(true)},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateQuestionOptions(tt.questionType, tt.options)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.True(t, apperrors.IsQuestionValidationError(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuestionService_CreateQuestion_SetsCreatedBy(t *testing.T) {
+	mockRepo := new(MockQuestionRepository)
+	questionService := service.NewQuestionService(mockRepo, mapper.NewQuestionMapper())
+
+	req := dto.QuestionRequest{
+		SystemID:     3,
+		QuestionText: "  Which drug is first-line for anaphylaxis?  ",
+		QuestionType: models.QuestionTypeSBA,
+		Options: map[string]dto.QuestionOptionInput{
+			"a": {Text: "Adrenaline", IsCorrect: boolPtr(true)},
+			"b": {Text: "Chlorpheniramine", IsCorrect: boolPtr(false)},
+		},
+		Tags: []string{"emergency", " pharmacology ", "emergency"},
+	}
+
+	mockRepo.On("GetSystemByID", uint(3)).Return(&models.System{ID: 3}, nil)
+	mockRepo.On("CreateQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		q.ID = 42
+		return true
+	})).Return(nil)
+	mockRepo.On("GetQuestionByID", uint(42)).Return(&models.Question{
+		ID:           42,
+		SystemID:     3,
+		QuestionText: "Which drug is first-line for anaphylaxis?",
+		QuestionType: models.QuestionTypeSBA,
+		Options:      `{"a":{"text":"Adrenaline","is_correct":true},"b":{"text":"Chlorpheniramine","is_correct":false}}`,
+		Tags:         `["emergency","pharmacology"]`,
+		IsActive:     true,
+	}, nil)
+
+	result, err := questionService.CreateQuestion(req, 7)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, uint(42), result.ID)
+	assert.True(t, result.Options["a"].IsCorrect)
+	assert.Equal(t, "UNUSED", result.UsageLevel)
+
+	created := mockRepo.Calls[1].Arguments.Get(0).(*models.Question)
+	assert.NotNil(t, created.CreatedBy)
+	assert.Equal(t, uint(7), *created.CreatedBy)
+	assert.Equal(t, "Which drug is first-line for anaphylaxis?", created.QuestionText)
+	assert.Equal(t, models.DifficultyMedium, created.DifficultyLevel)
+	assert.True(t, created.IsActive)
+
+	var tags []string
+	assert.NoError(t, json.Unmarshal([]byte(created.Tags), &tags))
+	assert.Equal(t, []string{"emergency", "pharmacology"}, tags)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQuestionService_CreateQuestion_UnknownSystem(t *testing.T) {
+	mockRepo := new(MockQuestionRepository)
+	questionService := service.NewQuestionService(mockRepo, mapper.NewQuestionMapper())
+
+	req := dto.QuestionRequest{
+		SystemID:     99,
+		QuestionText: "Question",
+		QuestionType: models.QuestionTypeTrueFalse,
+		Options: map[string]dto.QuestionOptionInput{
+			"a": {Text: "Stem A", IsCorrect: boolPtr(true)},
+			"b": {Text: "Stem B", IsCorrect: boolPtr(false)},
+		},
+	}
+
+	mockRepo.On("GetSystemByID", uint(99)).Return(nil, repository.ErrSystemNotFound)
+
+	result, err := questionService.CreateQuestion(req, 1)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrSystemNotFound)
+	mockRepo.AssertNotCalled(t, "CreateQuestion", mock.Anything)
+}
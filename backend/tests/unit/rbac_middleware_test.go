@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/middleware"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRBACRouter builds a router that injects the given user the way AuthMiddleware does
+func newRBACRouter(user *models.User, guards ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user != nil {
+			c.Set("user", user)
+			c.Set("userID", user.ID)
+		}
+		c.Next()
+	})
+	handlers := append(guards, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/protected", handlers...)
+	return router
+}
+
+func performRBACRequest(router *gin.Engine) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *models.User
+		expected int
+	}{
+		{"admin allowed", &models.User{ID: 1, Role: models.RoleAdmin}, http.StatusOK},
+		{"editor allowed", &models.User{ID: 2, Role: models.RoleEditor}, http.StatusOK},
+		{"student forbidden", &models.User{ID: 3, Role: models.RoleStudent}, http.StatusForbidden},
+		{"missing user unauthorized", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newRBACRouter(tt.user, middleware.RequireRole(models.RoleAdmin, models.RoleEditor))
+			assert.Equal(t, tt.expected, performRBACRequest(router))
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	extraGrant := `["analytics:view"]`
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		permission models.Permission
+		expected   int
+	}{
+		{"admin manages enrollments", &models.User{Role: models.RoleAdmin}, models.PermissionManageEnrollments, http.StatusOK},
+		{"editor manages questions", &models.User{Role: models.RoleEditor}, models.PermissionManageQuestions, http.StatusOK},
+		{"editor cannot manage users", &models.User{Role: models.RoleEditor}, models.PermissionManageUsers, http.StatusForbidden},
+		{"student without grant", &models.User{Role: models.RoleStudent}, models.PermissionViewAnalytics, http.StatusForbidden},
+		{"student with extra grant", &models.User{Role: models.RoleStudent, Permissions: &extraGrant}, models.PermissionViewAnalytics, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newRBACRouter(tt.user, middleware.RequirePermission(tt.permission))
+			assert.Equal(t, tt.expected, performRBACRequest(router))
+		})
+	}
+}
+
+func TestUserPermissions_IncludesRoleAndExtraGrants(t *testing.T) {
+	extraGrant := `["users:manage","questions:manage"]`
+	user := &models.User{Role: models.RoleEditor, Permissions: &extraGrant}
+
+	assert.ElementsMatch(t, []string{"questions:manage", "exams:manage", "analytics:view", "users:manage"}, user.GetPermissionNames())
+	assert.True(t, user.HasRole(models.RoleEditor))
+	assert.False(t, user.HasRole(models.RoleAdmin))
+	assert.True(t, models.RoleStudent.IsValid())
+	assert.False(t, models.UserRole("ROOT").IsValid())
+}
@@ -0,0 +1,420 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/mapper"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/payment"
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// newPaidFakeGateway returns a fake gateway holding a paid payment of 1500 for enrollment 30, along with
+// that payment's transaction
+func newPaidFakeGateway(t *testing.T) (*payment.FakeProvider, *models.PaymentTransaction) {
+	fake := payment.NewFakeProvider(true)
+	_, err := fake.InitiateCheckout(context.Background(), payment.CheckoutRequest{
+		TransactionID: "MEDPAID1",
+		Amount:        1500,
+		Currency:      "BDT",
+		ReturnURL:     "https://api.medecole.test/api/payments/callback/fake",
+	})
+	assert.NoError(t, err)
+
+	transaction := createInitiatedTransaction("MEDPAID1", 1500)
+	transaction.EnrollmentID = 30
+	transaction.Status = models.PaymentTransactionPaid
+	return fake, transaction
+}
+
+// newTestRefundService returns a refund service whose fake gateway holds a paid payment of 1500 for
+// enrollment 30, along with that payment's transaction
+func newTestRefundService(t *testing.T, paymentRepo *MockPaymentRepository, enrollmentRepo *MockEnrollmentRepository) (service.RefundService, *models.PaymentTransaction) {
+	fake, transaction := newPaidFakeGateway(t)
+	refundService := service.NewRefundService(paymentRepo, enrollmentRepo, payment.NewRegistry(fake), mapper.NewEnrollmentMapper())
+	return refundService, transaction
+}
+
+// unansweredRefundGateway makes refunds at the fake gateway but loses every answer, as on a timeout
+type unansweredRefundGateway struct {
+	*payment.FakeProvider
+}
+
+func (g unansweredRefundGateway) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	if _, err := g.FakeProvider.Refund(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("request failed: context deadline exceeded")
+}
+
+// reserveRefund makes a mocked reservation behave like the stored one, giving the refund its ID and amount
+func reserveRefund(id uint, amount float64) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		refund := args.Get(0).(*models.PaymentRefund)
+		refund.ID = id
+		refund.Amount = amount
+		refund.Status = models.PaymentRefundReserved
+	}
+}
+
+func TestRefundService_RefundEnrollment_FullRefundCancels(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.TransactionID == "MEDPAID1" && refund.RefundedBy == 1 && refund.UserID == 5
+	}), (*float64)(nil)).Run(reserveRefund(7, 1500)).Return(1500.0, nil)
+	paymentRepo.On("SettleRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.ID == 7 && refund.Amount == 1500 &&
+			refund.Status == models.PaymentRefundProcessed && refund.RefundReference != nil
+	})).Return(true, nil)
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 1500, Processed: 1500}, nil)
+	enrollmentRepo.On("CancelEnrollment", uint(30), repository.EnrollmentCancellation{
+		CancelledBy: 1,
+		Reason:      "Duplicate purchase",
+		Refunded:    true,
+	}).Return(nil)
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1500.0, result.Amount)
+	assert.Equal(t, 0.0, result.RemainingRefundable)
+	assert.True(t, result.EnrollmentCancelled)
+	paymentRepo.AssertExpectations(t)
+	enrollmentRepo.AssertExpectations(t)
+}
+
+func TestRefundService_RefundEnrollment_PartialRefundKeepsEnrollment(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	amount := 300.0
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetTransaction", "MEDPAID1").Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, &amount).Run(reserveRefund(7, 300)).Return(1000.0, nil)
+	paymentRepo.On("SettleRefund", mock.Anything).Return(true, nil)
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 800, Processed: 800}, nil)
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30,
+		dto.RefundRequest{TransactionID: "MEDPAID1", Amount: &amount, Reason: "Goodwill"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 700.0, result.RemainingRefundable)
+	assert.False(t, result.EnrollmentCancelled)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_PartialRefundCancelsWhenAsked(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	amount := 300.0
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.CancelEnrollment
+	}), &amount).Run(reserveRefund(7, 300)).Return(1500.0, nil)
+	paymentRepo.On("SettleRefund", mock.Anything).Return(true, nil)
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 300, Processed: 300}, nil)
+	// Most of the payment was kept, so the enrollment is cancelled without being marked refunded
+	enrollmentRepo.On("CancelEnrollment", uint(30), repository.EnrollmentCancellation{
+		CancelledBy: 1,
+		Reason:      "Left the course",
+		Refunded:    false,
+	}).Return(nil)
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30,
+		dto.RefundRequest{Amount: &amount, Reason: "Left the course", CancelEnrollment: true})
+
+	assert.NoError(t, err)
+	assert.True(t, result.EnrollmentCancelled)
+	assert.Equal(t, 1200.0, result.RemainingRefundable)
+	enrollmentRepo.AssertExpectations(t)
+}
+
+func TestRefundService_RefundEnrollment_AmountExceedsRefundable(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, mock.Anything).Return(500.0, repository.ErrInvalidRefundAmount)
+
+	amount := 600.0
+	_, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Amount: &amount, Reason: "Goodwill"})
+
+	assert.True(t, apperrors.IsInvalidRefundAmountError(err))
+	paymentRepo.AssertNotCalled(t, "SettleRefund", mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_AlreadyRefunded(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Return(0.0, repository.ErrInvalidRefundAmount)
+
+	_, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+
+	assert.True(t, apperrors.IsRefundNotAllowedError(err))
+	paymentRepo.AssertNotCalled(t, "SettleRefund", mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_GatewayRejectionReleasesReservation(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	// More than the gateway took, so it turns the refund down
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Run(reserveRefund(7, 1600)).Return(1600.0, nil)
+	paymentRepo.On("ReleaseRefund", uint(7)).Return(nil)
+
+	_, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+
+	assert.True(t, apperrors.IsPaymentGatewayError(err))
+	paymentRepo.AssertExpectations(t)
+	paymentRepo.AssertNotCalled(t, "SettleRefund", mock.Anything)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_PendingRefundCancelsOnceProcessed(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	fake, transaction := newPaidFakeGateway(t)
+	fake.HoldRefunds(true)
+	refundService := service.NewRefundService(paymentRepo, enrollmentRepo, payment.NewRegistry(fake), mapper.NewEnrollmentMapper())
+
+	var pending models.PaymentRefund
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Run(reserveRefund(7, 1500)).Return(1500.0, nil)
+	paymentRepo.On("SettleRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.Status == models.PaymentRefundPending
+	})).Run(func(args mock.Arguments) {
+		pending = *args.Get(0).(*models.PaymentRefund)
+	}).Return(true, nil).Once()
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+
+	// The gateway is still processing the refund, the enrollment stays until it is done
+	assert.NoError(t, err)
+	assert.Equal(t, "PENDING", result.Status)
+	assert.False(t, result.EnrollmentCancelled)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+
+	fake.SetRefundStatus(*pending.RefundReference, payment.StatusPaid)
+	paymentRepo.On("GetPendingRefunds", mock.Anything).Return([]models.PaymentRefund{pending}, nil)
+	paymentRepo.On("GetTransaction", "MEDPAID1").Return(transaction, nil)
+	paymentRepo.On("SettleRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.ID == 7 && refund.Status == models.PaymentRefundProcessed
+	})).Return(true, nil).Once()
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 1500, Processed: 1500}, nil)
+	enrollmentRepo.On("CancelEnrollment", uint(30), repository.EnrollmentCancellation{
+		CancelledBy: 1,
+		Reason:      "Duplicate purchase",
+		Refunded:    true,
+	}).Return(nil)
+
+	reconciled, err := refundService.ReconcileRefunds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reconciled.Processed)
+	paymentRepo.AssertExpectations(t)
+	enrollmentRepo.AssertExpectations(t)
+}
+
+func TestRefundService_RefundEnrollment_UnansweredRefundStaysReserved(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	fake, transaction := newPaidFakeGateway(t)
+	refundService := service.NewRefundService(paymentRepo, enrollmentRepo,
+		payment.NewRegistry(unansweredRefundGateway{fake}), mapper.NewEnrollmentMapper())
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Run(reserveRefund(7, 1500)).Return(1500.0, nil)
+	paymentRepo.On("SettleRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.Status == models.PaymentRefundPending && refund.RefundReference == nil
+	})).Return(true, nil)
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+
+	// The gateway may have refunded, so the amount stays reserved instead of being offered again
+	assert.NoError(t, err)
+	assert.Equal(t, "PENDING", result.Status)
+	assert.False(t, result.EnrollmentCancelled)
+	paymentRepo.AssertNotCalled(t, "ReleaseRefund", mock.Anything)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_ReconcileRefunds_RefundUnknownToGatewayFails(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	lost := models.PaymentRefund{ID: 7, TransactionID: "MEDPAID1", EnrollmentID: 30, Provider: payment.FakeProviderName,
+		Amount: 1500, Status: models.PaymentRefundPending, RefundedBy: 1}
+	paymentRepo.On("GetPendingRefunds", mock.Anything).Return([]models.PaymentRefund{lost}, nil)
+	paymentRepo.On("GetTransaction", "MEDPAID1").Return(transaction, nil)
+	paymentRepo.On("SettleRefund", mock.MatchedBy(func(refund *models.PaymentRefund) bool {
+		return refund.ID == 7 && refund.Status == models.PaymentRefundFailed
+	})).Return(true, nil)
+
+	result, err := refundService.ReconcileRefunds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.RefundReconciliationResponse{Checked: 1, Failed: 1}, result)
+	paymentRepo.AssertExpectations(t)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_ConcurrentRefundsReserveOnce(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	// The ledger stands in for the locked transaction row: reservations are made one at a time
+	var ledger sync.Mutex
+	reserved := 0.0
+	reserve := func(refund *models.PaymentRefund, amount *float64) (float64, error) {
+		ledger.Lock()
+		defer ledger.Unlock()
+		refundable := transaction.Amount - reserved
+		if refundable <= 0 {
+			return refundable, repository.ErrInvalidRefundAmount
+		}
+		refund.Amount = refundable
+		reserved += refundable
+		return refundable, nil
+	}
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Return(reserve, nil)
+	paymentRepo.On("SettleRefund", mock.Anything).Return(true, nil)
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 1500, Processed: 1500}, nil)
+	enrollmentRepo.On("CancelEnrollment", uint(30), mock.Anything).Return(nil)
+
+	const admins = 5
+	errs := make(chan error, admins)
+	var wg sync.WaitGroup
+	for i := 0; i < admins; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Duplicate purchase"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, apperrors.IsRefundNotAllowedError(err), "unexpected error: %v", err)
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1500.0, reserved)
+	paymentRepo.AssertNumberOfCalls(t, "SettleRefund", 1)
+	enrollmentRepo.AssertNumberOfCalls(t, "CancelEnrollment", 1)
+}
+
+func TestRefundService_RefundEnrollment_RenewalRefundTakesPeriodOff(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+	renewalID := uint(9)
+	transaction.RenewalID = &renewalID
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetLatestPaidTransaction", uint(30)).Return(transaction, nil)
+	paymentRepo.On("ReserveRefund", mock.Anything, (*float64)(nil)).Run(reserveRefund(7, 1500)).Return(1500.0, nil)
+	paymentRepo.On("SettleRefund", mock.Anything).Return(true, nil)
+	paymentRepo.On("GetRefundTotals", "MEDPAID1").Return(&repository.RefundTotals{Refunded: 1500, Processed: 1500}, nil)
+	enrollmentRepo.On("RefundRenewal", uint(9)).Return(nil)
+
+	result, err := refundService.RefundEnrollment(context.Background(), 1, 30, dto.RefundRequest{Reason: "Renewed by mistake"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.RenewalRefunded)
+	assert.Equal(t, &renewalID, result.RenewalID)
+	assert.False(t, result.EnrollmentCancelled)
+	enrollmentRepo.AssertExpectations(t)
+	enrollmentRepo.AssertNotCalled(t, "CancelEnrollment", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_RefundEnrollment_OtherEnrollmentsTransaction(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, transaction := newTestRefundService(t, paymentRepo, enrollmentRepo)
+	transaction.EnrollmentID = 40
+
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(createPaidEnrollment(5, 3), nil)
+	paymentRepo.On("GetTransaction", "MEDPAID1").Return(transaction, nil)
+
+	_, err := refundService.RefundEnrollment(context.Background(), 1, 30,
+		dto.RefundRequest{TransactionID: "MEDPAID1", Reason: "Duplicate purchase"})
+
+	assert.ErrorIs(t, err, repository.ErrPaymentTransactionNotFound)
+}
+
+func TestRefundService_CancelEnrollment(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, _ := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	cancelled := createPaidEnrollment(5, 3)
+	cancelled.IsActive = false
+	reason := "Account shared"
+	cancelledBy := uint(1)
+	cancelled.CancellationReason = &reason
+	cancelled.CancelledBy = &cancelledBy
+	cancelled.CancelledAt = cancelled.ExpiresAt
+
+	enrollmentRepo.On("CancelEnrollment", uint(30), repository.EnrollmentCancellation{CancelledBy: 1, Reason: reason}).Return(nil)
+	enrollmentRepo.On("GetEnrollmentByID", uint(30)).Return(cancelled, nil)
+
+	result, err := refundService.CancelEnrollment(context.Background(), 1, 30, dto.CancelEnrollmentRequest{Reason: reason})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsActive)
+	assert.False(t, result.CanAccessContent)
+	assert.Equal(t, &reason, result.CancellationReason)
+	paymentRepo.AssertNotCalled(t, "ReserveRefund", mock.Anything, mock.Anything)
+}
+
+func TestRefundService_CancelEnrollment_AlreadyCancelled(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	enrollmentRepo := &MockEnrollmentRepository{}
+	refundService, _ := newTestRefundService(t, paymentRepo, enrollmentRepo)
+
+	enrollmentRepo.On("CancelEnrollment", uint(30), mock.Anything).Return(repository.ErrEnrollmentNotActive)
+
+	_, err := refundService.CancelEnrollment(context.Background(), 1, 30, dto.CancelEnrollmentRequest{Reason: "Account shared"})
+
+	assert.ErrorIs(t, err, repository.ErrEnrollmentNotActive)
+}
@@ -0,0 +1,226 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/dto"
+	apperrors "github.com/Mahfuz2811/medecole/backend/internal/errors"
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockReviewScheduleRepository is a mock implementation of ReviewScheduleRepository
+type MockReviewScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewScheduleRepository) GetSchedules(userID uint, questionIDs []uint) (map[uint]models.QuestionReviewSchedule, error) {
+	args := m.Called(userID, questionIDs)
+	return args.Get(0).(map[uint]models.QuestionReviewSchedule), args.Error(1)
+}
+
+func (m *MockReviewScheduleRepository) SaveSchedules(schedules []models.QuestionReviewSchedule) error {
+	args := m.Called(schedules)
+	return args.Error(0)
+}
+
+func (m *MockReviewScheduleRepository) GetDueSchedules(userID uint, dueBy time.Time, limit int) ([]models.QuestionReviewSchedule, error) {
+	args := m.Called(userID, dueBy, limit)
+	return args.Get(0).([]models.QuestionReviewSchedule), args.Error(1)
+}
+
+func (m *MockReviewScheduleRepository) CountDueSchedules(userID uint, dueBy time.Time) (int64, error) {
+	args := m.Called(userID, dueBy)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockReviewService is a mock implementation of ReviewService
+type MockReviewService struct {
+	mock.Mock
+}
+
+func (m *MockReviewService) RecordAnswers(answers []models.UserQuestionAnswer) error {
+	args := m.Called(answers)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) GetDueQueue(userID uint, limit int) (*dto.ReviewQueueResponse, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ReviewQueueResponse), args.Error(1)
+}
+
+// acceptingReviewService records any answers without checking them
+func acceptingReviewService() *MockReviewService {
+	reviewService := &MockReviewService{}
+	reviewService.On("RecordAnswers", mock.Anything).Return(nil)
+	return reviewService
+}
+
+func TestQuestionReviewSchedule_ApplyReview(t *testing.T) {
+	day := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	schedule := models.NewQuestionReviewSchedule(5, 11)
+
+	// Missed for the first time: back tomorrow, not a lapse yet
+	schedule.ApplyReview(1, day)
+	assert.Equal(t, 1, schedule.IntervalDays)
+	assert.Equal(t, 0, schedule.Lapses)
+	assert.Equal(t, 1.96, schedule.EaseFactor)
+	assert.Equal(t, day.AddDate(0, 0, 1), schedule.DueAt)
+
+	// Correct answers grow the interval: 1 day, 6 days, then interval x ease
+	schedule.ApplyReview(4, day.AddDate(0, 0, 1))
+	assert.Equal(t, 1, schedule.IntervalDays)
+	schedule.ApplyReview(4, day.AddDate(0, 0, 2))
+	assert.Equal(t, 6, schedule.IntervalDays)
+	schedule.ApplyReview(4, day.AddDate(0, 0, 8))
+	assert.Equal(t, 12, schedule.IntervalDays)
+	assert.Equal(t, 3, schedule.Repetitions)
+	assert.Equal(t, day.AddDate(0, 0, 20), schedule.DueAt)
+
+	// Missing it again starts over and counts a lapse
+	schedule.ApplyReview(0, day.AddDate(0, 0, 20))
+	assert.Equal(t, 1, schedule.IntervalDays)
+	assert.Equal(t, 0, schedule.Repetitions)
+	assert.Equal(t, 1, schedule.Lapses)
+	assert.Equal(t, models.MinEaseFactor, schedule.EaseFactor)
+	assert.Equal(t, 5, schedule.TotalReviews)
+}
+
+func TestReviewService_RecordAnswers(t *testing.T) {
+	mockRepo := &MockReviewScheduleRepository{}
+	reviewService := service.NewReviewService(mockRepo)
+
+	answeredAt := time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)
+	existing := models.NewQuestionReviewSchedule(5, 12)
+	existing.ID = 3
+	existing.ApplyReview(1, answeredAt.AddDate(0, 0, -1))
+	alreadyApplied := models.NewQuestionReviewSchedule(5, 14)
+	alreadyApplied.ID = 4
+	alreadyApplied.ApplyReview(1, answeredAt)
+
+	mockRepo.On("GetSchedules", uint(5), []uint{11, 12, 13, 14}).Return(map[uint]models.QuestionReviewSchedule{
+		12: existing,
+		14: alreadyApplied,
+	}, nil)
+	mockRepo.On("SaveSchedules", mock.AnythingOfType("[]models.QuestionReviewSchedule")).Return(nil)
+
+	err := reviewService.RecordAnswers([]models.UserQuestionAnswer{
+		{AttemptID: 70, UserID: 5, QuestionID: 11, SystemID: 3, SubjectID: 1, IsCorrect: false, AnsweredAt: answeredAt},
+		{AttemptID: 70, UserID: 5, QuestionID: 12, IsCorrect: true, AnsweredAt: answeredAt},
+		{AttemptID: 70, UserID: 5, QuestionID: 13, IsCorrect: true, AnsweredAt: answeredAt},
+		{AttemptID: 70, UserID: 5, QuestionID: 14, IsCorrect: false, AnsweredAt: answeredAt},
+	})
+
+	assert.NoError(t, err)
+	saved := mockRepo.Calls[1].Arguments.Get(0).([]models.QuestionReviewSchedule)
+	if assert.Len(t, saved, 2, "correct answers to unscheduled questions and answers already applied are skipped") {
+		missed := saved[0]
+		assert.Equal(t, uint(11), missed.QuestionID)
+		assert.Equal(t, uint(0), missed.ID)
+		assert.Equal(t, uint(3), missed.SystemID)
+		assert.Equal(t, uint(70), missed.LastAttemptID)
+		assert.Equal(t, answeredAt.AddDate(0, 0, 1), missed.DueAt)
+
+		reviewed := saved[1]
+		assert.Equal(t, uint(3), reviewed.ID)
+		assert.Equal(t, 1, reviewed.Repetitions)
+		assert.Equal(t, 2, reviewed.TotalReviews)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReviewService_GetDueQueue(t *testing.T) {
+	mockRepo := &MockReviewScheduleRepository{}
+	reviewService := service.NewReviewService(mockRepo)
+
+	dueAt := time.Date(2026, 10, 9, 12, 0, 0, 0, time.UTC)
+	schedule := models.QuestionReviewSchedule{
+		QuestionID:   11,
+		SystemID:     3,
+		EaseFactor:   1.96,
+		IntervalDays: 1,
+		DueAt:        dueAt,
+		Question:     models.Question{ID: 11, QuestionText: "Q1", QuestionType: models.QuestionTypeSBA},
+	}
+	endOfToday := mock.MatchedBy(func(dueBy time.Time) bool {
+		return dueBy.After(time.Now()) && dueBy.Sub(time.Now()) < 24*time.Hour
+	})
+	mockRepo.On("CountDueSchedules", uint(5), endOfToday).Return(int64(120), nil)
+	mockRepo.On("GetDueSchedules", uint(5), endOfToday, 50).Return([]models.QuestionReviewSchedule{schedule}, nil)
+
+	result, err := reviewService.GetDueQueue(5, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), result.DueCount)
+	if assert.Len(t, result.Items, 1) {
+		assert.Equal(t, "Q1", result.Items[0].QuestionText)
+		assert.Equal(t, "SBA", result.Items[0].QuestionType)
+		assert.Equal(t, dueAt.Format(time.RFC3339), result.Items[0].DueAt)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPracticeService_StartReview(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	due := make([]models.QuestionReviewSchedule, 0)
+	for _, question := range buildBankQuestions(11, 3, models.DifficultyMedium, 0) {
+		due = append(due, models.QuestionReviewSchedule{QuestionID: question.ID, Question: question})
+	}
+	mocks.reviewRepo.On("CountDueSchedules", uint(1), mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+	mocks.reviewRepo.On("GetDueSchedules", uint(1), mock.AnythingOfType("time.Time"), 10).Return(due, nil)
+
+	var exam *models.Exam
+	mocks.expectPracticeAttempt(&exam)
+
+	result, err := mocks.service().StartReview(1, dto.StartReviewRequest{PackageSlug: "cardiology", QuestionCount: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.QuestionCount)
+	assert.Equal(t, int64(3), result.DueCount)
+	if assert.NotNil(t, exam) {
+		assert.True(t, exam.IsPractice())
+		assert.Equal(t, models.ExamModeTutor, exam.SessionMode())
+		assert.Equal(t, 3, exam.TotalQuestions)
+	}
+}
+
+func TestPracticeService_StartReview_NothingDue(t *testing.T) {
+	mocks := setupPracticeMocks(true)
+	mocks.reviewRepo.On("CountDueSchedules", uint(1), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	mocks.reviewRepo.On("GetDueSchedules", uint(1), mock.AnythingOfType("time.Time"), 20).Return([]models.QuestionReviewSchedule{}, nil)
+
+	result, err := mocks.service().StartReview(1, dto.StartReviewRequest{PackageSlug: "cardiology"})
+
+	assert.Nil(t, result)
+	assert.True(t, apperrors.IsNoDueReviewsError(err))
+	mocks.practiceRepo.AssertNotCalled(t, "CreatePracticeExam", mock.Anything)
+}
+
+func TestQuestionAnalyticsService_ReviewScheduleFailureLeavesAttemptPending(t *testing.T) {
+	mockRepo := &MockQuestionAnswerRepository{}
+	reviewService := &MockReviewService{}
+	analyticsService := service.NewQuestionAnalyticsService(mockRepo, reviewService, service.QuestionAnalyticsConfig{BatchSize: 10})
+
+	mockRepo.On("GetAnalyticsScanStart").Return(uint(0), nil)
+	mockRepo.On("GetAttemptsForAnalytics", uint(0), 10, false).Return([]models.UserExamAttempt{createAnalyticsTestAttempt(100)}, nil)
+	reviewService.On("RecordAnswers", mock.MatchedBy(func(answers []models.UserQuestionAnswer) bool {
+		return len(answers) == 3 && answers[0].QuestionID == 11
+	})).Return(errors.New("database unavailable"))
+	mockRepo.On("RecordAnalyticsFailure", uint(100), "database unavailable").Return(nil)
+
+	processed, err := analyticsService.ProcessPendingAttempts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+	mockRepo.AssertNotCalled(t, "ReplaceQuestionAnswers", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+	reviewService.AssertExpectations(t)
+}
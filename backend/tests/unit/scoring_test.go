@@ -0,0 +1,232 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/models"
+	"github.com/Mahfuz2811/medecole/backend/internal/scoring"
+)
+
+const scoringTestQuestionsData = `[
+	{
+		"id": 1,
+		"question_text": "Most common cause of MI?",
+		"question_type": "SBA",
+		"points": 4,
+		"options": {
+			"a": {"text": "Atherosclerosis", "is_correct": true},
+			"b": {"text": "Vasospasm", "is_correct": false, "weight": 0.5},
+			"c": {"text": "Embolism", "is_correct": false}
+		}
+	},
+	{
+		"id": 2,
+		"question_text": "Regarding the heart",
+		"question_type": "TRUE_FALSE",
+		"points": 4,
+		"options": {
+			"a": {"text": "Has four chambers", "is_correct": true, "weight": 0.1},
+			"b": {"text": "Left ventricle is thinnest", "is_correct": false, "weight": 0.1},
+			"c": {"text": "SA node is the pacemaker", "is_correct": true, "weight": 0.4},
+			"d": {"text": "Mitral valve has three cusps", "is_correct": false, "weight": 0.4}
+		}
+	}
+]`
+
+func parseScoringTestQuestions(t *testing.T) []scoring.Question {
+	questions, err := scoring.ParseQuestions(scoringTestQuestionsData)
+	require.NoError(t, err)
+	require.Len(t, questions, 2)
+	return questions
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Run("nil uses default policy", func(t *testing.T) {
+		policy, err := scoring.ParsePolicy(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, scoring.DefaultPolicy(), policy)
+	})
+
+	t.Run("unset fields keep defaults", func(t *testing.T) {
+		policy, err := scoring.ParsePolicy(stringPtr(`{"negative_marking": {"enabled": true, "penalty_ratio": 0.25}}`))
+		assert.NoError(t, err)
+		assert.True(t, policy.PartialCredit)
+		assert.True(t, policy.NegativeMarking.Enabled)
+		assert.Equal(t, 0.25, policy.NegativeMarking.PenaltyRatio)
+	})
+
+	t.Run("penalty ratio out of range", func(t *testing.T) {
+		_, err := scoring.ParsePolicy(stringPtr(`{"negative_marking": {"enabled": true, "penalty_ratio": 1.5}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := scoring.ParsePolicy(stringPtr(`{`))
+		assert.Error(t, err)
+	})
+}
+
+func TestEngine_SBA(t *testing.T) {
+	question := parseScoringTestQuestions(t)[0]
+
+	t.Run("correct answer earns full points", func(t *testing.T) {
+		result := scoring.NewEngine(scoring.DefaultPolicy()).ScoreQuestion(question, "a")
+		assert.True(t, result.IsCorrect)
+		assert.Equal(t, 4.0, result.PointsEarned)
+		assert.Equal(t, "a", result.CorrectAnswer)
+	})
+
+	t.Run("unanswered earns nothing even with negative marking", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.NegativeMarking = scoring.NegativeMarking{Enabled: true, PenaltyRatio: 0.25}
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, "")
+		assert.False(t, result.Answered)
+		assert.Equal(t, 0.0, result.PointsEarned)
+	})
+
+	t.Run("wrong answer is penalised with negative marking", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.NegativeMarking = scoring.NegativeMarking{Enabled: true, PenaltyRatio: 0.25}
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, "c")
+		assert.False(t, result.IsCorrect)
+		assert.Equal(t, -1.0, result.PointsEarned)
+	})
+
+	t.Run("weighted wrong option earns its weight", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.UseOptionWeights = true
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, "b")
+		assert.False(t, result.IsCorrect)
+		assert.Equal(t, 2.0, result.PointsEarned)
+	})
+}
+
+func TestEngine_TrueFalse(t *testing.T) {
+	question := parseScoringTestQuestions(t)[1]
+	answer := `["a:true","b:false","c:false","d:true"]` // a and b right, c and d wrong
+
+	t.Run("partial credit per correct stem", func(t *testing.T) {
+		result := scoring.NewEngine(scoring.DefaultPolicy()).ScoreQuestion(question, answer)
+		assert.False(t, result.IsCorrect)
+		assert.Equal(t, 2.0, result.PointsEarned)
+	})
+
+	t.Run("all or nothing without partial credit", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.PartialCredit = false
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, answer)
+		assert.Equal(t, 0.0, result.PointsEarned)
+
+		result = scoring.NewEngine(policy).ScoreQuestion(question, `["a:true","b:false","c:true","d:false"]`)
+		assert.True(t, result.IsCorrect)
+		assert.Equal(t, 4.0, result.PointsEarned)
+	})
+
+	t.Run("option weights decide partial credit", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.UseOptionWeights = true
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, answer)
+		assert.InDelta(t, 0.8, result.PointsEarned, 1e-9)
+	})
+
+	t.Run("negative marking deducts per wrong stem", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.NegativeMarking = scoring.NegativeMarking{Enabled: true, PenaltyRatio: 0.5}
+
+		result := scoring.NewEngine(policy).ScoreQuestion(question, answer)
+		assert.Equal(t, 1.0, result.PointsEarned) // 2 points earned, 2 stems x 0.5 x 1 point deducted
+	})
+}
+
+type fixedScorer struct{}
+
+func (f *fixedScorer) Score(question scoring.Question, answer []string, policy scoring.Policy) scoring.Result {
+	return scoring.Result{Answered: len(answer) > 0, IsCorrect: true, PointsEarned: 1}
+}
+
+func TestEngine_ScoreExam(t *testing.T) {
+	questions := parseScoringTestQuestions(t)
+
+	t.Run("totals all questions", func(t *testing.T) {
+		result := scoring.NewEngine(scoring.DefaultPolicy()).ScoreExam(questions, map[uint]string{
+			1: "a",
+			2: `["a:true","b:false","c:false","d:true"]`,
+		})
+		assert.Len(t, result.Results, 2)
+		assert.Equal(t, 6.0, result.TotalPoints)
+		assert.Equal(t, 8.0, result.MaxPoints)
+		assert.Equal(t, 1, result.CorrectAnswers)
+	})
+
+	t.Run("registered scorer replaces built-in one", func(t *testing.T) {
+		engine := scoring.NewEngine(scoring.DefaultPolicy())
+		engine.Register(models.QuestionTypeSBA, &fixedScorer{})
+
+		result := engine.ScoreExam(questions, map[uint]string{1: "c"})
+		assert.Equal(t, 1.0, result.TotalPoints)
+		assert.Equal(t, uint(1), result.Results[0].QuestionID)
+	})
+}
+
+func TestEngine_NegativeMarking(t *testing.T) {
+	questions := parseScoringTestQuestions(t)
+
+	t.Run("fixed penalties override the ratio", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.NegativeMarking = scoring.NegativeMarking{
+			Enabled:          true,
+			PenaltyRatio:     0.5,
+			SBAPenalty:       float64Ptr(0.25),
+			TrueFalsePenalty: float64Ptr(0.2),
+		}
+
+		result := scoring.NewEngine(policy).ScoreExam(questions, map[uint]string{
+			1: "c",
+			2: `["a:true","b:false","c:false","d:true"]`,
+		})
+		assert.Equal(t, -0.25, result.Results[0].PointsEarned)
+		assert.Equal(t, 0.25, result.Results[0].Penalty)
+		assert.InDelta(t, 1.6, result.Results[1].PointsEarned, 1e-9)
+		assert.InDelta(t, 0.65, result.PenaltyPoints, 1e-9)
+		assert.InDelta(t, 1.35, result.TotalPoints, 1e-9)
+		assert.InDelta(t, 2.0, result.RawPoints(), 1e-9)
+		assert.True(t, result.NegativeMarking)
+	})
+
+	t.Run("skipped answers are only penalised when configured", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.NegativeMarking = scoring.NegativeMarking{Enabled: true, PenaltyRatio: 0.25}
+
+		result := scoring.NewEngine(policy).ScoreExam(questions, map[uint]string{2: `["a:true"]`})
+		assert.Equal(t, 0.0, result.PenaltyPoints)
+
+		policy.NegativeMarking.PenalizeSkipped = true
+		result = scoring.NewEngine(policy).ScoreExam(questions, map[uint]string{2: `["a:true"]`})
+		assert.Equal(t, -1.0, result.Results[0].PointsEarned)
+		assert.False(t, result.Results[0].Answered)
+		assert.Equal(t, 0.25, result.Results[1].PointsEarned) // 1 stem right, 3 skipped stems x 0.25
+	})
+
+	t.Run("question score floored at zero", func(t *testing.T) {
+		policy := scoring.DefaultPolicy()
+		policy.PartialCredit = false
+		policy.NegativeMarking = scoring.NegativeMarking{Enabled: true, TrueFalsePenalty: float64Ptr(1), FloorQuestionScore: true}
+
+		result := scoring.NewEngine(policy).ScoreQuestion(questions[1], `["a:false","b:true","c:false","d:true"]`)
+		assert.Equal(t, 0.0, result.PointsEarned)
+		assert.Equal(t, 0.0, result.Penalty)
+	})
+
+	t.Run("negative fixed penalty is invalid", func(t *testing.T) {
+		_, err := scoring.ParsePolicy(stringPtr(`{"negative_marking": {"enabled": true, "sba_penalty": -1}}`))
+		assert.Error(t, err)
+	})
+}
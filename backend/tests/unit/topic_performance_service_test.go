@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Mahfuz2811/medecole/backend/internal/repository"
+	"github.com/Mahfuz2811/medecole/backend/internal/service"
+)
+
+// MockTopicPerformanceRepository is a mock implementation of TopicPerformanceRepository
+type MockTopicPerformanceRepository struct {
+	mock.Mock
+}
+
+func (m *MockTopicPerformanceRepository) GetSubjectPerformance(userID uint) ([]repository.TopicPerformance, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]repository.TopicPerformance), args.Error(1)
+}
+
+func (m *MockTopicPerformanceRepository) GetSystemPerformance(userID uint) ([]repository.TopicPerformance, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]repository.TopicPerformance), args.Error(1)
+}
+
+func (m *MockTopicPerformanceRepository) GetSubjectTrend(userID uint, since time.Time) ([]repository.TopicTrendPoint, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).([]repository.TopicTrendPoint), args.Error(1)
+}
+
+func createTopicPerformanceSystems() []repository.TopicPerformance {
+	return []repository.TopicPerformance{
+		{TopicID: 3, TopicName: "Cardiovascular", SubjectID: 1, SubjectName: "Medicine", Attempts: 4, Questions: 40, Answered: 38, Correct: 30, Score: 30, MaxScore: 40, TotalTimeSpent: 2400},
+		{TopicID: 4, TopicName: "Respiratory", SubjectID: 1, SubjectName: "Medicine", Attempts: 3, Questions: 20, Answered: 20, Correct: 9, Score: 8.5, MaxScore: 20, TotalTimeSpent: 1500},
+		{TopicID: 7, TopicName: "Renal", SubjectID: 2, SubjectName: "Physiology", Attempts: 1, Questions: 4, Answered: 4, Correct: 0, Score: 0, MaxScore: 4, TotalTimeSpent: 200},
+		{TopicID: 8, TopicName: "Endocrine", SubjectID: 2, SubjectName: "Physiology", Attempts: 2, Questions: 12, Answered: 12, Correct: 6, Score: 6, MaxScore: 12, TotalTimeSpent: 720},
+		{TopicID: 0, Questions: 10, Answered: 10, Correct: 1, Score: 1, MaxScore: 10},
+	}
+}
+
+func TestTopicPerformanceService_GetTopicPerformance_RanksWeakestSystems(t *testing.T) {
+	mockRepo := &MockTopicPerformanceRepository{}
+	mockRepo.On("GetSubjectPerformance", uint(5)).Return([]repository.TopicPerformance{
+		{TopicID: 1, TopicName: "Medicine", Attempts: 4, Questions: 60, Answered: 58, Correct: 39, Score: 38.5, MaxScore: 60, TotalTimeSpent: 3900},
+		{TopicID: 2, TopicName: "Physiology", Attempts: 2, Questions: 16, Answered: 16, Correct: 6, Score: 6, MaxScore: 16, TotalTimeSpent: 920},
+	}, nil)
+	mockRepo.On("GetSystemPerformance", uint(5)).Return(createTopicPerformanceSystems(), nil)
+	mockRepo.On("GetSubjectTrend", uint(5), mock.AnythingOfType("time.Time")).Return([]repository.TopicTrendPoint{}, nil)
+
+	performanceService := service.NewTopicPerformanceService(mockRepo)
+	result, err := performanceService.GetTopicPerformance(5, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, result.TrendWeeks)
+
+	// Subjects are ordered weakest first
+	assert.Equal(t, "Physiology", result.Subjects[0].Name)
+	assert.Equal(t, 37.5, result.Subjects[0].AccuracyRate)
+	assert.Equal(t, 65.0, result.Subjects[1].AccuracyRate)
+	assert.Equal(t, 64.17, result.Subjects[1].ScoreRate)
+	assert.Equal(t, 65.0, result.Subjects[1].AverageTimeSpent)
+
+	assert.Len(t, result.Systems, 5)
+	assert.Equal(t, "Renal", result.Systems[0].Name)
+
+	// Renal has too few answers and unclassified answers are not a topic to study
+	if assert.Len(t, result.WeakestAreas, 3) {
+		assert.Equal(t, "Respiratory", result.WeakestAreas[0].Name)
+		assert.Equal(t, 45.0, result.WeakestAreas[0].AccuracyRate)
+		assert.Equal(t, "Physiology", result.WeakestAreas[1].SubjectName)
+		assert.Equal(t, "Cardiovascular", result.WeakestAreas[2].Name)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopicPerformanceService_GetTopicPerformance_BuildsSubjectTrends(t *testing.T) {
+	mockRepo := &MockTopicPerformanceRepository{}
+	mockRepo.On("GetSubjectPerformance", uint(5)).Return([]repository.TopicPerformance{
+		{TopicID: 1, TopicName: "Medicine", Questions: 30, Correct: 20, MaxScore: 30},
+		{TopicID: 2, TopicName: "Physiology", Questions: 20, Correct: 10, MaxScore: 20},
+	}, nil)
+	mockRepo.On("GetSystemPerformance", uint(5)).Return([]repository.TopicPerformance{}, nil)
+	mockRepo.On("GetSubjectTrend", uint(5), mock.MatchedBy(func(since time.Time) bool {
+		// Four weeks back from this week's Monday
+		return since.Weekday() == time.Monday && time.Since(since) < 4*7*24*time.Hour
+	})).Return([]repository.TopicTrendPoint{
+		{TopicID: 1, WeekStart: "2026-09-21", Questions: 10, Correct: 5, Score: 5, MaxScore: 10},
+		{TopicID: 2, WeekStart: "2026-09-21", Questions: 10, Correct: 6, Score: 6, MaxScore: 10},
+		{TopicID: 1, WeekStart: "2026-09-28", Questions: 10, Correct: 7, Score: 7, MaxScore: 10},
+		{TopicID: 2, WeekStart: "2026-10-05", Questions: 10, Correct: 4, Score: 3.5, MaxScore: 10},
+		{TopicID: 1, WeekStart: "2026-10-12", Questions: 10, Correct: 8, Score: 8, MaxScore: 10},
+		{TopicID: 0, WeekStart: "2026-10-12", Questions: 5, Correct: 3, Score: 3, MaxScore: 5},
+	}, nil)
+
+	performanceService := service.NewTopicPerformanceService(mockRepo)
+	result, err := performanceService.GetTopicPerformance(5, 4)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Trends, 3)
+
+	medicine := result.Trends[0]
+	assert.Equal(t, "Medicine", medicine.SubjectName)
+	assert.Len(t, medicine.Points, 3)
+	assert.Equal(t, 30.0, medicine.AccuracyChange)
+	assert.Equal(t, service.TrendImproving, medicine.Direction)
+
+	physiology := result.Trends[1]
+	assert.Equal(t, -20.0, physiology.AccuracyChange)
+	assert.Equal(t, 35.0, physiology.Points[1].ScoreRate)
+	assert.Equal(t, service.TrendDeclining, physiology.Direction)
+
+	unclassified := result.Trends[2]
+	assert.Equal(t, "Unclassified", unclassified.SubjectName)
+	assert.Equal(t, service.TrendSteady, unclassified.Direction)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopicPerformanceService_GetTopicPerformance_RepositoryError(t *testing.T) {
+	mockRepo := &MockTopicPerformanceRepository{}
+	mockRepo.On("GetSubjectPerformance", uint(5)).Return([]repository.TopicPerformance(nil), errors.New("database unavailable"))
+
+	performanceService := service.NewTopicPerformanceService(mockRepo)
+	result, err := performanceService.GetTopicPerformance(5, 12)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "GetSystemPerformance", mock.Anything)
+}
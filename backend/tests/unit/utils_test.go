@@ -423,3 +423,17 @@ func TestValidatePasswordEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateJWT_RoundTrip(t *testing.T) {
+	secret := "test-secret"
+	token, err := utils.GenerateJWT(12, "8801712345678", secret)
+	assert.NoError(t, err)
+
+	claims, err := utils.ValidateJWT(token, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), claims.UserID)
+	assert.Equal(t, "8801712345678", claims.MSISDN)
+
+	_, err = utils.ValidateJWT(token, "wrong-secret")
+	assert.Error(t, err)
+}